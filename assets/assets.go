@@ -0,0 +1,20 @@
+// Package assets holds the files compiled into the binary.
+package assets
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed maps
+var files embed.FS
+
+// Maps is the baseline map set: zone files plus map_keys.json,
+// zone_info.json and travel_links.json.
+func Maps() fs.FS {
+	sub, err := fs.Sub(files, "maps")
+	if err != nil {
+		panic(err) // The directory is embedded above
+	}
+	return sub
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devin-hart/nox-maps/assets"
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/eqlog"
+	"github.com/devin-hart/nox-maps/internal/maps"
+	"github.com/devin-hart/nox-maps/internal/parser"
+)
+
+// maxLocGap is the longest gap between two /locs that still counts as time
+// spent at the first one; longer gaps are breaks, logouts or a quiet macro.
+const maxLocGap = 60 * time.Second
+
+// analyze reads a directory of old EQ logs and aggregates where the
+// characters spent their time, what they killed and where they died, per
+// zone. The result goes to <config dir>/history, which the map shows with
+// View > Heatmap Data: History.
+func analyze(args []string) error {
+	cfg := config.Load()
+	defaultLogs := ""
+	if cfg.EQPath != "" {
+		defaultLogs = filepath.Join(cfg.EQPath, "Logs")
+	}
+
+	flags := newFlagSet("analyze")
+	logsDir := flags.String("logs", defaultLogs, "directory of eqlog_*.txt files (searched recursively)")
+	outDir := flags.String("out", config.GetHistoryDir(), "where to write zones.json and heatmaps/")
+	days := flags.Int("days", 0, "only lines from the last N days (0 = everything)")
+	cellSize := flags.Float64("cell", 50, "heatmap cell size in map units")
+	flags.Parse(args)
+
+	if *logsDir == "" {
+		return errors.New("no log directory: pass -logs or set the EQ path in nox-maps first")
+	}
+
+	files, total, err := findLogs(*logsDir)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no eqlog_*.txt files in %s", *logsDir)
+	}
+	// Engines are quiet; report bad overrides once here
+	_, errs := parser.CompilePatterns(cfg.ServerProfile.Parser)
+	for _, err := range errs {
+		fmt.Printf("⚠️  Ignoring parser override: %v\n", err)
+	}
+	fmt.Printf("📚 %d logs, %.1f MB\n", len(files), float64(total)/(1<<20))
+
+	// Zones logged by short name are kept under their long names, as the map does
+	maps.Builtin = assets.Maps()
+	maps.LoadZoneConfig(maps.Locate(config.GetMapDir(), "map_keys.json"))
+
+	a := &analyzer{
+		history:  config.NewHistory(),
+		heatmaps: make(map[string]*config.Heatmap),
+		cellSize: *cellSize,
+		progress: &progress{total: total},
+		parser:   cfg.ServerProfile.Parser,
+	}
+	if *days > 0 {
+		a.since = time.Now().AddDate(0, 0, -*days)
+	}
+
+	for _, path := range files {
+		if err := a.readLog(path); err != nil {
+			fmt.Fprintf(os.Stderr, "\n⚠️  %s: %v\n", filepath.Base(path), err)
+		}
+	}
+	a.progress.finish()
+
+	if err := a.save(*outDir); err != nil {
+		return fmt.Errorf("saving history: %w", err)
+	}
+	a.report(*outDir)
+	return nil
+}
+
+// findLogs lists every eqlog file under dir with their total size.
+func findLogs(dir string) ([]string, int64, error) {
+	var files []string
+	var total int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() || !strings.HasPrefix(name, "eqlog") || !strings.HasSuffix(name, ".txt") {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			total += info.Size()
+		}
+		files = append(files, path)
+		return nil
+	})
+	sort.Strings(files)
+	return files, total, err
+}
+
+type analyzer struct {
+	history  *config.History
+	heatmaps map[string]*config.Heatmap
+	cellSize float64
+	since    time.Time
+	progress *progress
+	parser   config.ParserOverrides
+}
+
+// lastLoc is the previous /loc of the log being read.
+type lastLoc struct {
+	zone string
+	x, y float64
+	at   time.Time
+}
+
+// readLog feeds one log through a fresh parser. Each log is one character,
+// so zone and position carry over only within the file.
+func (a *analyzer) readLog(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	engine := parser.NewEngine()
+	engine.Quiet = true
+	engine.SetOverrides(a.parser)
+	engine.ZoneLookup = maps.CanonicalZoneName
+	character, _ := eqlog.CharacterFromPath(path)
+	a.history.Logs++
+
+	var prev lastLoc
+	r := bufio.NewReader(io.TeeReader(f, a.progress))
+	for {
+		line, err := r.ReadString('\n')
+		if line = strings.TrimSpace(line); line != "" {
+			if at, ok := eqlog.ParseTimestamp(line); ok && !at.Before(a.since) {
+				a.line(engine, eqlog.LogLine{Line: line, Time: at, Character: character, Primary: true}, &prev)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (a *analyzer) line(engine *parser.Engine, l eqlog.LogLine, prev *lastLoc) {
+	if a.history.From.IsZero() || l.Time.Before(a.history.From) {
+		a.history.From = l.Time
+	}
+	if l.Time.After(a.history.To) {
+		a.history.To = l.Time
+	}
+
+	engine.ProcessLine(l)
+	state := engine.State()
+	if state.Zone == "" {
+		engine.DrainStats()
+		return
+	}
+
+	if engine.IsPosition(l.Line) {
+		if gap := l.Time.Sub(prev.at); prev.zone == state.Zone && gap > 0 && gap <= maxLocGap {
+			a.heatmap(state.Zone).Add(prev.x, prev.y, gap.Seconds())
+			a.history.Zone(state.Zone).Seconds += gap.Seconds()
+		}
+		*prev = lastLoc{zone: state.Zone, x: state.X, y: state.Y, at: l.Time}
+	}
+
+	for _, ev := range engine.DrainStats() {
+		z := a.history.Zone(state.Zone)
+		switch ev.Kind {
+		case parser.StatKill:
+			z.Kills++
+			z.KilledBy[ev.Target]++
+		case parser.StatDeath:
+			z.Deaths++
+			z.DeathLocs = append(z.DeathLocs, [2]float64{state.X, state.Y})
+		}
+	}
+}
+
+func (a *analyzer) heatmap(zone string) *config.Heatmap {
+	h, ok := a.heatmaps[zone]
+	if !ok {
+		h = config.NewHeatmap(a.cellSize)
+		a.heatmaps[zone] = h
+	}
+	return h
+}
+
+// save replaces any earlier history in dir.
+func (a *analyzer) save(dir string) error {
+	if err := os.RemoveAll(filepath.Join(dir, "heatmaps")); err != nil {
+		return err
+	}
+	for zone, h := range a.heatmaps {
+		if err := config.SaveHistoryHeatmap(dir, zone, h); err != nil {
+			return err
+		}
+	}
+	a.history.Generated = time.Now()
+	return a.history.Save(dir)
+}
+
+// report prints the zones with the most time.
+func (a *analyzer) report(dir string) {
+	zones := make([]string, 0, len(a.history.Zones))
+	for zone := range a.history.Zones {
+		zones = append(zones, zone)
+	}
+	sort.Slice(zones, func(i, j int) bool {
+		return a.history.Zones[zones[i]].Seconds > a.history.Zones[zones[j]].Seconds
+	})
+
+	fmt.Printf("\n🗺️  %d zones, %s to %s\n", len(zones),
+		a.history.From.Format("2006-01-02"), a.history.To.Format("2006-01-02"))
+	for i, zone := range zones {
+		if i == 15 {
+			fmt.Printf("   ... and %d more\n", len(zones)-i)
+			break
+		}
+		z := a.history.Zones[zone]
+		fmt.Printf("   %-28s %6.1fh  %5d kills  %3d deaths\n", zone, z.Seconds/3600, z.Kills, z.Deaths)
+	}
+	fmt.Printf("✅ Saved to %s\n", dir)
+}
+
+// progress draws a bar on stderr as bytes are read.
+type progress struct {
+	total, done int64
+	shown       int
+}
+
+func (p *progress) Write(b []byte) (int, error) {
+	p.done += int64(len(b))
+	if pct := int(p.done * 100 / max(p.total, 1)); pct != p.shown {
+		p.shown = pct
+		p.draw()
+	}
+	return len(b), nil
+}
+
+func (p *progress) draw() {
+	const width = 40
+	filled := min(p.shown*width/100, width)
+	fmt.Fprintf(os.Stderr, "\r[%s%s] %3d%%", strings.Repeat("#", filled), strings.Repeat(" ", width-filled), p.shown)
+}
+
+func (p *progress) finish() {
+	if p.shown != 100 {
+		p.shown = 100
+		p.draw()
+	}
+	fmt.Fprintln(os.Stderr)
+}
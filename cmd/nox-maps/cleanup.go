@@ -8,21 +8,26 @@ import (
 	"strings"
 )
 
-func main() {
+// cleanup deletes the map files in a directory (the built-in set by
+// default) that don't belong to any zone in its map_keys.json.
+func cleanup(args []string) error {
+	flags := newFlagSet("cleanup")
+	dir := flags.String("dir", "assets/maps", "map directory to clean, with its map_keys.json")
+	flags.Parse(args)
+
 	// 1. Load the valid keys from map_keys.json
-	validPrefixes, err := loadValidPrefixes("assets/maps/map_keys.json")
+	validPrefixes, err := loadValidPrefixes(filepath.Join(*dir, "map_keys.json"))
 	if err != nil {
-		panic(fmt.Sprintf("Failed to load keys: %v", err))
+		return fmt.Errorf("failed to load keys: %w", err)
 	}
 
 	// 2. Walk the directory and cleanup
-	dir := "assets/maps"
-	files, err := os.ReadDir(dir)
+	files, err := os.ReadDir(*dir)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	fmt.Printf("Scanning %s...\n", dir)
+	fmt.Printf("Scanning %s...\n", *dir)
 	deletedCount := 0
 	keptCount := 0
 
@@ -42,7 +47,7 @@ func main() {
 			keptCount++
 		} else {
 			// DELETE THE FILE
-			fullPath := filepath.Join(dir, file.Name())
+			fullPath := filepath.Join(*dir, file.Name())
 			if err := os.Remove(fullPath); err != nil {
 				fmt.Printf("Error deleting %s: %v\n", file.Name(), err)
 			} else {
@@ -53,6 +58,7 @@ func main() {
 	}
 
 	fmt.Printf("\nDone. Kept %d files. Deleted %d files.\n", keptCount, deletedCount)
+	return nil
 }
 
 func loadValidPrefixes(path string) (map[string]bool, error) {
@@ -77,7 +83,7 @@ func loadValidPrefixes(path string) (map[string]bool, error) {
 
 func shouldKeepFile(filename string, prefixes map[string]bool) bool {
 	lowerName := strings.ToLower(filename)
-	
+
 	// Remove extension
 	baseName := strings.TrimSuffix(lowerName, ".txt")
 
@@ -90,8 +96,8 @@ func shouldKeepFile(filename string, prefixes map[string]bool) bool {
 	// Split by underscore to find the base prefix
 	parts := strings.Split(baseName, "_")
 	if len(parts) > 1 {
-		// Reassemble the prefix if it contains underscores (rare but possible), 
-		// or just take the first part. 
+		// Reassemble the prefix if it contains underscores (rare but possible),
+		// or just take the first part.
 		// Standard EQ format is usually {code}_{layer}.
 		// However, some codes might have underscores.
 		// A safer check is to see if any valid prefix is the START of this string
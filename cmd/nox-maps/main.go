@@ -1,51 +1,112 @@
+// Command nox-maps is the map and its tools in one executable:
+//
+//	nox-maps [run] [-demo] [-replay file]    open the map (the default)
+//	nox-maps replay [-speed 10x] <eqlog>      open the map playing back an old log
+//	nox-maps import-markers [-replace] <file> add a marker file to your markers
+//	nox-maps export-markers [-zone name] <file>
+//	nox-maps validate-maps [-dir maps]        check the map files
+//	nox-maps analyze -logs <EQ>/Logs          build the history heatmaps
+//	nox-maps cleanup [-dir assets/maps]       delete map files no zone uses
+//
+// "nox-maps help <command>" lists a command's flags.
 package main
 
 import (
+	"flag"
 	"fmt"
-	"log"
 	"os"
-	"path/filepath"
+	"strings"
 
-	"github.com/devin-hart/nox-maps/internal/config"
-	"github.com/devin-hart/nox-maps/internal/eqlog"
-	"github.com/devin-hart/nox-maps/internal/parser"
-	"github.com/devin-hart/nox-maps/internal/ui"
-	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/devin-hart/nox-maps/internal/links"
 )
 
-func main() {
-	cfg := config.Load()
+type command struct {
+	name  string
+	args  string // Usage after the flags
+	short string
+	run   func(args []string) error
+}
 
-	cwd, _ := os.Getwd()
-	projectMapPath := filepath.Join(cwd, "assets", "maps")
+var commands []command
 
-	// CHANGED: Using JSON configuration
-	lookupPath := filepath.Join(projectMapPath, "map_keys.json")
+func init() {
+	commands = []command{
+		{"run", "[nox:// link]", "Open the map (the default when no command is given)", run},
+		{"replay", "<eqlog file>", "Open the map playing back an old log", replay},
+		{"import-markers", "<marker file>", "Add a marker file's markers to yours", importMarkers},
+		{"export-markers", "<marker file>", "Write your markers to a marker file", exportMarkers},
+		{"validate-maps", "", "Check the map files and list broken ones", validateMaps},
+		{"analyze", "", "Build the history heatmaps from a directory of old logs", analyze},
+		{"cleanup", "", "Delete the map files that no zone in map_keys.json uses", cleanup},
+	}
+}
 
-	fmt.Println("⚔️ Nox Maps Starting...")
+func main() {
+	args := os.Args[1:]
+	if len(args) > 0 && (args[0] == "-h" || args[0] == "-help" || args[0] == "--help") {
+		usage()
+		return
+	}
+	name := "run"
+	// Flags alone (and the nox:// links the OS passes) are for the map
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") && !strings.HasPrefix(args[0], links.Scheme+"://") {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" {
+		if len(args) > 0 {
+			if cmd := findCommand(args[0]); cmd != nil {
+				cmd.run([]string{"-h"}) // Prints the flags and exits
+			}
+		}
+		usage()
+		return
+	}
 
-	var reader *eqlog.Reader
-	engine := parser.NewEngine()
+	cmd := findCommand(name)
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", name)
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(args); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+}
 
-	// Only initialize log reader if path is configured
-	if cfg.EQPath != "" {
-		reader = eqlog.NewReader(cfg.EQPath)
-		if err := reader.Start(); err != nil {
-			log.Printf("Warning: Error starting log reader: %v", err)
-		} else {
-			go engine.ProcessLines(reader, reader.Lines)
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
 		}
-	} else {
-		fmt.Println("⚠️  No EQ path configured. Please set it in the menu bar.")
 	}
+	return nil
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: nox-maps [command] [flags]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", cmd.name, cmd.short)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun \"nox-maps help <command>\" for a command's flags.\n")
+}
 
-	// Initialize UI with JSON config path
-	window := ui.NewWindow(engine, projectMapPath, lookupPath, cfg)
-	if err := window.Init(); err != nil {
-		log.Printf("Window init warning: %v", err)
+// newFlagSet returns the flags for a command; -h prints its usage.
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		cmd := findCommand(name)
+		fmt.Fprintf(flags.Output(), "Usage: nox-maps %s [flags] %s\n\n%s.\n\n", name, cmd.args, cmd.short)
+		flags.PrintDefaults()
 	}
+	return flags
+}
 
-	if err := ebiten.RunGame(window); err != nil {
-		log.Fatal(err)
+// needArgs exits with the command's usage unless it got n arguments after
+// its flags.
+func needArgs(flags *flag.FlagSet, n int) {
+	if flags.NArg() != n {
+		flags.Usage()
+		os.Exit(2)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+)
+
+// Marker files from the command line, as File > Import/Export Markers does
+// them. Close the map first: it keeps the markers it has read in memory and
+// would write over an import in a zone it changes.
+
+func importMarkers(args []string) error {
+	flags := newFlagSet("import-markers")
+	replace := flags.Bool("replace", false, "discard your markers in the zones the file covers instead of merging")
+	flags.Parse(args)
+	needArgs(flags, 1)
+
+	path := flags.Arg(0)
+	file, err := config.ReadMarkerFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	cfg := config.Load()
+	added, duplicates := cfg.ImportMarkerFile(file, *replace)
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("saving imported markers: %w", err)
+	}
+	mode := "Merged"
+	if *replace {
+		mode = "Replaced with"
+	}
+	fmt.Printf("📥 %s %d markers in %d zones from %s (%d duplicates skipped)\n", mode, added, len(file.Markers), filepath.Base(path), duplicates)
+	return nil
+}
+
+func exportMarkers(args []string) error {
+	flags := newFlagSet("export-markers")
+	var zones []string
+	flags.Func("zone", "only this zone's markers (repeat for more; default every zone)", func(zone string) error {
+		zones = append(zones, zone)
+		return nil
+	})
+	flags.Parse(args)
+	needArgs(flags, 1)
+
+	cfg := config.Load()
+	markers := cfg.AllMarkers()
+	if len(zones) > 0 {
+		markers = selectZones(cfg, markers, zones)
+	}
+	if len(markers) == 0 {
+		return errors.New("there are no markers to export")
+	}
+	path := flags.Arg(0)
+	if err := config.WriteMarkerFile(path, markers); err != nil {
+		return fmt.Errorf("exporting markers: %w", err)
+	}
+	n := 0
+	for _, list := range markers {
+		n += len(list)
+	}
+	fmt.Printf("💾 Exported %d markers in %d zones to %s\n", n, len(markers), path)
+	return nil
+}
+
+// selectZones keeps the named zones' markers, matching names ignoring case.
+func selectZones(cfg *config.Config, markers map[string][]config.Marker, zones []string) map[string][]config.Marker {
+	picked := make(map[string][]config.Marker)
+	for _, name := range zones {
+		zone := cfg.MarkerZone(name)
+		if zone == "" {
+			fmt.Printf("⚠️  No markers in %s\n", name)
+			continue
+		}
+		picked[zone] = markers[zone]
+	}
+	return picked
+}
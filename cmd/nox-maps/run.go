@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/devin-hart/nox-maps/assets"
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/demo"
+	"github.com/devin-hart/nox-maps/internal/eqlog"
+	"github.com/devin-hart/nox-maps/internal/links"
+	"github.com/devin-hart/nox-maps/internal/maps"
+	"github.com/devin-hart/nox-maps/internal/parser"
+	"github.com/devin-hart/nox-maps/internal/ui"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// run opens the map, following the live log unless -demo or -replay is set.
+func run(args []string) error {
+	flags := newFlagSet("run")
+	demoMode := flags.Bool("demo", false, "run without EverQuest, walking a simulated character around a zone")
+	demoZone := flags.String("demo-zone", "East Commonlands", "zone for -demo")
+	demoPath := flags.String("demo-path", "", "file of positions for -demo to walk, one \"y, x[, z]\" /loc per line (default: the zone's saved breadcrumbs, else a loop)")
+	demoSpeed := flags.Float64("demo-speed", 0, "walking speed for -demo in map units per second (default 60)")
+	replay := flags.String("replay", "", "play back an old eqlog file instead of tailing the live one (see the replay command)")
+	replaySpeed := flags.String("replay-speed", "1x", "-replay speed: 1x, 10x (any multiple) or instant")
+	flags.Parse(args)
+
+	return runMap(mapOptions{
+		demo:        *demoMode,
+		demoZone:    *demoZone,
+		demoPath:    *demoPath,
+		demoSpeed:   *demoSpeed,
+		replay:      *replay,
+		replaySpeed: *replaySpeed,
+		link:        flags.Arg(0),
+	})
+}
+
+// replay opens the map playing back an old log.
+func replay(args []string) error {
+	flags := newFlagSet("replay")
+	speed := flags.String("speed", "1x", "1x, 10x (any multiple) or instant")
+	flags.Parse(args)
+	needArgs(flags, 1)
+	return runMap(mapOptions{replay: flags.Arg(0), replaySpeed: *speed})
+}
+
+// mapOptions is how the map gets its log lines.
+type mapOptions struct {
+	demo        bool
+	demoZone    string
+	demoPath    string
+	demoSpeed   float64
+	replay      string // eqlog file to play back
+	replaySpeed string
+	link        string // nox:// link the OS launched us for
+}
+
+// runMap loads the config and maps and runs the window until it's closed.
+func runMap(opts mapOptions) error {
+	cfg := config.Load()
+	cfg.Manage(500 * time.Millisecond) // Saves are written in the background; flushed by window.Close
+
+	// The built-in maps, with the user's maps directory laid over them
+	maps.Builtin = assets.Maps()
+	projectMapPath := config.GetMapDir()
+
+	lookupPath := maps.Locate(projectMapPath, "map_keys.json")
+
+	fmt.Println("⚔️ Nox Maps Starting...")
+
+	// Loaded before the log is read so zones logged by short name resolve
+	maps.LoadZoneConfig(lookupPath)
+	for _, err := range maps.SetColorKinds(cfg.LineKinds) {
+		fmt.Printf("⚠️  Ignoring line_kinds entry: %v\n", err)
+	}
+
+	var reader *eqlog.Reader
+	engine := parser.NewEngine()
+	engine.ZoneLookup = maps.CanonicalZoneName
+	engine.SetOverrides(cfg.ServerProfile.Parser)
+	engine.SetRules(cfg.Rules)
+
+	// Pick up hand-edited parser patterns and rules without a restart
+	config.WatchFile(2*time.Second, func(c *config.Config) {
+		engine.SetOverrides(c.ServerProfile.Parser)
+		engine.SetRules(c.Rules)
+	})
+
+	// Only initialize log reader if path is configured
+	if opts.demo {
+		path, source, err := demo.FindPath(projectMapPath, opts.demoZone, cfg.Character, opts.demoPath)
+		if err != nil {
+			return fmt.Errorf("demo: %w", err)
+		}
+		fmt.Printf("🎬 Demo mode: walking %s along %s (%d points)\n", opts.demoZone, source, len(path))
+		lines := make(chan eqlog.LogLine, 100)
+		go demo.Run(demo.Options{Zone: opts.demoZone, Path: path, Speed: opts.demoSpeed}, lines)
+		go engine.ProcessLines(nil, lines)
+	} else if opts.replay != "" {
+		speed, err := demo.ParseSpeed(opts.replaySpeed)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("⏯️  Replaying %s at %s\n", opts.replay, opts.replaySpeed)
+		lines := make(chan eqlog.LogLine, 1000)
+		go func() {
+			if err := demo.Replay(opts.replay, speed, lines); err != nil {
+				log.Printf("Replay stopped: %v", err)
+			}
+		}()
+		go engine.ProcessLines(nil, lines)
+	} else if cfg.EQPath != "" {
+		reader = eqlog.NewReader(cfg.EQPath)
+		reader.MultiCharacter = cfg.TrackAllCharacters
+		reader.SetCharacter(cfg.Character)
+		reader.IsPosition = engine.IsPosition
+		reader.MustKeep = engine.MustKeep
+		reader.ZoneOf = engine.ZoneOf
+		reader.SetLowLatency(cfg.LowLatency)
+		if err := reader.Start(); err != nil {
+			log.Printf("Warning: Error starting log reader: %v", err)
+		} else {
+			go engine.ProcessLines(reader, reader.Lines)
+		}
+	} else {
+		fmt.Println("⚠️  No EQ path configured. Please set it in the menu bar.")
+	}
+
+	// Initialize UI with JSON config path
+	window := ui.NewWindow(engine, projectMapPath, lookupPath, cfg)
+	window.SetLogSource(reader)
+	if opts.demo {
+		window.SetSimulated("Demo")
+	} else if opts.replay != "" {
+		window.SetSimulated("Replay")
+	}
+	if err := window.Init(); err != nil {
+		log.Printf("Window init warning: %v", err)
+	}
+
+	// Launched by the OS for a nox:// link
+	if strings.HasPrefix(opts.link, links.Scheme+"://") {
+		window.QueueLink(opts.link)
+	}
+
+	err := ebiten.RunGame(window)
+	window.Close()
+	return err
+}
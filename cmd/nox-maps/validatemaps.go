@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/devin-hart/nox-maps/assets"
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/maps"
+)
+
+// validateMaps runs the map check (Tools > Check Map Files) and prints its
+// report. It fails if any map file is broken.
+func validateMaps(args []string) error {
+	flags := newFlagSet("validate-maps")
+	dir := flags.String("dir", config.GetMapDir(), "maps directory to check, on top of the built-in maps")
+	missing := flags.Bool("missing", false, "list every known zone without a map")
+	moveBroken := flags.Bool("move-broken", false, "move broken files into a \"broken\" folder in the directory")
+	flags.Parse(args)
+
+	maps.Builtin = assets.Maps()
+	maps.LoadZoneConfig(maps.Locate(*dir, "map_keys.json"))
+	report, err := maps.CheckMaps(*dir, nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🩺 %s: %d zones in %d files (%d lines, %d labels)\n", report.MapDir, report.Zones, report.Files, report.Lines, report.Labels)
+	for _, p := range report.Broken {
+		fmt.Printf("❌ %s: %s\n", filepath.Base(p.Path), p.Problem)
+	}
+	for _, p := range report.Warnings {
+		fmt.Printf("⚠️  %s: %s\n", filepath.Base(p.Path), p.Problem)
+	}
+	if *missing {
+		for _, zone := range report.Missing {
+			fmt.Printf("   No map: %s\n", zone)
+		}
+	}
+	fmt.Printf("%d broken, %d with warnings, %d known zones without maps\n", len(report.Broken), len(report.Warnings), len(report.Missing))
+
+	if len(report.Broken) == 0 {
+		return nil
+	}
+	if *moveBroken {
+		moved, err := report.MoveBroken()
+		fmt.Printf("🩺 Moved %d broken map files to %s\n", moved, filepath.Join(report.MapDir, "broken"))
+		return err
+	}
+	return fmt.Errorf("%d broken map files", len(report.Broken))
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// config.json is replaced in one rename, so a crash mid-write leaves the
+// old file rather than half a new one. Before a write, the file on disk is
+// copied to config.json.1 (shifting older copies up to config.json.5) if
+// the newest backup is more than backupEvery old, so the backups span hours
+// of changes rather than the last few saves. A backup carries every zone's
+// marker file under "markers", as config.json did before the zone files, so
+// each one is a whole config on its own.
+
+const (
+	configBackups = 5
+	backupEvery   = time.Hour
+)
+
+// ConfigBackup is one rotated copy of config.json.
+type ConfigBackup struct {
+	Path string
+	Time time.Time // When it was backed up
+}
+
+func configBackupPath(n int) string {
+	return fmt.Sprintf("%s.%d", GetConfigPath(), n)
+}
+
+// ListConfigBackups returns the backups that exist, newest first.
+func ListConfigBackups() []ConfigBackup {
+	var list []ConfigBackup
+	for n := 1; n <= configBackups; n++ {
+		path := configBackupPath(n)
+		if fi, err := os.Stat(path); err == nil {
+			list = append(list, ConfigBackup{Path: path, Time: fi.ModTime()})
+		}
+	}
+	return list
+}
+
+// LoadConfigBackup reads a backup without touching the current config.
+func LoadConfigBackup(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// loadNewestBackup fills cfg from the newest backup that parses, for when
+// config.json itself doesn't.
+func loadNewestBackup(cfg *Config) bool {
+	for _, b := range ListConfigBackups() {
+		loaded, err := LoadConfigBackup(b.Path)
+		if err != nil {
+			continue
+		}
+		*cfg = *loaded
+		fmt.Printf("🛟 Loaded the config backup from %s (%s)\n", b.Time.Format("2006-01-02 15:04"), filepath.Base(b.Path))
+		return true
+	}
+	return false
+}
+
+// rotateConfigBackups copies config.json and the marker files to
+// config.json.1 if the newest backup is old enough. A config.json that
+// doesn't parse isn't kept.
+func rotateConfigBackups() error {
+	if fi, err := os.Stat(configBackupPath(1)); err == nil && time.Since(fi.ModTime()) < backupEvery {
+		return nil
+	}
+	data, err := os.ReadFile(GetConfigPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil {
+		return nil
+	}
+	markers, err := readAllMarkerFiles()
+	if err != nil {
+		return err
+	}
+	if len(markers) > 0 {
+		if fields["markers"], err = json.Marshal(markers); err != nil {
+			return err
+		}
+	}
+	if data, err = json.MarshalIndent(fields, "", "  "); err != nil {
+		return err
+	}
+
+	for n := configBackups - 1; n >= 1; n-- {
+		if err := os.Rename(configBackupPath(n), configBackupPath(n+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return writeFileAtomic(configBackupPath(1), data)
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it over path.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
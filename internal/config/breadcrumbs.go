@@ -0,0 +1,107 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type BreadcrumbPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+// GetBreadcrumbDir is where per-zone trails are kept between sessions.
+func GetBreadcrumbDir() string {
+	dir := filepath.Join(filepath.Dir(GetConfigPath()), "breadcrumbs")
+	os.MkdirAll(dir, 0755)
+	return dir
+}
+
+// zoneFileName turns a zone name like "The Feerrott" into "the_feerrott".
+func zoneFileName(zone string) string {
+	name := strings.ToLower(strings.TrimSpace(zone))
+	name = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+	return name
+}
+
+// BreadcrumbKey names the file a character's trail for a zone is kept
+// under, relative to GetBreadcrumbDir: one directory per character, so
+// switching boxes doesn't mix trails. Character "" is the shared trail from
+// before characters were told apart.
+func BreadcrumbKey(character, zone string) string {
+	if character == "" {
+		return zoneFileName(zone)
+	}
+	return zoneFileName(character) + "/" + zoneFileName(zone)
+}
+
+// breadcrumbPath turns a key into a file path. Keys may come from session
+// files, so each part is cleaned again rather than trusted.
+func breadcrumbPath(key string) string {
+	parts := strings.Split(key, "/")
+	for i, p := range parts {
+		parts[i] = zoneFileName(p)
+	}
+	return filepath.Join(GetBreadcrumbDir(), filepath.Join(parts...)+".json")
+}
+
+// LoadBreadcrumbs returns a character's saved trail for a zone (empty if
+// none), starting from the shared one if the character has none yet.
+func LoadBreadcrumbs(character, zone string) []BreadcrumbPoint {
+	if zone == "" {
+		return nil
+	}
+	points, err := readBreadcrumbs(breadcrumbPath(BreadcrumbKey(character, zone)))
+	if os.IsNotExist(err) && character != "" {
+		points, _ = readBreadcrumbs(breadcrumbPath(BreadcrumbKey("", zone)))
+	}
+	return points
+}
+
+func readBreadcrumbs(path string) ([]BreadcrumbPoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var points []BreadcrumbPoint
+	if err := json.Unmarshal(data, &points); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// SaveBreadcrumbs writes a character's trail for a zone, removing the file
+// when it's empty.
+func SaveBreadcrumbs(character, zone string, points []BreadcrumbPoint) error {
+	if zone == "" {
+		return nil
+	}
+	return SaveBreadcrumbsKey(BreadcrumbKey(character, zone), points)
+}
+
+// SaveBreadcrumbsKey writes the trail kept under a BreadcrumbKey.
+func SaveBreadcrumbsKey(key string, points []BreadcrumbPoint) error {
+	path := breadcrumbPath(key)
+	if len(points) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.Marshal(points)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,330 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Cloud Sync keeps markers and zone notes in one JSON document on a server
+// (a SyncProvider), so several machines share the same set. A sync fetches
+// the document, merges it with the local copy against the copy both last
+// agreed on (cloud_sync_base.json next to config.json), stores the result
+// if it changed and hands it back to be applied with ApplySync.
+//
+// The merge goes by marker ID. A change on one side wins over no change on
+// the other, and an edit wins over a removal. When both sides edited the
+// same marker, this machine's edit is kept and the other is reported.
+// Notes edited on both sides keep both texts, this machine's first. Storing
+// only succeeds if the document hasn't changed since it was fetched; if it
+// has, the sync starts over with the new one.
+
+const (
+	syncDataVersion = 1
+	syncRetries     = 3
+	maxSyncSize     = 64 << 20
+)
+
+var (
+	ErrNoSyncData   = errors.New("nothing synced yet")
+	ErrSyncConflict = errors.New("changed on the server since it was fetched")
+	ErrSyncInsecure = errors.New("the address isn't https://, so the login would be sent unencrypted")
+)
+
+// SyncProvider stores the shared document.
+type SyncProvider interface {
+	// Fetch returns the document and its revision (never ""), or
+	// ErrNoSyncData if there isn't one yet.
+	Fetch() (data []byte, rev string, err error)
+
+	// Store replaces the document if it's still at rev ("" = there was
+	// none) and returns the new revision, or ErrSyncConflict.
+	Store(data []byte, rev string) (string, error)
+
+	// String names the server, for messages.
+	String() string
+}
+
+// NewSyncProvider returns the provider opts configures.
+func NewSyncProvider(opts CloudSyncOptions) (SyncProvider, error) {
+	switch strings.ToLower(opts.Provider) {
+	case "", "webdav":
+		return newWebDAV(opts)
+	}
+	return nil, fmt.Errorf("unknown cloud sync provider %q", opts.Provider)
+}
+
+// SyncData is the shared document.
+type SyncData struct {
+	Version int                 `json:"version"`
+	Updated time.Time           `json:"updated"`
+	Markers map[string][]Marker `json:"markers"` // zone name -> markers
+	Notes   map[string]string   `json:"notes"`   // zone name -> notes
+}
+
+// SyncResult is how a sync went.
+type SyncResult struct {
+	Sent      SyncData // The local copy the sync started from
+	Merged    SyncData // What the server holds now
+	Pushed    bool     // The server's copy was replaced
+	Conflicts []string // Markers and notes changed on both sides
+	Err       error
+}
+
+// GetSyncBasePath holds the copy of the synced document both sides last
+// agreed on.
+func GetSyncBasePath() string {
+	return filepath.Join(filepath.Dir(GetConfigPath()), "cloud_sync_base.json")
+}
+
+// SyncData copies the markers and notes to sync. It reads every marker
+// file; call it on the goroutine that owns the config.
+func (c *Config) SyncData() SyncData {
+	d := SyncData{Markers: make(map[string][]Marker), Notes: make(map[string]string)}
+	for zone, markers := range c.AllMarkers() {
+		d.Markers[zone] = slices.Clone(markers)
+	}
+	for zone, notes := range c.ZoneNotes {
+		if notes != "" {
+			d.Notes[zone] = notes
+		}
+	}
+	return d
+}
+
+// Sync merges local with p's copy and stores the result. It doesn't touch
+// the config, so it can run on any goroutine; pass the result to ApplySync.
+func Sync(p SyncProvider, local SyncData) SyncResult {
+	res := SyncResult{Sent: local}
+	base, err := readSyncBase()
+	if err != nil {
+		fmt.Printf("⚠️  Error reading %s, syncing without it: %v\n", filepath.Base(GetSyncBasePath()), err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		remote, rev, err := fetchSyncData(p)
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		res.Merged, res.Conflicts = mergeSyncData(base, local, remote)
+		if rev != "" && sameSyncData(res.Merged, remote) {
+			break
+		}
+
+		res.Merged.Version = syncDataVersion
+		res.Merged.Updated = time.Now()
+		data, err := json.MarshalIndent(res.Merged, "", "  ")
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		_, err = p.Store(data, rev)
+		if errors.Is(err, ErrSyncConflict) && attempt < syncRetries {
+			continue
+		}
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		res.Pushed = true
+		break
+	}
+
+	if err := writeSyncBase(res.Merged); err != nil {
+		fmt.Printf("⚠️  Error saving %s: %v\n", filepath.Base(GetSyncBasePath()), err)
+	}
+	return res
+}
+
+// ApplySync puts a sync's result into the config. Changes made here since
+// the sync started are kept; they go up with the next one. It returns the
+// zones whose markers changed.
+func (c *Config) ApplySync(res SyncResult) []string {
+	current := c.SyncData()
+	applied, _ := mergeSyncData(res.Sent, current, res.Merged)
+
+	var zones []string
+	for _, zone := range syncZones(current.Markers, applied.Markers) {
+		if !slices.Equal(current.Markers[zone], applied.Markers[zone]) {
+			c.SetZoneMarkers(zone, applied.Markers[zone])
+			zones = append(zones, zone)
+		}
+	}
+	for _, zone := range syncZones(current.Notes, applied.Notes) {
+		if current.Notes[zone] == applied.Notes[zone] {
+			continue
+		}
+		if applied.Notes[zone] == "" {
+			delete(c.ZoneNotes, zone)
+			continue
+		}
+		if c.ZoneNotes == nil {
+			c.ZoneNotes = make(map[string]string)
+		}
+		c.ZoneNotes[zone] = applied.Notes[zone]
+	}
+	c.Save()
+	return zones
+}
+
+// fetchSyncData reads p's copy; rev is "" if there isn't one.
+func fetchSyncData(p SyncProvider) (SyncData, string, error) {
+	var d SyncData
+	data, rev, err := p.Fetch()
+	if errors.Is(err, ErrNoSyncData) {
+		return d, "", nil
+	}
+	if err != nil {
+		return d, "", err
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return d, "", fmt.Errorf("the synced copy isn't valid: %w", err)
+	}
+	if d.Version > syncDataVersion {
+		return d, "", fmt.Errorf("the synced copy is version %d, newer than this build supports", d.Version)
+	}
+	for _, markers := range d.Markers {
+		fillMarkerIDs(markers)
+	}
+	return d, rev, nil
+}
+
+func readSyncBase() (SyncData, error) {
+	var d SyncData
+	data, err := os.ReadFile(GetSyncBasePath())
+	if os.IsNotExist(err) {
+		return d, nil
+	}
+	if err != nil {
+		return d, err
+	}
+	return d, json.Unmarshal(data, &d)
+}
+
+func writeSyncBase(d SyncData) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(GetSyncBasePath(), data)
+}
+
+// mergeSyncData merges local and remote, both changed from base.
+func mergeSyncData(base, local, remote SyncData) (SyncData, []string) {
+	merged := SyncData{Markers: make(map[string][]Marker), Notes: make(map[string]string)}
+	var conflicts []string
+	for _, zone := range syncZones(local.Markers, remote.Markers) {
+		markers, both := mergeMarkers(base.Markers[zone], local.Markers[zone], remote.Markers[zone])
+		if len(markers) > 0 {
+			merged.Markers[zone] = markers
+		}
+		for _, label := range both {
+			conflicts = append(conflicts, fmt.Sprintf("%s: marker '%s' was edited on both sides; kept this machine's", zone, label))
+		}
+	}
+	for _, zone := range syncZones(local.Notes, remote.Notes) {
+		notes, both := mergeNotes(base.Notes[zone], local.Notes[zone], remote.Notes[zone])
+		if notes != "" {
+			merged.Notes[zone] = notes
+		}
+		if both {
+			conflicts = append(conflicts, fmt.Sprintf("%s: notes were edited on both sides; kept both", zone))
+		}
+	}
+	return merged, conflicts
+}
+
+// mergeMarkers merges one zone's markers, in the remote order with new
+// local markers after. It also returns the labels of markers edited on
+// both sides.
+func mergeMarkers(base, local, remote []Marker) ([]Marker, []string) {
+	index := func(markers []Marker) map[string]Marker {
+		byID := make(map[string]Marker, len(markers))
+		for _, m := range markers {
+			byID[m.ID] = m
+		}
+		return byID
+	}
+	b, l, r := index(base), index(local), index(remote)
+
+	var merged []Marker
+	var conflicts []string
+	seen := make(map[string]bool)
+	for _, m := range slices.Concat(remote, local) {
+		if seen[m.ID] {
+			continue
+		}
+		seen[m.ID] = true
+		bm, inBase := b[m.ID]
+		lm, inLocal := l[m.ID]
+		rm, inRemote := r[m.ID]
+		switch {
+		case inLocal && inRemote:
+			if inBase && lm == bm {
+				merged = append(merged, rm)
+				continue
+			}
+			if lm != rm && !(inBase && rm == bm) {
+				conflicts = append(conflicts, lm.Label)
+			}
+			merged = append(merged, lm)
+		case inLocal:
+			if !inBase || lm != bm { // Removed there, unless edited here
+				merged = append(merged, lm)
+			}
+		case inRemote:
+			if !inBase || rm != bm {
+				merged = append(merged, rm)
+			}
+		}
+	}
+	return merged, conflicts
+}
+
+// mergeNotes merges one zone's notes; true if both sides edited them.
+func mergeNotes(base, local, remote string) (string, bool) {
+	switch {
+	case local == remote || remote == base:
+		return local, false
+	case local == base:
+		return remote, false
+	case local == "": // Edited there, removed here
+		return remote, false
+	case remote == "":
+		return local, false
+	}
+	return local + "\n\n" + remote, true
+}
+
+func sameSyncData(a, b SyncData) bool {
+	for _, zone := range syncZones(a.Markers, b.Markers) {
+		if !slices.Equal(a.Markers[zone], b.Markers[zone]) {
+			return false
+		}
+	}
+	for _, zone := range syncZones(a.Notes, b.Notes) {
+		if a.Notes[zone] != b.Notes[zone] {
+			return false
+		}
+	}
+	return true
+}
+
+// syncZones lists the zones in either map, sorted.
+func syncZones[V any](a, b map[string]V) []string {
+	zones := slices.Collect(maps.Keys(a))
+	for zone := range b {
+		if _, ok := a[zone]; !ok {
+			zones = append(zones, zone)
+		}
+	}
+	slices.Sort(zones)
+	return zones
+}
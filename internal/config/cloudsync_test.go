@@ -0,0 +1,122 @@
+package config
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestMergeMarkers(t *testing.T) {
+	a := Marker{ID: "a", Label: "Entrance"}
+	b := Marker{ID: "b", Label: "Named"}
+	aMoved := Marker{ID: "a", Label: "Entrance", X: 10}
+	aRenamed := Marker{ID: "a", Label: "Front door"}
+
+	for _, tc := range []struct {
+		name                string
+		base, local, remote []Marker
+		want                []Marker
+		conflicts           []string
+	}{
+		{"unchanged", []Marker{a}, []Marker{a}, []Marker{a}, []Marker{a}, nil},
+		{"added here", nil, []Marker{a}, nil, []Marker{a}, nil},
+		{"added there", nil, nil, []Marker{a}, []Marker{a}, nil},
+		{"added on both sides", nil, []Marker{a}, []Marker{b}, []Marker{b, a}, nil},
+		{"same marker added on both sides", nil, []Marker{a}, []Marker{aMoved}, []Marker{a}, []string{"Entrance"}},
+		{"edited here", []Marker{a}, []Marker{aMoved}, []Marker{a}, []Marker{aMoved}, nil},
+		{"edited there", []Marker{a}, []Marker{a}, []Marker{aMoved}, []Marker{aMoved}, nil},
+		{"removed here", []Marker{a, b}, []Marker{b}, []Marker{a, b}, []Marker{b}, nil},
+		{"removed there", []Marker{a, b}, []Marker{a, b}, []Marker{b}, []Marker{b}, nil},
+		{"edited here, removed there", []Marker{a}, []Marker{aMoved}, nil, []Marker{aMoved}, nil},
+		{"removed here, edited there", []Marker{a}, nil, []Marker{aMoved}, []Marker{aMoved}, nil},
+		{"removed on both sides", []Marker{a}, nil, nil, nil, nil},
+		{"edited on both sides", []Marker{a}, []Marker{aRenamed}, []Marker{aMoved}, []Marker{aRenamed}, []string{"Front door"}},
+		{"same edit on both sides", []Marker{a}, []Marker{aMoved}, []Marker{aMoved}, []Marker{aMoved}, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, conflicts := mergeMarkers(tc.base, tc.local, tc.remote)
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("merged %+v, want %+v", got, tc.want)
+			}
+			if !slices.Equal(conflicts, tc.conflicts) {
+				t.Errorf("conflicts %q, want %q", conflicts, tc.conflicts)
+			}
+		})
+	}
+}
+
+func TestMergeNotes(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		base, local, remote string
+		want                string
+		both                bool
+	}{
+		{"unchanged", "camp", "camp", "camp", "camp", false},
+		{"added here", "", "camp", "", "camp", false},
+		{"added there", "", "", "camp", "camp", false},
+		{"added on both sides", "", "here", "there", "here\n\nthere", true},
+		{"edited here", "camp", "camp at 2", "camp", "camp at 2", false},
+		{"edited there", "camp", "camp", "camp at 2", "camp at 2", false},
+		{"edited here, removed there", "camp", "camp at 2", "", "camp at 2", false},
+		{"removed here, edited there", "camp", "", "camp at 2", "camp at 2", false},
+		{"removed on both sides", "camp", "", "", "", false},
+		{"edited on both sides", "camp", "camp at 2", "camp at 3", "camp at 2\n\ncamp at 3", true},
+		{"same edit on both sides", "camp", "camp at 2", "camp at 2", "camp at 2", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, both := mergeNotes(tc.base, tc.local, tc.remote)
+			if got != tc.want || both != tc.both {
+				t.Errorf("got %q, %v; want %q, %v", got, both, tc.want, tc.both)
+			}
+		})
+	}
+}
+
+func TestWebDAVRefusesHTTP(t *testing.T) {
+	opts := CloudSyncOptions{URL: "http://nas.local/dav/"}
+	if _, err := NewSyncProvider(opts); !errors.Is(err, ErrSyncInsecure) {
+		t.Errorf("http:// without allow_http: %v, want ErrSyncInsecure", err)
+	}
+	opts.AllowHTTP = true
+	if _, err := NewSyncProvider(opts); err != nil {
+		t.Errorf("http:// with allow_http: %v", err)
+	}
+	if _, err := NewSyncProvider(CloudSyncOptions{URL: "https://nas.local/dav/"}); err != nil {
+		t.Errorf("https://: %v", err)
+	}
+}
+
+func TestWebDAVWeakETag(t *testing.T) {
+	const etag = `"5f3a"`
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			rw.Header().Set("ETag", "W/"+etag) // As a compressing proxy sends it
+			rw.Write([]byte("{}"))
+		case http.MethodPut:
+			if r.Header.Get("If-Match") != etag {
+				rw.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			rw.Header().Set("ETag", `"5f3b"`)
+			rw.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	d, err := newWebDAV(CloudSyncOptions{URL: srv.URL + "/sync.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.client = srv.Client()
+	_, rev, err := d.Fetch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Store([]byte("{}"), rev); err != nil {
+		t.Errorf("storing at weak ETag %s: %v", rev, err)
+	}
+}
@@ -2,21 +2,431 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
+	"image/color"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 )
 
 type Marker struct {
+	ID    string  `json:"id,omitempty"` // Stable handle for links (tasks etc.)
 	X     float64 `json:"x"`
 	Y     float64 `json:"y"`
 	Label string  `json:"label"`
-	Color string  `json:"color"` // "red", "blue", "green", "yellow", "purple"
+	Color string  `json:"color"` // Hex "#rrggbb" (legacy: "red", "blue", "green", "yellow", "purple")
 	Shape string  `json:"shape"` // "circle", "square", "triangle", "diamond", "star"
+
+	Category string  `json:"category,omitempty"` // e.g. "quest", "camp"; "" = uncategorized
+	Radius   float64 `json:"radius,omitempty"`   // Map units; > 0 draws a circle (aggro range etc.)
+	Pack     string  `json:"pack,omitempty"`     // Marker pack it was installed from (see markerpack.go)
+}
+
+// Annotation is a path or polygon drawn over a zone ("safe hallway",
+// "roamer path").
+type Annotation struct {
+	ID     string       `json:"id"`
+	Kind   string       `json:"kind"` // "path" or "polygon"
+	Label  string       `json:"label,omitempty"`
+	Points [][2]float64 `json:"points"`          // Map coordinates
+	Color  string       `json:"color"`           // Hex "#rrggbb"
+	Width  float64      `json:"width,omitempty"` // Stroke in pixels (default 2)
+}
+
+// Label priorities, from always shown to never shown
+const (
+	LabelImportant = "important" // Shown in every label mode but None
+	LabelNormal    = "normal"    // Shown with Labels: All
+	LabelMinor     = "minor"     // Shown with Labels: All once zoomed in
+	LabelHidden    = "hidden"
+)
+
+// LabelRule gives the map labels matching it a priority. A rule with both
+// fields set needs both to match.
+type LabelRule struct {
+	Match    string `json:"match,omitempty"` // Case-insensitive substring of the text; "" = any
+	Color    string `json:"color,omitempty"` // Hex "#rrggbb"; "" = any
+	Priority string `json:"priority"`
 }
 
 type Config struct {
-	EQPath  string              `json:"eq_path"`
-	Markers map[string][]Marker `json:"markers"` // zone name -> markers
+	EQPath             string                  `json:"eq_path"`
+	Annotations        map[string][]Annotation `json:"annotations,omitempty"` // zone name -> paths and polygons
+	TrackAllCharacters bool                    `json:"track_all_characters"`  // Tail every active log (boxing)
+	Character          string                  `json:"character,omitempty"`   // Log to follow; "" = the most recently written
+	LowLatency         bool                    `json:"low_latency"`           // Poll logs faster and drop stale /locs when behind
+
+	// Manual overrides for GPUs/drivers the startup health check doesn't catch
+	DisableAntiAlias    bool `json:"disable_antialias"`
+	DisableTransparency bool `json:"disable_transparency"`
+
+	// Overlay window mode
+	AlwaysOnTop  bool `json:"always_on_top"`
+	ClickThrough bool `json:"click_through"` // Mouse input passes through to the window below
+	Frameless    bool `json:"frameless"`     // No OS title bar; the menu bar moves the window (see ui/frameless.go)
+	Tray         bool `json:"tray"`          // Tray icon; minimizing hides to it (see ui/tray.go)
+
+	Window WindowGeometry `json:"window,omitzero"` // Where the window was last; empty = default size on the primary monitor
+
+	// Player arrow interpolation between /loc updates (see ui/motion.go)
+	DisableInterpolation bool    `json:"disable_interpolation"`
+	MotionSmoothing      float64 `json:"motion_smoothing,omitempty"` // 0-1, higher = smoother but laggier (default 0.8)
+
+	DisableSmoothZoom bool `json:"disable_smooth_zoom"` // Wheel zoom jumps instead of easing (see ui/zoom.go)
+
+	RecentColors []string `json:"recent_colors"` // Most recent first, hex "#rrggbb"
+
+	ZoneNotes map[string]string `json:"zone_notes"` // zone name -> freeform notes
+	ZoneTasks map[string][]Task `json:"zone_tasks"` // zone name -> checklist
+
+	KeyBindings map[string]string `json:"key_bindings"` // action -> key name (e.g. "pan_up": "Up")
+
+	HiddenLayers map[string][]int `json:"hidden_layers"`         // zone name -> hidden map layers (0 = base file)
+	DrawLayers   []DrawLayer      `json:"draw_layers,omitempty"` // Overlay draw order, bottom first; empty = default
+
+	ZPresets map[string][]ZPreset `json:"z_presets,omitempty"` // zone name -> named Z-filter bands
+
+	CameraBookmarks map[string][]CameraBookmark `json:"camera_bookmarks,omitempty"` // zone name -> saved views, in number key order
+
+	ServerProfile ServerProfile `json:"server_profile"`
+
+	Rules []Rule `json:"rules,omitempty"` // User log rules, checked after the built-in parsing
+
+	MapsChecked string `json:"maps_checked,omitempty"` // Maps directory the first-run file check last covered
+
+	HiddenCategories []string `json:"hidden_categories"` // Marker categories not drawn ("" = uncategorized)
+
+	LabelRules map[string][]LabelRule `json:"label_rules,omitempty"` // maps directory -> label priority rules, first match wins
+
+	// Map label text scales with the zoom between these (pixels; equal = fixed size)
+	LabelMinSize int `json:"label_min_size,omitempty"` // Default 10
+	LabelMaxSize int `json:"label_max_size,omitempty"` // Default 22
+
+	// Text sizes: "small", "medium" (default) or "large". Both are scaled up
+	// on big monitors unless disable_font_autoscale is set.
+	UIFontSize           string `json:"ui_font_size,omitempty"`    // Menus, the info panel, marker and party names
+	LabelFontSize        string `json:"label_font_size,omitempty"` // Map labels (on top of label_min_size/label_max_size)
+	DisableFontAutoScale bool   `json:"disable_font_autoscale"`
+
+	MapOverrides map[string]string `json:"map_overrides,omitempty"` // lowercase zone name -> map file code to use instead of map_keys.json's
+
+	DirectionStyle string `json:"direction_style,omitempty"` // "compass" (default) or "relative" to the player's facing
+	MapBackground  string `json:"map_background,omitempty"`  // "dark" (default), "parchment" or "light"
+	MapColors      string `json:"map_colors,omitempty"`      // "" (the map's own), "dark" or "high_contrast"
+
+	// View rotation (see ui/rotation.go)
+	HeadingUp   bool    `json:"heading_up"`             // Turn the map so the player faces up
+	MapRotation float64 `json:"map_rotation,omitempty"` // Degrees clockwise otherwise; 0 = north up
+
+	Theme       string      `json:"theme,omitempty"`       // "classic" (default), "dark", "light" or "custom"
+	CustomTheme ThemeColors `json:"custom_theme,omitzero"` // The "custom" theme; empty fields keep classic's
+
+	// Map colors that mean water or zone lines in packs that don't follow
+	// the usual conventions: "#rrggbb" -> "water", "zone_line" or "wall"
+	LineKinds map[string]string `json:"line_kinds,omitempty"`
+
+	// Positions posted in group/guild chat ("loc: -1234, 567")
+	ChatLocPattern string `json:"chat_loc_pattern,omitempty"` // Must capture Y and X in /loc order
+	ChatLocTimeout int    `json:"chat_loc_timeout,omitempty"` // Seconds a posted loc stays on the map (default 60)
+
+	// Optional: read /loc from new screenshots with an external OCR program
+	ScreenshotOCR bool   `json:"screenshot_ocr"`
+	OCRCommand    string `json:"ocr_command,omitempty"` // e.g. "tesseract {image} stdout" (default)
+
+	// Optional: spoken status announcements through a text-to-speech program
+	Announcements bool   `json:"announcements"`
+	AnnounceEvery int    `json:"announce_every,omitempty"` // Seconds between status summaries (default 30)
+	SpeechCommand string `json:"speech_command,omitempty"` // e.g. "espeak-ng {text}"; platform default if empty
+
+	// Optional: alert sounds for events (see ui/alerts.go)
+	SoundAlerts      bool              `json:"sound_alerts"`
+	AlertSounds      map[string]string `json:"alert_sounds,omitempty"`       // event -> built-in tone or .wav path; "none" mutes
+	SoundCommand     string            `json:"sound_command,omitempty"`      // e.g. "paplay {file}"; platform default if empty
+	CorpseDecayHours float64           `json:"corpse_decay_hours,omitempty"` // Decay warning comes an hour before (default 168)
+
+	// Idle (AFK) detection, see ui/afk.go
+	AFKMinutes int  `json:"afk_minutes,omitempty"` // Minutes without moving before a character shows AFK (default 5)
+	AFKAlert   bool `json:"afk_alert"`             // Sound when you go AFK while holding a camp
+
+	Travel TravelOptions `json:"travel"`
+
+	// Optional: live marker sharing over the LAN (see internal/netsync)
+	Sync SyncOptions `json:"sync"`
+
+	// Optional: markers and zone notes shared through a server (see cloudsync.go)
+	CloudSync CloudSyncOptions `json:"cloud_sync,omitzero"`
+
+	CampClaims map[string][]CampClaim `json:"camp_claims"` // zone name -> camps currently held
+	CampLists  map[string][]CampList  `json:"camp_lists"`  // zone name -> waiting lists
+
+	// Marker packs (see markerpack.go)
+	MarkerPacks    map[string]InstalledPack `json:"marker_packs,omitempty"`    // pack name -> installed version
+	PublishedPacks map[string]int           `json:"published_packs,omitempty"` // pack name -> last version published here
+
+	// Map packs: community map sets downloaded into the maps directory (see internal/mappack)
+	MapPackSources []MapPackSource             `json:"map_pack_sources,omitempty"`
+	MapPacks       map[string]InstalledMapPack `json:"map_packs,omitempty"` // source name -> what's installed
+
+	// Markers are kept in a file per zone (see zonemarkers.go) and read
+	// through ZoneMarkers. This only holds markers found in config.json:
+	// from before the zone files, or in a backup (see backups.go).
+	BackupMarkers map[string][]Marker `json:"markers,omitempty"`
+
+	markers zoneMarkers
+	written []byte   // config.json as last written, to skip unchanged writes
+	manager *manager // Background saving, if started
+}
+
+// CampList is the waiting list for one camp, in order.
+type CampList struct {
+	Camp    string          `json:"camp"`
+	Entries []CampListEntry `json:"entries"`
+}
+
+type CampListEntry struct {
+	Name  string    `json:"name"`
+	Added time.Time `json:"added"`
+}
+
+// CampClaim records a camp being held: who claimed it, where and since when.
+type CampClaim struct {
+	Camp    string    `json:"camp"`
+	X       float64   `json:"x"`
+	Y       float64   `json:"y"`
+	Radius  float64   `json:"radius"`
+	Claimer string    `json:"claimer"`
+	Started time.Time `json:"started"`
+}
+
+// DrawLayer is one overlay layer's place in the draw order.
+type DrawLayer struct {
+	Name    string  `json:"name"`    // e.g. "markers", see ui/drawlayers.go
+	Opacity float64 `json:"opacity"` // 0-1
+}
+
+// ZPreset is a saved Z-filter band for part of a zone ("Basement").
+type ZPreset struct {
+	Name  string  `json:"name"`
+	Z     float64 `json:"z"`     // Center
+	Range float64 `json:"range"` // Lines within +/- this of Z are shown
+}
+
+// CameraBookmark is a saved view of a zone ("AE camp", "Overview").
+type CameraBookmark struct {
+	Name string  `json:"name"`
+	X    float64 `json:"x"` // Camera center, map coordinates
+	Y    float64 `json:"y"`
+	Zoom float64 `json:"zoom"`
+}
+
+// WindowGeometry is the window's size and place, in device-independent
+// pixels. The position is relative to the monitor's top-left corner.
+type WindowGeometry struct {
+	Monitor      string `json:"monitor,omitempty"` // Monitor name as the OS reports it
+	MonitorIndex int    `json:"monitor_index"`     // Tells apart monitors with the same name
+	X            int    `json:"x"`
+	Y            int    `json:"y"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	Maximized    bool   `json:"maximized"`
+}
+
+// ThemeColors are a theme's colors as "#rrggbb".
+type ThemeColors struct {
+	Background   string `json:"background,omitempty"` // Map background (unless map_background is set)
+	LineTint     string `json:"line_tint,omitempty"`  // Map lines are pulled toward it
+	Labels       string `json:"labels,omitempty"`     // Draws every map label in one color
+	MenuBar      string `json:"menu_bar,omitempty"`
+	MenuText     string `json:"menu_text,omitempty"`
+	MenuHover    string `json:"menu_hover,omitempty"` // Menu bar entry under the mouse or open
+	MenuPanel    string `json:"menu_panel,omitempty"` // Dropdowns and submenus
+	MenuBorder   string `json:"menu_border,omitempty"`
+	MenuSelected string `json:"menu_selected,omitempty"` // Dropdown item under the mouse
+}
+
+// TravelOptions are the travel planner's settings.
+type TravelOptions struct {
+	RunSpeed float64 `json:"run_speed,omitempty"` // Map units per second (0 = default)
+	UseBoats bool    `json:"use_boats"`
+	UsePorts bool    `json:"use_ports"`
+}
+
+// SyncOptions remembers the last marker sync addresses.
+type SyncOptions struct {
+	Listen        string `json:"listen,omitempty"`    // Host address, e.g. ":7777"
+	Code          string `json:"code,omitempty"`      // Join code when hosting
+	Host          string `json:"host,omitempty"`      // Address last joined
+	JoinCode      string `json:"join_code,omitempty"` // Code it was joined with
+	SharePosition bool   `json:"share_position"`
+
+	PeerTrails       bool              `json:"peer_trails"`                  // Draw fading trails behind shared positions
+	PeerTrailSeconds int               `json:"peer_trail_seconds,omitempty"` // How much of the trail is kept (0 = 60)
+	PeerColors       map[string]string `json:"peer_colors,omitempty"`        // Peer name -> color for their arrow and trail
+}
+
+// CloudSyncOptions says where Cloud Sync keeps its copy.
+type CloudSyncOptions struct {
+	Enabled  bool   `json:"enabled"`
+	Provider string `json:"provider,omitempty"` // "webdav" (default)
+	URL      string `json:"url,omitempty"`      // The sync file, or a folder to keep nox-maps-sync.json in
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"` // Stored as typed; an app password is best
+	Every    int    `json:"every,omitempty"`    // Minutes between syncs while enabled (default 10)
+
+	// AllowHTTP accepts an http:// URL, which sends the login and the
+	// markers unencrypted. Off, only https:// is used.
+	AllowHTTP bool `json:"allow_http,omitempty"`
+}
+
+// MapPackSource is where a map pack is published: a manifest (JSON with
+// the version, zip URL and checksum) or the zip itself.
+type MapPackSource struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256,omitempty"` // Expected checksum of a zip URL (manifests carry their own)
+}
+
+// InstalledMapPack records the map pack version copied into the maps
+// directory and the files it wrote.
+type InstalledMapPack struct {
+	Version   string    `json:"version"`
+	SHA256    string    `json:"sha256"`
+	Verified  bool      `json:"verified"` // The checksum matched a published one
+	Installed time.Time `json:"installed"`
+	Files     []string  `json:"files"`
+}
+
+// Task is one checklist entry for a zone, optionally tied to a marker.
+type Task struct {
+	Text       string `json:"text"`
+	Done       bool   `json:"done"`
+	MarkerID   string `json:"marker_id,omitempty"`
+	HideMarker bool   `json:"hide_marker,omitempty"` // Hide the linked marker once done
+}
+
+var markerIDCounter int64
+
+// NewMarkerID returns a unique marker ID.
+func NewMarkerID() string {
+	markerIDCounter++
+	return fmt.Sprintf("m%x%x", time.Now().UnixNano(), markerIDCounter)
+}
+
+// MarkerHidden reports whether a completed task hides the given marker.
+func (c *Config) MarkerHidden(zone, markerID string) bool {
+	if markerID == "" {
+		return false
+	}
+	for _, t := range c.ZoneTasks[zone] {
+		if t.MarkerID == markerID && t.Done && t.HideMarker {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultCategories are always offered, even before any marker uses them.
+var DefaultCategories = []string{"quest", "camp", "vendor", "danger"}
+
+// Categories returns the default categories, hidden ones and any others
+// markers use in the zones loaded so far, sorted.
+func (c *Config) Categories() []string {
+	seen := make(map[string]bool)
+	var cats []string
+	add := func(cat string) {
+		if cat != "" && !seen[cat] {
+			seen[cat] = true
+			cats = append(cats, cat)
+		}
+	}
+	for _, cat := range DefaultCategories {
+		add(cat)
+	}
+	for _, cat := range c.HiddenCategories {
+		add(cat)
+	}
+	for _, markers := range c.markers.loaded {
+		for _, m := range markers {
+			add(m.Category)
+		}
+	}
+	sort.Strings(cats)
+	return cats
+}
+
+func (c *Config) CategoryHidden(category string) bool {
+	for _, h := range c.HiddenCategories {
+		if h == category {
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleCategory shows a hidden category or hides a visible one.
+func (c *Config) ToggleCategory(category string) {
+	if c.CategoryHidden(category) {
+		kept := c.HiddenCategories[:0]
+		for _, h := range c.HiddenCategories {
+			if h != category {
+				kept = append(kept, h)
+			}
+		}
+		c.HiddenCategories = kept
+		return
+	}
+	c.HiddenCategories = append(c.HiddenCategories, category)
+}
+
+// MaxRecentColors caps the swatches remembered by the marker color picker.
+const MaxRecentColors = 8
+
+// legacyColors are the names the original five-color palette stored.
+var legacyColors = map[string]color.RGBA{
+	"red":    {255, 0, 0, 255},
+	"blue":   {0, 100, 255, 255},
+	"green":  {0, 255, 0, 255},
+	"yellow": {255, 255, 0, 255},
+	"purple": {200, 0, 255, 255},
+}
+
+// DefaultRecentColors seeds the swatches with the old palette so existing
+// users still find their familiar colors.
+var DefaultRecentColors = []string{"#ff0000", "#0064ff", "#00ff00", "#ffff00", "#c800ff"}
+
+// ParseColor reads a marker color stored as "#rrggbb" or a legacy color name.
+// Anything unrecognized falls back to red.
+func ParseColor(s string) color.RGBA {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if c, ok := legacyColors[s]; ok {
+		return c
+	}
+
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(strings.TrimPrefix(s, "#"), "%02x%02x%02x", &r, &g, &b); err == nil {
+		return color.RGBA{r, g, b, 255}
+	}
+	return legacyColors["red"]
+}
+
+// HexColor formats a color the way markers store it.
+func HexColor(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+// AddRecentColor moves hex to the front of RecentColors, dropping duplicates
+// and anything past MaxRecentColors.
+func (c *Config) AddRecentColor(hex string) {
+	recent := []string{hex}
+	for _, existing := range c.RecentColors {
+		if existing != hex && len(recent) < MaxRecentColors {
+			recent = append(recent, existing)
+		}
+	}
+	c.RecentColors = recent
 }
 
 func GetConfigPath() string {
@@ -31,32 +441,116 @@ func Load() *Config {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return &Config{
-			EQPath:  "",
-			Markers: make(map[string][]Marker),
+			EQPath:       "",
+			RecentColors: append([]string(nil), DefaultRecentColors...),
+			ZoneNotes:    make(map[string]string),
+			ZoneTasks:    make(map[string][]Task),
+			KeyBindings:  make(map[string]string),
+			HiddenLayers: make(map[string][]int),
+			CampClaims:   make(map[string][]CampClaim),
+			CampLists:    make(map[string][]CampList),
 		}
 	}
 
 	var cfg Config
 	if err := json.Unmarshal(data, &cfg); err != nil {
-		return &Config{
-			EQPath:  "",
-			Markers: make(map[string][]Marker),
+		// The next save replaces it, so keep a copy to look at
+		fmt.Printf("⚠️  config.json is unreadable, copied to config.json.bad: %v\n", err)
+		os.WriteFile(configPath+".bad", data, 0644)
+		if !loadNewestBackup(&cfg) {
+			return &Config{
+				EQPath:       "",
+				RecentColors: append([]string(nil), DefaultRecentColors...),
+				ZoneNotes:    make(map[string]string),
+				ZoneTasks:    make(map[string][]Task),
+				KeyBindings:  make(map[string]string),
+				HiddenLayers: make(map[string][]int),
+				CampClaims:   make(map[string][]CampClaim),
+				CampLists:    make(map[string][]CampList),
+			}
 		}
 	}
 
-	// Initialize markers map if nil
-	if cfg.Markers == nil {
-		cfg.Markers = make(map[string][]Marker)
+	if cfg.ZoneNotes == nil {
+		cfg.ZoneNotes = make(map[string]string)
+	}
+	if cfg.ZoneTasks == nil {
+		cfg.ZoneTasks = make(map[string][]Task)
+	}
+	if cfg.KeyBindings == nil {
+		cfg.KeyBindings = make(map[string]string)
+	}
+	if cfg.HiddenLayers == nil {
+		cfg.HiddenLayers = make(map[string][]int)
+	}
+	if cfg.CampClaims == nil {
+		cfg.CampClaims = make(map[string][]CampClaim)
+	}
+	if cfg.CampLists == nil {
+		cfg.CampLists = make(map[string][]CampList)
+	}
+
+	if len(cfg.RecentColors) == 0 {
+		cfg.RecentColors = append([]string(nil), DefaultRecentColors...)
+	}
+	if len(cfg.BackupMarkers) > 0 {
+		cfg.migrateMarkers()
+		if err := cfg.Save(); err != nil {
+			fmt.Printf("❌ Error moving markers to %s: %v\n", GetMarkerDir(), err)
+		}
 	}
 
 	return &cfg
 }
 
+// Save writes the config, or with a manager running (see manager.go) marks
+// it for a background write; errors are then reported by the writer.
 func (c *Config) Save() error {
-	configPath := GetConfigPath()
-	data, err := json.MarshalIndent(c, "", "  ")
+	if c.manager != nil {
+		c.manager.markDirty()
+		return nil
+	}
+	s, err := c.snapshot()
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(configPath, data, 0644)
+	if err := s.write(); err != nil {
+		c.unsave(s)
+		return err
+	}
+	return nil
+}
+
+// GetZoneIndexPath is where the precomputed all-zones index is cached.
+func GetZoneIndexPath() string {
+	return filepath.Join(filepath.Dir(GetConfigPath()), "zone_index.json")
+}
+
+// GetTravelLinksPath holds user-added boat/port links for the travel planner.
+func GetTravelLinksPath() string {
+	return filepath.Join(filepath.Dir(GetConfigPath()), "travel_links.json")
+}
+
+// GetZoneInfoPath holds user overrides for the bundled zone metadata.
+func GetZoneInfoPath() string {
+	return filepath.Join(filepath.Dir(GetConfigPath()), "zone_info.json")
+}
+
+// GetMapDir holds the user's maps: edits, map packs and hand-copied files,
+// each replacing the built-in map file with the same name.
+func GetMapDir() string {
+	dir := filepath.Join(filepath.Dir(GetConfigPath()), "maps")
+	os.MkdirAll(dir, 0755)
+	return dir
+}
+
+// GetMapBackupDir is where map files are copied before the app changes them
+// (see maps.BackupFiles).
+func GetMapBackupDir() string {
+	return filepath.Join(filepath.Dir(GetConfigPath()), "map_backups")
+}
+
+// GetThumbnailDir caches the zone thumbnails shown when picking a zone.
+func GetThumbnailDir() string {
+	return filepath.Join(filepath.Dir(GetConfigPath()), "thumbnails")
 }
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// Heatmaps record how long the player spent in each grid cell of a zone,
+// kept per zone in <config dir>/heatmaps.
+
+type HeatCell struct {
+	X, Y int // Cell index: floor(map coordinate / cell size)
+}
+
+type Heatmap struct {
+	CellSize float64
+	Seconds  map[HeatCell]float64
+}
+
+// heatFile is the on-disk form; JSON maps can't have struct keys.
+type heatFile struct {
+	CellSize float64      `json:"cell_size"`
+	Cells    [][3]float64 `json:"cells"` // x, y, seconds
+}
+
+func NewHeatmap(cellSize float64) *Heatmap {
+	return &Heatmap{CellSize: cellSize, Seconds: make(map[HeatCell]float64)}
+}
+
+// Add records time spent at a map position.
+func (h *Heatmap) Add(x, y, seconds float64) {
+	cell := HeatCell{int(math.Floor(x / h.CellSize)), int(math.Floor(y / h.CellSize))}
+	h.Seconds[cell] += seconds
+}
+
+// Max is the longest time spent in any one cell.
+func (h *Heatmap) Max() float64 {
+	max := 0.0
+	for _, s := range h.Seconds {
+		if s > max {
+			max = s
+		}
+	}
+	return max
+}
+
+func heatmapPath(zone string) string {
+	return filepath.Join(filepath.Dir(GetConfigPath()), "heatmaps", zoneFileName(zone)+".json")
+}
+
+// LoadHeatmap returns a zone's saved heatmap, or an empty one with the given
+// cell size. A saved map keeps the cell size it was recorded with.
+func LoadHeatmap(zone string, cellSize float64) *Heatmap {
+	return loadHeatFile(heatmapPath(zone), cellSize)
+}
+
+// SaveHeatmap writes a zone's heatmap, removing the file when it's empty.
+func SaveHeatmap(zone string, h *Heatmap) error {
+	if zone == "" {
+		return nil
+	}
+	return saveHeatFile(heatmapPath(zone), h)
+}
+
+func loadHeatFile(path string, cellSize float64) *Heatmap {
+	h := NewHeatmap(cellSize)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return h
+	}
+	var f heatFile
+	if err := json.Unmarshal(data, &f); err != nil || f.CellSize <= 0 {
+		return h
+	}
+	h.CellSize = f.CellSize
+	for _, c := range f.Cells {
+		h.Seconds[HeatCell{int(c[0]), int(c[1])}] = c[2]
+	}
+	return h
+}
+
+func saveHeatFile(path string, h *Heatmap) error {
+	if len(h.Seconds) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	f := heatFile{CellSize: h.CellSize}
+	for cell, s := range h.Seconds {
+		f.Cells = append(f.Cells, [3]float64{float64(cell.X), float64(cell.Y), math.Round(s)})
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
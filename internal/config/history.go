@@ -0,0 +1,88 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// History is what cmd/analyze aggregates from old logs, kept in
+// <config dir>/history: zones.json with the totals below and one heatmap per
+// zone in the same format as the live ones. The GUI only reads it.
+
+type ZoneHistory struct {
+	Seconds   float64        `json:"seconds"` // Time between /locs in the zone
+	Kills     int            `json:"kills"`
+	Deaths    int            `json:"deaths"`
+	DeathLocs [][2]float64   `json:"death_locs,omitempty"` // Map x, y of each death
+	KilledBy  map[string]int `json:"kills_by_name,omitempty"`
+}
+
+type History struct {
+	Generated time.Time               `json:"generated"`
+	Logs      int                     `json:"logs"` // Log files read
+	From      time.Time               `json:"from"` // Oldest line read
+	To        time.Time               `json:"to"`   // Newest line read
+	Zones     map[string]*ZoneHistory `json:"zones"`
+}
+
+func NewHistory() *History {
+	return &History{Zones: make(map[string]*ZoneHistory)}
+}
+
+// Zone returns a zone's totals, adding an empty entry on first use.
+func (h *History) Zone(zone string) *ZoneHistory {
+	z, ok := h.Zones[zone]
+	if !ok {
+		z = &ZoneHistory{KilledBy: make(map[string]int)}
+		h.Zones[zone] = z
+	}
+	return z
+}
+
+func GetHistoryDir() string {
+	return filepath.Join(filepath.Dir(GetConfigPath()), "history")
+}
+
+func historyHeatmapPath(dir, zone string) string {
+	return filepath.Join(dir, "heatmaps", zoneFileName(zone)+".json")
+}
+
+// LoadHistory reads dir/zones.json (dir is usually GetHistoryDir).
+func LoadHistory(dir string) (*History, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "zones.json"))
+	if err != nil {
+		return nil, err
+	}
+	h := NewHistory()
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, err
+	}
+	if h.Zones == nil {
+		h.Zones = make(map[string]*ZoneHistory)
+	}
+	return h, nil
+}
+
+// Save writes dir/zones.json.
+func (h *History) Save(dir string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "zones.json"), data, 0644)
+}
+
+// LoadHistoryHeatmap returns a zone's aggregated heatmap from dir (empty if
+// there is none).
+func LoadHistoryHeatmap(dir, zone string, cellSize float64) *Heatmap {
+	return loadHeatFile(historyHeatmapPath(dir, zone), cellSize)
+}
+
+func SaveHistoryHeatmap(dir, zone string, h *Heatmap) error {
+	return saveHeatFile(historyHeatmapPath(dir, zone), h)
+}
@@ -0,0 +1,262 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Saving in the background: once Manage is called, Save only marks the
+// config dirty. The owning goroutine (the UI) calls Pump every frame, which
+// runs changes queued from other goroutines and, once the config has been
+// quiet for the debounce delay, snapshots it and hands the bytes to a writer
+// goroutine. The snapshot is taken on the owning goroutine, so the writer
+// never sees a half-made change and disk I/O never blocks a frame. It holds
+// config.json only if that changed, and only the marker files of zones that
+// changed (see zonemarkers.go).
+//
+// Taking a snapshot counts its contents as saved. If the writer fails, it
+// hands the snapshot back and the next Pump marks those files unsaved again,
+// so they're retried (after saveRetryDelay) instead of waiting for another
+// change to the same zone.
+
+const (
+	maxSaveDelay   = 5 * time.Second  // Bounds how long a steady stream of changes can hold off a write
+	saveRetryDelay = 30 * time.Second // Wait after a failed write before trying again
+)
+
+type manager struct {
+	delay      time.Duration
+	dirty      bool
+	firstDirty time.Time // When the pending changes started
+	lastDirty  time.Time
+	retryAt    time.Time // No write before this, after one failed
+
+	mu      sync.Mutex
+	updates []func(*Config) // Queued by Apply from other goroutines
+	failed  []snapshot      // Written without success, for Pump to mark unsaved
+
+	pending chan snapshot // Latest snapshot waiting for the writer (capacity 1)
+	done    chan struct{}
+}
+
+// Manage starts the background writer. delay is how long to wait after the
+// last change before writing.
+func (c *Config) Manage(delay time.Duration) {
+	if c.manager != nil {
+		return
+	}
+	m := &manager{
+		delay:   delay,
+		pending: make(chan snapshot, 1),
+		done:    make(chan struct{}),
+	}
+	c.manager = m
+	go func() {
+		defer close(m.done)
+		for s := range m.pending {
+			if err := s.write(); err != nil {
+				fmt.Printf("❌ Error saving config (will retry): %v\n", err)
+				m.mu.Lock()
+				m.failed = append(m.failed, s)
+				m.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// Apply changes the config from any goroutine. fn runs on the owning
+// goroutine during the next Pump and the config is saved afterwards.
+// Without a manager it runs (and saves) immediately.
+func (c *Config) Apply(fn func(*Config)) {
+	m := c.manager
+	if m == nil {
+		fn(c)
+		c.Save()
+		return
+	}
+	m.mu.Lock()
+	m.updates = append(m.updates, fn)
+	m.mu.Unlock()
+}
+
+// Pump runs queued changes and starts a write when one is due. Call it from
+// the goroutine that owns the config.
+func (c *Config) Pump() {
+	m := c.manager
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	updates, failed := m.updates, m.failed
+	m.updates, m.failed = nil, nil
+	m.mu.Unlock()
+	for _, fn := range updates {
+		fn(c)
+	}
+	if len(updates) > 0 {
+		c.Save()
+	}
+	for _, s := range failed {
+		c.unsave(s)
+		m.markDirty()
+		m.retryAt = time.Now().Add(saveRetryDelay)
+	}
+
+	if !m.dirty {
+		return
+	}
+	now := time.Now()
+	if now.Before(m.retryAt) || (now.Sub(m.lastDirty) < m.delay && now.Sub(m.firstDirty) < maxSaveDelay) {
+		return
+	}
+	c.queueWrite()
+}
+
+// Flush writes any pending changes and waits for the writer to finish. The
+// config is left unmanaged (later saves write synchronously).
+func (c *Config) Flush() {
+	m := c.manager
+	if m == nil {
+		return
+	}
+	c.Pump()
+	if m.dirty {
+		c.queueWrite()
+	}
+	close(m.pending)
+	<-m.done
+	c.manager = nil
+	if len(m.failed) > 0 { // One last try; the app is closing
+		for _, s := range m.failed {
+			c.unsave(s)
+		}
+		if err := c.Save(); err != nil {
+			fmt.Printf("❌ Error saving config: %v\n", err)
+		}
+	}
+}
+
+// queueWrite snapshots the config for the writer, folding in an older
+// snapshot it hasn't picked up yet.
+func (c *Config) queueWrite() {
+	m := c.manager
+	s, err := c.snapshot()
+	if err != nil {
+		fmt.Printf("❌ Error saving config: %v\n", err)
+		return
+	}
+	m.dirty = false
+	if s.empty() {
+		return
+	}
+	for {
+		select {
+		case m.pending <- s:
+			return
+		default:
+		}
+		select {
+		case old := <-m.pending: // Superseded, but its marker files may not be in s
+			s = old.then(s)
+		default:
+		}
+	}
+}
+
+func (m *manager) markDirty() {
+	now := time.Now()
+	if !m.dirty {
+		m.dirty = true
+		m.firstDirty = now
+	}
+	m.lastDirty = now
+}
+
+// snapshot is what a save writes: config.json unless it's unchanged
+// (nil), and the marker files to write or remove.
+type snapshot struct {
+	config  []byte
+	markers map[string][]byte // File -> contents; nil removes it
+}
+
+func (c *Config) snapshot() (snapshot, error) {
+	files, err := c.changedMarkerFiles()
+	if err != nil {
+		return snapshot{}, err
+	}
+	s := snapshot{markers: files}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return snapshot{}, err
+	}
+	if !bytes.Equal(data, c.written) {
+		s.config = data
+		c.written = data
+	}
+	return s, nil
+}
+
+// unsave marks what s held as not written, so the next snapshot has it again.
+func (c *Config) unsave(s snapshot) {
+	if s.config != nil {
+		c.written = nil
+	}
+	for file := range s.markers {
+		c.markers.unsave(file)
+	}
+}
+
+func (s snapshot) empty() bool {
+	return s.config == nil && len(s.markers) == 0
+}
+
+// then combines s with the later snapshot next.
+func (s snapshot) then(next snapshot) snapshot {
+	if next.config == nil {
+		next.config = s.config
+	}
+	for file, data := range s.markers {
+		if _, ok := next.markers[file]; !ok {
+			if next.markers == nil {
+				next.markers = make(map[string][]byte)
+			}
+			next.markers[file] = data
+		}
+	}
+	return next
+}
+
+// write puts a snapshot on disk: backups are rotated first (see
+// backups.go), then the marker files, then config.json.
+func (s snapshot) write() error {
+	if err := rotateConfigBackups(); err != nil {
+		fmt.Printf("⚠️  Config backup failed: %v\n", err)
+	}
+	if err := writeMarkerFiles(s.markers); err != nil {
+		return err
+	}
+	if s.config == nil {
+		return nil
+	}
+	if err := writeFileAtomic(GetConfigPath(), s.config); err != nil {
+		return err
+	}
+	sum := sha256.Sum256(s.config)
+	writtenSum.Store(&sum)
+	return nil
+}
+
+// writtenSum is the checksum of the config.json last written, so WatchFile
+// can tell the app's own saves from hand edits.
+var writtenSum atomic.Pointer[[sha256.Size]byte]
+
+// wroteConfig reports whether data is the config.json the app last wrote.
+func wroteConfig(data []byte) bool {
+	sum := writtenSum.Load()
+	return sum != nil && *sum == sha256.Sum256(data)
+}
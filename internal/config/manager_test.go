@@ -0,0 +1,118 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// blockMarkerDir puts a file where the marker directory goes, so marker
+// writes fail until the returned func removes it.
+func blockMarkerDir(t *testing.T) func() {
+	t.Helper()
+	if err := os.WriteFile(GetMarkerDir(), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		if err := os.Remove(GetMarkerDir()); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readMarkers(t *testing.T, zone string) []Marker {
+	t.Helper()
+	return Load().ZoneMarkers(zone)
+}
+
+func TestSaveRetriesFailedMarkerFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	c := Load()
+	unblock := blockMarkerDir(t)
+	c.SetZoneMarkers("Befallen", []Marker{{ID: "a", Label: "Entrance"}})
+	if err := c.Save(); err == nil {
+		t.Fatal("Save succeeded with the marker directory blocked")
+	}
+
+	unblock()
+	if err := c.Save(); err != nil { // Nothing changed since, but the file is still unwritten
+		t.Fatal(err)
+	}
+	if got := readMarkers(t, "Befallen"); len(got) != 1 || got[0].Label != "Entrance" {
+		t.Errorf("markers on disk = %+v, want the Entrance marker", got)
+	}
+}
+
+func TestManagedSaveRetriesFailedWrite(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	c := Load()
+	c.Manage(0)
+	unblock := blockMarkerDir(t)
+	c.SetZoneMarkers("Befallen", []Marker{{ID: "a", Label: "Entrance"}})
+	c.ZoneNotes["Befallen"] = "Bring levitate"
+	c.Save()
+	c.Pump()
+
+	m := c.manager
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		m.mu.Lock()
+		failed := len(m.failed)
+		m.mu.Unlock()
+		if failed > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the write didn't fail")
+		}
+	}
+
+	unblock()
+	c.Pump() // Marks the failed write unsaved; the retry waits saveRetryDelay
+	if !m.dirty {
+		t.Fatal("config isn't dirty after a failed write")
+	}
+	m.retryAt = time.Time{}
+	c.Pump()
+	c.Flush()
+
+	if got := readMarkers(t, "Befallen"); len(got) != 1 || got[0].Label != "Entrance" {
+		t.Errorf("markers on disk = %+v, want the Entrance marker", got)
+	}
+	if got := Load().ZoneNotes["Befallen"]; got != "Bring levitate" {
+		t.Errorf("notes on disk = %q, want them saved", got)
+	}
+}
+
+func TestWatchFileSkipsOwnSaves(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	c := Load()
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	changes := make(chan *Config, 10)
+	WatchFile(10*time.Millisecond, func(c *Config) { changes <- c })
+
+	time.Sleep(20 * time.Millisecond)
+	c.Rules = []Rule{{Pattern: "from the app"}}
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-changes:
+		t.Fatalf("watcher reported the app's own save: %+v", got.Rules)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	edited := `{"rules":[{"pattern":"by hand"}]}`
+	if err := os.WriteFile(GetConfigPath(), []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-changes:
+		if len(got.Rules) != 1 || got.Rules[0].Pattern != "by hand" {
+			t.Errorf("watcher read rules %+v, want the hand edit", got.Rules)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watcher missed the hand edit")
+	}
+}
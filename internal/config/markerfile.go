@@ -0,0 +1,97 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Marker files: a standalone JSON export of markers for one or more zones,
+// for handing guild camp/named locations around without sharing config.json.
+
+const markerFileVersion = 1
+
+// markerDedupRadius is how close an imported point may be to an existing
+// marker with the same label before it's treated as a duplicate.
+const markerDedupRadius = 10.0
+
+type MarkerFile struct {
+	Version  int                 `json:"version"`
+	Exported time.Time           `json:"exported"`
+	Markers  map[string][]Marker `json:"markers"` // zone name -> markers (map coordinates)
+}
+
+// WriteMarkerFile saves the given zones' markers to path.
+func WriteMarkerFile(path string, markers map[string][]Marker) error {
+	data, err := marshalMarkerFile(markers)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func marshalMarkerFile(markers map[string][]Marker) ([]byte, error) {
+	return json.MarshalIndent(MarkerFile{
+		Version:  markerFileVersion,
+		Exported: time.Now(),
+		Markers:  markers,
+	}, "", "  ")
+}
+
+// ReadMarkerFile loads a file written by WriteMarkerFile.
+func ReadMarkerFile(path string) (*MarkerFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f MarkerFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	if f.Version > markerFileVersion {
+		return nil, fmt.Errorf("marker file version %d is newer than this build supports", f.Version)
+	}
+	if len(f.Markers) == 0 {
+		return nil, fmt.Errorf("no markers in file")
+	}
+	return &f, nil
+}
+
+// ImportMarkerFile merges f into the zones it covers, skipping markers a
+// zone already has, or with replace discards those zones' markers first.
+func (c *Config) ImportMarkerFile(f *MarkerFile, replace bool) (added, duplicates int) {
+	for zone, markers := range f.Markers {
+		var kept []Marker
+		if !replace {
+			kept = c.ZoneMarkers(zone)
+		}
+		for _, m := range markers {
+			if IsDuplicateMarker(m.Label, m.X, m.Y, kept) {
+				duplicates++
+				continue
+			}
+			if m.ID == "" || slices.ContainsFunc(kept, func(k Marker) bool { return k.ID == m.ID }) {
+				m.ID = NewMarkerID()
+			}
+			kept = append(kept, m)
+			added++
+		}
+		c.SetZoneMarkers(zone, kept)
+	}
+	return added, duplicates
+}
+
+// IsDuplicateMarker reports whether a marker with the same label (ignoring
+// case) already sits near the given position.
+func IsDuplicateMarker(label string, x, y float64, markers []Marker) bool {
+	for _, m := range markers {
+		if strings.EqualFold(m.Label, label) && math.Hypot(m.X-x, m.Y-y) <= markerDedupRadius {
+			return true
+		}
+	}
+	return false
+}
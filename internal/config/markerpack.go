@@ -0,0 +1,133 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Marker packs are versioned marker files a guild officer publishes for
+// everyone to install. Each pack is signed with the publisher's ed25519 key
+// (kept in <config dir>/publisher.key) so members can tell an update from
+// the same officer apart from a stranger's file with the same name.
+
+const markerPackFormat = 1
+
+type MarkerPack struct {
+	Format    int                 `json:"format"`
+	Name      string              `json:"name"`
+	Version   int                 `json:"version"`
+	Publisher string              `json:"publisher"`
+	Published time.Time           `json:"published"`
+	Markers   map[string][]Marker `json:"markers"` // zone name -> markers (map coordinates)
+
+	Key       string `json:"key"`       // Publisher's public key, base64
+	Signature string `json:"signature"` // Over the pack with this field empty, base64
+}
+
+// InstalledPack remembers which version of a pack is installed and who
+// signed it.
+type InstalledPack struct {
+	Version   int    `json:"version"`
+	Publisher string `json:"publisher"`
+	Key       string `json:"key"`
+}
+
+var ErrBadSignature = errors.New("marker pack signature doesn't match its contents")
+
+func publisherKeyPath() string {
+	return filepath.Join(filepath.Dir(GetConfigPath()), "publisher.key")
+}
+
+// PublisherKey loads the signing key, creating one on first use.
+func PublisherKey() (ed25519.PrivateKey, error) {
+	path := publisherKeyPath()
+	if data, err := os.ReadFile(path); err == nil {
+		seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("%s is not a valid key", path)
+		}
+		return ed25519.NewKeyFromSeed(seed), nil
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key.Seed())+"\n"), 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// KeyFingerprint is a short, readable form of a base64 public key for
+// comparing by eye ("3f2a 91c0 ...").
+func KeyFingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	h := hex.EncodeToString(sum[:8])
+	return h[0:4] + " " + h[4:8] + " " + h[8:12] + " " + h[12:16]
+}
+
+// signedBytes is what the signature covers.
+func (p MarkerPack) signedBytes() ([]byte, error) {
+	p.Signature = ""
+	return json.Marshal(p)
+}
+
+// WriteMarkerPack signs the pack and saves it to path.
+func WriteMarkerPack(path string, p MarkerPack, key ed25519.PrivateKey) error {
+	p.Format = markerPackFormat
+	p.Key = base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+	signed, err := p.signedBytes()
+	if err != nil {
+		return err
+	}
+	p.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, signed))
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ReadMarkerPack loads a pack and checks its signature.
+func ReadMarkerPack(path string) (*MarkerPack, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p MarkerPack
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	if p.Format > markerPackFormat {
+		return nil, fmt.Errorf("marker pack format %d is newer than this build supports", p.Format)
+	}
+	if p.Name == "" || len(p.Markers) == 0 {
+		return nil, fmt.Errorf("not a marker pack, or it has no markers")
+	}
+
+	pub, err := base64.StdEncoding.DecodeString(p.Key)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return nil, ErrBadSignature
+	}
+	sig, err := base64.StdEncoding.DecodeString(p.Signature)
+	if err != nil {
+		return nil, ErrBadSignature
+	}
+	signed, err := p.signedBytes()
+	if err != nil || !ed25519.Verify(pub, signed, sig) {
+		return nil, ErrBadSignature
+	}
+	return &p, nil
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ServerProfile holds per-server tweaks. Emulators sometimes word log
+// messages differently; the parser patterns let users fix that themselves.
+type ServerProfile struct {
+	Name   string          `json:"name,omitempty"`
+	Parser ParserOverrides `json:"parser"`
+}
+
+// ParserOverrides replaces the parser's built-in regular expressions. Empty
+// fields keep the default. Location must capture Y, X, Z (in /loc order),
+// optionally followed by a heading; ZoneEntry must capture the zone name,
+// SenseHeading the direction word, OOC, Group and Guild the speaker and
+// message, and Consider the mob name and the level phrase after "--". Repop
+// matches a server-wide repop announcement (an earthquake on P99). ZoneEntry
+// may be an alternation with a group per message; the first group that
+// matched is the zone. Loading matches the client's zoning message. Who must
+// capture a /who row's level, class, name, race and guild, and Tracking what
+// is tracked.
+type ParserOverrides struct {
+	Location     string `json:"location,omitempty"`
+	ZoneEntry    string `json:"zone_entry,omitempty"`
+	Death        string `json:"death,omitempty"`
+	Recovery     string `json:"recovery,omitempty"`
+	SenseHeading string `json:"sense_heading,omitempty"`
+	OOC          string `json:"ooc,omitempty"`
+	Group        string `json:"group,omitempty"`
+	Guild        string `json:"guild,omitempty"`
+	Consider     string `json:"consider,omitempty"`
+	Repop        string `json:"repop,omitempty"`
+	Kill         string `json:"kill,omitempty"`
+	Experience   string `json:"experience,omitempty"`
+	Loading      string `json:"loading,omitempty"`
+	Who          string `json:"who,omitempty"`
+	Tracking     string `json:"tracking,omitempty"`
+
+	// Units in a full turn for a heading reported with /loc (counter-
+	// clockwise from north, as the client stores it). Default 512.
+	HeadingUnits float64 `json:"heading_units,omitempty"`
+
+	// How the client writes numbers: "dot" (1,234.56), "comma" (1.234,56) or
+	// empty to guess per number. See eqlogparse.ParseNumber.
+	Numbers string `json:"numbers,omitempty"`
+}
+
+// Rule is a user-defined log rule: when Pattern matches a line, Action runs
+// with Text ($1-$9 are the pattern's groups, $0 the whole match). See
+// internal/rules.
+type Rule struct {
+	Name     string `json:"name,omitempty"`
+	Pattern  string `json:"pattern"`
+	Action   string `json:"action,omitempty"` // "log" (default), "marker" or "say"
+	Text     string `json:"text,omitempty"`   // Message or marker label; the matched text if empty
+	Color    string `json:"color,omitempty"`  // Marker color, hex "#rrggbb"
+	Sound    string `json:"sound,omitempty"`  // Alert sound; the "rule" alert's if empty, "none" for silence
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// WatchFile polls config.json and calls onChange with a freshly read copy
+// whenever it's modified by hand; the app's own saves are skipped. The copy
+// is only parsed, unlike Load: nothing is migrated, restored or saved from
+// the watcher.
+func WatchFile(interval time.Duration, onChange func(*Config)) {
+	path := GetConfigPath()
+	stamp := func() time.Time {
+		if fi, err := os.Stat(path); err == nil {
+			return fi.ModTime()
+		}
+		return time.Time{}
+	}
+
+	go func() {
+		last := stamp()
+		for range time.Tick(interval) {
+			current := stamp()
+			if current.Equal(last) {
+				continue
+			}
+			last = current
+			data, err := os.ReadFile(path)
+			if err != nil || wroteConfig(data) {
+				continue
+			}
+			var cfg Config
+			if err := json.Unmarshal(data, &cfg); err != nil {
+				fmt.Printf("⚠️  Ignoring the edited config.json: %v\n", err)
+				continue
+			}
+			onChange(&cfg)
+		}
+	}()
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Session files: the live state of a play session (where the character is,
+// outstanding corpses, camp timers, the waypoint, posted locs and trails),
+// for picking the overlay up on another machine mid-session.
+
+const sessionFileVersion = 1
+
+type SessionFile struct {
+	Version  int       `json:"version"`
+	Exported time.Time `json:"exported"`
+
+	Character string  `json:"character,omitempty"`
+	Zone      string  `json:"zone,omitempty"`
+	X         float64 `json:"x"` // Map coordinates
+	Y         float64 `json:"y"`
+	Z         float64 `json:"z"`
+	Heading   float64 `json:"heading"`
+
+	Waypoint *SessionPoint  `json:"waypoint,omitempty"` // In Zone
+	ChatLocs []SessionPoint `json:"chat_locs,omitempty"`
+	Corpses  []SessionPoint `json:"corpses,omitempty"`
+
+	CampClaims  map[string][]CampClaim       `json:"camp_claims,omitempty"`
+	CampLists   map[string][]CampList        `json:"camp_lists,omitempty"`
+	Breadcrumbs map[string][]BreadcrumbPoint `json:"breadcrumbs,omitempty"` // BreadcrumbKey -> trail
+}
+
+// SessionPoint is a timestamped position: a corpse (Label = character), a
+// posted loc (Label = speaker) or the waypoint.
+type SessionPoint struct {
+	Label string    `json:"label,omitempty"`
+	Zone  string    `json:"zone,omitempty"`
+	X     float64   `json:"x"`
+	Y     float64   `json:"y"`
+	Time  time.Time `json:"time"`
+}
+
+// WriteSessionFile saves a session bundle to path.
+func WriteSessionFile(path string, s *SessionFile) error {
+	s.Version = sessionFileVersion
+	s.Exported = time.Now()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ReadSessionFile loads a file written by WriteSessionFile.
+func ReadSessionFile(path string) (*SessionFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s SessionFile
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if s.Version == 0 {
+		return nil, fmt.Errorf("not a session file")
+	}
+	if s.Version > sessionFileVersion {
+		return nil, fmt.Errorf("session file version %d is newer than this build supports", s.Version)
+	}
+	return &s, nil
+}
+
+// LoadAllBreadcrumbs returns every saved trail, keyed by BreadcrumbKey.
+func LoadAllBreadcrumbs() map[string][]BreadcrumbPoint {
+	trails := make(map[string][]BreadcrumbPoint)
+	loadBreadcrumbDir(GetBreadcrumbDir(), "", trails)
+	return trails
+}
+
+// loadBreadcrumbDir adds the trails in dir, and in its character
+// directories when dir is the top.
+func loadBreadcrumbDir(dir, prefix string, trails map[string][]BreadcrumbPoint) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			if prefix == "" {
+				loadBreadcrumbDir(filepath.Join(dir, e.Name()), e.Name()+"/", trails)
+			}
+			continue
+		}
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok {
+			continue
+		}
+		if points, err := readBreadcrumbs(filepath.Join(dir, e.Name())); err == nil && len(points) > 0 {
+			trails[prefix+name] = points
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ShapeOp is one drawing step of a custom marker shape. Coordinates are in a
+// unit box from -1 to 1 (y pointing down) and get scaled to the marker size.
+//
+//	{"op": "move",  "x": 0, "y": -1}
+//	{"op": "line",  "x": 1, "y": 1}
+//	{"op": "arc",   "x": 0, "y": 0, "r": 1, "start": 0, "end": 360}
+//	{"op": "close"}
+type ShapeOp struct {
+	Op    string  `json:"op"` // "move", "line", "arc", "close"
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	R     float64 `json:"r"`     // Arc radius
+	Start float64 `json:"start"` // Arc start angle in degrees
+	End   float64 `json:"end"`   // Arc end angle in degrees
+}
+
+func GetShapesPath() string {
+	return filepath.Join(filepath.Dir(GetConfigPath()), "shapes.json")
+}
+
+// LoadShapes reads user-defined marker shapes (name -> path ops). A missing
+// file just means no custom shapes; invalid shapes are skipped with a warning.
+func LoadShapes() map[string][]ShapeOp {
+	shapes := make(map[string][]ShapeOp)
+
+	data, err := os.ReadFile(GetShapesPath())
+	if err != nil {
+		return shapes
+	}
+
+	var raw map[string][]ShapeOp
+	if err := json.Unmarshal(data, &raw); err != nil {
+		fmt.Printf("⚠️  Could not parse %s: %v\n", GetShapesPath(), err)
+		return shapes
+	}
+
+	for name, ops := range raw {
+		if err := validateShape(ops); err != nil {
+			fmt.Printf("⚠️  Skipping shape '%s': %v\n", name, err)
+			continue
+		}
+		shapes[name] = ops
+	}
+	return shapes
+}
+
+func validateShape(ops []ShapeOp) error {
+	if len(ops) == 0 {
+		return fmt.Errorf("no path ops")
+	}
+	for i, op := range ops {
+		switch op.Op {
+		case "move", "line", "close":
+		case "arc":
+			if op.R <= 0 {
+				return fmt.Errorf("op %d: arc needs a positive radius", i)
+			}
+		default:
+			return fmt.Errorf("op %d: unknown op '%s'", i, op.Op)
+		}
+	}
+	return nil
+}
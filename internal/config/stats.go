@@ -0,0 +1,94 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Session stats are kept per character in <config dir>/stats/<name>.json.
+// A session carries on across restarts as long as the character was last
+// seen less than SessionGap ago.
+
+const SessionGap = 30 * time.Minute
+
+type StatCounts struct {
+	Kills      int `json:"kills"`
+	Deaths     int `json:"deaths"`
+	Experience int `json:"experience"` // Experience messages (the log doesn't say how much)
+}
+
+func (c *StatCounts) add(o StatCounts) {
+	c.Kills += o.Kills
+	c.Deaths += o.Deaths
+	c.Experience += o.Experience
+}
+
+type CharacterStats struct {
+	Character string     `json:"character"`
+	Session   StatCounts `json:"session"`
+	Started   time.Time  `json:"started"`
+	LastSeen  time.Time  `json:"last_seen"`
+	LastKill  string     `json:"last_kill,omitempty"`
+
+	// Earlier sessions
+	Totals        StatCounts `json:"totals"`
+	Sessions      int        `json:"sessions"`
+	PlayedSeconds float64    `json:"played_seconds"`
+}
+
+func statsPath(character string) string {
+	return filepath.Join(filepath.Dir(GetConfigPath()), "stats", zoneFileName(character)+".json")
+}
+
+// LoadCharacterStats returns the saved stats (empty if none).
+func LoadCharacterStats(character string) *CharacterStats {
+	s := &CharacterStats{Character: character}
+	data, err := os.ReadFile(statsPath(character))
+	if err != nil {
+		return s
+	}
+	json.Unmarshal(data, s)
+	s.Character = character
+	return s
+}
+
+func (s *CharacterStats) Save() error {
+	path := statsPath(s.Character)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Touch marks the character as playing now, first starting a new session
+// if the last one ended more than SessionGap ago.
+func (s *CharacterStats) Touch(now time.Time) {
+	if s.Started.IsZero() || now.Sub(s.LastSeen) >= SessionGap {
+		s.NewSession(now)
+	}
+	s.LastSeen = now
+}
+
+// NewSession folds the current session into the totals and starts over.
+func (s *CharacterStats) NewSession(now time.Time) {
+	if !s.Started.IsZero() {
+		s.Totals.add(s.Session)
+		s.Sessions++
+		s.PlayedSeconds += s.LastSeen.Sub(s.Started).Seconds()
+	}
+	s.Session = StatCounts{}
+	s.LastKill = ""
+	s.Started = now
+	s.LastSeen = now
+}
+
+// Duration is how long the current session has run.
+func (s *CharacterStats) Duration() time.Duration {
+	return s.LastSeen.Sub(s.Started)
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Spreadsheet import: guild spawn tables are usually kept in Google Sheets
+// and exported as CSV or TSV with one row per spawn (zone, loc Y, loc X,
+// name, color). Coordinates are /loc values and are converted to map space.
+
+// Column indexes into a table row; -1 means "not present".
+type ColumnMapping struct {
+	Zone, LocY, LocX, Name, Color int
+}
+
+// MappingFields names the mapping fields in display order.
+var MappingFields = []string{"Zone", "Loc Y", "Loc X", "Name", "Color"}
+
+// Field returns a pointer to the field with the given display name.
+func (m *ColumnMapping) Field(name string) *int {
+	switch name {
+	case "Zone":
+		return &m.Zone
+	case "Loc Y":
+		return &m.LocY
+	case "Loc X":
+		return &m.LocX
+	case "Name":
+		return &m.Name
+	case "Color":
+		return &m.Color
+	}
+	return nil
+}
+
+// Table is a parsed spreadsheet export. Header is nil when the first row
+// already holds data.
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// ReadTable loads a CSV or TSV file, picking the delimiter from the first line.
+func ReadTable(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimPrefix(string(data), "\ufeff") // Excel BOM
+
+	firstLine := text
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		firstLine = text[:i]
+	}
+
+	r := csv.NewReader(strings.NewReader(text))
+	if strings.Count(firstLine, "\t") > strings.Count(firstLine, ",") {
+		r.Comma = '\t'
+	}
+	r.FieldsPerRecord = -1 // Sheets exports drop trailing empty cells
+	r.LazyQuotes = true
+	r.TrimLeadingSpace = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s is empty", path)
+	}
+
+	t := &Table{Rows: records}
+	if !looksLikeData(records[0]) {
+		t.Header = records[0]
+		t.Rows = records[1:]
+	}
+	return t, nil
+}
+
+// looksLikeData reports whether a row has at least two numeric cells (the
+// loc columns); header rows have none.
+func looksLikeData(row []string) bool {
+	numbers := 0
+	for _, cell := range row {
+		if _, err := strconv.ParseFloat(strings.TrimSpace(cell), 64); err == nil {
+			numbers++
+		}
+	}
+	return numbers >= 2
+}
+
+// GuessColumns maps columns by header name, falling back to the usual
+// zone, loc Y, loc X, name, color order.
+func (t *Table) GuessColumns() ColumnMapping {
+	m := ColumnMapping{Zone: 0, LocY: 1, LocX: 2, Name: 3, Color: 4}
+	if t.Header == nil {
+		return m
+	}
+
+	guess := ColumnMapping{-1, -1, -1, -1, -1}
+	for i, h := range t.Header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		switch {
+		case guess.Zone < 0 && strings.Contains(h, "zone"):
+			guess.Zone = i
+		case guess.LocY < 0 && (h == "y" || strings.HasSuffix(h, " y") || strings.Contains(h, "loc y")):
+			guess.LocY = i
+		case guess.LocX < 0 && (h == "x" || strings.HasSuffix(h, " x") || strings.Contains(h, "loc x")):
+			guess.LocX = i
+		case guess.Name < 0 && (strings.Contains(h, "name") || strings.Contains(h, "label") || strings.Contains(h, "mob") || strings.Contains(h, "spawn")):
+			guess.Name = i
+		case guess.Color < 0 && (strings.Contains(h, "color") || strings.Contains(h, "colour")):
+			guess.Color = i
+		}
+	}
+
+	// Keep the positional default for anything the header didn't name,
+	// unless that column is already taken
+	used := make(map[int]bool)
+	for _, field := range MappingFields {
+		if i := *guess.Field(field); i >= 0 {
+			used[i] = true
+		}
+	}
+	for _, field := range MappingFields {
+		def := *m.Field(field)
+		if *guess.Field(field) < 0 && def < len(t.Header) && !used[def] {
+			*guess.Field(field) = def
+			used[def] = true
+		}
+	}
+	return guess
+}
+
+// Markers converts the table's rows to markers grouped by zone. Rows without
+// a zone or a valid loc are counted as skipped.
+func (t *Table) Markers(m ColumnMapping) (markers map[string][]Marker, skipped int) {
+	markers = make(map[string][]Marker)
+	cell := func(row []string, i int) string {
+		if i < 0 || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	for _, row := range t.Rows {
+		zone := cell(row, m.Zone)
+		locY, errY := strconv.ParseFloat(cell(row, m.LocY), 64)
+		locX, errX := strconv.ParseFloat(cell(row, m.LocX), 64)
+		if zone == "" || errY != nil || errX != nil {
+			skipped++
+			continue
+		}
+
+		markerColor := "#ff0000"
+		if c := cell(row, m.Color); c != "" {
+			markerColor = HexColor(ParseColor(c))
+		}
+
+		markers[zone] = append(markers[zone], Marker{
+			ID:    NewMarkerID(),
+			X:     -locX, // /loc is (Y, X) with both axes negated vs. map space
+			Y:     -locY,
+			Label: cell(row, m.Name),
+			Color: markerColor,
+			Shape: "circle",
+		})
+	}
+	return markers, skipped
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// webDAV keeps the synced document as one file on a WebDAV server
+// (Nextcloud, ownCloud, a NAS, rclone serve webdav, ...). Revisions are the
+// server's ETags, so a store fails with 412 if another machine got there
+// first. A server that sends no ETag gets the last write. Servers that
+// compress on the fly often send weak ETags (W/"..."), which never match in
+// If-Match, so they go back without the W/.
+
+const (
+	syncFileName   = "nox-maps-sync.json"
+	webDAVTimeout  = 20 * time.Second
+	webDAVNoETag   = "*" // Revision of a file the server gave no ETag for
+	webDAVMaxError = 512 // Bytes of an error response shown
+)
+
+type webDAV struct {
+	url      *url.URL
+	username string
+	password string
+	client   *http.Client
+}
+
+func newWebDAV(opts CloudSyncOptions) (*webDAV, error) {
+	u, err := url.Parse(strings.TrimSpace(opts.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%q isn't an http:// or https:// address", opts.URL)
+	}
+	if u.Scheme == "http" && !opts.AllowHTTP {
+		return nil, ErrSyncInsecure
+	}
+	if u.Path == "" || strings.HasSuffix(u.Path, "/") {
+		u = u.JoinPath(syncFileName)
+	}
+	return &webDAV{
+		url:      u,
+		username: opts.Username,
+		password: opts.Password,
+		client:   &http.Client{Timeout: webDAVTimeout},
+	}, nil
+}
+
+func (d *webDAV) String() string {
+	return d.url.Redacted()
+}
+
+func (d *webDAV) Fetch() ([]byte, string, error) {
+	resp, err := d.do(http.MethodGet, nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, "", ErrNoSyncData
+	default:
+		return nil, "", statusError(resp)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSyncSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > maxSyncSize {
+		return nil, "", fmt.Errorf("the synced copy is over %d MB", maxSyncSize>>20)
+	}
+	rev := resp.Header.Get("ETag")
+	if rev == "" {
+		rev = webDAVNoETag
+	}
+	return data, rev, nil
+}
+
+func (d *webDAV) Store(data []byte, rev string) (string, error) {
+	header := http.Header{"Content-Type": {"application/json"}}
+	switch rev {
+	case "":
+		header.Set("If-None-Match", "*")
+	case webDAVNoETag:
+	default:
+		header.Set("If-Match", strings.TrimPrefix(rev, "W/"))
+	}
+	resp, err := d.do(http.MethodPut, data, header)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return resp.Header.Get("ETag"), nil
+	case http.StatusPreconditionFailed:
+		return "", ErrSyncConflict
+	case http.StatusConflict:
+		return "", fmt.Errorf("the folder for %s doesn't exist on the server", d)
+	}
+	return "", statusError(resp)
+}
+
+func (d *webDAV) do(method string, body []byte, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequest(method, d.url.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if header != nil {
+		req.Header = header
+	}
+	if d.username != "" || d.password != "" {
+		req.SetBasicAuth(d.username, d.password)
+	}
+	return d.client.Do(req)
+}
+
+// statusError describes a response the request didn't expect.
+func statusError(resp *http.Response) error {
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, webDAVMaxError))
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("the server refused the login (%s)", resp.Status)
+	}
+	if text := strings.TrimSpace(string(msg)); text != "" && !strings.HasPrefix(text, "<") {
+		return fmt.Errorf("%s: %s", resp.Status, text)
+	}
+	return errors.New(resp.Status)
+}
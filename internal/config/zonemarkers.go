@@ -0,0 +1,247 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Markers live in one file per zone under markers/ next to config.json
+// (markers/east_commonlands.json), in the marker file format. A zone's file
+// is read the first time something asks for its markers, and a save only
+// rewrites the files of zones that changed since the last one. Two zone
+// names that clean up to the same file name share the file, so reading it
+// loads both.
+
+// zoneMarkers is the in-memory side of the marker files.
+type zoneMarkers struct {
+	loaded   map[string][]Marker // Zones read or set so far; nil or empty = no markers
+	saved    map[string][]Marker // Each loaded zone as last written
+	unsaved  map[string]bool     // Files whose last write failed
+	files    map[string]bool     // Files already read
+	allFiles bool                // Every file has been read
+}
+
+// GetMarkerDir holds the per-zone marker files.
+func GetMarkerDir() string {
+	return filepath.Join(filepath.Dir(GetConfigPath()), "markers")
+}
+
+func markerFilePath(file string) string {
+	return filepath.Join(GetMarkerDir(), file+".json")
+}
+
+// ZoneMarkers returns a zone's markers, reading its file the first time.
+// Markers may be changed in place; adding or removing them goes through
+// SetZoneMarkers.
+func (c *Config) ZoneMarkers(zone string) []Marker {
+	if zone == "" {
+		return nil
+	}
+	c.readMarkerFile(zoneFileName(zone))
+	return c.markers.loaded[zone]
+}
+
+// SetZoneMarkers replaces a zone's markers; empty removes its markers.
+func (c *Config) SetZoneMarkers(zone string, markers []Marker) {
+	if zone == "" {
+		return
+	}
+	c.readMarkerFile(zoneFileName(zone)) // Its file-mates must be loaded before the file is rewritten
+	c.markers.loaded[zone] = markers
+}
+
+// AllMarkers reads every zone's file and returns zone name -> markers for
+// the zones that have any. The map is a copy; changes go through
+// SetZoneMarkers.
+func (c *Config) AllMarkers() map[string][]Marker {
+	if !c.markers.allFiles {
+		entries, err := os.ReadDir(GetMarkerDir())
+		if err != nil && !os.IsNotExist(err) {
+			fmt.Printf("⚠️  Error listing marker files: %v\n", err)
+		}
+		for _, e := range entries {
+			if name, ok := strings.CutSuffix(e.Name(), ".json"); ok && !e.IsDir() {
+				c.readMarkerFile(name)
+			}
+		}
+		c.markers.allFiles = err == nil
+	}
+	all := make(map[string][]Marker)
+	for zone, markers := range c.markers.loaded {
+		if len(markers) > 0 {
+			all[zone] = markers
+		}
+	}
+	return all
+}
+
+// MarkerZone returns the name markers are saved under for a zone name
+// that matches ignoring case, or "" if there are none.
+func (c *Config) MarkerZone(name string) string {
+	c.readMarkerFile(zoneFileName(name))
+	for zone, markers := range c.markers.loaded {
+		if len(markers) > 0 && strings.EqualFold(zone, name) {
+			return zone
+		}
+	}
+	return ""
+}
+
+// readMarkerFile loads one marker file's zones, once. A file that can't be
+// read is reported and treated as empty; it's only replaced if one of its
+// zones changes.
+func (c *Config) readMarkerFile(file string) {
+	m := &c.markers
+	if m.loaded == nil {
+		m.loaded = make(map[string][]Marker)
+		m.saved = make(map[string][]Marker)
+		m.files = make(map[string]bool)
+	}
+	if m.files[file] {
+		return
+	}
+	m.files[file] = true
+	f, err := ReadMarkerFile(markerFilePath(file))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Printf("⚠️  Error reading markers/%s.json: %v\n", file, err)
+		}
+		return
+	}
+	for zone, markers := range f.Markers {
+		fillMarkerIDs(markers)
+		m.loaded[zone] = markers
+		m.saved[zone] = slices.Clone(markers)
+	}
+}
+
+// fillMarkerIDs gives markers from older files an ID so they can be linked.
+func fillMarkerIDs(markers []Marker) {
+	for i := range markers {
+		if markers[i].ID == "" {
+			markers[i].ID = NewMarkerID()
+		}
+	}
+}
+
+// changedMarkerFiles returns the contents of every marker file whose zones
+// changed since the last call, or that failed to write (nil = remove the
+// file), and records them as saved.
+func (c *Config) changedMarkerFiles() (map[string][]byte, error) {
+	m := &c.markers
+	changed := maps.Clone(m.unsaved)
+	if changed == nil {
+		changed = make(map[string]bool)
+	}
+	for zone, markers := range m.loaded {
+		if !slices.Equal(markers, m.saved[zone]) {
+			changed[zoneFileName(zone)] = true
+		}
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	files := make(map[string][]byte, len(changed))
+	for file := range changed {
+		zones := make(map[string][]Marker)
+		for zone, markers := range m.loaded {
+			if zoneFileName(zone) == file && len(markers) > 0 {
+				zones[zone] = markers
+			}
+		}
+		if len(zones) == 0 {
+			files[file] = nil
+			continue
+		}
+		data, err := marshalMarkerFile(zones)
+		if err != nil {
+			return nil, err
+		}
+		files[file] = data
+	}
+	for zone, markers := range m.loaded {
+		if changed[zoneFileName(zone)] {
+			m.saved[zone] = slices.Clone(markers)
+		}
+	}
+	m.unsaved = nil
+	return files, nil
+}
+
+// unsave has the next changedMarkerFiles write file again.
+func (m *zoneMarkers) unsave(file string) {
+	if m.unsaved == nil {
+		m.unsaved = make(map[string]bool)
+	}
+	m.unsaved[file] = true
+}
+
+// writeMarkerFiles writes (or removes) the files from changedMarkerFiles.
+func writeMarkerFiles(files map[string][]byte) error {
+	if len(files) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(GetMarkerDir(), 0755); err != nil {
+		return err
+	}
+	for _, file := range slices.Sorted(maps.Keys(files)) {
+		path := markerFilePath(file)
+		var err error
+		if files[file] == nil {
+			if err = os.Remove(path); os.IsNotExist(err) {
+				err = nil
+			}
+		} else {
+			err = writeFileAtomic(path, files[file])
+		}
+		if err != nil {
+			return fmt.Errorf("markers/%s.json: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// readAllMarkerFiles reads the marker files on disk, for backups.
+func readAllMarkerFiles() (map[string][]Marker, error) {
+	entries, err := os.ReadDir(GetMarkerDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	all := make(map[string][]Marker)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		f, err := ReadMarkerFile(filepath.Join(GetMarkerDir(), e.Name()))
+		if err != nil {
+			continue // Reported when the zone is loaded
+		}
+		maps.Copy(all, f.Markers)
+	}
+	return all, nil
+}
+
+// migrateMarkers moves markers kept in config.json (before marker files)
+// into the zone files, keeping any a zone's file already has.
+func (c *Config) migrateMarkers() {
+	for zone, markers := range c.BackupMarkers {
+		fillMarkerIDs(markers)
+		existing := c.ZoneMarkers(zone)
+		for _, m := range markers {
+			if !slices.ContainsFunc(existing, func(e Marker) bool { return e.ID == m.ID }) {
+				existing = append(existing, m)
+			}
+		}
+		c.SetZoneMarkers(zone, existing)
+	}
+	fmt.Printf("📦 Moving markers for %d zones from config.json to %s\n", len(c.BackupMarkers), GetMarkerDir())
+	c.BackupMarkers = nil
+}
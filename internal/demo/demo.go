@@ -0,0 +1,146 @@
+// Package demo stands in for the EverQuest log so the map can be worked on
+// without the game: it either enters a zone and walks a character along a
+// path, writing the log lines the client would (-demo), or plays back an
+// old log (-replay).
+package demo
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/eqlog"
+	"github.com/devin-hart/nox-maps/internal/maps"
+)
+
+// Character is the name demo lines are logged under.
+const Character = "Demo"
+
+const (
+	defaultSpeed    = 60.0 // Map units per second, about a running character
+	defaultInterval = 500 * time.Millisecond
+)
+
+// Point is a position in map coordinates (as breadcrumbs store them).
+type Point = config.BreadcrumbPoint
+
+type Options struct {
+	Zone     string
+	Path     []Point       // Walked in order, then back to the start
+	Speed    float64       // Map units per second (0 = default)
+	Interval time.Duration // Between /locs (0 = default)
+}
+
+// Run enters the zone and walks the path forever, sending a /loc every
+// interval. It never returns; start it on its own goroutine.
+func Run(opts Options, lines chan<- eqlog.LogLine) {
+	speed, interval := opts.Speed, opts.Interval
+	if speed <= 0 {
+		speed = defaultSpeed
+	}
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	send := func(line string) {
+		lines <- eqlog.LogLine{Line: line, Time: time.Now(), Character: Character, Primary: true}
+	}
+
+	send(fmt.Sprintf("You have entered %s.", opts.Zone))
+	step := speed * interval.Seconds()
+	for {
+		for i := range opts.Path {
+			from, to := opts.Path[i], opts.Path[(i+1)%len(opts.Path)]
+			n := max(int(math.Ceil(math.Hypot(to.X-from.X, to.Y-from.Y)/step)), 1)
+			for k := 0; k < n; k++ {
+				t := float64(k) / float64(n)
+				x, y, z := from.X+(to.X-from.X)*t, from.Y+(to.Y-from.Y)*t, from.Z+(to.Z-from.Z)*t
+				// /loc prints Y, X, Z with both axes negated (see parser)
+				send(fmt.Sprintf("Your Location is %.2f, %.2f, %.2f", -y, -x, z))
+				time.Sleep(interval)
+			}
+		}
+	}
+}
+
+// FindPath picks the path to walk: the file if one is given, else the
+// character's saved breadcrumbs for the zone, else a loop around the
+// middle of its map.
+func FindPath(mapDir, zone, character, file string) ([]Point, string, error) {
+	if file != "" {
+		path, err := LoadPath(file)
+		return path, file, err
+	}
+	if trail := config.LoadBreadcrumbs(character, zone); len(trail) >= 2 {
+		return trail, "saved breadcrumbs", nil
+	}
+
+	code := maps.GetZoneFileName(zone)
+	if code == "" {
+		code = zone
+	}
+	zm, err := maps.LoadZoneQuiet(mapDir, code)
+	if err != nil {
+		return nil, "", err
+	}
+	return loopPath(zm), "a loop around the map", nil
+}
+
+// loopPath is an ellipse through the middle of the map, a third of its size.
+func loopPath(zm *maps.ZoneMap) []Point {
+	cx, cy := (zm.MinX+zm.MaxX)/2, (zm.MinY+zm.MaxY)/2
+	rx, ry := (zm.MaxX-zm.MinX)/6, (zm.MaxY-zm.MinY)/6
+	const points = 24
+	path := make([]Point, points)
+	for i := range path {
+		a := 2 * math.Pi * float64(i) / points
+		path[i] = Point{X: cx + rx*math.Cos(a), Y: cy + ry*math.Sin(a)}
+	}
+	return path
+}
+
+// locNumbers finds the coordinates on a path file line, after "Your
+// Location is" if the line was copied from a log.
+var locNumbers = regexp.MustCompile(`(?:Your Location is\s*)?(-?\d+(?:\.\d+)?),\s*(-?\d+(?:\.\d+)?)(?:,\s*(-?\d+(?:\.\d+)?))?\s*$`)
+
+// LoadPath reads a scripted path: one position per line in /loc order
+// ("y, x" or "y, x, z", as the game prints them; log lines work too).
+// Blank lines and lines starting with # are skipped.
+func LoadPath(file string) ([]Point, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var path []Point
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		m := locNumbers.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("%s:%d: expected \"y, x\" or \"y, x, z\"", file, n)
+		}
+		locY, _ := strconv.ParseFloat(m[1], 64)
+		locX, _ := strconv.ParseFloat(m[2], 64)
+		z := 0.0
+		if m[3] != "" {
+			z, _ = strconv.ParseFloat(m[3], 64)
+		}
+		path = append(path, Point{X: -locX, Y: -locY, Z: z})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(path) < 2 {
+		return nil, fmt.Errorf("%s: a path needs at least two positions", file)
+	}
+	return path, nil
+}
@@ -0,0 +1,67 @@
+package demo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/eqlog"
+)
+
+// maxReplayWait caps the real time spent on one gap between lines, so an
+// hour spent sitting (or logged off) doesn't stall the replay.
+const maxReplayWait = 3 * time.Second
+
+// ParseSpeed reads a replay speed: "1", "10x", "2.5x", or "instant" (0).
+func ParseSpeed(s string) (float64, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "instant" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "x"), 64)
+	if err != nil || v <= 0 {
+		return 0, fmt.Errorf("replay speed %q: want e.g. 1x, 10x or instant", s)
+	}
+	return v, nil
+}
+
+// Replay feeds an old log to the parser as if it were being written now,
+// keeping the gaps between lines' timestamps divided by speed (0 sends
+// everything at once). Lines are stamped with the time they're sent, like
+// live tailing does. Returns when the file ends.
+func Replay(path string, speed float64, lines chan<- eqlog.LogLine) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	character, _ := eqlog.CharacterFromPath(path)
+
+	var prev time.Time
+	count := 0
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadString('\n')
+		if line = strings.TrimSpace(line); line != "" {
+			if at, ok := eqlog.ParseTimestamp(line); ok && speed > 0 {
+				if !prev.IsZero() && at.After(prev) {
+					time.Sleep(min(time.Duration(float64(at.Sub(prev))/speed), maxReplayWait))
+				}
+				prev = at
+			}
+			lines <- eqlog.LogLine{Line: line, Time: time.Now(), Character: character, Primary: true}
+			count++
+		}
+		if err == io.EOF {
+			fmt.Printf("⏹️  Replay finished: %d lines from %s\n", count, path)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
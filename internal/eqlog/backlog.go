@@ -0,0 +1,107 @@
+package eqlog
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Low-latency mode polls the logs more often and, when the parser falls
+// behind, holds back position lines so only the newest one per character is
+// queued. Older positions are worthless once a newer one exists; everything
+// else is still delivered in order.
+
+const (
+	lowLatencyIdle = 10 * time.Millisecond
+
+	// positionBacklog is how many queued lines count as falling behind
+	positionBacklog = 4
+)
+
+// Stats describes the backlog between the log tails and the parser.
+type Stats struct {
+	Queued     int   // Lines waiting for the parser now
+	Capacity   int   // Lines that can wait before old ones are dropped
+	PeakQueued int   // Most lines ever waiting at once
+	Dropped    int64 // Lines discarded because the backlog was full
+	StaleLocs  int64 // Position lines dropped for a newer one
+	LowLatency bool
+}
+
+type readerStats struct {
+	peak      atomic.Int64
+	dropped   atomic.Int64
+	staleLocs atomic.Int64
+}
+
+func (r *Reader) SetLowLatency(on bool) {
+	r.lowLatency.Store(on)
+}
+
+func (r *Reader) LowLatency() bool {
+	return r.lowLatency.Load()
+}
+
+// Stats is safe to call from any goroutine.
+func (r *Reader) Stats() Stats {
+	return Stats{
+		Queued:     r.queued(),
+		Capacity:   cap(r.Lines) + r.ring.cap(),
+		PeakQueued: int(r.stats.peak.Load()),
+		Dropped:    r.stats.dropped.Load(),
+		StaleLocs:  r.stats.staleLocs.Load(),
+		LowLatency: r.lowLatency.Load(),
+	}
+}
+
+// send queues one line, holding back positions in low-latency mode while
+// the parser is behind. A held position goes out before the next other line
+// from the same character so zone changes and deaths keep their order.
+func (r *Reader) send(l LogLine) {
+	if r.lowLatency.Load() && r.IsPosition != nil && r.IsPosition(l.Line) {
+		if _, held := r.pending[l.Character]; held {
+			r.stats.staleLocs.Add(1)
+			delete(r.pending, l.Character)
+		}
+		if r.queued() >= positionBacklog {
+			r.pending[l.Character] = l
+			return
+		}
+	} else if held, ok := r.pending[l.Character]; ok {
+		delete(r.pending, l.Character)
+		r.push(held)
+	}
+	r.push(l)
+}
+
+// flushPending sends held-back positions once the parser has caught up.
+func (r *Reader) flushPending() {
+	for character, l := range r.pending {
+		if r.queued() >= positionBacklog {
+			return
+		}
+		delete(r.pending, character)
+		r.push(l)
+	}
+}
+
+// push hands a line to the ring (see ring.go); it never waits.
+func (r *Reader) push(l LogLine) {
+	keep := r.MustKeep != nil && r.MustKeep(l.Line)
+	if r.ring.push(l, keep) {
+		r.stats.dropped.Add(1)
+	}
+	if n := int64(r.queued()); n > r.stats.peak.Load() {
+		r.stats.peak.Store(n)
+	}
+}
+
+// forward feeds the parser from the ring.
+func (r *Reader) forward() {
+	for {
+		r.Lines <- r.ring.pop()
+	}
+}
+
+func (r *Reader) queued() int {
+	return len(r.Lines) + r.ring.len()
+}
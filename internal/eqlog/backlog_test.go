@@ -0,0 +1,43 @@
+package eqlog
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLowLatencyBacklog(t *testing.T) {
+	r := NewReader(t.TempDir())
+	r.SetLowLatency(true)
+	r.IsPosition = func(line string) bool { return strings.HasPrefix(line, "loc") }
+	send := func(character, line string) {
+		r.send(LogLine{Character: character, Line: line})
+	}
+
+	// Nothing reads the queue, so the parser counts as behind from the
+	// fourth line on
+	send("Tank", "loc 1")
+	for _, l := range []string{"say 1", "say 2", "say 3"} {
+		send("Tank", l)
+	}
+	send("Tank", "loc 2")   // Held
+	send("Tank", "loc 3")   // Replaces loc 2
+	send("Healer", "loc 1") // Held; another character's
+	send("Tank", "zone")    // Takes loc 3 out ahead of it
+
+	if got, want := popAll(r.ring), []string{"loc 1", "say 1", "say 2", "say 3", "loc 3", "zone"}; !slices.Equal(got, want) {
+		t.Errorf("queued %q, want %q", got, want)
+	}
+	if s := r.Stats(); s.StaleLocs != 1 || s.PeakQueued != 6 || s.Dropped != 0 {
+		t.Errorf("stats %+v, want 1 stale loc, a peak of 6 and nothing dropped", s)
+	}
+
+	// The parser caught up: the held position goes out
+	r.flushPending()
+	if got := popAll(r.ring); !slices.Equal(got, []string{"loc 1"}) {
+		t.Errorf("flushed %q, want Healer's loc 1", got)
+	}
+	if len(r.pending) != 0 {
+		t.Errorf("still holding %+v", r.pending)
+	}
+}
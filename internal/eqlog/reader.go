@@ -8,30 +8,77 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
 type LogLine struct {
-	Line string
-	Time time.Time
+	Line      string
+	Time      time.Time
+	Character string // Parsed from eqlog_<Character>_<server>.txt ("" if unknown)
+	Primary   bool   // True when the line came from the followed log (see primaryLog)
 }
 
 type Reader struct {
 	EqDir       string
 	Lines       chan LogLine
 	InitialZone string
+
+	// MultiCharacter tails every recently active log at once (boxing)
+	// instead of following only the newest one.
+	MultiCharacter bool
+
+	// IsPosition reports whether a line is a /loc result; in low-latency
+	// mode stale ones are dropped when the parser falls behind (see backlog.go).
+	IsPosition func(line string) bool
+
+	// MustKeep reports whether a line (zone change, death) may never be
+	// discarded when the backlog overflows (see ring.go).
+	MustKeep func(line string) bool
+
+	// ZoneOf returns the zone a line reports entering ("" if none), to find
+	// the zone at startup with the parser's patterns. If nil only "You have
+	// entered X." is recognized.
+	ZoneOf func(line string) string
+
+	character atomic.Pointer[string] // Log to follow (see SetCharacter)
+	recheck   atomic.Bool            // Look at the directory again now
+
+	lowLatency atomic.Bool
+	pending    map[string]LogLine // Newest held-back position per character
+	ring       *lineRing
+	stats      readerStats
+}
+
+// activeLogWindow is how recently a log must have been written to be tailed
+// in multi-character mode.
+const activeLogWindow = 15 * time.Minute
+
+// primaryIdleAfter is how long the primary log can go unwritten in
+// multi-character mode before the newest log takes over.
+const primaryIdleAfter = 5 * time.Minute
+
+// tail is one open log file being followed by the reader.
+type tail struct {
+	path      string
+	character string
+	file      *os.File
+	reader    *bufio.Reader
 }
 
 func NewReader(eqDir string) *Reader {
 	return &Reader{
-		EqDir: eqDir,
-		Lines: make(chan LogLine, 1000),
+		EqDir:   eqDir,
+		Lines:   make(chan LogLine, 1000),
+		pending: make(map[string]LogLine),
+		ring:    newLineRing(ringSize),
 	}
 }
 
 func (r *Reader) Start() error {
 	// Try to detect initial zone from log history
 	r.detectInitialZone()
+	go r.forward()
 	go r.pollAndRead()
 	return nil
 }
@@ -56,22 +103,15 @@ func (r *Reader) detectInitialZone() {
 	}
 	file.Seek(startPos, 0)
 
-	// Compile regex once
-	zoneRegex := regexp.MustCompile(`You have entered (.+)\.`)
+	zoneOf := r.ZoneOf
+	if zoneOf == nil {
+		zoneOf = enteredZone
+	}
 	scanner := bufio.NewScanner(file)
 
 	var lastZone string
 	for scanner.Scan() {
-		if matches := zoneRegex.FindStringSubmatch(scanner.Text()); len(matches) == 2 {
-			zoneName := matches[1]
-
-			// Filter out status messages that aren't real zones
-			// e.g., "an Arena (PvP) area" is a status, not a zone name
-			if strings.Contains(zoneName, "(PvP)") ||
-			   strings.HasSuffix(zoneName, " area") {
-				continue
-			}
-
+		if zoneName := zoneOf(strings.TrimSpace(scanner.Text())); zoneName != "" {
 			lastZone = zoneName
 		}
 	}
@@ -82,89 +122,242 @@ func (r *Reader) detectInitialZone() {
 	}
 }
 
+var enteredRegex = regexp.MustCompile(`You have entered (.+)\.`)
+
+// enteredZone is the zone in a "You have entered X." line.
+func enteredZone(line string) string {
+	matches := enteredRegex.FindStringSubmatch(line)
+	if len(matches) != 2 {
+		return ""
+	}
+	// Filter out status messages that aren't real zones
+	// e.g., "an Arena (PvP) area" is a status, not a zone name
+	if strings.Contains(matches[1], "(PvP)") ||
+		strings.HasSuffix(matches[1], " area") {
+		return ""
+	}
+	return matches[1]
+}
+
 func (r *Reader) pollAndRead() {
-	var currentPath string
-	var file *os.File
-	var reader *bufio.Reader
-	
+	tails := make(map[string]*tail)
+	var primaryPath string
+
 	// Check for new files every 3 seconds
 	checkInterval := 3 * time.Second
 	lastCheck := time.Now()
 
 	for {
-		// 1. Check for Character Switch
-		if time.Since(lastCheck) > checkInterval {
-			latestPath, err := r.findLatestLog()
-			if err == nil && latestPath != currentPath {
-				fmt.Printf("🔄 Loading Log: %s\n", filepath.Base(latestPath))
-				
-				if file != nil {
-					file.Close()
+		// 1. Check for Character Switch (and, in multi mode, newly active boxes)
+		switched := r.recheck.Swap(false)
+		if time.Since(lastCheck) > checkInterval || switched {
+			latestPath, err := r.primaryLog(primaryPath, switched)
+			if err == nil {
+				wanted := map[string]bool{latestPath: true}
+				if r.MultiCharacter {
+					for _, path := range r.findActiveLogs() {
+						wanted[path] = true
+					}
 				}
 
-				newFile, err := os.Open(latestPath)
-				if err != nil {
-					fmt.Printf("❌ Error opening log: %v\n", err)
-				} else {
-					// SMART SEEK:
-					// Instead of skipping to the very end (SeekEnd), back up 5KB.
-					// This ensures we catch the "You have entered..." message 
-					// that often appears right before/during login.
-					stat, _ := newFile.Stat()
-					startPos := stat.Size() - 5000 
-					if startPos < 0 { startPos = 0 }
-					newFile.Seek(startPos, 0)
-					
-					file = newFile
-					currentPath = latestPath
-					reader = bufio.NewReader(file)
+				// Close logs that are no longer wanted
+				for path, t := range tails {
+					if !wanted[path] {
+						fmt.Printf("⏹️  Stopped tailing: %s\n", filepath.Base(path))
+						t.file.Close()
+						delete(tails, path)
+					}
 				}
+
+				// Open newly wanted logs
+				for path := range wanted {
+					if _, ok := tails[path]; ok {
+						continue
+					}
+					fmt.Printf("🔄 Loading Log: %s\n", filepath.Base(path))
+					t, err := openTail(path)
+					if err != nil {
+						fmt.Printf("❌ Error opening log: %v\n", err)
+						continue
+					}
+					tails[path] = t
+				}
+				primaryPath = latestPath
 			}
 			lastCheck = time.Now()
 		}
 
-		// 2. Read Loop
-		if reader != nil {
-			line, err := reader.ReadString('\n')
+		// 2. Read Loop - drain whatever each log has available
+		readAny := false
+		for path, t := range tails {
+			line, err := t.reader.ReadString('\n')
 			if err != nil {
-				time.Sleep(100 * time.Millisecond)
 				continue
 			}
+			readAny = true
 
 			if cleanLine := strings.TrimSpace(line); cleanLine != "" {
-				r.Lines <- LogLine{
-					Line: cleanLine,
-					Time: time.Now(),
-				}
+				r.send(LogLine{
+					Line:      cleanLine,
+					Time:      time.Now(),
+					Character: t.character,
+					Primary:   path == primaryPath,
+				})
 			}
-		} else {
+		}
+		if len(r.pending) > 0 {
+			r.flushPending()
+		}
+
+		idle := 100 * time.Millisecond
+		if r.lowLatency.Load() {
+			idle = lowLatencyIdle
+		}
+		if len(tails) == 0 {
 			time.Sleep(1 * time.Second)
+		} else if !readAny {
+			time.Sleep(idle)
 		}
 	}
 }
 
-func (r *Reader) findLatestLog() (string, error) {
-	// Check Root
-	logs, _ := r.scanDir(r.EqDir)
-	
-	// Check Logs Subdir
-	if len(logs) == 0 {
-		subDir := filepath.Join(r.EqDir, "Logs")
-		logs, _ = r.scanDir(subDir)
+func openTail(path string) (*tail, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
 	}
 
+	// SMART SEEK:
+	// Instead of skipping to the very end (SeekEnd), back up 5KB.
+	// This ensures we catch the "You have entered..." message
+	// that often appears right before/during login.
+	stat, _ := file.Stat()
+	startPos := stat.Size() - 5000
+	if startPos < 0 {
+		startPos = 0
+	}
+	file.Seek(startPos, 0)
+
+	name, _ := CharacterFromPath(path)
+	return &tail{
+		path:      path,
+		character: name,
+		file:      file,
+		reader:    bufio.NewReader(file),
+	}, nil
+}
+
+// CharacterFromPath extracts the character and server names from a log file
+// named like "eqlog_Soandso_P1999Green.txt". Both are "" if the name doesn't
+// follow that pattern.
+func CharacterFromPath(path string) (character, server string) {
+	base := strings.TrimSuffix(filepath.Base(path), ".txt")
+	parts := strings.SplitN(base, "_", 3)
+	if len(parts) < 2 || parts[0] != "eqlog" {
+		return "", ""
+	}
+	character = parts[1]
+	if len(parts) == 3 {
+		server = parts[2]
+	}
+	return character, server
+}
+
+// findActiveLogs returns every log written to within activeLogWindow.
+func (r *Reader) findActiveLogs() []string {
+	logs := r.allLogs()
+	var active []string
+	for _, path := range logs {
+		if fi, err := os.Stat(path); err == nil && time.Since(fi.ModTime()) < activeLogWindow {
+			active = append(active, path)
+		}
+	}
+	return active
+}
+
+// primaryLog returns the log whose lines are Primary. Following only one
+// log, that's findLatestLog's. With every box tailed, the current one stays
+// primary until the user picks a character (switched) or it goes
+// primaryIdleAfter without a line, so two boxes writing at once don't take
+// turns every check.
+func (r *Reader) primaryLog(current string, switched bool) (string, error) {
+	if r.MultiCharacter && !switched && current != "" {
+		if fi, err := os.Stat(current); err == nil && time.Since(fi.ModTime()) < primaryIdleAfter {
+			return current, nil
+		}
+	}
+	return r.findLatestLog()
+}
+
+// findLatestLog returns the log to follow: the chosen character's newest
+// log, else the most recently written one.
+func (r *Reader) findLatestLog() (string, error) {
+	logs := r.Logs()
 	if len(logs) == 0 {
 		return "", fmt.Errorf("no logs found")
 	}
+	if name := r.Character(); name != "" {
+		for _, l := range logs {
+			if strings.EqualFold(l.Character, name) {
+				return l.Path, nil
+			}
+		}
+	}
+	return logs[0].Path, nil
+}
+
+// LogFile is one character's log in the EQ directory.
+type LogFile struct {
+	Path      string
+	Character string // "" if the file isn't named eqlog_<Character>_<server>.txt
+	Server    string
+	ModTime   time.Time
+}
 
-	// Sort Oldest -> Newest
+// Logs lists the logs in the EQ directory, most recently written first.
+func (r *Reader) Logs() []LogFile {
+	var logs []LogFile
+	for _, path := range r.allLogs() {
+		fi, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		character, server := CharacterFromPath(path)
+		logs = append(logs, LogFile{Path: path, Character: character, Server: server, ModTime: fi.ModTime()})
+	}
 	sort.Slice(logs, func(i, j int) bool {
-		fi, _ := os.Stat(logs[i])
-		fj, _ := os.Stat(logs[j])
-		return fi.ModTime().Before(fj.ModTime())
+		return logs[i].ModTime.After(logs[j].ModTime)
 	})
+	return logs
+}
+
+// SetCharacter makes the named character's newest log the one followed
+// (its lines are Primary), whichever box was played last. "" follows the
+// most recently written log. A character with no log falls back to that too.
+func (r *Reader) SetCharacter(name string) {
+	r.character.Store(&name)
+	r.recheck.Store(true)
+}
+
+// Character returns the character set by SetCharacter.
+func (r *Reader) Character() string {
+	if name := r.character.Load(); name != nil {
+		return *name
+	}
+	return ""
+}
 
-	return logs[len(logs)-1], nil
+// allLogs lists every eqlog file in the EQ root, falling back to the Logs subdir.
+func (r *Reader) allLogs() []string {
+	// Check Root
+	logs, _ := r.scanDir(r.EqDir)
+
+	// Check Logs Subdir
+	if len(logs) == 0 {
+		subDir := filepath.Join(r.EqDir, "Logs")
+		logs, _ = r.scanDir(subDir)
+	}
+	return logs
 }
 
 func (r *Reader) scanDir(path string) ([]string, error) {
@@ -180,4 +373,4 @@ func (r *Reader) scanDir(path string) ([]string, error) {
 		}
 	}
 	return logs, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,58 @@
+package eqlog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeLog creates a character's log last written age ago.
+func writeLog(t *testing.T, dir, character string, age time.Duration) string {
+	t.Helper()
+	path := filepath.Join(dir, "eqlog_"+character+"_P1999Green.txt")
+	if err := os.WriteFile(path, []byte("[Mon Oct 12 20:01:02 2026] Welcome to EverQuest!\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	when := time.Now().Add(-age)
+	if err := os.Chtimes(path, when, when); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPrimaryLog(t *testing.T) {
+	dir := t.TempDir()
+	main := writeLog(t, dir, "Tank", 10*time.Second)
+	box := writeLog(t, dir, "Healer", time.Second)
+	idle := writeLog(t, dir, "Bard", primaryIdleAfter+time.Minute)
+
+	tests := []struct {
+		name     string
+		multi    bool
+		chosen   string
+		current  string
+		switched bool
+		want     string
+	}{
+		{"first check follows the newest", true, "", "", false, box},
+		{"box written since keeps the primary", true, "", main, false, main},
+		{"idle primary gives way to the newest", true, "", idle, false, box},
+		{"picking a character switches", true, "Tank", box, true, main},
+		{"picking most recent switches", true, "", main, true, box},
+		{"one log follows the newest", false, "", main, false, box},
+		{"one log follows the chosen character", false, "Bard", main, false, idle},
+	}
+	for _, tt := range tests {
+		r := NewReader(dir)
+		r.MultiCharacter = tt.multi
+		r.SetCharacter(tt.chosen)
+		got, err := r.primaryLog(tt.current, tt.switched)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: primaryLog = %s, want %s", tt.name, filepath.Base(got), filepath.Base(tt.want))
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package eqlog
+
+import "sync"
+
+// ringSize is how many lines can wait on top of the Lines buffer before
+// old ones are discarded.
+const ringSize = 4096
+
+// lineRing sits between the log tails and the Lines channel so reading
+// never waits on the parser. When it's full, the oldest line that isn't
+// marked keep (zone changes, deaths) is discarded to make room; if every
+// line is one to keep, the ring grows instead.
+type lineRing struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	entries []ringEntry
+	head, n int
+}
+
+type ringEntry struct {
+	line LogLine
+	keep bool
+}
+
+func newLineRing(size int) *lineRing {
+	q := &lineRing{entries: make([]ringEntry, size)}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// push adds a line and reports whether an older one was discarded for it.
+func (q *lineRing) push(l LogLine, keep bool) (dropped bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	size := len(q.entries)
+	if q.n == size {
+		i := 0
+		for i < q.n && q.entries[(q.head+i)%size].keep {
+			i++
+		}
+		if i == q.n {
+			q.grow()
+			size = len(q.entries)
+		} else {
+			// Close the gap by moving the kept lines ahead of it up one slot
+			for j := i; j > 0; j-- {
+				q.entries[(q.head+j)%size] = q.entries[(q.head+j-1)%size]
+			}
+			q.entries[q.head] = ringEntry{}
+			q.head = (q.head + 1) % size
+			q.n--
+			dropped = true
+		}
+	}
+
+	q.entries[(q.head+q.n)%size] = ringEntry{line: l, keep: keep}
+	q.n++
+	q.cond.Signal()
+	return dropped
+}
+
+func (q *lineRing) grow() {
+	entries := make([]ringEntry, len(q.entries)*2)
+	for i := 0; i < q.n; i++ {
+		entries[i] = q.entries[(q.head+i)%len(q.entries)]
+	}
+	q.entries = entries
+	q.head = 0
+}
+
+// pop waits for the oldest line and removes it.
+func (q *lineRing) pop() LogLine {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.n == 0 {
+		q.cond.Wait()
+	}
+	e := q.entries[q.head]
+	q.entries[q.head] = ringEntry{}
+	q.head = (q.head + 1) % len(q.entries)
+	q.n--
+	return e.line
+}
+
+func (q *lineRing) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.n
+}
+
+func (q *lineRing) cap() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.entries)
+}
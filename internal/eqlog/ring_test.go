@@ -0,0 +1,87 @@
+package eqlog
+
+import (
+	"slices"
+	"testing"
+)
+
+// popAll empties the ring, returning the lines in the order popped.
+func popAll(q *lineRing) []string {
+	var lines []string
+	for q.len() > 0 {
+		lines = append(lines, q.pop().Line)
+	}
+	return lines
+}
+
+func TestLineRingOrder(t *testing.T) {
+	q := newLineRing(4)
+	for _, l := range []string{"a", "b", "c"} {
+		q.push(LogLine{Line: l}, false)
+	}
+	if got := q.pop().Line; got != "a" {
+		t.Fatalf("popped %q, want a", got)
+	}
+	// Wrap around the end of the buffer
+	for _, l := range []string{"d", "e"} {
+		q.push(LogLine{Line: l}, false)
+	}
+	if got, want := popAll(q), []string{"b", "c", "d", "e"}; !slices.Equal(got, want) {
+		t.Errorf("popped %q, want %q", got, want)
+	}
+}
+
+func TestLineRingOverflow(t *testing.T) {
+	q := newLineRing(4)
+	var dropped int
+	for _, l := range []string{"a", "b", "c", "d", "e", "f"} {
+		if q.push(LogLine{Line: l}, false) {
+			dropped++
+		}
+	}
+	if dropped != 2 {
+		t.Errorf("dropped %d lines, want 2", dropped)
+	}
+	if q.cap() != 4 {
+		t.Errorf("capacity %d, want 4", q.cap())
+	}
+	if got, want := popAll(q), []string{"c", "d", "e", "f"}; !slices.Equal(got, want) {
+		t.Errorf("popped %q, want %q", got, want)
+	}
+}
+
+func TestLineRingKeepsMarkedLines(t *testing.T) {
+	q := newLineRing(4)
+	q.push(LogLine{Line: "zone"}, true)
+	q.push(LogLine{Line: "loc 1"}, false)
+	q.push(LogLine{Line: "death"}, true)
+	q.push(LogLine{Line: "loc 2"}, false)
+
+	// The oldest line not marked keep makes room, the rest keep their order
+	if !q.push(LogLine{Line: "loc 3"}, false) {
+		t.Error("full ring took a line without dropping one")
+	}
+	if !q.push(LogLine{Line: "loc 4"}, false) {
+		t.Error("full ring took a line without dropping one")
+	}
+	if got, want := popAll(q), []string{"zone", "death", "loc 3", "loc 4"}; !slices.Equal(got, want) {
+		t.Errorf("popped %q, want %q", got, want)
+	}
+}
+
+func TestLineRingGrowsWhenAllKept(t *testing.T) {
+	q := newLineRing(2)
+	q.push(LogLine{Line: "zone 0"}, true)
+	q.pop() // So the lines below wrap around before the ring grows
+	for _, l := range []string{"zone 1", "zone 2", "zone 3"} {
+		if q.push(LogLine{Line: l}, true) {
+			t.Errorf("dropped a line for %q", l)
+		}
+	}
+	if q.cap() != 4 {
+		t.Errorf("capacity %d, want 4", q.cap())
+	}
+	if got, want := popAll(q), []string{"zone 1", "zone 2", "zone 3"}; !slices.Equal(got, want) {
+		t.Errorf("popped %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,14 @@
+package eqlog
+
+import (
+	"time"
+
+	"github.com/devin-hart/nox-maps/pkg/eqlogparse"
+)
+
+// ParseTimestamp reads the time a line was written from its prefix, in
+// local time. Live tailing stamps lines when they are read instead; this is
+// for reading old logs.
+func ParseTimestamp(line string) (time.Time, bool) {
+	return eqlogparse.ParseTimestamp(line)
+}
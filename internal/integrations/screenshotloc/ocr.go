@@ -0,0 +1,82 @@
+// Package screenshotloc is an optional integration that reads the /loc
+// overlay from EverQuest screenshots. It's off unless enabled in the config
+// and relies on an external OCR program, so nothing else depends on it.
+package screenshotloc
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Backend turns an image into text. Implementations are expected to be slow
+// (they run an OCR engine) and are only called from the watcher goroutine.
+type Backend interface {
+	Recognize(imagePath string) (string, error)
+}
+
+// CommandBackend runs an OCR program that prints the recognized text to
+// stdout. "{image}" in Args is replaced by the screenshot path; the default
+// is Tesseract: `tesseract <image> stdout`.
+type CommandBackend struct {
+	Command string
+	Args    []string
+}
+
+func NewTesseract() *CommandBackend {
+	return &CommandBackend{Command: "tesseract", Args: []string{"{image}", "stdout"}}
+}
+
+// NewCommandBackend parses a command line such as "tesseract {image} stdout".
+// The image path is appended if the line doesn't mention {image}.
+func NewCommandBackend(commandLine string) (*CommandBackend, error) {
+	fields := strings.Fields(commandLine)
+	if len(fields) == 0 {
+		return NewTesseract(), nil
+	}
+	b := &CommandBackend{Command: fields[0], Args: fields[1:]}
+	if !strings.Contains(commandLine, "{image}") {
+		b.Args = append(b.Args, "{image}")
+	}
+	if _, err := exec.LookPath(b.Command); err != nil {
+		return nil, fmt.Errorf("OCR program not found: %s", b.Command)
+	}
+	return b, nil
+}
+
+func (b *CommandBackend) Recognize(imagePath string) (string, error) {
+	args := make([]string, len(b.Args))
+	for i, a := range b.Args {
+		args[i] = strings.ReplaceAll(a, "{image}", imagePath)
+	}
+	out, err := exec.Command(b.Command, args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", b.Command, err)
+	}
+	return string(out), nil
+}
+
+// OCR output is noisy: commas become periods, spaces go missing, so the
+// loc is matched loosely as three numbers following "Location".
+var locPattern = regexp.MustCompile(`(?i)location[\s:]*(?:is[\s:]*)?(-?\d+(?:\.\d+)?)[,.\s]+(-?\d+(?:\.\d+)?)[,.\s]+(-?\d+(?:\.\d+)?)`)
+
+// ParseLoc finds a /loc readout ("Your Location is Y, X, Z") in OCR text.
+func ParseLoc(text string) (locY, locX, locZ float64, ok bool) {
+	m := locPattern.FindStringSubmatch(text)
+	if m == nil {
+		return 0, 0, 0, false
+	}
+	var err error
+	if locY, err = strconv.ParseFloat(m[1], 64); err != nil {
+		return 0, 0, 0, false
+	}
+	if locX, err = strconv.ParseFloat(m[2], 64); err != nil {
+		return 0, 0, 0, false
+	}
+	if locZ, err = strconv.ParseFloat(m[3], 64); err != nil {
+		return 0, 0, 0, false
+	}
+	return locY, locX, locZ, true
+}
@@ -0,0 +1,109 @@
+package screenshotloc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Result is a /loc read from one screenshot.
+type Result struct {
+	Path             string
+	LocY, LocX, LocZ float64
+}
+
+// Watcher polls the screenshots directory and OCRs each new image. Files
+// present when it starts are ignored so old screenshots don't flood the map.
+type Watcher struct {
+	Results chan Result
+
+	dir      string
+	backend  Backend
+	interval time.Duration
+	stop     chan struct{}
+}
+
+var imageExts = map[string]bool{".bmp": true, ".jpg": true, ".jpeg": true, ".png": true}
+
+func Watch(dir string, backend Backend, interval time.Duration) *Watcher {
+	w := &Watcher{
+		Results:  make(chan Result, 8),
+		dir:      dir,
+		backend:  backend,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+	go w.poll()
+	return w
+}
+
+func (w *Watcher) Stop() {
+	close(w.stop)
+}
+
+func (w *Watcher) poll() {
+	done := w.listImages()            // Already handled (or pre-existing)
+	pending := make(map[string]int64) // New files waiting for their size to settle
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+
+		for path, size := range w.listImages() {
+			if _, ok := done[path]; ok {
+				continue
+			}
+			// Only OCR once the client has finished writing the file
+			if prev, ok := pending[path]; ok && prev == size && size > 0 {
+				delete(pending, path)
+				done[path] = size
+				w.process(path)
+				continue
+			}
+			pending[path] = size
+		}
+	}
+}
+
+func (w *Watcher) process(path string) {
+	text, err := w.backend.Recognize(path)
+	if err != nil {
+		fmt.Printf("❌ Screenshot OCR failed for %s: %v\n", filepath.Base(path), err)
+		return
+	}
+	locY, locX, locZ, ok := ParseLoc(text)
+	if !ok {
+		fmt.Printf("📷 No /loc found in %s\n", filepath.Base(path))
+		return
+	}
+
+	select {
+	case w.Results <- Result{Path: path, LocY: locY, LocX: locX, LocZ: locZ}:
+	case <-w.stop:
+	}
+}
+
+// listImages returns image files in the directory with their sizes.
+func (w *Watcher) listImages() map[string]int64 {
+	images := make(map[string]int64)
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return images
+	}
+	for _, e := range entries {
+		if e.IsDir() || !imageExts[strings.ToLower(filepath.Ext(e.Name()))] {
+			continue
+		}
+		if info, err := e.Info(); err == nil {
+			images[filepath.Join(w.dir, e.Name())] = info.Size()
+		}
+	}
+	return images
+}
@@ -0,0 +1,113 @@
+// Package sound is an optional integration that plays short alert sounds
+// (built-in tones or the user's own WAV files) through the system's audio
+// player, for players who run the overlay where they won't see it change.
+package sound
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Backend plays one sound file and returns when it's done.
+type Backend interface {
+	Play(path string) error
+}
+
+// CommandBackend runs an audio player. "{file}" in Args is replaced by the
+// file's path, which is also passed in the NOX_SOUND environment variable.
+type CommandBackend struct {
+	Command string
+	Args    []string
+}
+
+// NewCommandBackend parses a command line such as "paplay {file}"; the path
+// is appended if the line doesn't mention {file}. An empty line picks the
+// platform default.
+func NewCommandBackend(commandLine string) (*CommandBackend, error) {
+	fields := strings.Fields(commandLine)
+	if len(fields) == 0 {
+		return Default()
+	}
+	b := &CommandBackend{Command: fields[0], Args: fields[1:]}
+	if !strings.Contains(commandLine, "{file}") {
+		b.Args = append(b.Args, "{file}")
+	}
+	if _, err := exec.LookPath(b.Command); err != nil {
+		return nil, fmt.Errorf("sound program not found: %s", b.Command)
+	}
+	return b, nil
+}
+
+// Default finds the platform's player: Media.SoundPlayer through PowerShell
+// on Windows, afplay on macOS, paplay / pw-play / aplay elsewhere.
+func Default() (*CommandBackend, error) {
+	switch runtime.GOOS {
+	case "windows":
+		return &CommandBackend{Command: "powershell", Args: []string{
+			"-NoProfile", "-Command",
+			"(New-Object Media.SoundPlayer $env:NOX_SOUND).PlaySync()",
+		}}, nil
+	case "darwin":
+		return &CommandBackend{Command: "afplay", Args: []string{"{file}"}}, nil
+	}
+	for _, name := range []string{"paplay", "pw-play", "aplay"} {
+		if _, err := exec.LookPath(name); err == nil {
+			args := []string{"{file}"}
+			if name == "aplay" {
+				args = []string{"-q", "{file}"}
+			}
+			return &CommandBackend{Command: name, Args: args}, nil
+		}
+	}
+	return nil, fmt.Errorf("no audio player found (install pulseaudio-utils or set sound_command)")
+}
+
+func (b *CommandBackend) Play(path string) error {
+	args := make([]string, len(b.Args))
+	for i, a := range b.Args {
+		args[i] = strings.ReplaceAll(a, "{file}", path)
+	}
+	cmd := exec.Command(b.Command, args...)
+	cmd.Env = append(os.Environ(), "NOX_SOUND="+path)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", b.Command, err)
+	}
+	return nil
+}
+
+// Player plays sounds one at a time in the background. Sounds that arrive
+// while it's busy are dropped; a burst of alerts would only blur together.
+type Player struct {
+	backend Backend
+	queue   chan string
+}
+
+func NewPlayer(backend Backend) *Player {
+	p := &Player{backend: backend, queue: make(chan string, 1)}
+	go func() {
+		for path := range p.queue {
+			if err := p.backend.Play(path); err != nil {
+				fmt.Printf("⚠️  Sound failed: %v\n", err)
+			}
+		}
+	}()
+	return p
+}
+
+// Play queues a sound file; it returns false if the sound was dropped.
+func (p *Player) Play(path string) bool {
+	select {
+	case p.queue <- path:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stop ends the player once the queued sound has played.
+func (p *Player) Stop() {
+	close(p.queue)
+}
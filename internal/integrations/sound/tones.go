@@ -0,0 +1,113 @@
+package sound
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const sampleRate = 22050
+
+// note is one beep of a built-in tone.
+type note struct {
+	freq     float64 // Hz; 0 = silence
+	duration float64 // Seconds
+}
+
+// tones are the built-in alert sounds, generated as WAV files on first use
+// so nothing has to ship with the app.
+var tones = map[string][]note{
+	"chime": {{880, 0.12}, {1320, 0.25}},
+	"blip":  {{1200, 0.08}},
+	"alert": {{660, 0.15}, {0, 0.05}, {660, 0.15}},
+	"alarm": {{988, 0.2}, {740, 0.2}, {988, 0.2}, {740, 0.2}},
+}
+
+// Tones lists the built-in tone names.
+func Tones() []string {
+	names := make([]string, 0, len(tones))
+	for name := range tones {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsTone reports whether name is a built-in tone.
+func IsTone(name string) bool {
+	_, ok := tones[name]
+	return ok
+}
+
+// Resolve turns a configured sound (a tone name or a WAV path) into a file
+// to play, writing the tone into dir if it isn't there yet.
+func Resolve(dir, sound string) (string, error) {
+	notes, ok := tones[sound]
+	if !ok {
+		if _, err := os.Stat(sound); err != nil {
+			return "", err
+		}
+		return sound, nil
+	}
+
+	path := filepath.Join(dir, sound+".wav")
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, toneWAV(notes), 0644); err != nil {
+		return "", fmt.Errorf("writing tone %s: %w", sound, err)
+	}
+	return path, nil
+}
+
+// toneWAV renders notes as 16-bit mono PCM. Each note fades in and out over
+// a few milliseconds so it doesn't click.
+func toneWAV(notes []note) []byte {
+	var samples []int16
+	fade := sampleRate / 200
+	for _, n := range notes {
+		count := int(n.duration * sampleRate)
+		for i := 0; i < count; i++ {
+			if n.freq == 0 {
+				samples = append(samples, 0)
+				continue
+			}
+			gain := 1.0
+			if i < fade {
+				gain = float64(i) / float64(fade)
+			} else if count-i < fade {
+				gain = float64(count-i) / float64(fade)
+			}
+			v := math.Sin(2*math.Pi*n.freq*float64(i)/sampleRate) * gain * 0.5
+			samples = append(samples, int16(v*math.MaxInt16))
+		}
+	}
+
+	var buf bytes.Buffer
+	dataSize := uint32(len(samples) * 2)
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, 36+dataSize)
+	buf.WriteString("WAVEfmt ")
+	for _, v := range []any{
+		uint32(16),             // fmt chunk size
+		uint16(1),              // PCM
+		uint16(1),              // Mono
+		uint32(sampleRate),     // Sample rate
+		uint32(sampleRate * 2), // Byte rate
+		uint16(2),              // Block align
+		uint16(16),             // Bits per sample
+	} {
+		binary.Write(&buf, binary.LittleEndian, v)
+	}
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, dataSize)
+	binary.Write(&buf, binary.LittleEndian, samples)
+	return buf.Bytes()
+}
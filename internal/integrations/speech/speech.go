@@ -0,0 +1,115 @@
+// Package speech is an optional integration that speaks short status
+// announcements through the system's text-to-speech program, for players
+// who can't comfortably read the overlay.
+package speech
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Backend speaks one phrase and returns when it's done.
+type Backend interface {
+	Speak(text string) error
+}
+
+// CommandBackend runs a speech program. "{text}" in Args is replaced by the
+// phrase, which is also passed in the NOX_SAY environment variable for
+// programs that are easier to script that way (PowerShell).
+type CommandBackend struct {
+	Command string
+	Args    []string
+}
+
+// NewCommandBackend parses a command line such as "espeak-ng {text}"; the
+// phrase is appended if the line doesn't mention {text}. An empty line picks
+// the platform default.
+func NewCommandBackend(commandLine string) (*CommandBackend, error) {
+	fields := strings.Fields(commandLine)
+	if len(fields) == 0 {
+		return Default()
+	}
+	b := &CommandBackend{Command: fields[0], Args: fields[1:]}
+	if !strings.Contains(commandLine, "{text}") {
+		b.Args = append(b.Args, "{text}")
+	}
+	if _, err := exec.LookPath(b.Command); err != nil {
+		return nil, fmt.Errorf("speech program not found: %s", b.Command)
+	}
+	return b, nil
+}
+
+// Default finds the platform's speech program: SAPI through PowerShell on
+// Windows, say on macOS, espeak-ng / espeak / spd-say elsewhere.
+func Default() (*CommandBackend, error) {
+	switch runtime.GOOS {
+	case "windows":
+		return &CommandBackend{Command: "powershell", Args: []string{
+			"-NoProfile", "-Command",
+			"Add-Type -AssemblyName System.Speech; (New-Object System.Speech.Synthesis.SpeechSynthesizer).Speak($env:NOX_SAY)",
+		}}, nil
+	case "darwin":
+		return &CommandBackend{Command: "say", Args: []string{"{text}"}}, nil
+	}
+	for _, name := range []string{"espeak-ng", "espeak", "spd-say"} {
+		if _, err := exec.LookPath(name); err == nil {
+			args := []string{"{text}"}
+			if name == "spd-say" {
+				args = []string{"--wait", "{text}"}
+			}
+			return &CommandBackend{Command: name, Args: args}, nil
+		}
+	}
+	return nil, fmt.Errorf("no speech program found (install espeak-ng or set speech_command)")
+}
+
+func (b *CommandBackend) Speak(text string) error {
+	args := make([]string, len(b.Args))
+	for i, a := range b.Args {
+		args[i] = strings.ReplaceAll(a, "{text}", text)
+	}
+	cmd := exec.Command(b.Command, args...)
+	cmd.Env = append(os.Environ(), "NOX_SAY="+text)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", b.Command, err)
+	}
+	return nil
+}
+
+// Speaker speaks phrases one at a time in the background. If it falls
+// behind, new phrases are dropped rather than queued: stale directions are
+// worse than none.
+type Speaker struct {
+	backend Backend
+	queue   chan string
+}
+
+func NewSpeaker(backend Backend) *Speaker {
+	s := &Speaker{backend: backend, queue: make(chan string, 2)}
+	go func() {
+		for text := range s.queue {
+			if err := s.backend.Speak(text); err != nil {
+				fmt.Printf("⚠️  Speech failed: %v\n", err)
+			}
+		}
+	}()
+	return s
+}
+
+// Say queues a phrase; it returns false if the phrase was dropped.
+func (s *Speaker) Say(text string) bool {
+	select {
+	case s.queue <- text:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stop ends the speaker once queued phrases are spoken.
+func (s *Speaker) Stop() {
+	close(s.queue)
+}
@@ -0,0 +1,63 @@
+package links
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// The clipboard is reached through each platform's command-line tools, the
+// same way dialogs go through zenity, so no cgo clipboard binding is needed.
+
+func CopyToClipboard(s string) error {
+	cmd, err := clipboardCommand(true)
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = strings.NewReader(s)
+	return cmd.Run()
+}
+
+func ReadClipboard() (string, error) {
+	cmd, err := clipboardCommand(false)
+	if err != nil {
+		return "", err
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func clipboardCommand(write bool) (*exec.Cmd, error) {
+	switch runtime.GOOS {
+	case "windows":
+		if write {
+			return exec.Command("clip"), nil
+		}
+		return exec.Command("powershell", "-NoProfile", "-Command", "Get-Clipboard"), nil
+	case "darwin":
+		if write {
+			return exec.Command("pbcopy"), nil
+		}
+		return exec.Command("pbpaste"), nil
+	}
+
+	// Linux/BSD: prefer Wayland, then X11 tools
+	candidates := [][]string{
+		{"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"},
+	}
+	if !write {
+		candidates = [][]string{
+			{"wl-paste", "--no-newline"}, {"xclip", "-selection", "clipboard", "-o"}, {"xsel", "--clipboard", "--output"},
+		}
+	}
+	for _, c := range candidates {
+		if _, err := exec.LookPath(c[0]); err == nil {
+			return exec.Command(c[0], c[1:]...), nil
+		}
+	}
+	return nil, fmt.Errorf("no clipboard tool found (install wl-clipboard, xclip or xsel)")
+}
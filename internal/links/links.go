@@ -0,0 +1,77 @@
+package links
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Scheme is the URI scheme for shareable map locations:
+//
+//	nox://loc/East%20Commonlands?loc=-1234.5,567.0&label=Bank
+//
+// The location is an EQ /loc (Y, X) so links read the same as in-game coordinates.
+const Scheme = "nox"
+
+type Link struct {
+	Zone  string
+	LocY  float64
+	LocX  float64
+	Label string
+}
+
+// FromMap builds a link from map-space coordinates (as used by markers).
+func FromMap(zone string, mapX, mapY float64, label string) Link {
+	return Link{Zone: zone, LocY: -mapY, LocX: -mapX, Label: label}
+}
+
+// MapXY converts the link's /loc back into map-space coordinates.
+func (l Link) MapXY() (float64, float64) {
+	return -l.LocX, -l.LocY
+}
+
+func (l Link) String() string {
+	q := url.Values{}
+	q.Set("loc", fmt.Sprintf("%.1f,%.1f", l.LocY, l.LocX))
+	if l.Label != "" {
+		q.Set("label", l.Label)
+	}
+	u := url.URL{
+		Scheme:   Scheme,
+		Host:     "loc",
+		Path:     "/" + l.Zone,
+		RawQuery: q.Encode(),
+	}
+	return u.String()
+}
+
+// Parse reads a nox:// link. Surrounding whitespace (common when pasting
+// from chat) is ignored.
+func Parse(s string) (Link, error) {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return Link{}, err
+	}
+	if u.Scheme != Scheme || u.Host != "loc" {
+		return Link{}, fmt.Errorf("not a %s://loc link", Scheme)
+	}
+
+	zone := strings.TrimPrefix(u.Path, "/")
+	if zone == "" {
+		return Link{}, fmt.Errorf("link has no zone")
+	}
+
+	q := u.Query()
+	parts := strings.Split(q.Get("loc"), ",")
+	if len(parts) != 2 {
+		return Link{}, fmt.Errorf("link has no valid loc")
+	}
+	locY, errY := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	locX, errX := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if errY != nil || errX != nil {
+		return Link{}, fmt.Errorf("link has no valid loc")
+	}
+
+	return Link{Zone: zone, LocY: locY, LocX: locX, Label: q.Get("label")}, nil
+}
@@ -0,0 +1,70 @@
+package links
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// RegisterHandler makes the OS open nox:// links with this executable
+// (passing the link as the first argument). Supported on Linux desktops
+// (xdg) and Windows (per-user registry); macOS needs an app bundle.
+func RegisterHandler() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return registerXDG(exe)
+	case "windows":
+		return registerWindows(exe)
+	default:
+		return fmt.Errorf("registering %s:// links isn't supported on %s", Scheme, runtime.GOOS)
+	}
+}
+
+func registerXDG(exe string) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	appDir := filepath.Join(home, ".local", "share", "applications")
+	if err := os.MkdirAll(appDir, 0755); err != nil {
+		return err
+	}
+
+	desktopFile := "nox-maps-link.desktop"
+	entry := fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=Nox Maps
+Exec="%s" %%u
+Path=%s
+Terminal=false
+NoDisplay=true
+MimeType=x-scheme-handler/%s;
+`, exe, filepath.Dir(exe), Scheme)
+
+	if err := os.WriteFile(filepath.Join(appDir, desktopFile), []byte(entry), 0644); err != nil {
+		return err
+	}
+	return exec.Command("xdg-mime", "default", desktopFile, "x-scheme-handler/"+Scheme).Run()
+}
+
+func registerWindows(exe string) error {
+	key := `HKCU\Software\Classes\` + Scheme
+	commands := [][]string{
+		{"reg", "add", key, "/ve", "/d", "URL:Nox Maps Link", "/f"},
+		{"reg", "add", key, "/v", "URL Protocol", "/d", "", "/f"},
+		{"reg", "add", key + `\shell\open\command`, "/ve", "/d", fmt.Sprintf(`"%s" "%%1"`, exe), "/f"},
+	}
+	for _, args := range commands {
+		if err := exec.Command(args[0], args[1:]...).Run(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
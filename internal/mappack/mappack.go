@@ -0,0 +1,296 @@
+// Package mappack downloads community map sets (Brewall's, Good's, ...)
+// and installs their map files into the maps directory.
+//
+// A source is either a manifest, JSON naming the pack's version, the URL of
+// its zip and the zip's SHA-256:
+//
+//	{"name": "Brewall", "version": "2025-06-01", "url": "brewall.zip", "sha256": "9f2c..."}
+//
+// or the zip itself, optionally with the expected checksum in config. The
+// zip is downloaded to a temporary file, checked, and every .txt in it is
+// copied into the maps directory (folders inside the zip are flattened).
+// A pack without a checksum isn't downloaded unless the caller allows it,
+// having asked the user.
+package mappack
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+)
+
+const (
+	maxManifestSize = 1 << 20
+	maxArchiveSize  = 500 << 20
+	maxMapFileSize  = 50 << 20 // Per file inside the zip
+)
+
+var (
+	ErrChecksum   = errors.New("download doesn't match the published checksum")
+	ErrUnverified = errors.New("no checksum is published to verify the download against")
+)
+
+var (
+	manifestClient = &http.Client{Timeout: 30 * time.Second}
+	archiveClient  = &http.Client{Timeout: 10 * time.Minute} // Up to maxArchiveSize on a slow line
+)
+
+// Manifest describes one version of a pack.
+type Manifest struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	URL     string `json:"url"` // The zip, absolute or relative to the manifest
+	SHA256  string `json:"sha256"`
+}
+
+// Result is how an install went.
+type Result struct {
+	Source   string // Source name
+	Version  string // The manifest's, else "sha256:" and the start of the checksum
+	SHA256   string // Of the downloaded zip
+	Verified bool   // SHA256 matched a published checksum
+	UpToDate bool   // Already installed; nothing was written
+	Files    []string
+	Err      error
+}
+
+// Install is a download and install running in the background.
+type Install struct {
+	Done chan Result // Receives the result once
+
+	received, total atomic.Int64
+}
+
+// StartInstall fetches src and installs it into mapDir unless installed
+// (the version currently installed, "" if none) is already current. A pack
+// with no checksum fails with ErrUnverified before downloading unless
+// unverified is set. backup is called with the paths about to be written;
+// an error stops the install.
+func StartInstall(src config.MapPackSource, mapDir, installed string, unverified bool, backup func(paths []string) error) *Install {
+	in := &Install{Done: make(chan Result, 1)}
+	go func() {
+		res := in.run(src, mapDir, installed, unverified, backup)
+		res.Source = src.Name
+		in.Done <- res
+	}()
+	return in
+}
+
+// Progress reports bytes downloaded so far and the size (0 if unknown).
+func (in *Install) Progress() (received, total int64) {
+	return in.received.Load(), in.total.Load()
+}
+
+func (in *Install) run(src config.MapPackSource, mapDir, installed string, unverified bool, backup func([]string) error) Result {
+	m, err := Resolve(src)
+	if err != nil {
+		return Result{Err: err}
+	}
+	if m.Version != "" && m.Version == installed {
+		return Result{Version: m.Version, UpToDate: true}
+	}
+	if m.SHA256 == "" && !unverified {
+		return Result{Version: m.Version, Err: ErrUnverified}
+	}
+
+	archive, sum, err := in.download(m.URL)
+	if archive != "" {
+		defer os.Remove(archive)
+	}
+	if err != nil {
+		return Result{Err: err}
+	}
+	res := Result{Version: m.Version, SHA256: sum}
+	if res.Version == "" {
+		res.Version = "sha256:" + sum[:12]
+	}
+	if m.SHA256 != "" {
+		if !strings.EqualFold(m.SHA256, sum) {
+			res.Err = ErrChecksum
+			return res
+		}
+		res.Verified = true
+	}
+	if res.Version == installed {
+		res.UpToDate = true
+		return res
+	}
+
+	res.Files, res.Err = extract(archive, mapDir, backup)
+	return res
+}
+
+// Resolve reads a source's manifest. A source that points straight at a
+// zip gets one made from its config entry, without a version.
+func Resolve(src config.MapPackSource) (*Manifest, error) {
+	if isArchive(src.URL) {
+		return &Manifest{Name: src.Name, URL: src.URL, SHA256: src.SHA256}, nil
+	}
+
+	resp, err := get(manifestClient, src.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var m Manifest
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(&m); err != nil {
+		return nil, fmt.Errorf("%s is not a map pack manifest: %v", src.URL, err)
+	}
+	if m.URL == "" {
+		return nil, fmt.Errorf("manifest %s has no url", src.URL)
+	}
+	base, err := url.Parse(src.URL)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := url.Parse(m.URL)
+	if err != nil {
+		return nil, fmt.Errorf("manifest %s: bad url: %v", src.URL, err)
+	}
+	m.URL = base.ResolveReference(ref).String()
+	if m.Name == "" {
+		m.Name = src.Name
+	}
+	return &m, nil
+}
+
+func isArchive(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(path.Ext(u.Path), ".zip")
+}
+
+func get(client *http.Client, rawURL string) (*http.Response, error) {
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %s", rawURL, resp.Status)
+	}
+	return resp, nil
+}
+
+// download saves the zip to a temporary file, returning its path and
+// SHA-256.
+func (in *Install) download(rawURL string) (string, string, error) {
+	resp, err := get(archiveClient, rawURL)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.ContentLength > maxArchiveSize {
+		return "", "", fmt.Errorf("%s is too large (%d MB)", rawURL, resp.ContentLength>>20)
+	}
+	in.total.Store(max(resp.ContentLength, 0))
+
+	f, err := os.CreateTemp("", "nox-mappack-*.zip")
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, h, progressWriter{&in.received}), io.LimitReader(resp.Body, maxArchiveSize+1))
+	if err == nil && n > maxArchiveSize {
+		err = fmt.Errorf("%s is too large", rawURL)
+	}
+	if err != nil {
+		return f.Name(), "", err
+	}
+	return f.Name(), hex.EncodeToString(h.Sum(nil)), nil
+}
+
+type progressWriter struct{ n *atomic.Int64 }
+
+func (p progressWriter) Write(b []byte) (int, error) {
+	p.n.Add(int64(len(b)))
+	return len(b), nil
+}
+
+// extract copies the zip's map files into mapDir, returning their names.
+func extract(archive, mapDir string, backup func([]string) error) ([]string, error) {
+	zr, err := zip.OpenReader(archive)
+	if err != nil {
+		return nil, fmt.Errorf("not a zip archive: %v", err)
+	}
+	defer zr.Close()
+
+	var files []*zip.File
+	var names, dests []string
+	seen := make(map[string]bool)
+	for _, f := range zr.File {
+		name := path.Base(strings.ReplaceAll(f.Name, "\\", "/"))
+		key := strings.ToLower(name)
+		if f.FileInfo().IsDir() || !strings.HasSuffix(key, ".txt") || strings.HasPrefix(name, ".") ||
+			strings.Contains(f.Name, "__MACOSX") || seen[key] {
+			continue
+		}
+		seen[key] = true
+		files = append(files, f)
+		names = append(names, name)
+		dests = append(dests, filepath.Join(mapDir, name))
+	}
+	if len(files) == 0 {
+		return nil, errors.New("the archive has no map files")
+	}
+	if err := os.MkdirAll(mapDir, 0755); err != nil {
+		return nil, err
+	}
+	if backup != nil {
+		if err := backup(dests); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, f := range files {
+		if err := extractFile(f, dests[i]); err != nil {
+			return names[:i], fmt.Errorf("%s: %v", names[i], err)
+		}
+	}
+	return names, nil
+}
+
+// extractFile writes one entry next to its destination and renames it into
+// place, so a failed install doesn't leave a half-written map.
+func extractFile(f *zip.File, dest string) error {
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	tmp := dest + ".download"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(out, io.LimitReader(r, maxMapFileSize+1))
+	if err == nil && n > maxMapFileSize {
+		err = errors.New("file too large")
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
@@ -0,0 +1,118 @@
+package mappack
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+)
+
+// packServer serves a zip of map files at /pack.zip and a manifest for it
+// at /manifest.json (/bare.json has no checksum). It returns the zip's
+// SHA-256.
+func packServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range map[string]string{
+		"maps/befallen.txt":   "L 0, 0, 0, 10, 10, 0, 0, 0, 0\n",
+		"maps/befallen_1.txt": "P 0, 0, 0, 255, 0, 0, 2, Entrance\n",
+		"readme.md":           "not a map",
+	} {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(data))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	h := sha256.Sum256(buf.Bytes())
+	sum := hex.EncodeToString(h[:])
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pack.zip", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write(buf.Bytes())
+	})
+	mux.HandleFunc("/manifest.json", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(rw, `{"name": "Test", "version": "2026-10-01", "url": "pack.zip", "sha256": %q}`, sum)
+	})
+	mux.HandleFunc("/bare.json", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `{"name": "Test", "version": "2026-10-01", "url": "pack.zip"}`)
+	})
+	s := httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s, sum
+}
+
+func install(src config.MapPackSource, mapDir, installed string, unverified bool) Result {
+	return (&Install{}).run(src, mapDir, installed, unverified, nil)
+}
+
+func TestInstall(t *testing.T) {
+	s, sum := packServer(t)
+	tests := []struct {
+		name       string
+		src        config.MapPackSource
+		unverified bool
+		wantErr    error
+		verified   bool
+	}{
+		{"manifest", config.MapPackSource{Name: "Test", URL: s.URL + "/manifest.json"}, false, nil, true},
+		{"zip with checksum", config.MapPackSource{Name: "Test", URL: s.URL + "/pack.zip", SHA256: sum}, false, nil, true},
+		{"zip with wrong checksum", config.MapPackSource{Name: "Test", URL: s.URL + "/pack.zip", SHA256: sum[1:] + "0"}, false, ErrChecksum, false},
+		{"zip without checksum", config.MapPackSource{Name: "Test", URL: s.URL + "/pack.zip"}, false, ErrUnverified, false},
+		{"manifest without checksum", config.MapPackSource{Name: "Test", URL: s.URL + "/bare.json"}, false, ErrUnverified, false},
+		{"unverified allowed", config.MapPackSource{Name: "Test", URL: s.URL + "/bare.json"}, true, nil, false},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		res := install(tt.src, dir, "", tt.unverified)
+		if !errors.Is(res.Err, tt.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tt.name, res.Err, tt.wantErr)
+		}
+		if res.Verified != tt.verified {
+			t.Errorf("%s: Verified = %v, want %v", tt.name, res.Verified, tt.verified)
+		}
+		entries, _ := os.ReadDir(dir)
+		if tt.wantErr != nil {
+			if len(entries) > 0 {
+				t.Errorf("%s: wrote %d files after failing", tt.name, len(entries))
+			}
+			continue
+		}
+		slices.Sort(res.Files)
+		if want := []string{"befallen.txt", "befallen_1.txt"}; !slices.Equal(res.Files, want) {
+			t.Errorf("%s: installed %v, want %v", tt.name, res.Files, want)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "befallen_1.txt")); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+	}
+}
+
+func TestInstallUpToDate(t *testing.T) {
+	s, _ := packServer(t)
+	res := install(config.MapPackSource{Name: "Test", URL: s.URL + "/manifest.json"}, t.TempDir(), "2026-10-01", false)
+	if res.Err != nil || !res.UpToDate {
+		t.Errorf("install of the installed version = %+v, want up to date", res)
+	}
+}
+
+func TestGetRefusesErrors(t *testing.T) {
+	s, _ := packServer(t)
+	if _, err := get(manifestClient, s.URL+"/missing.json"); err == nil {
+		t.Error("get of a missing file succeeded")
+	}
+}
@@ -0,0 +1,171 @@
+package maps
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Anything that writes into the EQ client's map files backs them up here
+// first. Each backup is a timestamped folder holding copies of the files and
+// a backup.json manifest saying where they came from, so a restore puts them
+// back even if the maps directory moved in the meantime.
+
+const (
+	backupManifest = "backup.json"
+	backupIDFormat = "20060102-150405.000"
+	maxBackups     = 50 // Oldest are deleted past this
+)
+
+// Backup is one set of files copied before a change.
+type Backup struct {
+	ID     string       `json:"-"` // Folder name
+	Time   time.Time    `json:"time"`
+	Reason string       `json:"reason"`
+	Files  []BackupFile `json:"files"`
+}
+
+type BackupFile struct {
+	Path    string `json:"path"`              // Original location, restored to
+	Copy    string `json:"copy,omitempty"`    // File name inside the backup folder
+	Created bool   `json:"created,omitempty"` // Didn't exist yet; restoring removes it
+}
+
+// BackupFiles copies paths into a new backup under root before they are
+// overwritten. Paths that don't exist yet are recorded as created.
+func BackupFiles(root, reason string, paths ...string) (*Backup, error) {
+	b := &Backup{Time: time.Now(), Reason: reason}
+	b.ID = b.Time.Format(backupIDFormat)
+	dir := filepath.Join(root, b.ID)
+	for n := 2; ; n++ {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			break
+		}
+		b.ID = fmt.Sprintf("%s-%d", b.Time.Format(backupIDFormat), n)
+		dir = filepath.Join(root, b.ID)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	for i, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		f := BackupFile{Path: abs}
+		if _, err := os.Stat(abs); os.IsNotExist(err) {
+			f.Created = true
+		} else {
+			// Numbered so two layers with the same name from different
+			// directories can't collide
+			f.Copy = fmt.Sprintf("%d_%s", i, filepath.Base(abs))
+			if err := copyFile(abs, filepath.Join(dir, f.Copy)); err != nil {
+				os.RemoveAll(dir)
+				return nil, fmt.Errorf("backing up %s: %w", filepath.Base(abs), err)
+			}
+		}
+		b.Files = append(b.Files, f)
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filepath.Join(dir, backupManifest), data, 0644)
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	pruneBackups(root)
+	return b, nil
+}
+
+// ListBackups returns the backups under root, newest first.
+func ListBackups(root string) ([]Backup, error) {
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []Backup
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(root, e.Name(), backupManifest))
+		if err != nil {
+			continue // Half-written or not ours
+		}
+		var b Backup
+		if json.Unmarshal(data, &b) != nil {
+			continue
+		}
+		b.ID = e.Name()
+		list = append(list, b)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Time.After(list[j].Time) })
+	return list, nil
+}
+
+// RestoreBackup puts the backed-up files back. The files as they are now are
+// backed up first, so a restore can itself be undone.
+func RestoreBackup(root string, b Backup) error {
+	paths := make([]string, len(b.Files))
+	for i, f := range b.Files {
+		paths[i] = f.Path
+	}
+	if _, err := BackupFiles(root, "Before restoring "+b.Time.Format("2006-01-02 15:04:05"), paths...); err != nil {
+		return err
+	}
+
+	dir := filepath.Join(root, b.ID)
+	for _, f := range b.Files {
+		if f.Created {
+			if err := os.Remove(f.Path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+			return err
+		}
+		if err := copyFile(filepath.Join(dir, f.Copy), f.Path); err != nil {
+			return fmt.Errorf("restoring %s: %w", filepath.Base(f.Path), err)
+		}
+	}
+	return nil
+}
+
+// pruneBackups deletes the oldest backups past maxBackups.
+func pruneBackups(root string) {
+	list, err := ListBackups(root)
+	if err != nil || len(list) <= maxBackups {
+		return
+	}
+	for _, b := range list[maxBackups:] {
+		os.RemoveAll(filepath.Join(root, b.ID))
+	}
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,108 @@
+package maps
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// The map set compiled into the binary sits under the maps directory: a
+// file in the directory replaces the built-in one with the same name (case
+// ignored), so edits, map packs and hand-copied maps win and the built-in
+// set only fills the gaps. Built-in files are named by BuiltinPrefix plus
+// their name, and are read through Open / ReadFile / Stat.
+
+// BuiltinPrefix marks a path into Builtin rather than the filesystem.
+const BuiltinPrefix = "builtin:"
+
+// Builtin is the embedded map set (see assets.Maps); nil if there is none.
+var Builtin fs.FS
+
+// IsBuiltin reports whether path names a built-in file.
+func IsBuiltin(path string) bool {
+	return strings.HasPrefix(path, BuiltinPrefix)
+}
+
+// fileName is a map path's file name, built-in or not.
+func fileName(p string) string {
+	if IsBuiltin(p) {
+		return path.Base(strings.TrimPrefix(p, BuiltinPrefix))
+	}
+	return filepath.Base(p)
+}
+
+// Open opens a map directory path or a built-in one.
+func Open(p string) (io.ReadCloser, error) {
+	if IsBuiltin(p) {
+		if Builtin == nil {
+			return nil, &fs.PathError{Op: "open", Path: p, Err: fs.ErrNotExist}
+		}
+		return Builtin.Open(strings.TrimPrefix(p, BuiltinPrefix))
+	}
+	return os.Open(p)
+}
+
+// ReadFile reads a map directory path or a built-in one.
+func ReadFile(p string) ([]byte, error) {
+	f, err := Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// Stat describes a map directory path or a built-in one.
+func Stat(p string) (fs.FileInfo, error) {
+	if IsBuiltin(p) {
+		if Builtin == nil {
+			return nil, &fs.PathError{Op: "stat", Path: p, Err: fs.ErrNotExist}
+		}
+		return fs.Stat(Builtin, strings.TrimPrefix(p, BuiltinPrefix))
+	}
+	return os.Stat(p)
+}
+
+// Locate returns the path of a file in the maps directory, else of the
+// built-in copy. With neither it returns the directory path, so reading it
+// fails with a not-exist error.
+func Locate(mapDir, name string) string {
+	p := filepath.Join(mapDir, name)
+	if _, err := os.Stat(p); err != nil && Builtin != nil {
+		if _, err := fs.Stat(Builtin, name); err == nil {
+			return BuiltinPrefix + name
+		}
+	}
+	return p
+}
+
+// listFiles globs the maps directory and the built-in set, a directory file
+// hiding the built-in one with the same name.
+func listFiles(mapDir, pattern string) ([]string, error) {
+	dirFiles, err := filepath.Glob(filepath.Join(mapDir, pattern))
+	if err != nil {
+		return nil, err
+	}
+	if Builtin == nil {
+		return dirFiles, nil
+	}
+	builtin, err := fs.Glob(Builtin, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	have := make(map[string]bool, len(dirFiles))
+	for _, p := range dirFiles {
+		have[strings.ToLower(filepath.Base(p))] = true
+	}
+	files := dirFiles
+	for _, name := range builtin {
+		if !have[strings.ToLower(name)] {
+			files = append(files, BuiltinPrefix+name)
+		}
+	}
+	return files, nil
+}
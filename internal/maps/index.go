@@ -0,0 +1,177 @@
+package maps
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ZoneBounds is the index entry for one zone: the extent of its map (all
+// layers, map space) and every label it contains.
+type ZoneBounds struct {
+	FileCode               string
+	MinX, MaxX, MinY, MaxY float64
+	Labels                 []MapLabel `json:",omitempty"`
+
+	// Size/mtime fingerprint of the zone's files; an entry is only
+	// re-parsed when this changes.
+	Stamp string
+}
+
+// Contains reports whether a point lies within the bounds, grown by margin.
+func (b ZoneBounds) Contains(x, y, margin float64) bool {
+	return x >= b.MinX-margin && x <= b.MaxX+margin && y >= b.MinY-margin && y <= b.MaxY+margin
+}
+
+func (b ZoneBounds) Area() float64 {
+	return (b.MaxX - b.MinX) * (b.MaxY - b.MinY)
+}
+
+// BoundsIndex holds the bounds and labels of every zone in a map directory.
+type BoundsIndex struct {
+	Zones []ZoneBounds
+}
+
+var layerSuffix = regexp.MustCompile(`(?i)_[1-3]\.txt$`)
+
+// ZoneCodes lists the zone file codes present in a map directory (a zone
+// counts even if only its layer files exist).
+func ZoneCodes(mapDir string) ([]string, error) {
+	files, err := listFiles(mapDir, "*.txt")
+	if err != nil {
+		return nil, fmt.Errorf("could not list map directory: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	var codes []string
+	for _, path := range files {
+		name := fileName(path)
+		code := layerSuffix.ReplaceAllString(name, "")
+		code = strings.TrimSuffix(code, filepath.Ext(code))
+		key := strings.ToLower(code)
+		if !seen[key] {
+			seen[key] = true
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	return codes, nil
+}
+
+// BuildBoundsIndex parses every zone in mapDir and records its bounds.
+// Entries from previous (typically a cached index) are reused when the
+// zone's files haven't changed. progress, if set, is called after each zone.
+func BuildBoundsIndex(mapDir string, previous *BoundsIndex, progress func(done, total int)) (*BoundsIndex, error) {
+	codes, err := ZoneCodes(mapDir)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := make(map[string]ZoneBounds)
+	if previous != nil {
+		for _, b := range previous.Zones {
+			cached[strings.ToLower(b.FileCode)] = b
+		}
+	}
+
+	idx := &BoundsIndex{}
+	for i, code := range codes {
+		paths, _, err := zoneFiles(mapDir, code)
+		if err == nil && len(paths) > 0 {
+			stamp := filesStamp(paths)
+			if b, ok := cached[strings.ToLower(code)]; ok && b.Stamp == stamp {
+				idx.Zones = append(idx.Zones, b)
+			} else if b, ok := zoneBounds(code, paths); ok {
+				b.Stamp = stamp
+				idx.Zones = append(idx.Zones, b)
+			}
+		}
+		if progress != nil {
+			progress(i+1, len(codes))
+		}
+	}
+	return idx, nil
+}
+
+// zoneBounds loads a zone quietly (LoadZone logs every file) and returns its entry.
+func zoneBounds(code string, paths []string) (ZoneBounds, bool) {
+	zm := parseZoneFiles(code, paths)
+	if len(zm.Lines) == 0 {
+		return ZoneBounds{}, false
+	}
+	return ZoneBounds{
+		FileCode: code,
+		MinX:     zm.MinX,
+		MaxX:     zm.MaxX,
+		MinY:     zm.MinY,
+		MaxY:     zm.MaxY,
+		Labels:   zm.Labels,
+	}, true
+}
+
+func filesStamp(paths []string) string {
+	var b strings.Builder
+	for _, path := range paths {
+		if fi, err := Stat(path); err == nil {
+			fmt.Fprintf(&b, "%s:%d:%d;", filepath.Base(path), fi.Size(), fi.ModTime().UnixNano())
+		}
+	}
+	return b.String()
+}
+
+// Containing returns the zones whose bounds (grown by margin) contain the
+// point, tightest fit first.
+func (idx *BoundsIndex) Containing(x, y, margin float64) []ZoneBounds {
+	var matches []ZoneBounds
+	for _, b := range idx.Zones {
+		if b.Contains(x, y, margin) {
+			matches = append(matches, b)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Area() < matches[j].Area()
+	})
+	return matches
+}
+
+// MissingZones lists zones from the lookup table that have no map files in
+// the index, as "zone name (file code)", sorted.
+func (idx *BoundsIndex) MissingZones() []string {
+	have := make(map[string]bool)
+	for _, b := range idx.Zones {
+		have[strings.ToLower(b.FileCode)] = true
+	}
+	var missing []string
+	for name, code := range ZoneFileMap {
+		if !have[strings.ToLower(code)] {
+			missing = append(missing, fmt.Sprintf("%s (%s)", name, code))
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// LoadBoundsIndex reads a cached index; nil if there is none.
+func LoadBoundsIndex(path string) *BoundsIndex {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var idx BoundsIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil
+	}
+	return &idx
+}
+
+func (idx *BoundsIndex) Save(path string) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
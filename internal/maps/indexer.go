@@ -0,0 +1,44 @@
+package maps
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Indexer builds the zone index in a background goroutine, starting from
+// the on-disk cache so only new or edited zones are parsed.
+type Indexer struct {
+	Done chan *BoundsIndex // Receives the finished index once
+
+	done, total atomic.Int32
+}
+
+// StartIndexer begins (re)building the index for mapDir; cachePath is read
+// first and rewritten when the build finishes.
+func StartIndexer(mapDir, cachePath string) *Indexer {
+	ix := &Indexer{Done: make(chan *BoundsIndex, 1)}
+	go func() {
+		cached := LoadBoundsIndex(cachePath)
+		idx, err := BuildBoundsIndex(mapDir, cached, func(done, total int) {
+			ix.done.Store(int32(done))
+			ix.total.Store(int32(total))
+		})
+		if err != nil {
+			fmt.Printf("❌ Error indexing maps: %v\n", err)
+			idx = cached
+			if idx == nil {
+				idx = &BoundsIndex{}
+			}
+		} else if err := idx.Save(cachePath); err != nil {
+			fmt.Printf("⚠️  Could not cache zone index: %v\n", err)
+		}
+		fmt.Printf("🗂️  Zone index ready: %d zones\n", len(idx.Zones))
+		ix.Done <- idx
+	}()
+	return ix
+}
+
+// Progress reports how many zones have been processed so far.
+func (ix *Indexer) Progress() (done, total int) {
+	return int(ix.done.Load()), int(ix.total.Load())
+}
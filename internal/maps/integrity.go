@@ -0,0 +1,136 @@
+package maps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// MapProblem is one map file the integrity scan flagged.
+type MapProblem struct {
+	Path    string
+	Problem string
+}
+
+// IntegrityReport summarizes a map directory: what's there, what can't be
+// read, and which known zones have no map at all.
+type IntegrityReport struct {
+	MapDir        string
+	Zones, Files  int
+	Lines, Labels int
+	Broken        []MapProblem // Unreadable, or has content but nothing usable
+	Warnings      []MapProblem // Usable, but some lines were skipped
+	Missing       []string     // Known zones without files, "zone name (file code)"
+}
+
+// CheckMaps parses every map file in mapDir and the built-in set it
+// doesn't replace; only the directory's files are reported as broken.
+// progress, if set, is called after each file.
+func CheckMaps(mapDir string, progress func(done, total int)) (*IntegrityReport, error) {
+	files, err := listFiles(mapDir, "*.txt")
+	if err != nil {
+		return nil, fmt.Errorf("could not list map directory: %v", err)
+	}
+	sort.Strings(files)
+
+	report := &IntegrityReport{MapDir: mapDir, Files: len(files)}
+	have := make(map[string]bool)
+	for i, path := range files {
+		zm := &ZoneMap{MinX: 99999, MaxX: -99999, MinY: 99999, MaxY: -99999}
+		_, err := zm.parseFile(path, 0)
+		switch {
+		case IsBuiltin(path) && len(zm.Lines)+len(zm.Labels) == 0:
+			// Nothing to fix or move; a file in the directory replaces it
+		case err != nil && len(zm.Lines)+len(zm.Labels) == 0:
+			report.Broken = append(report.Broken, MapProblem{path, err.Error()})
+		case len(zm.Lines)+len(zm.Labels) == 0 && zm.Skipped > 0:
+			report.Broken = append(report.Broken, MapProblem{path, "no readable lines or labels"})
+		case len(zm.Lines)+len(zm.Labels) == 0:
+			// Empty file, e.g. a layer blanked on purpose
+		default:
+			switch {
+			case IsBuiltin(path):
+				// Only files that can be edited get warnings
+			case err != nil:
+				report.Warnings = append(report.Warnings, MapProblem{path, "stopped early: " + err.Error()})
+			case zm.Skipped > 0:
+				report.Warnings = append(report.Warnings, MapProblem{path, fmt.Sprintf("%d unreadable lines", zm.Skipped)})
+			}
+			code := layerSuffix.ReplaceAllString(fileName(path), "")
+			have[lowerCode(code)] = true
+		}
+		report.Lines += len(zm.Lines)
+		report.Labels += len(zm.Labels)
+		if progress != nil {
+			progress(i+1, len(files))
+		}
+	}
+	report.Zones = len(have)
+
+	for name, code := range ZoneFileMap {
+		if !have[lowerCode(code)] {
+			report.Missing = append(report.Missing, fmt.Sprintf("%s (%s)", name, code))
+		}
+	}
+	sort.Strings(report.Missing)
+	return report, nil
+}
+
+// lowerCode normalizes a file code or file name for comparison.
+func lowerCode(code string) string {
+	return strings.ToLower(strings.TrimSuffix(code, filepath.Ext(code)))
+}
+
+// MoveBroken moves the report's broken files into a "broken" folder in the
+// map directory (rather than deleting them) and returns how many moved.
+func (r *IntegrityReport) MoveBroken() (int, error) {
+	if len(r.Broken) == 0 {
+		return 0, nil
+	}
+	dir := filepath.Join(r.MapDir, "broken")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, err
+	}
+	moved := 0
+	for _, p := range r.Broken {
+		if err := os.Rename(p.Path, filepath.Join(dir, filepath.Base(p.Path))); err != nil {
+			return moved, err
+		}
+		moved++
+	}
+	r.Broken = nil
+	return moved, nil
+}
+
+// IntegrityScan runs CheckMaps in a background goroutine.
+type IntegrityScan struct {
+	Done chan *IntegrityReport // Receives the report once (nil on failure)
+
+	done, total atomic.Int32
+}
+
+func StartIntegrityScan(mapDir string) *IntegrityScan {
+	s := &IntegrityScan{Done: make(chan *IntegrityReport, 1)}
+	go func() {
+		report, err := CheckMaps(mapDir, func(done, total int) {
+			s.done.Store(int32(done))
+			s.total.Store(int32(total))
+		})
+		if err != nil {
+			fmt.Printf("❌ Error checking maps: %v\n", err)
+		} else {
+			fmt.Printf("🩺 Map check: %d zones in %d files, %d broken, %d with warnings, %d known zones missing\n",
+				report.Zones, report.Files, len(report.Broken), len(report.Warnings), len(report.Missing))
+		}
+		s.Done <- report
+	}()
+	return s
+}
+
+// Progress reports how many files have been checked so far.
+func (s *IntegrityScan) Progress() (done, total int) {
+	return int(s.done.Load()), int(s.total.Load())
+}
@@ -0,0 +1,109 @@
+package maps
+
+import "math"
+
+// LineGrid buckets a zone's lines by the square cells they pass through,
+// so questions about one area only look at the lines near it.
+type LineGrid struct {
+	lines      []MapLine
+	minX, minY float64
+	cell       float64
+	cols, rows int
+	cells      [][]int32 // Line indexes, row-major
+
+	// Query marks lines it has returned so a line spanning several cells
+	// is reported once; gen avoids clearing marks between queries.
+	marks []uint32
+	gen   uint32
+}
+
+// NewLineGrid indexes lines in cells of the given size (map units). The
+// grid keeps the slice; it must not change while the grid is in use.
+func NewLineGrid(lines []MapLine, cell float64) *LineGrid {
+	g := &LineGrid{lines: lines, cell: cell, marks: make([]uint32, len(lines))}
+	if len(lines) == 0 {
+		return g
+	}
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, l := range lines {
+		minX, maxX = min(minX, l.X1, l.X2), max(maxX, l.X1, l.X2)
+		minY, maxY = min(minY, l.Y1, l.Y2), max(maxY, l.Y1, l.Y2)
+	}
+	g.minX, g.minY = minX, minY
+	g.cols = int((maxX-minX)/cell) + 1
+	g.rows = int((maxY-minY)/cell) + 1
+	g.cells = make([][]int32, g.cols*g.rows)
+
+	for i, l := range lines {
+		// Every cell in the line's bounding box: a few extra for diagonals,
+		// but map lines are short
+		c1, r1 := g.cellOf(min(l.X1, l.X2), min(l.Y1, l.Y2))
+		c2, r2 := g.cellOf(max(l.X1, l.X2), max(l.Y1, l.Y2))
+		for r := r1; r <= r2; r++ {
+			for c := c1; c <= c2; c++ {
+				g.cells[r*g.cols+c] = append(g.cells[r*g.cols+c], int32(i))
+			}
+		}
+	}
+	return g
+}
+
+// cellOf returns the cell containing a point, clamped to the grid.
+func (g *LineGrid) cellOf(x, y float64) (col, row int) {
+	col = min(max(int((x-g.minX)/g.cell), 0), g.cols-1)
+	row = min(max(int((y-g.minY)/g.cell), 0), g.rows-1)
+	return col, row
+}
+
+// Query calls fn once for each line that may lie in the rectangle (every
+// line sharing a cell with it).
+func (g *LineGrid) Query(minX, minY, maxX, maxY float64, fn func(i int, l MapLine)) {
+	if len(g.cells) == 0 {
+		return
+	}
+	g.gen++
+	if g.gen == 0 { // Wrapped: old marks could match again
+		clear(g.marks)
+		g.gen = 1
+	}
+	c1, r1 := g.cellOf(minX, minY)
+	c2, r2 := g.cellOf(maxX, maxY)
+	for r := r1; r <= r2; r++ {
+		for c := c1; c <= c2; c++ {
+			for _, i := range g.cells[r*g.cols+c] {
+				if g.marks[i] == g.gen {
+					continue
+				}
+				g.marks[i] = g.gen
+				fn(int(i), g.lines[i])
+			}
+		}
+	}
+}
+
+// Crossings returns the lines that the segment from (x1, y1) to (x2, y2)
+// properly crosses (touching an end doesn't count), among those keep accepts.
+func (g *LineGrid) Crossings(x1, y1, x2, y2 float64, keep func(l MapLine) bool) []int {
+	var hits []int
+	g.Query(min(x1, x2), min(y1, y2), max(x1, x2), max(y1, y2), func(i int, l MapLine) {
+		if (keep == nil || keep(l)) && SegmentsCross(x1, y1, x2, y2, l.X1, l.Y1, l.X2, l.Y2) {
+			hits = append(hits, i)
+		}
+	})
+	return hits
+}
+
+// SegmentsCross reports whether segments AB and CD cross at a single point
+// strictly inside both.
+func SegmentsCross(ax, ay, bx, by, cx, cy, dx, dy float64) bool {
+	d1 := cross(cx, cy, dx, dy, ax, ay)
+	d2 := cross(cx, cy, dx, dy, bx, by)
+	d3 := cross(ax, ay, bx, by, cx, cy)
+	d4 := cross(ax, ay, bx, by, dx, dy)
+	return d1*d2 < 0 && d3*d4 < 0
+}
+
+// cross is the z component of (q-p) x (r-p): which side of line pq r is on.
+func cross(px, py, qx, qy, rx, ry float64) float64 {
+	return (qx-px)*(ry-py) - (qy-py)*(rx-px)
+}
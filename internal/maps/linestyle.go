@@ -0,0 +1,100 @@
+package maps
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// LineKind is what a map line depicts. Map files only carry a color, so the
+// kind comes from the color conventions packs share (see SetColorKinds).
+type LineKind uint8
+
+const (
+	KindWall     LineKind = iota // Walls, terrain and any color without a convention
+	KindWater                    // Shorelines, rivers, lakes
+	KindZoneLine                 // Where the zone connects to the next
+)
+
+// LineStyle is how a line is stroked.
+type LineStyle uint8
+
+const (
+	StyleSolid LineStyle = iota
+	StyleDashed
+)
+
+var lineKindNames = map[string]LineKind{
+	"wall":      KindWall,
+	"water":     KindWater,
+	"zone_line": KindZoneLine,
+}
+
+// defaultColorKinds are the conventions of the Brewall-style sets: pure blue
+// for water and pure red for zone lines (the same red as "to ..." labels).
+var defaultColorKinds = map[color.RGBA]LineKind{
+	{0, 0, 255, 255}: KindWater,
+	{255, 0, 0, 255}: KindZoneLine,
+}
+
+// colorKinds is read while maps load; SetColorKinds replaces it at startup.
+var colorKinds = defaultColorKinds
+
+// SetColorKinds lays a color -> kind table over the defaults, for packs
+// with other conventions. Colors are "#rrggbb" or "r, g, b" as in map
+// files; kinds are "water", "zone_line" or "wall" (the last drops a
+// default). Maps loaded afterwards use it. Bad entries are reported and
+// skipped.
+func SetColorKinds(kinds map[string]string) []error {
+	merged := make(map[color.RGBA]LineKind, len(defaultColorKinds)+len(kinds))
+	for c, k := range defaultColorKinds {
+		merged[c] = k
+	}
+	var errs []error
+	for spec, name := range kinds {
+		c, err := parseColorSpec(spec)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		kind, ok := lineKindNames[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%q: unknown line kind %q (use water, zone_line or wall)", spec, name))
+			continue
+		}
+		if kind == KindWall {
+			delete(merged, c)
+		} else {
+			merged[c] = kind
+		}
+	}
+	colorKinds = merged
+	return errs
+}
+
+func parseColorSpec(spec string) (color.RGBA, error) {
+	spec = strings.TrimSpace(spec)
+	var r, g, b uint8
+	if hex, ok := strings.CutPrefix(spec, "#"); ok {
+		if _, err := fmt.Sscanf(hex, "%02x%02x%02x", &r, &g, &b); err == nil && len(hex) == 6 {
+			return color.RGBA{r, g, b, 255}, nil
+		}
+	} else if _, err := fmt.Sscanf(spec, "%d,%d,%d", &r, &g, &b); err == nil {
+		return color.RGBA{r, g, b, 255}, nil
+	}
+	return color.RGBA{}, fmt.Errorf("%q is not a color (use #rrggbb or r, g, b)", spec)
+}
+
+// KindOf is the kind of a line drawn in color c.
+func KindOf(c color.RGBA) LineKind {
+	return colorKinds[color.RGBA{c.R, c.G, c.B, 255}]
+}
+
+// Style is how lines of the kind are drawn: zone lines dashed, the rest
+// solid.
+func (k LineKind) Style() LineStyle {
+	if k == KindZoneLine {
+		return StyleDashed
+	}
+	return StyleSolid
+}
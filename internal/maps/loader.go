@@ -15,6 +15,9 @@ type MapLine struct {
 	X1, Y1, Z1 float64
 	X2, Y2, Z2 float64
 	Color      color.RGBA
+	Layer      int       // File it came from: 0 = base, 1-3 = _1/_2/_3
+	Kind       LineKind  // From the color (see linestyle.go)
+	Style      LineStyle // From the kind
 }
 
 type MapLabel struct {
@@ -22,12 +25,15 @@ type MapLabel struct {
 	Color   color.RGBA
 	Size    int
 	Text    string
+	Layer   int
 }
 
 type ZoneMap struct {
-	Name   string
-	Lines  []MapLine
-	Labels []MapLabel
+	Name       string
+	Lines      []MapLine
+	Labels     []MapLabel
+	Layers     []int // Layers that had at least one item, ascending
+	Skipped    int   // Non-empty lines that weren't a usable L or P entry
 	MinX, MaxX float64
 	MinY, MaxY float64
 }
@@ -41,42 +47,28 @@ func LoadZone(mapDir, zoneName string) (*ZoneMap, error) {
 		MinY: 99999, MaxY: -99999,
 	}
 
-	// 1. Build a case-insensitive map of all files in the directory
-	// This ensures we find "EastKarana.txt" even if we ask for "eastkarana.txt"
-	globPattern := filepath.Join(mapDir, "*")
-	allFiles, err := filepath.Glob(globPattern)
+	// 1-2. Find the base file and layers 1-3 (case-insensitive)
+	paths, allFiles, err := zoneFiles(mapDir, zoneName)
 	if err != nil {
-		return nil, fmt.Errorf("could not list map directory: %v", err)
-	}
-
-	fileMap := make(map[string]string)
-	for _, path := range allFiles {
-		filename := filepath.Base(path)
-		lower := strings.ToLower(filename)
-		fileMap[lower] = path
-	}
-
-	// 2. Identify target files (Base + Layers 1-3)
-	targets := []string{
-		strings.ToLower(fmt.Sprintf("%s.txt", zoneName)),
-		strings.ToLower(fmt.Sprintf("%s_1.txt", zoneName)),
-		strings.ToLower(fmt.Sprintf("%s_2.txt", zoneName)),
-		strings.ToLower(fmt.Sprintf("%s_3.txt", zoneName)),
+		return nil, err
 	}
 
 	// 3. Load them
 	foundAtLeastOne := false
-	for _, target := range targets {
-		if realPath, exists := fileMap[target]; exists {
-			fmt.Printf("📄 Parsing: %s ... ", filepath.Base(realPath))
-			itemsAdded, err := zm.parseFile(realPath)
-			if err == nil && itemsAdded > 0 {
-				foundAtLeastOne = true
-				fmt.Printf("OK (%d items)\n", itemsAdded)
-			} else {
-				// Don't panic, just report
-				fmt.Printf("Found 0 valid items. (might be empty or bad format)\n")
+	for _, realPath := range paths {
+		fmt.Printf("📄 Parsing: %s ... ", filepath.Base(realPath))
+		layer := layerOf(realPath, zoneName)
+		itemsAdded, err := zm.parseFile(realPath, layer)
+		if itemsAdded > 0 { // A read error part way through keeps what was read
+			foundAtLeastOne = true
+			zm.Layers = append(zm.Layers, layer)
+			fmt.Printf("OK (%d items)\n", itemsAdded)
+			if err != nil {
+				fmt.Printf("⚠️  Stopped reading %s early: %v\n", filepath.Base(realPath), err)
 			}
+		} else {
+			// Don't panic, just report
+			fmt.Printf("Found 0 valid items. (might be empty or bad format)\n")
 		}
 	}
 
@@ -96,8 +88,96 @@ func LoadZone(mapDir, zoneName string) (*ZoneMap, error) {
 	return zm, nil
 }
 
-func (zm *ZoneMap) parseFile(path string) (int, error) {
-	f, err := os.Open(path)
+// LoadFile parses a single map file (e.g. a community POI set) on its own.
+func LoadFile(path string) (*ZoneMap, error) {
+	zm := &ZoneMap{
+		Name: filepath.Base(path),
+		MinX: 99999, MaxX: -99999,
+		MinY: 99999, MaxY: -99999,
+	}
+	if _, err := zm.parseFile(path, 0); err != nil {
+		return nil, err
+	}
+	return zm, nil
+}
+
+// LoadZoneQuiet is LoadZone without the console report, for code that
+// loads many zones.
+func LoadZoneQuiet(mapDir, zoneName string) (*ZoneMap, error) {
+	paths, _, err := zoneFiles(mapDir, zoneName)
+	if err != nil {
+		return nil, err
+	}
+	zm := parseZoneFiles(zoneName, paths)
+	if len(zm.Lines) == 0 && len(zm.Labels) == 0 {
+		return nil, fmt.Errorf("no map files found for zone: %s", zoneName)
+	}
+	return zm, nil
+}
+
+// parseZoneFiles reads the files zoneFiles found, ignoring errors.
+func parseZoneFiles(zoneName string, paths []string) *ZoneMap {
+	zm := &ZoneMap{Name: zoneName, MinX: 99999, MaxX: -99999, MinY: 99999, MaxY: -99999}
+	for _, path := range paths {
+		layer := layerOf(path, zoneName)
+		if n, _ := zm.parseFile(path, layer); n > 0 {
+			zm.Layers = append(zm.Layers, layer)
+		}
+	}
+	return zm
+}
+
+// zoneFileNames are the lowercased names of a zone's base file and layers 1-3.
+func zoneFileNames(zoneName string) []string {
+	return []string{
+		strings.ToLower(fmt.Sprintf("%s.txt", zoneName)),
+		strings.ToLower(fmt.Sprintf("%s_1.txt", zoneName)),
+		strings.ToLower(fmt.Sprintf("%s_2.txt", zoneName)),
+		strings.ToLower(fmt.Sprintf("%s_3.txt", zoneName)),
+	}
+}
+
+// zoneFiles returns the existing files for a zone (base + layers 1-3), plus
+// everything in the directory for diagnostics.
+func zoneFiles(mapDir, zoneName string) ([]string, []string, error) {
+	// 1. Build a case-insensitive map of all files in the directory
+	// This ensures we find "EastKarana.txt" even if we ask for "eastkarana.txt"
+	// (built-in files included, see builtin.go)
+	allFiles, err := listFiles(mapDir, "*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not list map directory: %v", err)
+	}
+
+	fileMap := make(map[string]string)
+	for _, path := range allFiles {
+		filename := fileName(path)
+		lower := strings.ToLower(filename)
+		fileMap[lower] = path
+	}
+
+	// 2. Identify target files (Base + Layers 1-3)
+	var paths []string
+	for _, target := range zoneFileNames(zoneName) {
+		if realPath, exists := fileMap[target]; exists {
+			paths = append(paths, realPath)
+		}
+	}
+	return paths, allFiles, nil
+}
+
+// layerOf tells which layer a file returned by zoneFiles holds.
+func layerOf(path, zoneName string) int {
+	name := strings.ToLower(fileName(path))
+	for layer := 1; layer <= 3; layer++ {
+		if name == strings.ToLower(fmt.Sprintf("%s_%d.txt", zoneName, layer)) {
+			return layer
+		}
+	}
+	return 0
+}
+
+func (zm *ZoneMap) parseFile(path string, layer int) (int, error) {
+	f, err := Open(path)
 	if err != nil {
 		return 0, err
 	}
@@ -128,7 +208,7 @@ func (zm *ZoneMap) parseFile(path string) (int, error) {
 			}
 		}
 
-		if cmdIndex == -1 { continue } // No command found
+		if cmdIndex == -1 { zm.Skipped++; continue } // No command found
 
 		// Extract the useful part: "123.45, 67.89, ..."
 		// We skip the command char (L/P) and any leading junk
@@ -150,16 +230,21 @@ func (zm *ZoneMap) parseFile(path string) (int, error) {
 				l := MapLine{
 					X1: x1, Y1: y1, Z1: z1,
 					X2: x2, Y2: y2, Z2: z2,
+					Layer: layer,
 				}
 				if len(parts) >= 9 {
 					l.Color = parseColor(parts[6], parts[7], parts[8])
 				} else {
 					l.Color = color.RGBA{150, 150, 150, 255}
 				}
+				l.Kind = KindOf(l.Color)
+				l.Style = l.Kind.Style()
 				zm.Lines = append(zm.Lines, l)
 				zm.updateBounds(l.X1, l.Y1)
 				zm.updateBounds(l.X2, l.Y2)
 				count++
+			} else {
+				zm.Skipped++
 			}
 		} else if cmdType == 'P' {
 			// EQ Map Format: X, Y, Z, R, G, B, size, text...
@@ -173,6 +258,7 @@ func (zm *ZoneMap) parseFile(path string) (int, error) {
 					X: x, Y: y, Z: z,
 					Color: parseColor(parts[3], parts[4], parts[5]),
 					Size:  parseInt(parts[6]),
+					Layer: layer,
 				}
 				if len(parts) >= 8 {
 					p.Text = strings.TrimSpace(strings.Join(parts[7:], ","))
@@ -181,10 +267,12 @@ func (zm *ZoneMap) parseFile(path string) (int, error) {
 				}
 				zm.Labels = append(zm.Labels, p)
 				count++
+			} else {
+				zm.Skipped++
 			}
 		}
 	}
-	return count, nil
+	return count, scanner.Err()
 }
 
 func (zm *ZoneMap) updateBounds(x, y float64) {
@@ -212,4 +300,60 @@ func parseColor(r, g, b string) color.RGBA {
 		return color.RGBA{130, 130, 130, 255}
 	}
 	return color.RGBA{uint8(ri), uint8(gi), uint8(bi), 255}
-}
\ No newline at end of file
+}
+// WriteLayer saves lines in the standard EQ map format ("L x1, y1, z1, x2, y2, z2, r, g, b"),
+// so they can be dropped next to a zone's files as an extra layer.
+func WriteLayer(path string, lines []MapLine) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	for _, l := range lines {
+		fmt.Fprintf(w, "L %.4f, %.4f, %.4f, %.4f, %.4f, %.4f, %d, %d, %d\n",
+			l.X1, l.Y1, l.Z1, l.X2, l.Y2, l.Z2, l.Color.R, l.Color.G, l.Color.B)
+	}
+	return w.Flush()
+}
+
+// WriteZoneFile saves a complete map file (lines and labels) in the standard
+// EQ format. Label text gets its underscores back.
+func WriteZoneFile(path string, lines []MapLine, labels []MapLabel) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	for _, l := range lines {
+		fmt.Fprintf(w, "L %.4f, %.4f, %.4f, %.4f, %.4f, %.4f, %d, %d, %d\n",
+			l.X1, l.Y1, l.Z1, l.X2, l.Y2, l.Z2, l.Color.R, l.Color.G, l.Color.B)
+	}
+	for _, p := range labels {
+		fmt.Fprintf(w, "P %.4f, %.4f, %.4f, %d, %d, %d, %d, %s\n",
+			p.X, p.Y, p.Z, p.Color.R, p.Color.G, p.Color.B, p.Size, strings.ReplaceAll(p.Text, " ", "_"))
+	}
+	return w.Flush()
+}
+
+// LayerPath returns the file a zone layer is stored in: the existing file
+// if there is one (whatever its case), else where it would be created. A
+// built-in layer is written to the directory, where it replaces that one.
+func LayerPath(mapDir, zoneName string, layer int) string {
+	paths, _, _ := zoneFiles(mapDir, zoneName)
+	for _, p := range paths {
+		if layerOf(p, zoneName) == layer {
+			if IsBuiltin(p) {
+				return filepath.Join(mapDir, fileName(p))
+			}
+			return p
+		}
+	}
+	if layer == 0 {
+		return filepath.Join(mapDir, zoneName+".txt")
+	}
+	return filepath.Join(mapDir, fmt.Sprintf("%s_%d.txt", zoneName, layer))
+}
@@ -2,14 +2,18 @@ package maps
 
 import (
 	"encoding/json"
-	"os"
 	"strings"
+	"unicode"
 )
 
 var ZoneFileMap = make(map[string]string)
 
+// zoneNames keeps a long name per file code (lowercased) as written in the
+// lookup file, since ZoneFileMap's keys are lowercased.
+var zoneNames = make(map[string]string)
+
 func LoadZoneConfig(path string) error {
-	file, err := os.Open(path)
+	file, err := Open(path)
 	if err != nil {
 		return err
 	}
@@ -22,13 +26,114 @@ func LoadZoneConfig(path string) error {
 
 	for k, v := range rawMap {
 		ZoneFileMap[strings.ToLower(k)] = v
+		zoneNames[strings.ToLower(v)] = k
 	}
 	return nil
 }
 
+// GetZoneFileName is the file code for a zone name, or "" if unknown. Names
+// that aren't a key exactly are matched leniently (see fuzzyZoneFileName).
 func GetZoneFileName(zoneName string) string {
 	if val, ok := ZoneFileMap[strings.ToLower(zoneName)]; ok {
 		return val
 	}
-	return ""
-}
\ No newline at end of file
+	return fuzzyZoneFileName(zoneName)
+}
+
+// GetZoneName is the reverse of GetZoneFileName: the long zone name for a
+// file code ("" if unknown).
+func GetZoneName(fileCode string) string {
+	return zoneNames[strings.ToLower(fileCode)]
+}
+
+// CanonicalZoneName turns a zone as some servers report it (the file code
+// or short name, "qeynos2") into its long name ("North Qeynos"). Names
+// already in the lookup, and ones it doesn't know, are returned as is.
+func CanonicalZoneName(zone string) string {
+	if _, ok := ZoneFileMap[strings.ToLower(zone)]; ok {
+		return zone
+	}
+	if name := GetZoneName(zone); name != "" {
+		return name
+	}
+	return zone
+}
+
+// fuzzyZoneFileName matches a name the lookup doesn't have word for word:
+// first ignoring case, punctuation and articles ("The Ruins of Old
+// Paineel" is "Ruins of Old Paineel"), then allowing a typo or two in
+// longer names. Only an unambiguous match counts.
+func fuzzyZoneFileName(zoneName string) string {
+	name := normalizeZoneName(zoneName)
+	if name == "" {
+		return ""
+	}
+
+	maxDist := 0
+	switch {
+	case len(name) >= 12:
+		maxDist = 2
+	case len(name) >= 6:
+		maxDist = 1
+	}
+	best, bestDist, tied := "", maxDist+1, false
+	for known, code := range ZoneFileMap {
+		knownName := normalizeZoneName(known)
+		if knownName == "" || knownName[0] != name[0] {
+			continue // "Eastern Wastes" is not a typo of "Western Wastes"
+		}
+		d := EditDistance(name, knownName)
+		switch {
+		case d < bestDist:
+			best, bestDist, tied = code, d, false
+		case d == bestDist && !strings.EqualFold(code, best):
+			tied = true
+		}
+	}
+	if tied {
+		return ""
+	}
+	return best
+}
+
+// normalizeZoneName lowercases a zone name and drops punctuation and the
+// articles "the", "a" and "an".
+func normalizeZoneName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		if r == '\'' || r == '`' {
+			return -1 // "Innothule's" and "Innothules" are the same zone
+		}
+		return ' '
+	}, name)
+	var words []string
+	for _, w := range strings.Fields(name) {
+		if w != "the" && w != "a" && w != "an" {
+			words = append(words, w)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// EditDistance is the Levenshtein distance between two strings.
+func EditDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
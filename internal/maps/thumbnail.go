@@ -0,0 +1,175 @@
+package maps
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// ThumbnailSize is the longest side of a zone thumbnail in pixels.
+const ThumbnailSize = 160
+
+var thumbnailBackground = color.RGBA{20, 20, 20, 255}
+
+// RenderThumbnail draws a whole zone small, in software so it can run off
+// the game loop with no GPU context. Lines too dark to see on the dark
+// background are drawn gray.
+func RenderThumbnail(zm *ZoneMap, size int) *image.RGBA {
+	width, height := zm.MaxX-zm.MinX, zm.MaxY-zm.MinY
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	scale := float64(size-4) / max(width, height)
+	img := image.NewRGBA(image.Rect(0, 0, int(width*scale)+4, int(height*scale)+4))
+	draw.Draw(img, img.Bounds(), image.NewUniform(thumbnailBackground), image.Point{}, draw.Src)
+
+	for _, l := range zm.Lines {
+		c := l.Color
+		if int(c.R)+int(c.G)+int(c.B) < 150 {
+			c = color.RGBA{140, 140, 140, 255}
+		}
+		c.A = 255
+		plotLine(img,
+			2+(l.X1-zm.MinX)*scale, 2+(l.Y1-zm.MinY)*scale,
+			2+(l.X2-zm.MinX)*scale, 2+(l.Y2-zm.MinY)*scale, c)
+	}
+	return img
+}
+
+// plotLine steps along the line one pixel at a time; at thumbnail size
+// plain stepping looks as good as anti-aliasing would.
+func plotLine(img *image.RGBA, x1, y1, x2, y2 float64, c color.RGBA) {
+	steps := int(math.Ceil(max(math.Abs(x2-x1), math.Abs(y2-y1))))
+	if steps == 0 {
+		img.SetRGBA(int(x1), int(y1), c)
+		return
+	}
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		img.SetRGBA(int(x1+(x2-x1)*t), int(y1+(y2-y1)*t), c)
+	}
+}
+
+// Thumbnailer renders thumbnails for every zone in a background goroutine,
+// caching them as PNGs so only new or edited zones are redrawn.
+type Thumbnailer struct {
+	Done chan struct{} // Closed when every zone has been processed
+
+	dir         string
+	done, total atomic.Int32
+}
+
+// thumbnailStamps records the files fingerprint (see filesStamp) each
+// cached thumbnail was drawn from, by lowercase file code.
+const thumbnailStampsFile = "thumbnails.json"
+
+// StartThumbnailer begins bringing the thumbnail cache in dir up to date
+// with mapDir.
+func StartThumbnailer(mapDir, dir string) *Thumbnailer {
+	t := &Thumbnailer{Done: make(chan struct{}), dir: dir}
+	go func() {
+		defer close(t.Done)
+		rendered, err := t.build(mapDir)
+		if err != nil {
+			fmt.Printf("❌ Error rendering zone thumbnails: %v\n", err)
+			return
+		}
+		if rendered > 0 {
+			fmt.Printf("🖼️  Rendered %d zone thumbnails\n", rendered)
+		}
+	}()
+	return t
+}
+
+func (t *Thumbnailer) build(mapDir string) (int, error) {
+	codes, err := ZoneCodes(mapDir)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(t.dir, 0755); err != nil {
+		return 0, err
+	}
+	t.total.Store(int32(len(codes)))
+
+	stamps := make(map[string]string)
+	stampsPath := filepath.Join(t.dir, thumbnailStampsFile)
+	if data, err := os.ReadFile(stampsPath); err == nil {
+		json.Unmarshal(data, &stamps)
+	}
+
+	rendered := 0
+	for i, code := range codes {
+		t.done.Store(int32(i + 1))
+		key := strings.ToLower(code)
+		paths, _, err := zoneFiles(mapDir, code)
+		if err != nil || len(paths) == 0 {
+			continue
+		}
+		stamp := filesStamp(paths)
+		if stamps[key] == stamp {
+			if _, err := os.Stat(t.path(code)); err == nil {
+				continue
+			}
+		}
+
+		img := RenderThumbnail(parseZoneFiles(code, paths), ThumbnailSize)
+		if img == nil {
+			continue
+		}
+		if err := writePNG(t.path(code), img); err != nil {
+			return rendered, err
+		}
+		stamps[key] = stamp
+		rendered++
+	}
+
+	data, err := json.Marshal(stamps)
+	if err != nil {
+		return rendered, err
+	}
+	return rendered, os.WriteFile(stampsPath, data, 0644)
+}
+
+func (t *Thumbnailer) path(code string) string {
+	return ThumbnailPath(t.dir, code)
+}
+
+// Progress reports how many zones have been processed so far.
+func (t *Thumbnailer) Progress() (done, total int) {
+	return int(t.done.Load()), int(t.total.Load())
+}
+
+// ThumbnailPath is where a zone's cached thumbnail lives in dir.
+func ThumbnailPath(dir, code string) string {
+	return filepath.Join(dir, strings.ToLower(code)+".png")
+}
+
+// LoadThumbnail reads a cached thumbnail.
+func LoadThumbnail(dir, code string) (image.Image, error) {
+	f, err := os.Open(ThumbnailPath(dir, code))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,153 @@
+package maps
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watcher signals Changed when any of a zone's map files (base + layers) is
+// edited, created or removed. It listens for file system events on the map
+// directory, and falls back to polling the files every interval if that
+// can't be set up (the directory doesn't exist, the OS is out of watches).
+type Watcher struct {
+	Changed chan struct{}
+
+	mapDir   string
+	zoneName string
+	interval time.Duration
+	stop     chan struct{}
+}
+
+// watchSettle is how long events must stop before the files are compared,
+// so an editor's save (often several writes, or write + rename) signals once.
+const watchSettle = 100 * time.Millisecond
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// WatchZone starts watching the files LoadZone would read for zoneName.
+// interval is the polling period if events can't be used.
+func WatchZone(mapDir, zoneName string, interval time.Duration) *Watcher {
+	w := &Watcher{
+		Changed:  make(chan struct{}, 1),
+		mapDir:   mapDir,
+		zoneName: zoneName,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+	// Events and the first snapshot are set up before returning, so a change
+	// made right after WatchZone isn't taken for how the files started
+	events, err := w.listen()
+	last := w.snapshot()
+	if err == nil {
+		go w.watch(events, last)
+	} else {
+		go w.poll(last)
+	}
+	return w
+}
+
+func (w *Watcher) Stop() {
+	close(w.stop)
+}
+
+// listen starts file system events for the map directory.
+func (w *Watcher) listen() (*fsnotify.Watcher, error) {
+	events, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := events.Add(w.mapDir); err != nil {
+		events.Close()
+		return nil, err
+	}
+	return events, nil
+}
+
+func (w *Watcher) watch(events *fsnotify.Watcher, last map[string]fileStamp) {
+	defer events.Close()
+	names := zoneFileNames(w.zoneName)
+	settle := time.NewTimer(watchSettle)
+	settle.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case ev, ok := <-events.Events:
+			if !ok {
+				return
+			}
+			if slices.Contains(names, strings.ToLower(filepath.Base(ev.Name))) {
+				settle.Reset(watchSettle)
+			}
+		case _, ok := <-events.Errors:
+			if !ok {
+				return
+			}
+			settle.Reset(watchSettle) // Events may have been lost; compare anyway
+		case <-settle.C:
+			last = w.compare(last)
+		}
+	}
+}
+
+func (w *Watcher) poll(last map[string]fileStamp) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			last = w.compare(last)
+		}
+	}
+}
+
+// compare signals Changed if the files differ from last, and returns them
+// as they are now.
+func (w *Watcher) compare(last map[string]fileStamp) map[string]fileStamp {
+	current := w.snapshot()
+	if !sameStamps(last, current) {
+		// Non-blocking: one pending notification is enough
+		select {
+		case w.Changed <- struct{}{}:
+		default:
+		}
+	}
+	return current
+}
+
+func (w *Watcher) snapshot() map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+	paths, _, err := zoneFiles(w.mapDir, w.zoneName)
+	if err != nil {
+		return stamps
+	}
+	for _, path := range paths {
+		if fi, err := Stat(path); err == nil {
+			stamps[path] = fileStamp{modTime: fi.ModTime(), size: fi.Size()}
+		}
+	}
+	return stamps
+}
+
+func sameStamps(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, stamp := range a {
+		if b[path] != stamp {
+			return false
+		}
+	}
+	return true
+}
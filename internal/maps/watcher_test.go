@@ -0,0 +1,63 @@
+package maps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitChanged reports whether w signals within d.
+func waitChanged(w *Watcher, d time.Duration) bool {
+	select {
+	case <-w.Changed:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+func writeMap(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchZone(t *testing.T) {
+	dir := t.TempDir()
+	writeMap(t, filepath.Join(dir, "befallen.txt"), "L 0, 0, 0, 10, 10, 0, 0, 0, 0\n")
+	w := WatchZone(dir, "befallen", time.Hour) // Only events can be seen in time
+	defer w.Stop()
+
+	writeMap(t, filepath.Join(dir, "Befallen_1.txt"), "P 0, 0, 0, 255, 0, 0, 2, Entrance\n")
+	if !waitChanged(w, 5*time.Second) {
+		t.Fatal("no change signalled for a new layer file")
+	}
+
+	writeMap(t, filepath.Join(dir, "qeynos.txt"), "L 0, 0, 0, 1, 1, 0, 0, 0, 0\n")
+	if waitChanged(w, 500*time.Millisecond) {
+		t.Error("change signalled for another zone's file")
+	}
+
+	if err := os.Remove(filepath.Join(dir, "befallen.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if !waitChanged(w, 5*time.Second) {
+		t.Fatal("no change signalled for a removed file")
+	}
+}
+
+func TestWatchZonePolls(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "maps") // Doesn't exist yet, so it can't be watched
+	w := WatchZone(dir, "befallen", 20*time.Millisecond)
+	defer w.Stop()
+
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeMap(t, filepath.Join(dir, "befallen.txt"), "L 0, 0, 0, 10, 10, 0, 0, 0, 0\n")
+	if !waitChanged(w, 5*time.Second) {
+		t.Fatal("polling didn't see the new file")
+	}
+}
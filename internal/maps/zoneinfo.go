@@ -0,0 +1,66 @@
+package maps
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ZoneInfo is per-zone planning metadata. Zero values mean unknown.
+type ZoneInfo struct {
+	MinLevel int     `json:"min_level,omitempty"`
+	MaxLevel int     `json:"max_level,omitempty"`
+	ZEM      float64 `json:"zem,omitempty"` // Zone experience modifier
+	Indoor   bool    `json:"indoor,omitempty"`
+	Note     string  `json:"note,omitempty"`
+}
+
+// ZoneInfoMap is keyed by lowercase file code.
+var ZoneInfoMap = make(map[string]ZoneInfo)
+
+// LoadZoneInfo merges a zone info file into ZoneInfoMap; entries replace
+// earlier ones for the same zone, so a user file can override the bundled
+// data. A missing file is not an error.
+func LoadZoneInfo(path string) error {
+	data, err := ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var raw map[string]ZoneInfo
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for code, info := range raw {
+		ZoneInfoMap[strings.ToLower(code)] = info
+	}
+	return nil
+}
+
+// GetZoneInfo returns the metadata for a file code.
+func GetZoneInfo(fileCode string) (ZoneInfo, bool) {
+	info, ok := ZoneInfoMap[strings.ToLower(fileCode)]
+	return info, ok
+}
+
+// Summary is a compact one-line description, e.g. "Lv 10-25 | ZEM 1.2 | Outdoor".
+func (z ZoneInfo) Summary() string {
+	var parts []string
+	switch {
+	case z.MinLevel > 0 && z.MaxLevel > 0:
+		parts = append(parts, fmt.Sprintf("Lv %d-%d", z.MinLevel, z.MaxLevel))
+	case z.MinLevel > 0:
+		parts = append(parts, fmt.Sprintf("Lv %d+", z.MinLevel))
+	}
+	if z.ZEM > 0 {
+		parts = append(parts, fmt.Sprintf("ZEM %g", z.ZEM))
+	}
+	parts = append(parts, map[bool]string{true: "Indoor", false: "Outdoor"}[z.Indoor])
+	if z.Note != "" {
+		parts = append(parts, z.Note)
+	}
+	return strings.Join(parts, " | ")
+}
@@ -0,0 +1,70 @@
+package nav
+
+import (
+	"math"
+	"strings"
+)
+
+var compassPoints16 = []string{
+	"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE",
+	"S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW",
+}
+
+// CompassDirection16 converts a bearing into a 16-point compass label ("NNE").
+func CompassDirection16(bearing float64) string {
+	idx := int(math.Floor((compassDegrees(bearing)+11.25)/22.5)) % len(compassPoints16)
+	return compassPoints16[idx]
+}
+
+var compassWords = map[byte]string{'N': "north", 'E': "east", 'S': "south", 'W': "west"}
+
+// CompassWords spells out a compass label the way EQ's Sense Heading
+// does: "NNE" -> "north-northeast", "NE" -> "northeast".
+func CompassWords(label string) string {
+	spell := func(s string) string {
+		var b strings.Builder
+		for i := 0; i < len(s); i++ {
+			b.WriteString(compassWords[s[i]])
+		}
+		return b.String()
+	}
+	if len(label) == 3 {
+		return spell(label[:1]) + "-" + spell(label[1:])
+	}
+	return spell(label)
+}
+
+// RelativeAngle is how far a bearing lies from a facing, in radians
+// (-π, π]; positive is to the right (clockwise on screen).
+func RelativeAngle(bearing, heading float64) float64 {
+	a := math.Mod(bearing-heading, 2*math.Pi)
+	if a <= -math.Pi {
+		a += 2 * math.Pi
+	} else if a > math.Pi {
+		a -= 2 * math.Pi
+	}
+	return a
+}
+
+// RelativeDirection describes a bearing relative to a facing: "ahead",
+// "slightly left", "right", "sharp left", "behind".
+func RelativeDirection(bearing, heading float64) string {
+	a := RelativeAngle(bearing, heading)
+	deg := math.Abs(a) * 180 / math.Pi
+	side := "right"
+	if a < 0 {
+		side = "left"
+	}
+	switch {
+	case deg < 15:
+		return "ahead"
+	case deg < 45:
+		return "slightly " + side
+	case deg < 110:
+		return side
+	case deg < 160:
+		return "sharp " + side
+	default:
+		return "behind"
+	}
+}
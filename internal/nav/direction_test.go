@@ -0,0 +1,153 @@
+package nav
+
+import (
+	"math"
+	"testing"
+)
+
+// bearingOf turns degrees clockwise from north into a screen bearing.
+func bearingOf(deg float64) float64 {
+	return (deg - 90) * math.Pi / 180
+}
+
+func rad(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func TestCompassDirection16(t *testing.T) {
+	tests := []struct {
+		deg  float64
+		want string
+	}{
+		{0, "N"},
+		{90, "E"},
+		{180, "S"},
+		{270, "W"},
+		{348.76, "N"}, // NNW/N boundary at 348.75
+		{348.74, "NNW"},
+		{359.99, "N"},
+		{360, "N"},
+		{720 + 45, "NE"},
+		{-90, "W"},
+		{-1, "N"},
+		{-11.26, "NNW"},
+		{-720 - 90, "W"},
+	}
+	for _, tt := range tests {
+		if got := CompassDirection16(bearingOf(tt.deg)); got != tt.want {
+			t.Errorf("CompassDirection16(%v°) = %s, want %s", tt.deg, got, tt.want)
+		}
+	}
+
+	// Either side of every boundary: N ends at 11.25°, NNE at 33.75°, ...
+	for i, point := range compassPoints16 {
+		mid := float64(i) * 22.5
+		if got := CompassDirection16(bearingOf(mid - 11.24)); got != point {
+			t.Errorf("%v° = %s, want %s", mid-11.24, got, point)
+		}
+		if got := CompassDirection16(bearingOf(mid + 11.24)); got != point {
+			t.Errorf("%v° = %s, want %s", mid+11.24, got, point)
+		}
+		next := compassPoints16[(i+1)%len(compassPoints16)]
+		if got := CompassDirection16(bearingOf(mid + 11.26)); got != next {
+			t.Errorf("%v° = %s, want %s", mid+11.26, got, next)
+		}
+	}
+
+	// Bearings as Atan2 returns them, either side of ±π (due west)
+	if got := CompassDirection16(math.Pi); got != "W" {
+		t.Errorf("CompassDirection16(π) = %s, want W", got)
+	}
+	if got := CompassDirection16(-math.Pi); got != "W" {
+		t.Errorf("CompassDirection16(-π) = %s, want W", got)
+	}
+}
+
+func TestCompassDirection(t *testing.T) {
+	tests := []struct {
+		deg  float64
+		want string
+	}{
+		{0, "N"},
+		{22.4, "N"},
+		{22.6, "NE"},
+		{337.6, "N"},
+		{337.4, "NW"},
+		{-360 - 135, "SW"},
+	}
+	for _, tt := range tests {
+		if got := CompassDirection(bearingOf(tt.deg)); got != tt.want {
+			t.Errorf("CompassDirection(%v°) = %s, want %s", tt.deg, got, tt.want)
+		}
+	}
+}
+
+func TestCompassWords(t *testing.T) {
+	tests := map[string]string{
+		"N":   "north",
+		"NE":  "northeast",
+		"NNE": "north-northeast",
+		"WSW": "west-southwest",
+	}
+	for label, want := range tests {
+		if got := CompassWords(label); got != want {
+			t.Errorf("CompassWords(%s) = %s, want %s", label, got, want)
+		}
+	}
+}
+
+func TestRelativeAngle(t *testing.T) {
+	tests := []struct {
+		bearing, heading, want float64 // Degrees
+	}{
+		{10, 0, 10},
+		{0, 10, -10},
+		{-170, 170, 20}, // Across ±180°
+		{170, -170, -20},
+		{180, 0, 180},
+		{-180, 0, 180}, // Straight behind is +180°, never -180°
+		{0, 180, 180},
+		{720 + 30, 0, 30},
+		{0, -720 - 30, 30},
+	}
+	for _, tt := range tests {
+		got := RelativeAngle(rad(tt.bearing), rad(tt.heading)) * 180 / math.Pi
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("RelativeAngle(%v°, %v°) = %v°, want %v°", tt.bearing, tt.heading, got, tt.want)
+		}
+	}
+}
+
+func TestRelativeDirection(t *testing.T) {
+	tests := []struct {
+		offset float64 // Degrees clockwise from the heading
+		want   string
+	}{
+		{0, "ahead"},
+		{14.9, "ahead"},
+		{-14.9, "ahead"},
+		{15.1, "slightly right"},
+		{-15.1, "slightly left"},
+		{44.9, "slightly right"},
+		{-44.9, "slightly left"},
+		{45.1, "right"},
+		{-45.1, "left"},
+		{109.9, "right"},
+		{-109.9, "left"},
+		{110.1, "sharp right"},
+		{-110.1, "sharp left"},
+		{159.9, "sharp right"},
+		{-159.9, "sharp left"},
+		{160.1, "behind"},
+		{-160.1, "behind"},
+		{180, "behind"},
+	}
+	for _, heading := range []float64{0, 90, 175, -175, 540} {
+		for _, tt := range tests {
+			bearing := rad(heading + tt.offset)
+			if got := RelativeDirection(bearing, rad(heading)); got != tt.want {
+				t.Errorf("RelativeDirection(%v° from %v°) = %s, want %s", tt.offset, heading, got, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package nav
+
+import (
+	"math"
+)
+
+// All positions here are in map space (the same space as PlayerState.X/Y):
+// +X is east and +Y is south, so "up" on screen is north.
+
+type Waypoint struct {
+	X, Y  float64
+	Label string
+}
+
+type Navigator struct {
+	Target       *Waypoint
+	ArriveRadius float64 // Distance at which the waypoint counts as reached
+
+	// Live readout, refreshed by Update
+	Distance float64
+	Bearing  float64 // Radians, same convention as PlayerState.Heading
+}
+
+func NewNavigator() *Navigator {
+	return &Navigator{
+		ArriveRadius: 20.0,
+	}
+}
+
+func (n *Navigator) Set(x, y float64, label string) {
+	n.Target = &Waypoint{X: x, Y: y, Label: label}
+	n.Distance = 0
+	n.Bearing = 0
+}
+
+func (n *Navigator) Clear() {
+	n.Target = nil
+}
+
+func (n *Navigator) Active() bool {
+	return n.Target != nil
+}
+
+// Update recomputes distance and bearing from the player's position and
+// reports whether the waypoint has been reached (it is cleared if so).
+func (n *Navigator) Update(playerX, playerY float64) (arrived bool) {
+	if n.Target == nil {
+		return false
+	}
+
+	n.Distance = Distance(playerX, playerY, n.Target.X, n.Target.Y)
+	n.Bearing = Bearing(playerX, playerY, n.Target.X, n.Target.Y)
+
+	if n.Distance <= n.ArriveRadius {
+		n.Target = nil
+		return true
+	}
+	return false
+}
+
+func Distance(x1, y1, x2, y2 float64) float64 {
+	dx := x2 - x1
+	dy := y2 - y1
+	return math.Sqrt(dx*dx + dy*dy)
+}
+
+// Bearing is the screen-space angle from one point to another, suitable for
+// drawing arrows with cos/sin the same way the player arrow is drawn.
+func Bearing(fromX, fromY, toX, toY float64) float64 {
+	return math.Atan2(toY-fromY, toX-fromX)
+}
+
+var compassPoints = []string{"N", "NE", "E", "SE", "S", "SW", "W", "NW"}
+
+// CompassDirection converts a bearing into an 8-point compass label.
+func CompassDirection(bearing float64) string {
+	idx := int(math.Floor((compassDegrees(bearing)+22.5)/45)) % len(compassPoints)
+	return compassPoints[idx]
+}
+
+// compassDegrees turns a bearing into degrees clockwise from north (screen
+// up, -Y), in [0, 360).
+func compassDegrees(bearing float64) float64 {
+	deg := math.Mod(bearing*180/math.Pi+90, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
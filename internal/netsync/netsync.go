@@ -0,0 +1,337 @@
+// Package netsync shares marker changes (and optionally positions) between
+// instances on a LAN. One instance hosts a WebSocket server; the others join
+// it with the host's join code, and the host relays every message to
+// everyone else.
+//
+// The host decides who a message is from: a peer is named by its hello
+// (made unique among the peers), and every message it sends after that is
+// relayed under that name whatever its From says. Requests from a browser
+// (anything with an Origin header) are refused, so a web page can't join
+// through a player's machine.
+package netsync
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+)
+
+// DefaultPort is used when an address has no port.
+const DefaultPort = "7777"
+
+// codeHeader carries the join code in the WebSocket handshake.
+const codeHeader = "X-Nox-Maps-Code"
+
+// ErrWrongCode is returned by Join when the host refuses the join code.
+var ErrWrongCode = errors.New("the host refused the join code")
+
+// Message types
+const (
+	TypeHello    = "hello"    // A peer joined
+	TypeLeave    = "leave"    // A peer left (or the host went away)
+	TypeMarker   = "marker"   // Marker added or edited (matched by ID)
+	TypeRemove   = "remove"   // Marker removed
+	TypePosition = "position" // A peer's position
+)
+
+// maxQueued bounds the incoming queue if the UI stops draining it, and
+// each peer's outgoing queue if its connection stalls.
+const maxQueued = 256
+
+type Message struct {
+	Type     string         `json:"type"`
+	From     string         `json:"from"`
+	Zone     string         `json:"zone,omitempty"`
+	Marker   *config.Marker `json:"marker,omitempty"`
+	MarkerID string         `json:"marker_id,omitempty"`
+	X        float64        `json:"x,omitempty"`
+	Y        float64        `json:"y,omitempty"`
+	Heading  float64        `json:"heading,omitempty"`
+}
+
+// peer is one open connection with its own writer so a slow peer can't
+// stall the UI or the other peers.
+type peer struct {
+	ws   *wsConn
+	name string // Host side: from the peer's hello, set by the read loop under Session.mu; "" until then
+	out  chan []byte
+}
+
+func newPeer(ws *wsConn) *peer {
+	p := &peer{ws: ws, out: make(chan []byte, maxQueued)}
+	go func() {
+		for data := range p.out {
+			if err := ws.writeFrame(opText, data); err != nil {
+				ws.conn.Close() // Ends the read loop, which cleans up
+				return
+			}
+		}
+	}()
+	return p
+}
+
+func (p *peer) send(data []byte) {
+	select {
+	case p.out <- data:
+	default: // Stalled; drop rather than block
+	}
+}
+
+// Session is a hosted or joined sync session.
+type Session struct {
+	Name string // Sent as From on our messages
+	Addr string // Listening or host address
+
+	host   bool
+	code   string // Host side: the join code peers must send
+	server *http.Server
+
+	mu       sync.Mutex
+	peers    map[*peer]bool
+	incoming []Message
+	closed   bool
+}
+
+// NewCode returns a random join code.
+func NewCode() string {
+	var b [5]byte
+	rand.Read(b[:])
+	return base32.StdEncoding.EncodeToString(b[:])
+}
+
+// normalizeCode lets a code be typed in any case with spaces around it.
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Host starts a session that others can join at addr (":7777" listens on
+// every interface) with code.
+func Host(addr, name, code string) (*Session, error) {
+	code = normalizeCode(code)
+	if code == "" {
+		return nil, errors.New("a join code is needed")
+	}
+	addr = withPort(addr)
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &Session{Name: name, Addr: ln.Addr().String(), host: true, code: code, peers: make(map[*peer]bool)}
+	s.server = &http.Server{Handler: http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Origin") != "" {
+			http.Error(rw, "browsers can't join", http.StatusForbidden)
+			return
+		}
+		sent := normalizeCode(r.Header.Get(codeHeader))
+		if subtle.ConstantTimeCompare([]byte(sent), []byte(s.code)) != 1 {
+			http.Error(rw, "wrong join code", http.StatusForbidden)
+			return
+		}
+		ws, err := upgrade(rw, r)
+		if err != nil {
+			return
+		}
+		if p := newPeer(ws); s.add(p) {
+			s.serve(p)
+		}
+	})}
+	go s.server.Serve(ln)
+	return s, nil
+}
+
+// Join connects to a hosted session with its join code. addr may be
+// "host", "host:port" or a ws:// URL.
+func Join(addr, name, code string) (*Session, error) {
+	addr = strings.TrimPrefix(strings.TrimPrefix(addr, "ws://"), "http://")
+	addr = withPort(strings.TrimSuffix(addr, "/"))
+	ws, err := dial(addr, http.Header{codeHeader: {normalizeCode(code)}})
+	if err != nil {
+		return nil, err
+	}
+	s := &Session{Name: name, Addr: addr, peers: make(map[*peer]bool)}
+	p := newPeer(ws)
+	s.add(p)
+	go s.serve(p)
+	s.Send(Message{Type: TypeHello})
+	return s, nil
+}
+
+func withPort(addr string) string {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return net.JoinHostPort(strings.Trim(addr, "[]"), DefaultPort)
+	}
+	return addr
+}
+
+// add registers a new connection; false if the session is already closed.
+func (s *Session) add(p *peer) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		close(p.out)
+		p.ws.close()
+		return false
+	}
+	s.peers[p] = true
+	return true
+}
+
+// serve reads one peer until it disconnects. The host relays everything it
+// receives to the other peers, from the name the peer said hello with;
+// anything before the hello is dropped.
+func (s *Session) serve(p *peer) {
+	for {
+		data, err := p.ws.readMessage()
+		if err != nil {
+			break
+		}
+		var msg Message
+		if json.Unmarshal(data, &msg) != nil || msg.Type == "" {
+			continue
+		}
+		if s.host {
+			if p.name == "" {
+				if msg.Type != TypeHello {
+					continue
+				}
+				s.namePeer(p, msg.From)
+			} else if msg.Type == TypeHello {
+				continue // Already named
+			}
+			msg.From = p.name
+			if data, err = json.Marshal(msg); err != nil {
+				continue
+			}
+			s.broadcast(data, p)
+		}
+		s.queue(msg)
+	}
+
+	s.mu.Lock()
+	delete(s.peers, p)
+	closed := s.closed
+	s.mu.Unlock()
+	close(p.out)
+	p.ws.conn.Close()
+	if closed {
+		return
+	}
+	left := Message{Type: TypeLeave, From: p.name}
+	if s.host && p.name != "" {
+		data, _ := json.Marshal(left)
+		s.broadcast(data, nil)
+	}
+	s.queue(left)
+}
+
+// namePeer names a new peer: the name it asked for, unless the host or
+// another peer has it.
+func (s *Session) namePeer(p *peer, name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Player"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	taken := map[string]bool{s.Name: true}
+	for p := range s.peers {
+		taken[p.name] = true
+	}
+	p.name = name
+	for n := 2; taken[p.name]; n++ {
+		p.name = fmt.Sprintf("%s (%d)", name, n)
+	}
+}
+
+func (s *Session) broadcast(data []byte, except *peer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for p := range s.peers {
+		if p != except {
+			p.send(data)
+		}
+	}
+}
+
+func (s *Session) queue(msg Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.incoming) >= maxQueued {
+		s.incoming = s.incoming[1:]
+	}
+	s.incoming = append(s.incoming, msg)
+}
+
+// Send shares a message with every other instance.
+func (s *Session) Send(msg Message) {
+	msg.From = s.Name
+	data, err := json.Marshal(msg)
+	if err != nil {
+		fmt.Printf("⚠️  Sync message not sent: %v\n", err)
+		return
+	}
+	s.broadcast(data, nil)
+}
+
+// Drain returns the messages received since the last call.
+func (s *Session) Drain() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msgs := s.incoming
+	s.incoming = nil
+	return msgs
+}
+
+// Connected reports whether a joined session still has its host. Hosts are
+// always connected.
+func (s *Session) Connected() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.host || len(s.peers) > 0
+}
+
+// Peers returns how many connections are open (for a joined session, 1
+// while the host is reachable).
+func (s *Session) Peers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.peers)
+}
+
+// IsHost reports whether this instance is hosting.
+func (s *Session) IsHost() bool {
+	return s.host
+}
+
+// Close leaves the session (and stops the server when hosting).
+func (s *Session) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	peers := s.peers
+	s.peers = make(map[*peer]bool)
+	s.mu.Unlock()
+
+	for p := range peers {
+		p.ws.close()
+	}
+	if s.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		s.server.Shutdown(ctx)
+	}
+}
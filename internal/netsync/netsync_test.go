@@ -0,0 +1,142 @@
+package netsync
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func host(t *testing.T) *Session {
+	t.Helper()
+	s, err := Host("127.0.0.1:0", "Hostess", "k3d9 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Close)
+	return s
+}
+
+// drain waits for n messages to reach s.
+func drain(t *testing.T, s *Session, n int) []Message {
+	t.Helper()
+	var msgs []Message
+	for deadline := time.Now().Add(5 * time.Second); len(msgs) < n; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d messages, want %d: %+v", len(msgs), n, msgs)
+		}
+		msgs = append(msgs, s.Drain()...)
+	}
+	return msgs
+}
+
+func TestHostNeedsCode(t *testing.T) {
+	if _, err := Host("127.0.0.1:0", "Hostess", " "); err == nil {
+		t.Error("Host without a join code succeeded")
+	}
+}
+
+func TestJoinCode(t *testing.T) {
+	s := host(t)
+	if _, err := Join(s.Addr, "Mallory", "nope"); !errors.Is(err, ErrWrongCode) {
+		t.Errorf("Join with the wrong code: %v, want ErrWrongCode", err)
+	}
+	if _, err := Join(s.Addr, "Mallory", ""); !errors.Is(err, ErrWrongCode) {
+		t.Errorf("Join without a code: %v, want ErrWrongCode", err)
+	}
+	j, err := Join(s.Addr, "Alice", "K3D9")
+	if err != nil {
+		t.Fatalf("Join with the code: %v", err)
+	}
+	defer j.Close()
+	if msgs := drain(t, s, 1); msgs[0].Type != TypeHello || msgs[0].From != "Alice" {
+		t.Errorf("host got %+v, want Alice's hello", msgs[0])
+	}
+}
+
+func TestBrowserRefused(t *testing.T) {
+	s := host(t)
+	req, _ := http.NewRequest("GET", "http://"+s.Addr+"/", nil)
+	req.Header.Set(codeHeader, "K3D9")
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("request with an Origin got %s, want 403", resp.Status)
+	}
+}
+
+func TestHostSetsFrom(t *testing.T) {
+	s := host(t)
+	ws, err := dial(s.Addr, http.Header{codeHeader: {"K3D9"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.close()
+	alice, err := Join(s.Addr, "Alice", "K3D9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer alice.Close()
+	drain(t, s, 1)
+
+	// Messages before the hello are dropped, the hello can't take a name in
+	// use, and later messages can't claim to be someone else
+	for _, raw := range []string{
+		`{"type":"remove","from":"Hostess","zone":"Befallen","marker_id":"1"}`,
+		`{"type":"hello","from":"Alice"}`,
+		`{"type":"hello","from":"Bob"}`,
+		`{"type":"remove","from":"Hostess","zone":"Befallen","marker_id":"2"}`,
+	} {
+		if err := ws.writeFrame(opText, []byte(raw)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, got := range [][]Message{drain(t, s, 2), drain(t, alice, 2)} {
+		if got[0].Type != TypeHello || got[0].From != "Alice (2)" {
+			t.Errorf("got %+v, want a hello from Alice (2)", got[0])
+		}
+		if got[1].Type != TypeRemove || got[1].From != "Alice (2)" || got[1].MarkerID != "2" {
+			t.Errorf("got %+v, want marker 2 removed by Alice (2)", got[1])
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	if extra := s.Drain(); len(extra) > 0 {
+		t.Errorf("host got more messages: %+v", extra)
+	}
+}
+
+func TestCloseDuringStuckWrite(t *testing.T) {
+	// Nothing reads the other end of the pipe, so the write blocks holding wmu
+	conn, other := net.Pipe()
+	defer other.Close()
+	ws := &wsConn{conn: conn}
+	written := make(chan error, 1)
+	go func() { written <- ws.writeFrame(opText, []byte(`{"type":"hello"}`)) }()
+	for ws.wmu.TryLock() {
+		ws.wmu.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		ws.close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("close waited for the stuck write")
+	}
+	if err := <-written; err == nil {
+		t.Error("the stuck write succeeded after close")
+	}
+}
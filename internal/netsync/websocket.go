@@ -0,0 +1,231 @@
+package netsync
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Just enough of RFC 6455 for JSON text messages between instances, so the
+// module doesn't need a WebSocket dependency.
+
+const (
+	websocketGUID  = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	maxMessageSize = 1 << 20
+
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+
+	// writeTimeout bounds every frame write, so a peer that stops reading
+	// can't hold wmu (and whoever waits on it) forever
+	writeTimeout = 5 * time.Second
+	closeTimeout = 250 * time.Millisecond
+)
+
+// wsConn is one WebSocket connection. Reads happen on a single goroutine;
+// writes (including pongs from the reader) are serialized by wmu.
+type wsConn struct {
+	conn   net.Conn
+	br     *bufio.Reader
+	client bool // Clients must mask their frames
+	wmu    sync.Mutex
+}
+
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// upgrade completes the server side of the handshake.
+func upgrade(rw http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || key == "" {
+		http.Error(rw, "websocket upgrade required", http.StatusBadRequest)
+		return nil, errors.New("not a websocket request")
+	}
+	hj, ok := rw.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("connection can't be hijacked")
+	}
+	conn, buf, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(buf, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", acceptKey(key))
+	if err := buf.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, br: buf.Reader}, nil
+}
+
+// dial connects to ws://addr/ and completes the client handshake, sending
+// header along with it.
+func dial(addr string, header http.Header) (*wsConn, error) {
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	var nonce [16]byte
+	rand.Read(nonce[:])
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+
+	req, _ := http.NewRequest("GET", "http://"+addr+"/", nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusForbidden {
+		conn.Close()
+		return nil, ErrWrongCode
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		conn.Close()
+		return nil, fmt.Errorf("handshake refused: %s", resp.Status)
+	}
+	conn.SetDeadline(time.Time{})
+	return &wsConn{conn: conn, br: br, client: true}, nil
+}
+
+// writeFrame sends one unfragmented frame.
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	frame := c.frame(opcode, payload)
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// frame encodes one unfragmented frame, masked if we're the client.
+func (c *wsConn) frame(opcode byte, payload []byte) []byte {
+	header := make([]byte, 2, 14+len(payload))
+	header[0] = 0x80 | opcode
+	switch n := len(payload); {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	if !c.client {
+		return append(header, payload...)
+	}
+	header[1] |= 0x80
+	var mask [4]byte
+	rand.Read(mask[:])
+	header = append(header, mask[:]...)
+	for i, b := range payload {
+		header = append(header, b^mask[i%4])
+	}
+	return header
+}
+
+// readMessage returns the next data message, answering pings on the way.
+// A close frame is returned as io.EOF.
+func (c *wsConn) readMessage() ([]byte, error) {
+	var message []byte
+	for {
+		var head [2]byte
+		if _, err := io.ReadFull(c.br, head[:]); err != nil {
+			return nil, err
+		}
+		fin, opcode := head[0]&0x80 != 0, head[0]&0x0F
+		masked := head[1]&0x80 != 0
+		length := uint64(head[1] & 0x7F)
+		switch length {
+		case 126:
+			var ext [2]byte
+			if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+				return nil, err
+			}
+			length = uint64(binary.BigEndian.Uint16(ext[:]))
+		case 127:
+			var ext [8]byte
+			if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+				return nil, err
+			}
+			length = binary.BigEndian.Uint64(ext[:])
+		}
+		if length > maxMessageSize || uint64(len(message))+length > maxMessageSize {
+			return nil, errors.New("message too large")
+		}
+		var mask [4]byte
+		if masked {
+			if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+				return nil, err
+			}
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(c.br, payload); err != nil {
+			return nil, err
+		}
+		if masked {
+			for i := range payload {
+				payload[i] ^= mask[i%4]
+			}
+		}
+
+		switch opcode {
+		case opClose:
+			return nil, io.EOF
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opText, opBinary, opContinuation:
+			message = append(message, payload...)
+			if fin {
+				return message, nil
+			}
+		default:
+			return nil, fmt.Errorf("unknown opcode %d", opcode)
+		}
+	}
+}
+
+// close sends a close frame and closes the connection. The frame is skipped
+// when another write holds wmu: that one may be stuck on a peer that stopped
+// reading, and closing the connection is what unsticks it.
+func (c *wsConn) close() {
+	if c.wmu.TryLock() {
+		c.conn.SetWriteDeadline(time.Now().Add(closeTimeout))
+		c.conn.Write(c.frame(opClose, nil))
+		c.wmu.Unlock()
+	}
+	c.conn.Close()
+}
@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"time"
+
+	"github.com/devin-hart/nox-maps/pkg/eqlogparse"
+)
+
+// Chat channels
+const (
+	ChannelOOC   = eqlogparse.ChannelOOC
+	ChannelGroup = eqlogparse.ChannelGroup
+	ChannelGuild = eqlogparse.ChannelGuild
+	ChannelRepop = "repop" // Server-wide repop announcement; Text is the whole line
+)
+
+// maxQueuedChat bounds the queue if the UI stops draining it.
+const maxQueuedChat = 256
+
+// ChatMessage is one chat line the UI may want to act on.
+type ChatMessage struct {
+	Channel   string
+	Speaker   string // "You" for the log's own character
+	Text      string
+	Self      bool
+	Character string // Whose log the line came from
+	Time      time.Time
+}
+
+func (e *Engine) queueChat(msg ChatMessage) {
+	e.chatMu.Lock()
+	defer e.chatMu.Unlock()
+	if len(e.chat) >= maxQueuedChat {
+		e.chat = e.chat[1:]
+	}
+	e.chat = append(e.chat, msg)
+}
+
+// DrainChat returns the chat lines parsed since the last call.
+func (e *Engine) DrainChat() []ChatMessage {
+	e.chatMu.Lock()
+	defer e.chatMu.Unlock()
+	msgs := e.chat
+	e.chat = nil
+	return msgs
+}
@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// considerDistance is how far in front of the player a considered mob is
+// assumed to stand (you have to be facing it, but /loc can't say how far).
+const considerDistance = 25.0
+
+// Con colors, from the level half of a consider message.
+const (
+	ConGreen     = "green"
+	ConLightBlue = "lightblue"
+	ConBlue      = "blue"
+	ConWhite     = "white"
+	ConYellow    = "yellow"
+	ConRed       = "red"
+)
+
+// Target is the last mob the character considered, with an estimated
+// position in front of where they stood.
+type Target struct {
+	Name string
+	Con  string // One of the Con* colors; "" if the message wasn't recognized
+	X, Y float64
+	Zone string
+	Time time.Time
+}
+
+// conPhrases maps the level half of a consider message ("-- looks kind of
+// dangerous.") to its con color.
+var conPhrases = []struct {
+	phrase, con string
+}{
+	{"tombstone", ConRed},
+	{"dangerous", ConYellow},
+	{"even fight", ConWhite},
+	{"upper hand", ConBlue},
+	{"reasonably safe", ConLightBlue},
+	{"probably win", ConGreen},
+	{"easy", ConGreen},
+}
+
+func conFromPhrase(phrase string) string {
+	phrase = strings.ToLower(phrase)
+	for _, p := range conPhrases {
+		if strings.Contains(phrase, p.phrase) {
+			return p.con
+		}
+	}
+	return ""
+}
+
+// considerTarget builds the target for a consider message seen by state.
+func considerTarget(state *PlayerState, name, phrase string, at time.Time) *Target {
+	return &Target{
+		Name: strings.TrimSpace(name),
+		Con:  conFromPhrase(phrase),
+		X:    state.X + math.Cos(state.Heading)*considerDistance,
+		Y:    state.Y + math.Sin(state.Heading)*considerDistance,
+		Zone: state.Zone,
+		Time: at,
+	}
+}
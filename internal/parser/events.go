@@ -0,0 +1,160 @@
+package parser
+
+import (
+	"sync"
+	"time"
+)
+
+// Event is something the engine noticed in the log. Subscribers get every
+// event in order, for every tracked character (Primary tells them apart),
+// instead of comparing states from frame to frame. The bus carries changes
+// only: for where the player is now (camera follow, the info panel, menus)
+// the UI reads Engine.State.
+type Event interface {
+	event()
+}
+
+// ZoneChanged is sent when a character enters a zone. From is "" for the
+// first zone seen.
+type ZoneChanged struct {
+	Character string
+	Primary   bool
+	From, To  string
+	X, Y, Z   float64 // Position when the zone changed (map coordinates)
+	Time      time.Time
+}
+
+// PositionUpdated is sent for every /loc.
+type PositionUpdated struct {
+	Character string
+	Primary   bool
+	X, Y, Z   float64 // Map coordinates
+	Heading   float64
+	Time      time.Time
+}
+
+// Died is sent when a character dies, with the corpse it left.
+type Died struct {
+	Character string
+	Primary   bool
+	Corpse    Corpse
+}
+
+// Why a corpse stopped being outstanding
+const (
+	CorpseRecovered = "recovered" // Rezzed, summoned or looted
+	CorpseDecayed   = "decayed"
+	CorpseDismissed = "dismissed" // Cleared by hand
+)
+
+// CorpseCleared is sent when a corpse is recovered, decays or is cleared
+// by hand.
+type CorpseCleared struct {
+	Character string
+	Primary   bool
+	Corpse    Corpse
+	Reason    string
+}
+
+func (ZoneChanged) event()     {}
+func (PositionUpdated) event() {}
+func (Died) event()            {}
+func (CorpseCleared) event()   {}
+
+// maxQueuedEvents bounds a subscriber that stops draining. Only positions
+// are dropped: the next /loc supersedes them, where a lost zone change or
+// death would leave the subscriber wrong until the next one, so once only
+// those are queued the queue grows instead.
+const maxQueuedEvents = 4096
+
+// Subscription receives the engine's events. Drain them from the loop that
+// owns the subscriber's state; Ready signals when there's something new,
+// for subscribers that would rather block than poll.
+type Subscription struct {
+	Ready <-chan struct{}
+
+	ready  chan struct{}
+	mu     sync.Mutex
+	events []Event
+}
+
+// Drain returns the events sent since the last call, oldest first.
+func (s *Subscription) Drain() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	events := s.events
+	s.events = nil
+	return events
+}
+
+func (s *Subscription) push(ev Event) {
+	s.mu.Lock()
+	if len(s.events) >= maxQueuedEvents {
+		for i, queued := range s.events {
+			if _, ok := queued.(PositionUpdated); ok {
+				s.events = append(s.events[:i], s.events[i+1:]...)
+				break
+			}
+		}
+	}
+	s.events = append(s.events, ev)
+	s.mu.Unlock()
+
+	select {
+	case s.ready <- struct{}{}:
+	default: // Already signaled
+	}
+}
+
+// bus fans events out to the subscriptions.
+type bus struct {
+	mu   sync.Mutex
+	subs []*Subscription
+}
+
+// Subscribe starts receiving events. The subscription's first events are
+// the primary character's current zone and position, if known, so a
+// subscriber that starts late doesn't miss where the player already is.
+func (e *Engine) Subscribe() *Subscription {
+	ready := make(chan struct{}, 1)
+	sub := &Subscription{Ready: ready, ready: ready}
+
+	e.events.mu.Lock()
+	defer e.events.mu.Unlock()
+	if s := e.State(); s.Zone != "" {
+		sub.push(ZoneChanged{Character: s.Character, Primary: true, To: s.Zone, X: s.X, Y: s.Y, Z: s.Z, Time: time.Now()})
+		if !s.LocTime.IsZero() {
+			sub.push(PositionUpdated{Character: s.Character, Primary: true, X: s.X, Y: s.Y, Z: s.Z, Heading: s.Heading, Time: s.LocTime})
+		}
+	}
+	e.events.subs = append(e.events.subs, sub)
+	return sub
+}
+
+// Unsubscribe stops sending events to a subscription.
+func (e *Engine) Unsubscribe(sub *Subscription) {
+	e.events.mu.Lock()
+	defer e.events.mu.Unlock()
+	for i, s := range e.events.subs {
+		if s == sub {
+			e.events.subs = append(e.events.subs[:i], e.events.subs[i+1:]...)
+			return
+		}
+	}
+}
+
+func (e *Engine) publish(ev Event) {
+	e.events.mu.Lock()
+	defer e.events.mu.Unlock()
+	// Refreshed under the same lock, so Subscribe's starting events and
+	// the ones that follow neither miss nor repeat this one
+	e.publishState()
+	for _, sub := range e.events.subs {
+		sub.push(ev)
+	}
+}
+
+// isPrimary reports whether a state is the primary character's.
+func (e *Engine) isPrimary(state *PlayerState) bool {
+	return state == &e.current
+}
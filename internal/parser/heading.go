@@ -0,0 +1,34 @@
+package parser
+
+import (
+	"math"
+	"strings"
+)
+
+// PlayerState.Heading is a screen-space angle in radians (0 = east, +Y is
+// south), the same convention math.Atan2(dy, dx) gives for map movement.
+
+// headingFromUnits converts a client heading (counter-clockwise from north,
+// units per full turn) to a screen angle.
+func headingFromUnits(value, units float64) float64 {
+	clockwise := (units - math.Mod(value, units)) / units * 2 * math.Pi
+	return clockwise - math.Pi/2
+}
+
+var senseDirections = map[string]float64{
+	"north":     -math.Pi / 2,
+	"northeast": -math.Pi / 4,
+	"east":      0,
+	"southeast": math.Pi / 4,
+	"south":     math.Pi / 2,
+	"southwest": 3 * math.Pi / 4,
+	"west":      math.Pi,
+	"northwest": -3 * math.Pi / 4,
+}
+
+// headingFromDirection reads a Sense Heading direction ("NorthEast", "north east").
+func headingFromDirection(direction string) (float64, bool) {
+	key := strings.ToLower(strings.ReplaceAll(direction, " ", ""))
+	h, ok := senseDirections[key]
+	return h, ok
+}
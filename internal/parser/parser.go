@@ -3,116 +3,637 @@ package parser
 import (
 	"fmt"
 	"math"
-	"regexp"
-	"strconv"
-	"strings"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/devin-hart/nox-maps/internal/config"
 	"github.com/devin-hart/nox-maps/internal/eqlog"
+	"github.com/devin-hart/nox-maps/pkg/eqlogparse"
 )
 
 type PlayerState struct {
-	X, Y, Z    float64
-	Heading    float64
-	Zone       string
+	X, Y, Z   float64
+	Heading   float64
+	Zone      string
+	Character string
+	LocTime   time.Time // When the last /loc was parsed (drives interpolation)
+	MovedTime time.Time // When a /loc last showed the position change (idle detection)
 
-	// CORPSE STATE
-	CorpseX    float64
-	CorpseY    float64
-	CorpseZone string
-	HasCorpse  bool
+	// CORPSE STATE - one entry per unrecovered death, oldest first
+	Corpses []Corpse
+
+	Target *Target // Last considered mob (see consider.go)
+}
+
+type Corpse struct {
+	X, Y float64
+	Zone string
+	Time time.Time // When the death was logged
+}
+
+// sameDeath reports whether c and o are the same corpse, going by zone and
+// time of death.
+func (c Corpse) sameDeath(o Corpse) bool {
+	return c.Zone == o.Zone && c.Time.Equal(o.Time)
+}
+
+func (s PlayerState) HasCorpse() bool {
+	return len(s.Corpses) > 0
 }
 
 type Engine struct {
-	CurrentState PlayerState
+	// The primary character, only touched on the engine goroutine; other
+	// goroutines read the copy from State
+	current PlayerState
+
+	// Other characters tailed in multi-character mode, keyed by name.
+	// Guarded by partyMu since the UI reads it from another goroutine.
+	party   map[string]*PlayerState
+	partyMu sync.RWMutex
+
+	// Changes asked for from other goroutines (clearing corpses, zone
+	// corrections, a resumed session) run on the ProcessLines goroutine
+	// between lines, so current has one writer. Before ProcessLines
+	// starts they run straight away.
+	requests chan func()
+	runMu    sync.Mutex // Guards running, and a request run without ProcessLines
+	running  bool
+
+	// Copy of current for other goroutines, replaced after every line,
+	// event or request that may change it (see State)
+	snapshot atomic.Pointer[PlayerState]
+
+	// Regexes used by ProcessLines; swapped atomically on config reload
+	patterns atomic.Pointer[Patterns]
+
+	// Chat lines waiting for the UI (see chat.go)
+	chat   []ChatMessage
+	chatMu sync.Mutex
+
+	// Kills, deaths and experience waiting for the UI (see stats.go)
+	stats   []StatEvent
+	statsMu sync.Mutex
+
+	// User log rules and the hits waiting for the UI (see rules.go)
+	rules  atomic.Pointer[ruleSet]
+	hits   []RuleHit
+	hitsMu sync.Mutex
+
+	// Subscribers to zone changes, positions and deaths (see events.go)
+	events bus
+
+	// Previous position per character, to derive heading from movement
+	trackers map[string]*movementTracker
+
+	// Quiet silences the per-event console messages (offline analysis)
+	Quiet bool
+
+	// ZoneLookup turns a zone as logged into the name used everywhere else
+	// (servers that report short names); set before ProcessLines
+	ZoneLookup func(string) string
 }
 
+// movementTracker remembers the previous position of one character so
+// heading can be derived from movement.
+type movementTracker struct {
+	lastX, lastY float64
+	hasMoved     bool
+	trueHeading  bool      // The log reports heading with /loc; don't infer it
+	zoningSince  time.Time // LOADING seen and the new zone not yet known
+}
+
+// idleTolerance is how far (map units) a character can drift between /locs
+// and still count as standing still.
+const idleTolerance = 1.0
+
+// zoningHold is how long /locs are ignored after the client starts zoning
+// without the new zone being logged; they'd land on the old zone's map.
+const zoningHold = 10 * time.Second
+
 func NewEngine() *Engine {
-	return &Engine{}
+	e := &Engine{
+		party:    make(map[string]*PlayerState),
+		trackers: make(map[string]*movementTracker),
+		requests: make(chan func(), 64),
+	}
+	p, _ := CompilePatterns(config.ParserOverrides{})
+	e.patterns.Store(p)
+	return e
+}
+
+// State returns the primary character's state as of the last line or
+// request handled, safe to call from any goroutine. The Corpses slice is
+// shared between callers and mustn't be modified.
+func (e *Engine) State() PlayerState {
+	if s := e.snapshot.Load(); s != nil {
+		return *s
+	}
+	return PlayerState{}
+}
+
+// publishState replaces the copy State returns. Called on the goroutine
+// that writes current.
+func (e *Engine) publishState() {
+	s := e.current
+	s.Corpses = append([]Corpse(nil), s.Corpses...) // Don't share with the parser
+	if s.Target != nil {
+		target := *s.Target
+		s.Target = &target
+	}
+	e.snapshot.Store(&s)
+}
+
+// PartyMembers returns a snapshot of every non-primary character, sorted by name.
+func (e *Engine) PartyMembers() []PlayerState {
+	e.partyMu.RLock()
+	defer e.partyMu.RUnlock()
+	return e.partyMembers()
+}
+
+func (e *Engine) partyMembers() []PlayerState {
+	members := make([]PlayerState, 0, len(e.party))
+	for _, s := range e.party {
+		member := *s
+		member.Corpses = append([]Corpse(nil), s.Corpses...) // Don't share with the parser
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Character < members[j].Character
+	})
+	return members
+}
+
+// AllCharacters returns the primary character followed by the party.
+func (e *Engine) AllCharacters() []PlayerState {
+	// Both under partyMu, so a character switch (see stateFor) can't show
+	// someone twice or not at all
+	e.partyMu.RLock()
+	defer e.partyMu.RUnlock()
+	return append([]PlayerState{e.State()}, e.partyMembers()...)
 }
 
+// ClearCorpse removes one corpse of the named character (primary or
+// party), found by its zone and time of death since an index could be stale
+// by the time the request runs. It takes effect on the engine goroutine; a
+// CorpseCleared event follows.
+func (e *Engine) ClearCorpse(character string, corpse Corpse) {
+	e.request(func() {
+		var cleared []Corpse
+		primary := e.withCharacter(character, func(s *PlayerState) {
+			for i, c := range s.Corpses {
+				if c.sameDeath(corpse) {
+					cleared = append(cleared, c)
+					s.Corpses = append(s.Corpses[:i:i], s.Corpses[i+1:]...)
+					break
+				}
+			}
+		})
+		e.publishCleared(character, primary, cleared)
+	})
+}
+
+// ClearCorpses removes every corpse of the named character, like ClearCorpse.
+func (e *Engine) ClearCorpses(character string) {
+	e.request(func() {
+		var cleared []Corpse
+		primary := e.withCharacter(character, func(s *PlayerState) {
+			cleared = s.Corpses
+			s.Corpses = nil
+		})
+		e.publishCleared(character, primary, cleared)
+	})
+}
+
+// request runs fn on the ProcessLines goroutine, or now if that isn't
+// running, then publishes the state it may have changed.
+func (e *Engine) request(fn func()) {
+	run := func() {
+		fn()
+		e.publishState()
+	}
+	e.runMu.Lock()
+	if !e.running {
+		run()
+		e.runMu.Unlock()
+		return
+	}
+	e.runMu.Unlock()
+
+	// Sent without runMu: waiting on a full queue while holding it would
+	// keep ProcessLines from stopping, and so from emptying the queue
+	e.requests <- run
+
+	// If ProcessLines stopped before taking it, run it here
+	e.runMu.Lock()
+	if !e.running {
+		e.runRequests()
+	}
+	e.runMu.Unlock()
+}
+
+// runRequests runs the queued requests without waiting for more.
+func (e *Engine) runRequests() {
+	for {
+		select {
+		case fn := <-e.requests:
+			fn()
+		default:
+			return
+		}
+	}
+}
+
+func (e *Engine) publishCleared(character string, primary bool, corpses []Corpse) {
+	for _, c := range corpses {
+		e.publish(CorpseCleared{Character: character, Primary: primary, Corpse: c, Reason: CorpseDismissed})
+	}
+}
+
+// SetZone corrects the primary character's zone when the log missed the
+// change (see ui/sanity.go). It takes effect on the engine goroutine; a
+// ZoneChanged event follows.
+func (e *Engine) SetZone(zone string) {
+	e.request(func() { e.setZone(zone) })
+}
+
+func (e *Engine) setZone(zone string) {
+	s := &e.current
+	if zone == s.Zone {
+		return
+	}
+	from := s.Zone
+	s.Zone = zone
+	e.publish(ZoneChanged{Character: s.Character, Primary: true, From: from, To: zone, X: s.X, Y: s.Y, Z: s.Z, Time: time.Now()})
+}
+
+// Resume restores state carried over from another machine (see
+// config.SessionFile); states[0] is that session's primary character. It
+// takes the position only if the log hasn't reported one yet, and corpses
+// already known are not added twice. Like SetZone it takes effect on the
+// engine goroutine.
+func (e *Engine) Resume(states []PlayerState) {
+	e.request(func() {
+		for i, s := range states {
+			if i == 0 && (e.current.Character == "" || e.current.Character == s.Character) {
+				if e.current.Zone == "" {
+					e.current.X, e.current.Y, e.current.Z = s.X, s.Y, s.Z
+					e.current.Heading = s.Heading
+					e.setZone(s.Zone)
+				}
+				e.current.Character = s.Character
+				e.current.Corpses = mergeCorpses(e.current.Corpses, s.Corpses)
+				continue
+			}
+			if s.Character == e.current.Character {
+				e.current.Corpses = mergeCorpses(e.current.Corpses, s.Corpses)
+				continue
+			}
+
+			if len(s.Corpses) == 0 {
+				continue // Other characters' positions would be stale
+			}
+			e.partyMu.Lock()
+			member, ok := e.party[s.Character]
+			if !ok {
+				member = &PlayerState{Character: s.Character}
+				e.party[s.Character] = member
+			}
+			member.Corpses = mergeCorpses(member.Corpses, s.Corpses)
+			e.partyMu.Unlock()
+		}
+	})
+}
+
+// mergeCorpses adds the corpses not already in have, keeping death order.
+func mergeCorpses(have, add []Corpse) []Corpse {
+	for _, c := range add {
+		known := false
+		for _, h := range have {
+			if h.sameDeath(c) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			have = append(have, c)
+		}
+	}
+	sort.SliceStable(have, func(i, j int) bool {
+		return have[i].Time.Before(have[j].Time)
+	})
+	return have
+}
+
+// withCharacter runs fn on the named character's state, reporting whether
+// that's the primary character.
+func (e *Engine) withCharacter(character string, fn func(*PlayerState)) bool {
+	if character == e.current.Character {
+		fn(&e.current)
+		return true
+	}
+	e.partyMu.Lock()
+	defer e.partyMu.Unlock()
+	if s, ok := e.party[character]; ok {
+		fn(s)
+	}
+	return false
+}
+
+// ProcessLines handles lines until the channel closes. reader gives the
+// starting zone; it's nil when the lines don't come from a live log
+// (demo, replay).
 func (e *Engine) ProcessLines(reader *eqlog.Reader, lines <-chan eqlog.LogLine) {
-	// Compile regexes once
-	locRegex := regexp.MustCompile(`Your Location is ([0-9.-]+), ([0-9.-]+), ([0-9.-]+)`)
-	zoneRegex := regexp.MustCompile(`You have entered (.+)\.`)
+	e.runMu.Lock()
+	e.running = true
+	e.runMu.Unlock()
+	defer func() {
+		e.runMu.Lock()
+		e.running = false
+		e.runRequests()
+		e.runMu.Unlock()
+	}()
 
 	// Set initial zone if detected from log history
-	if reader.InitialZone != "" {
-		e.CurrentState.Zone = reader.InitialZone
+	if reader != nil && reader.InitialZone != "" {
 		fmt.Printf("🗺️  Starting with zone: '%s'\n", reader.InitialZone)
+		e.setZone(reader.InitialZone)
+		e.publishState()
 	}
 
-	// Track previous position to calculate heading
-	var lastX, lastY float64
-	var hasMoved bool
-
-	for logEntry := range lines {
-		line := logEntry.Line
-
-		// 1. POSITION & HEADING
-		if matches := locRegex.FindStringSubmatch(line); len(matches) == 4 {
-			eqY, _ := strconv.ParseFloat(matches[1], 64)
-			eqX, _ := strconv.ParseFloat(matches[2], 64)
-			eqZ, _ := strconv.ParseFloat(matches[3], 64)
-
-			// Map files use SWAPPED and NEGATED coordinates compared to /loc output
-			x := -eqX
-			y := -eqY
-
-			if !hasMoved {
-				fmt.Printf("📍 First position - EQ: (%.1f, %.1f) -> Map: (%.1f, %.1f)\n", eqY, eqX, x, y)
-				hasMoved = true
-			} else {
-				// Calculate heading based on movement
-				dx := x - lastX
-				dy := y - lastY
-				if math.Abs(dx) > 0.1 || math.Abs(dy) > 0.1 {
-					e.CurrentState.Heading = math.Atan2(dy, dx)
-				}
+	for {
+		select {
+		case fn := <-e.requests:
+			fn()
+		case logEntry, ok := <-lines:
+			if !ok {
+				return
 			}
+			state := e.handle(logEntry)
 
-			e.CurrentState.X = x
-			e.CurrentState.Y = y
-			e.CurrentState.Z = eqZ
-			lastX = x
-			lastY = y
-			continue
+			// 5. USER RULES (after the built-in handlers, so they see the new state)
+			e.applyRules(logEntry, state)
+
+			if e.isPrimary(state) {
+				e.publishState()
+			}
 		}
+	}
+}
 
-		// 2. ZONE
-		if matches := zoneRegex.FindStringSubmatch(line); len(matches) == 2 {
-			newZone := matches[1]
+// ProcessLine runs the built-in handlers for one line without user rules,
+// for offline tools (cmd/analyze) that feed old logs in directly.
+func (e *Engine) ProcessLine(logEntry eqlog.LogLine) {
+	if state := e.handle(logEntry); e.isPrimary(state) {
+		e.publishState()
+	}
+}
 
-			// Filter out status messages that aren't real zones
-			// e.g., "an Arena (PvP) area" is a status, not a zone name
-			if strings.Contains(newZone, "(PvP)") ||
-			   strings.HasSuffix(newZone, " area") {
-				continue
+// handle runs the built-in handlers and returns the state the line updated.
+func (e *Engine) handle(logEntry eqlog.LogLine) *PlayerState {
+	patterns := e.patterns.Load()
+
+	track, ok := e.trackers[logEntry.Character]
+	if !ok {
+		track = &movementTracker{}
+		e.trackers[logEntry.Character] = track
+	}
+
+	// Lines from the primary log drive current; everything else
+	// updates that character's party entry.
+	state := e.stateFor(logEntry)
+
+	e.processLine(logEntry, patterns, track, state)
+	return state
+}
+
+// processLine runs the built-in handlers for one line.
+func (e *Engine) processLine(logEntry eqlog.LogLine, patterns *Patterns, track *movementTracker, state *PlayerState) {
+	line := logEntry.Line
+
+	switch ev := patterns.Classify(line).(type) {
+	// 1. POSITION & HEADING
+	case eqlogparse.Location:
+		if !track.zoningSince.IsZero() && logEntry.Time.Sub(track.zoningSince) < zoningHold {
+			return
+		}
+		if ev.Err != nil {
+			// A misread /loc would jump the player to 0,0; skip it instead
+			e.logf("⚠️  Skipping unreadable /loc (%v); check server_profile.parser.numbers\n", ev.Err)
+			return
+		}
+
+		// Map files use SWAPPED and NEGATED coordinates compared to /loc output
+		x := -ev.X
+		y := -ev.Y
+
+		e.lockParty(logEntry)
+		if ev.HasHeading {
+			state.Heading = headingFromUnits(ev.Heading, patterns.HeadingUnits)
+			track.trueHeading = true
+		}
+		if !track.hasMoved {
+			e.logf("📍 First position - EQ: (%.1f, %.1f) -> Map: (%.1f, %.1f)\n", ev.Y, ev.X, x, y)
+			track.hasMoved = true
+		} else if !track.trueHeading {
+			// Calculate heading based on movement
+			dx := x - track.lastX
+			dy := y - track.lastY
+			if math.Abs(dx) > 0.1 || math.Abs(dy) > 0.1 {
+				state.Heading = math.Atan2(dy, dx)
 			}
+		}
+
+		if state.MovedTime.IsZero() || math.Hypot(x-state.X, y-state.Y) > idleTolerance {
+			state.MovedTime = time.Now()
+		}
+		state.X = x
+		state.Y = y
+		state.Z = ev.Z
+		state.LocTime = time.Now()
+		moved := PositionUpdated{Character: state.Character, Primary: e.isPrimary(state), X: x, Y: y, Z: ev.Z, Heading: state.Heading, Time: state.LocTime}
+		e.unlockParty(logEntry)
+		track.lastX = x
+		track.lastY = y
+		e.publish(moved)
+
+	// 2. ZONE
+	case eqlogparse.Loading:
+		track.zoningSince = logEntry.Time
+	case eqlogparse.ZoneEntered:
+		newZone := e.lookupZone(ev.Zone)
+		track.zoningSince = time.Time{}
 
-			if newZone != e.CurrentState.Zone {
-				fmt.Printf("🌍 Zone detected: '%s'\n", newZone)
-				e.CurrentState.Zone = newZone
+		e.lockParty(logEntry)
+		from := state.Zone
+		if newZone != from {
+			e.logf("🌍 Zone detected: '%s'%s\n", newZone, characterSuffix(logEntry))
+			state.Zone = newZone
+		}
+		changed := ZoneChanged{Character: state.Character, Primary: e.isPrimary(state), From: from, To: newZone, X: state.X, Y: state.Y, Z: state.Z, Time: logEntry.Time}
+		e.unlockParty(logEntry)
+		if newZone != from {
+			e.publish(changed)
+		}
+
+	// 2b. SENSE HEADING ("You think you are heading NorthEast.")
+	case eqlogparse.SenseHeading:
+		if h, ok := headingFromDirection(ev.Direction); ok {
+			e.lockParty(logEntry)
+			state.Heading = h
+			e.unlockParty(logEntry)
+		}
+
+	// 2c. CHAT (camp claims and lists come from OOC, shared locs from
+	// group and guild)
+	case eqlogparse.Chat:
+		e.queueChat(ChatMessage{
+			Channel:   ev.Channel,
+			Speaker:   ev.Speaker,
+			Text:      ev.Text,
+			Self:      ev.Self,
+			Character: logEntry.Character,
+			Time:      logEntry.Time,
+		})
+
+	// 2d. CONSIDER ("a gnoll regards you indifferently -- looks kind of dangerous.")
+	case eqlogparse.Consider:
+		e.lockParty(logEntry)
+		state.Target = considerTarget(state, ev.Target, ev.Level, logEntry.Time)
+		e.unlockParty(logEntry)
+
+	// 2e. SERVER-WIDE REPOP
+	case eqlogparse.Repop:
+		e.logf("🌋 Server-wide repop%s\n", characterSuffix(logEntry))
+		e.queueChat(ChatMessage{
+			Channel:   ChannelRepop,
+			Text:      line,
+			Character: logEntry.Character,
+			Time:      logEntry.Time,
+		})
+
+	// 2f. KILLS & EXPERIENCE (session stats)
+	case eqlogparse.Kill:
+		e.queueStat(StatEvent{Kind: StatKill, Character: state.Character, Target: ev.Target, Time: logEntry.Time})
+	case eqlogparse.Experience:
+		e.queueStat(StatEvent{Kind: StatExperience, Character: state.Character, Time: logEntry.Time})
+
+	// 3. DEATH
+	case eqlogparse.Death:
+		e.lockParty(logEntry)
+		corpse := Corpse{X: state.X, Y: state.Y, Zone: state.Zone, Time: logEntry.Time}
+		state.Corpses = append(state.Corpses, corpse)
+		count := len(state.Corpses)
+		died := Died{Character: state.Character, Primary: e.isPrimary(state), Corpse: corpse}
+		e.unlockParty(logEntry)
+		e.queueStat(StatEvent{Kind: StatDeath, Character: state.Character, Time: logEntry.Time})
+		e.publish(died)
+		e.logf("💀 Died in zone: '%s' at (%.1f, %.1f), %d corpse(s) outstanding%s\n", state.Zone, state.X, state.Y, count, characterSuffix(logEntry))
+
+	// 4. RECOVERY - Multiple ways to recover corpse (see eqlogparse.DefaultPatterns)
+	case eqlogparse.Recovery:
+		e.lockParty(logEntry)
+		corpse, recovered := recoverCorpse(state, ev.Decayed)
+		cleared := CorpseCleared{Character: state.Character, Primary: e.isPrimary(state), Corpse: corpse, Reason: CorpseRecovered}
+		e.unlockParty(logEntry)
+		if recovered {
+			e.logf("💀 Corpse recovered/cleared%s\n", characterSuffix(logEntry))
+			if ev.Decayed {
+				cleared.Reason = CorpseDecayed
 			}
-			continue
+			e.publish(cleared)
 		}
+	}
+}
 
-		// 3. DEATH
-		if strings.Contains(line, "You have been slain") {
-			e.CurrentState.CorpseX = e.CurrentState.X
-			e.CurrentState.CorpseY = e.CurrentState.Y
-			e.CurrentState.CorpseZone = e.CurrentState.Zone
-			e.CurrentState.HasCorpse = true
-			fmt.Printf("💀 Died in zone: '%s' at (%.1f, %.1f)\n", e.CurrentState.CorpseZone, e.CurrentState.CorpseX, e.CurrentState.CorpseY)
-			continue
+// recoverCorpse removes the corpse a recovery message most likely refers
+// to: decay takes the oldest; a rez or summon the newest in the current
+// zone (or the newest anywhere).
+func recoverCorpse(state *PlayerState, decayed bool) (Corpse, bool) {
+	n := len(state.Corpses)
+	if n == 0 {
+		return Corpse{}, false
+	}
+	index := n - 1
+	if decayed {
+		index = 0
+	} else {
+		for i := n - 1; i >= 0; i-- {
+			if state.Corpses[i].Zone == state.Zone {
+				index = i
+				break
+			}
 		}
+	}
+	corpse := state.Corpses[index]
+	state.Corpses = append(state.Corpses[:index:index], state.Corpses[index+1:]...)
+	return corpse, true
+}
 
-		// 4. RECOVERY - Multiple ways to recover corpse
-		if strings.Contains(line, "Summoning") && strings.Contains(line, "corpse") ||
-			strings.Contains(line, "You receive a resurrection") ||
-			strings.Contains(line, "You have been resurrected") ||
-			strings.Contains(line, "corpse decays") {
-			e.CurrentState.HasCorpse = false
-			fmt.Printf("💀 Corpse recovered/cleared\n")
+// stateFor returns the state a log line should update. When the primary log
+// switches to another character, the old primary is moved into the party and
+// the new one (if already known) is promoted to current.
+func (e *Engine) stateFor(logEntry eqlog.LogLine) *PlayerState {
+	if logEntry.Primary || logEntry.Character == "" {
+		if logEntry.Character != "" && logEntry.Character != e.current.Character {
+			e.partyMu.Lock()
+			if e.current.Character != "" {
+				old := e.current
+				e.party[old.Character] = &old
+			}
+			from := e.current.Zone
+			if known, ok := e.party[logEntry.Character]; ok {
+				e.current = *known
+				delete(e.party, logEntry.Character)
+			}
+			e.current.Character = logEntry.Character
+			e.publishState() // With the party change, for AllCharacters
+			e.partyMu.Unlock()
+
+			// The map follows the primary character, wherever the new one is
+			if s := e.current; s.Zone != from {
+				e.publish(ZoneChanged{Character: s.Character, Primary: true, From: from, To: s.Zone, X: s.X, Y: s.Y, Z: s.Z, Time: logEntry.Time})
+			}
 		}
+		return &e.current
 	}
-}
\ No newline at end of file
+
+	e.partyMu.Lock()
+	defer e.partyMu.Unlock()
+	s, ok := e.party[logEntry.Character]
+	if !ok {
+		e.logf("👥 Tracking party member: %s\n", logEntry.Character)
+		s = &PlayerState{Character: logEntry.Character}
+		e.party[logEntry.Character] = s
+	}
+	return s
+}
+
+// lockParty/unlockParty guard writes to party entries. current isn't
+// locked: it's only written on the ProcessLines goroutine (other goroutines'
+// changes come in through request), and the UI reads State instead.
+func (e *Engine) lockParty(logEntry eqlog.LogLine) {
+	if !logEntry.Primary && logEntry.Character != "" {
+		e.partyMu.Lock()
+	}
+}
+
+func (e *Engine) unlockParty(logEntry eqlog.LogLine) {
+	if !logEntry.Primary && logEntry.Character != "" {
+		e.partyMu.Unlock()
+	}
+}
+
+func (e *Engine) logf(format string, args ...any) {
+	if !e.Quiet {
+		fmt.Printf(format, args...)
+	}
+}
+
+func characterSuffix(logEntry eqlog.LogLine) string {
+	if logEntry.Primary || logEntry.Character == "" {
+		return ""
+	}
+	return fmt.Sprintf(" [%s]", logEntry.Character)
+}
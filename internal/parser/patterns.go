@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/pkg/eqlogparse"
+)
+
+// Patterns classify log lines (see pkg/eqlogparse) for the engine.
+type Patterns struct {
+	*eqlogparse.Classifier
+
+	HeadingUnits float64
+
+	source config.ParserOverrides // What they were compiled from
+}
+
+const defaultHeadingUnits = 512
+
+// CompilePatterns builds the pattern set from config overrides. An override
+// that doesn't compile (or lacks the required capture groups) is reported
+// and the default is used in its place, so a typo can't break tracking.
+func CompilePatterns(o config.ParserOverrides) (*Patterns, []error) {
+	c, errs := eqlogparse.Compile(eqlogparse.Patterns{
+		Location:     o.Location,
+		ZoneEntry:    o.ZoneEntry,
+		Death:        o.Death,
+		Recovery:     o.Recovery,
+		SenseHeading: o.SenseHeading,
+		OOC:          o.OOC,
+		Group:        o.Group,
+		Guild:        o.Guild,
+		Consider:     o.Consider,
+		Repop:        o.Repop,
+		Kill:         o.Kill,
+		Experience:   o.Experience,
+		Loading:      o.Loading,
+		Who:          o.Who,
+		Tracking:     o.Tracking,
+		Numbers:      o.Numbers,
+	})
+	p := &Patterns{Classifier: c, HeadingUnits: o.HeadingUnits, source: o}
+	if p.HeadingUnits <= 0 {
+		p.HeadingUnits = defaultHeadingUnits
+	}
+	return p, errs
+}
+
+// SetOverrides recompiles the patterns if the overrides changed. Safe to
+// call while ProcessLines is running (used for config hot-reload).
+func (e *Engine) SetOverrides(o config.ParserOverrides) {
+	if current := e.patterns.Load(); current != nil && current.source == o {
+		return
+	}
+	p, errs := CompilePatterns(o)
+	for _, err := range errs {
+		e.logf("⚠️  Ignoring parser override: %v\n", err)
+	}
+	e.patterns.Store(p)
+	e.logf("🔧 Parser patterns loaded\n")
+}
+
+// IsPosition reports whether a line is a /loc result under the current
+// patterns. The log reader calls it from its own goroutine.
+func (e *Engine) IsPosition(line string) bool {
+	return e.patterns.Load().Location.MatchString(line)
+}
+
+// MustKeep reports whether losing a line would corrupt tracked state (zone
+// changes and zoning, deaths, corpse recovery), for the log reader's overflow policy.
+func (e *Engine) MustKeep(line string) bool {
+	p := e.patterns.Load()
+	return p.ZoneEntry.MatchString(line) || p.Loading.MatchString(line) || p.Death.MatchString(line) || p.Recovery.MatchString(line)
+}
+
+// ZoneOf returns the zone a line reports entering, resolved through
+// ZoneLookup, or "" if it doesn't report one. The log reader uses it to
+// find the zone at startup.
+func (e *Engine) ZoneOf(line string) string {
+	zone := e.patterns.Load().Zone(line)
+	if zone != "" {
+		zone = e.lookupZone(zone)
+	}
+	return zone
+}
+
+func (e *Engine) lookupZone(zone string) string {
+	if e.ZoneLookup != nil {
+		return e.ZoneLookup(zone)
+	}
+	return zone
+}
@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/eqlog"
+	"github.com/devin-hart/nox-maps/internal/rules"
+)
+
+// RuleHit is a user rule that fired, with where the character was at the
+// time. The UI carries out the action.
+type RuleHit struct {
+	rules.Match
+	Character string
+	Zone      string
+	X, Y      float64
+	Time      time.Time
+}
+
+// ruleSet pairs the compiled rules with the config they came from.
+type ruleSet struct {
+	set    *rules.Set
+	source []config.Rule
+}
+
+// SetRules recompiles the user rules if they changed. Safe to call while
+// ProcessLines is running.
+func (e *Engine) SetRules(list []config.Rule) {
+	if current := e.rules.Load(); current != nil && reflect.DeepEqual(current.source, list) {
+		return
+	}
+	set, errs := rules.Compile(list)
+	for _, err := range errs {
+		fmt.Printf("⚠️  Ignoring log rule: %v\n", err)
+	}
+	e.rules.Store(&ruleSet{set: set, source: append([]config.Rule(nil), list...)})
+	if set.Len() > 0 {
+		fmt.Printf("📜 %d log rules loaded\n", set.Len())
+	}
+}
+
+// applyRules queues a hit for every user rule matching the line.
+func (e *Engine) applyRules(logEntry eqlog.LogLine, state *PlayerState) {
+	current := e.rules.Load()
+	if current == nil || current.set.Len() == 0 {
+		return
+	}
+	matches := current.set.Match(logEntry.Line)
+	if len(matches) == 0 {
+		return
+	}
+
+	e.lockParty(logEntry)
+	character, zone, x, y := state.Character, state.Zone, state.X, state.Y
+	e.unlockParty(logEntry)
+
+	e.hitsMu.Lock()
+	defer e.hitsMu.Unlock()
+	for _, m := range matches {
+		if len(e.hits) >= maxQueuedChat {
+			e.hits = e.hits[1:]
+		}
+		e.hits = append(e.hits, RuleHit{Match: m, Character: character, Zone: zone, X: x, Y: y, Time: logEntry.Time})
+	}
+}
+
+// DrainRuleHits returns the rule hits since the last call.
+func (e *Engine) DrainRuleHits() []RuleHit {
+	e.hitsMu.Lock()
+	defer e.hitsMu.Unlock()
+	hits := e.hits
+	e.hits = nil
+	return hits
+}
@@ -0,0 +1,36 @@
+package parser
+
+import "time"
+
+// Stat event kinds
+const (
+	StatKill       = "kill"
+	StatDeath      = "death"
+	StatExperience = "experience"
+)
+
+// StatEvent is one kill, death or experience message for the session stats.
+type StatEvent struct {
+	Kind      string
+	Character string // "" if the log's character isn't known
+	Target    string // What was killed
+	Time      time.Time
+}
+
+func (e *Engine) queueStat(ev StatEvent) {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+	if len(e.stats) >= maxQueuedChat {
+		e.stats = e.stats[1:]
+	}
+	e.stats = append(e.stats, ev)
+}
+
+// DrainStats returns the stat events parsed since the last call.
+func (e *Engine) DrainStats() []StatEvent {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+	events := e.stats
+	e.stats = nil
+	return events
+}
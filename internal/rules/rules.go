@@ -0,0 +1,106 @@
+// Package rules matches log lines against user-defined regex -> action
+// rules from config.json. It only decides which rules fired; carrying out
+// the actions is up to the caller.
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+)
+
+// Actions a rule can take
+const (
+	ActionLog    = "log"    // Print to the console (default)
+	ActionMarker = "marker" // Drop a marker where the character stands
+	ActionSay    = "say"    // Speak the text through the speech program
+)
+
+type compiled struct {
+	rule config.Rule
+	re   *regexp.Regexp
+}
+
+// Set is a compiled list of rules.
+type Set struct {
+	rules []compiled
+}
+
+// Match is one rule that fired on a line.
+type Match struct {
+	Rule   config.Rule
+	Groups []string // Groups[0] is the whole match
+}
+
+// Compile builds a rule set. Rules with an invalid pattern or unknown
+// action are reported and left out; the rest still work.
+func Compile(list []config.Rule) (*Set, []error) {
+	s := &Set{}
+	var errs []error
+	for i, r := range list {
+		name := r.Name
+		if name == "" {
+			name = "#" + strconv.Itoa(i+1)
+		}
+		if r.Disabled {
+			continue
+		}
+		switch r.Action {
+		case "", ActionLog, ActionMarker, ActionSay:
+		default:
+			errs = append(errs, fmt.Errorf("rule %s: unknown action %q", name, r.Action))
+			continue
+		}
+		re, err := regexp.Compile(r.Pattern)
+		if err == nil && r.Pattern == "" {
+			err = fmt.Errorf("empty pattern")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %s: %v", name, err))
+			continue
+		}
+		r.Name = name
+		s.rules = append(s.rules, compiled{rule: r, re: re})
+	}
+	return s, errs
+}
+
+// Len is the number of usable rules.
+func (s *Set) Len() int {
+	return len(s.rules)
+}
+
+// Match returns every rule whose pattern matches the line, in config order.
+func (s *Set) Match(line string) []Match {
+	var matches []Match
+	for _, c := range s.rules {
+		if groups := c.re.FindStringSubmatch(line); groups != nil {
+			matches = append(matches, Match{Rule: c.rule, Groups: groups})
+		}
+	}
+	return matches
+}
+
+var groupRef = regexp.MustCompile(`\$([0-9])`)
+
+// Expand fills $0-$9 in a rule's text with the match's groups.
+func (m Match) Expand(template string) string {
+	return groupRef.ReplaceAllStringFunc(template, func(ref string) string {
+		i := int(ref[1] - '0')
+		if i < len(m.Groups) {
+			return m.Groups[i]
+		}
+		return ""
+	})
+}
+
+// Text is the rule's text expanded for this match, or the matched text if
+// the rule has none.
+func (m Match) Text() string {
+	if m.Rule.Text == "" {
+		return m.Groups[0]
+	}
+	return m.Expand(m.Rule.Text)
+}
@@ -0,0 +1,123 @@
+// Package travel plans multi-zone routes. Zone connections come from the
+// "to <zone>" labels in the map files (via the all-zones index); boats and
+// ports, which maps don't show, come from a small editable data file.
+package travel
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/maps"
+)
+
+// Edge kinds
+const (
+	KindZoneLine = "zoneline"
+	KindBoat     = "boat"
+	KindPort     = "port"
+)
+
+// Edge leaves zone From for zone To. Zone lines are left at (X, Y); boats
+// and ports take a fixed time and are boarded/cast at (X, Y) when known.
+type Edge struct {
+	From    string  `json:"from"` // File code, "*" = any zone (ports)
+	To      string  `json:"to"`
+	Kind    string  `json:"kind"`
+	X       float64 `json:"x,omitempty"`
+	Y       float64 `json:"y,omitempty"`
+	HasLoc  bool    `json:"has_loc,omitempty"`
+	Seconds float64 `json:"seconds,omitempty"` // Fixed duration (boats, ports)
+	Note    string  `json:"note,omitempty"`
+}
+
+// Graph holds every known connection plus each zone's bounds (used to
+// guess where you arrive when the destination has no matching zone line).
+type Graph struct {
+	edges  map[string][]Edge // From (lowercase) -> edges
+	ports  []Edge            // From "*"
+	bounds map[string]maps.ZoneBounds
+	labels map[string][]maps.MapLabel
+}
+
+// BuildGraph reads zone lines from the index and adds the extra links.
+func BuildGraph(idx *maps.BoundsIndex, extra []Edge) *Graph {
+	g := &Graph{
+		edges:  make(map[string][]Edge),
+		bounds: make(map[string]maps.ZoneBounds),
+		labels: make(map[string][]maps.MapLabel),
+	}
+
+	for _, b := range idx.Zones {
+		from := strings.ToLower(b.FileCode)
+		g.bounds[from] = b
+		g.labels[from] = b.Labels
+		for _, lbl := range b.Labels {
+			target, ok := zoneLineTarget(lbl.Text)
+			if !ok {
+				continue
+			}
+			to := ResolveZone(target)
+			if to == "" || to == from {
+				continue
+			}
+			g.edges[from] = append(g.edges[from], Edge{From: from, To: to, Kind: KindZoneLine, X: lbl.X, Y: lbl.Y, HasLoc: true})
+		}
+	}
+
+	for _, e := range extra {
+		e.From = strings.ToLower(e.From)
+		e.To = strings.ToLower(e.To)
+		if e.From == "*" {
+			g.ports = append(g.ports, e)
+		} else {
+			g.edges[e.From] = append(g.edges[e.From], e)
+		}
+	}
+	return g
+}
+
+// zoneLineTarget extracts the destination from a label like "to West Freeport".
+func zoneLineTarget(text string) (string, bool) {
+	if len(text) < 4 || !strings.EqualFold(text[:3], "to ") {
+		return "", false
+	}
+	return strings.TrimSpace(text[3:]), true
+}
+
+// Zones lists every zone code in the graph.
+func (g *Graph) Zones() []string {
+	codes := make([]string, 0, len(g.bounds))
+	for code := range g.bounds {
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+// arrivalPoint guesses where you appear in zone `to` when coming from
+// `from`: at its zone line back to `from`, else the middle of the map.
+func (g *Graph) arrivalPoint(to, from string) (float64, float64) {
+	for _, e := range g.edges[to] {
+		if e.To == from && e.Kind == KindZoneLine {
+			return e.X, e.Y
+		}
+	}
+	b := g.bounds[to]
+	return (b.MinX + b.MaxX) / 2, (b.MinY + b.MaxY) / 2
+}
+
+// LoadLinks reads boat/port links from a JSON file (missing file = none).
+func LoadLinks(path string) ([]Edge, error) {
+	data, err := maps.ReadFile(path) // May be the built-in copy
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var links []Edge
+	if err := json.Unmarshal(data, &links); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
@@ -0,0 +1,133 @@
+package travel
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DefaultRunSpeed is an unbuffed run speed in map units per second.
+const DefaultRunSpeed = 30.0
+
+type Options struct {
+	RunSpeed float64 // Map units per second
+	UseBoats bool
+	UsePorts bool
+}
+
+// Step is one leg of a route: crossing Zone and leaving it via Edge.
+type Step struct {
+	Zone     string
+	Edge     Edge
+	Distance float64 // Run distance inside Zone
+	Seconds  float64 // Run time plus any fixed boat/port time
+}
+
+type Route struct {
+	Steps   []Step
+	Seconds float64
+}
+
+// Plan finds the fastest route from a position in one zone to another zone.
+func (g *Graph) Plan(from string, x, y float64, to string, opts Options) (*Route, error) {
+	from, to = strings.ToLower(from), strings.ToLower(to)
+	if opts.RunSpeed <= 0 {
+		return nil, fmt.Errorf("run speed must be positive")
+	}
+	if from == to {
+		return &Route{}, nil
+	}
+
+	// Dijkstra over zones, remembering where each zone was entered
+	type visit struct {
+		seconds float64
+		x, y    float64
+		prev    string
+		step    Step
+	}
+	best := map[string]*visit{from: {x: x, y: y}}
+	done := make(map[string]bool)
+	pq := &queue{{zone: from}}
+
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(queueItem)
+		if done[item.zone] {
+			continue
+		}
+		done[item.zone] = true
+		if item.zone == to {
+			break
+		}
+		here := best[item.zone]
+
+		for _, e := range g.outgoing(item.zone, opts) {
+			if done[e.To] {
+				continue
+			}
+			var dist float64
+			if e.HasLoc {
+				dist = math.Hypot(e.X-here.x, e.Y-here.y)
+			}
+			cost := dist/opts.RunSpeed + e.Seconds
+
+			total := here.seconds + cost
+			if v, ok := best[e.To]; ok && v.seconds <= total {
+				continue
+			}
+			ax, ay := g.arrivalPoint(e.To, item.zone)
+			best[e.To] = &visit{
+				seconds: total, x: ax, y: ay, prev: item.zone,
+				step: Step{Zone: item.zone, Edge: e, Distance: dist, Seconds: cost},
+			}
+			heap.Push(pq, queueItem{zone: e.To, seconds: total})
+		}
+	}
+
+	end, ok := best[to]
+	if !ok {
+		return nil, fmt.Errorf("no known route from %s to %s", from, to)
+	}
+	route := &Route{Seconds: end.seconds}
+	for zone := to; zone != from; zone = best[zone].prev {
+		route.Steps = append([]Step{best[zone].step}, route.Steps...)
+	}
+	return route, nil
+}
+
+func (g *Graph) outgoing(zone string, opts Options) []Edge {
+	var edges []Edge
+	for _, e := range g.edges[zone] {
+		if (e.Kind == KindBoat && !opts.UseBoats) || (e.Kind == KindPort && !opts.UsePorts) {
+			continue
+		}
+		edges = append(edges, e)
+	}
+	if opts.UsePorts {
+		for _, e := range g.ports {
+			e.From = zone
+			if e.To != zone {
+				edges = append(edges, e)
+			}
+		}
+	}
+	return edges
+}
+
+type queueItem struct {
+	zone    string
+	seconds float64
+}
+
+type queue []queueItem
+
+func (q queue) Len() int            { return len(q) }
+func (q queue) Less(i, j int) bool  { return q[i].seconds < q[j].seconds }
+func (q queue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *queue) Push(x interface{}) { *q = append(*q, x.(queueItem)) }
+func (q *queue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
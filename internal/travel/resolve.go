@@ -0,0 +1,100 @@
+package travel
+
+import (
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/maps"
+)
+
+// ResolveZone maps a zone name as written on a map label ("North Desert of
+// Ro") to a file code ("nro"). Labels don't always use the exact name from
+// map_keys.json, so after exact matches it accepts the one known zone whose
+// words line up with the label's by prefix ("East Karana" -> "eastern
+// plains of karana"), then a near-miss spelling. Returns "" if unsure.
+func ResolveZone(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.ReplaceAll(name, "`", "'") // Map files use backticks for apostrophes
+	if i := strings.IndexByte(name, '('); i > 0 {
+		name = strings.TrimSpace(name[:i]) // "Qeynos Hills (Zone 2)"
+	}
+	for _, candidate := range []string{name, "the " + name, strings.TrimPrefix(name, "the ")} {
+		if code := maps.GetZoneFileName(candidate); code != "" {
+			return strings.ToLower(code)
+		}
+	}
+	for _, code := range maps.ZoneFileMap {
+		if strings.EqualFold(code, name) {
+			return strings.ToLower(code)
+		}
+	}
+
+	words := significantWords(name)
+	if len(words) == 0 {
+		return ""
+	}
+	best, matches := "", 0
+	for known, code := range maps.ZoneFileMap {
+		if wordsLineUp(words, significantWords(known)) {
+			best = strings.ToLower(code)
+			matches++
+		}
+	}
+	if matches == 1 {
+		return best
+	}
+	if matches > 1 {
+		return "" // Ambiguous
+	}
+
+	// Misspellings ("Toxullia Forest", "Cablis West"): closest name within
+	// two edits, or the only zone whose first word matches ("Kithicor Forest")
+	closest, closestDist, firstWord, firstWordMatches := "", 3, "", 0
+	flat := strings.Join(words, " ")
+	for known, code := range maps.ZoneFileMap {
+		knownWords := significantWords(known)
+		knownFlat := strings.Join(knownWords, " ")
+		if knownFlat == "" || knownFlat[0] != flat[0] {
+			continue // "Eastern Wastes" is not a typo of "Western Wastes"
+		}
+		if d := maps.EditDistance(flat, knownFlat); d < closestDist {
+			closest, closestDist = strings.ToLower(code), d
+		}
+		if knownWords[0] == words[0] {
+			firstWord = strings.ToLower(code)
+			firstWordMatches++
+		}
+	}
+	if closest != "" {
+		return closest
+	}
+	if firstWordMatches == 1 && len(words[0]) >= 4 {
+		return firstWord
+	}
+	return ""
+}
+
+func significantWords(name string) []string {
+	var words []string
+	for _, w := range strings.Fields(name) {
+		if w != "the" && w != "of" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// wordsLineUp reports whether every label word is a prefix of a word of the
+// known name, in order ("east karana" fits "eastern plains karana").
+func wordsLineUp(a, b []string) bool {
+	j := 0
+	for _, w := range a {
+		for j < len(b) && !strings.HasPrefix(b[j], w) {
+			j++
+		}
+		if j == len(b) {
+			return false
+		}
+		j++
+	}
+	return true
+}
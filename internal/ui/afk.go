@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// A character whose /locs (or shared positions) haven't moved for a while
+// is shown as AFK. The parser records when each character last moved.
+
+const defaultAFKMinutes = 5
+
+// afkMinuteChoices are what AFK After cycles through.
+var afkMinuteChoices = []int{2, 5, 10, 15, 30}
+
+func (w *Window) afkAfter() time.Duration {
+	minutes := w.Config.AFKMinutes
+	if minutes <= 0 {
+		minutes = defaultAFKMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// isAFK reports whether someone last seen moving at moved has been still
+// long enough to count as away.
+func (w *Window) isAFK(moved time.Time) bool {
+	return !moved.IsZero() && time.Since(moved) >= w.afkAfter()
+}
+
+// drawAFKBadge tags an arrow at (px, py) as AFK, in the arrow's color.
+func (w *Window) drawAFKBadge(screen *ebiten.Image, px, py float32, moved time.Time, c color.RGBA) {
+	if !w.isAFK(moved) {
+		return
+	}
+	badgeW, badgeH := float32(w.uiTextWidth("AFK")+6), float32(w.panelRowHeight())
+	bx, by := px-badgeW/2, py-12-badgeH
+	vector.DrawFilledRect(screen, bx, by, badgeW, badgeH, color.RGBA{20, 20, 20, 210}, false)
+	vector.StrokeRect(screen, bx, by, badgeW, badgeH, 1, c, false)
+	w.drawUITextIn(screen, "AFK", int(bx)+3, int(by), int(badgeH), c)
+}
+
+// updateAFKAlert sounds once when you've gone idle while holding a camp
+// in your zone, so the claim isn't lost to an unattended character.
+func (w *Window) updateAFKAlert() {
+	if !w.Config.AFKAlert || w.LogReader == nil || w.simulated {
+		return
+	}
+	s := w.LogReader.State()
+	if !w.isAFK(s.MovedTime) {
+		w.afkAlerted = false
+		return
+	}
+	if w.afkAlerted {
+		return
+	}
+	var camps []string
+	for _, c := range w.Config.CampClaims[w.logZone] {
+		if c.Claimer == s.Character {
+			camps = append(camps, c.Camp)
+		}
+	}
+	if len(camps) == 0 {
+		return
+	}
+	w.afkAlerted = true
+	fmt.Printf("💤 Idle for %.0f min while holding %s\n", time.Since(s.MovedTime).Minutes(), strings.Join(camps, ", "))
+	w.playAlert(alertAFK)
+}
+
+// afkMenuItems are the Tools menu's idle detection settings.
+func (w *Window) afkMenuItems() []MenuItem {
+	return []MenuItem{
+		{
+			Label: fmt.Sprintf("AFK After: %d min", int(w.afkAfter()/time.Minute)),
+			Action: func() {
+				next := afkMinuteChoices[0]
+				for _, m := range afkMinuteChoices {
+					if m > int(w.afkAfter()/time.Minute) {
+						next = m
+						break
+					}
+				}
+				w.Config.AFKMinutes = next
+				w.Config.Save()
+				w.openMenu = ""
+			},
+		},
+		{
+			Label: fmt.Sprintf("AFK at Camp Alert: %s", map[bool]string{true: "ON", false: "OFF"}[w.Config.AFKAlert]),
+			Action: func() {
+				w.Config.AFKAlert = !w.Config.AFKAlert
+				w.Config.Save()
+				w.openMenu = ""
+			},
+		},
+	}
+}
@@ -0,0 +1,185 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/integrations/sound"
+	"github.com/ncruces/zenity"
+)
+
+// Alert events and their default sounds
+const (
+	alertZone        = "zone"
+	alertDeath       = "death"
+	alertCorpseDecay = "corpse_decay"
+	alertRule        = "rule"
+	alertAFK         = "afk"
+
+	soundNone = "none"
+
+	defaultCorpseDecayHours = 168 // One week, as on most classic rulesets
+	corpseDecayWarning      = time.Hour
+)
+
+var alertEvents = []struct {
+	id, label, sound string
+}{
+	{alertZone, "Zone change", "chime"},
+	{alertDeath, "Death", "alarm"},
+	{alertCorpseDecay, "Corpse about to decay", "alert"},
+	{alertRule, "Log rule match", "blip"},
+	{alertAFK, "AFK while holding a camp", "alert"},
+}
+
+// startSoundAlerts starts the audio player when sound alerts are on.
+func (w *Window) startSoundAlerts() {
+	if !w.Config.SoundAlerts || w.soundPlayer != nil {
+		return
+	}
+	backend, err := sound.NewCommandBackend(w.Config.SoundCommand)
+	if err != nil {
+		w.addNotice(fmt.Sprintf("Sound alerts disabled: %v", err))
+		return
+	}
+	w.soundPlayer = sound.NewPlayer(backend)
+}
+
+func (w *Window) stopSoundAlerts() {
+	if w.soundPlayer != nil {
+		w.soundPlayer.Stop()
+		w.soundPlayer = nil
+	}
+}
+
+// alertSound is the sound configured for an event, or its default.
+func (w *Window) alertSound(event string) string {
+	if s, ok := w.Config.AlertSounds[event]; ok && s != "" {
+		return s
+	}
+	for _, e := range alertEvents {
+		if e.id == event {
+			return e.sound
+		}
+	}
+	return soundNone
+}
+
+func (w *Window) playAlert(event string) {
+	w.playSound(w.alertSound(event))
+}
+
+// playSound plays a built-in tone or WAV file; built-in tones are written
+// next to config.json the first time they're needed.
+func (w *Window) playSound(name string) {
+	if w.soundPlayer == nil || name == "" || name == soundNone {
+		return
+	}
+	path, err := sound.Resolve(filepath.Join(filepath.Dir(config.GetConfigPath()), "sounds"), name)
+	if err != nil {
+		fmt.Printf("⚠️  Alert sound %s: %v\n", name, err)
+		return
+	}
+	w.soundPlayer.Play(path)
+}
+
+// updateAlerts sounds corpses close to decaying; zone changes and deaths
+// are sounded as their events arrive (see events.go).
+func (w *Window) updateAlerts() {
+	if w.soundPlayer == nil || w.LogReader == nil {
+		return
+	}
+	decay := time.Duration(w.Config.CorpseDecayHours * float64(time.Hour))
+	if decay <= 0 {
+		decay = defaultCorpseDecayHours * time.Hour
+	}
+	for _, c := range w.outstandingCorpses() {
+		key := c.Character + "|" + c.Time.String()
+		left := decay - time.Since(c.Time)
+		if left > corpseDecayWarning || w.decayWarned[key] {
+			continue
+		}
+		if w.decayWarned == nil {
+			w.decayWarned = make(map[string]bool)
+		}
+		w.decayWarned[key] = true
+		fmt.Printf("⏳ Corpse %s in %s decays in about %.0f min\n", c.label(), c.Zone, max(left.Minutes(), 0))
+		w.playAlert(alertCorpseDecay)
+	}
+}
+
+// playRuleSound sounds a log rule that fired.
+func (w *Window) playRuleSound(rule config.Rule) {
+	if rule.Sound != "" {
+		w.playSound(rule.Sound)
+		return
+	}
+	w.playAlert(alertRule)
+}
+
+// chooseAlertSounds picks the sound for each event from the built-in tones
+// or a WAV file, previewing the choice when alerts are on.
+func (w *Window) chooseAlertSounds() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	for {
+		items := make([]string, len(alertEvents))
+		for i, e := range alertEvents {
+			items[i] = fmt.Sprintf("%s: %s", e.label, filepath.Base(w.alertSound(e.id)))
+		}
+		choice, err := zenity.List(
+			"Alert sounds:",
+			items,
+			zenity.Title("Alert Sounds"),
+		)
+		if err != nil || choice == "" {
+			return
+		}
+		label := choice[:strings.LastIndex(choice, ":")]
+		for _, e := range alertEvents {
+			if e.label == label {
+				w.chooseAlertSound(e.id, e.label)
+			}
+		}
+	}
+}
+
+const customSound = "WAV file..."
+
+func (w *Window) chooseAlertSound(event, label string) {
+	options := append(sound.Tones(), soundNone, customSound)
+	choice, err := zenity.List(
+		label+":",
+		options,
+		zenity.Title("Alert Sounds"),
+		zenity.DefaultItems(w.alertSound(event)),
+	)
+	if err != nil || choice == "" {
+		return
+	}
+	if choice == customSound {
+		choice, err = zenity.SelectFile(
+			zenity.Title("Choose Alert Sound"),
+			zenity.FileFilters{{Name: "WAV files", Patterns: []string{"*.wav"}, CaseFold: true}},
+		)
+		if err != nil {
+			return
+		}
+	}
+
+	if w.Config.AlertSounds == nil {
+		w.Config.AlertSounds = make(map[string]string)
+	}
+	w.Config.AlertSounds[event] = choice
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving alert sounds: %v\n", err)
+	}
+	w.playSound(choice)
+}
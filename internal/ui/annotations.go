@@ -0,0 +1,338 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/ncruces/zenity"
+)
+
+// Paths and polygons are drawn like map edits, one click per point, and
+// saved per zone in config.json (Annotations).
+
+const (
+	annotationPath    = "path"
+	annotationPolygon = "polygon"
+
+	annotationPickPixels   = 6.0 // Clicks within this many screen pixels of an edge hit it
+	defaultAnnotationWidth = 2.0
+)
+
+// annotationDraft is a path or polygon being drawn.
+type annotationDraft struct {
+	kind   string
+	points [][2]float64
+}
+
+func annotationKindName(kind string) string {
+	if kind == annotationPolygon {
+		return "Polygon"
+	}
+	return "Path"
+}
+
+// startAnnotation begins drawing one; asking for the kind already being
+// drawn cancels it.
+func (w *Window) startAnnotation(kind string) {
+	if w.drawing != nil && w.drawing.kind == kind {
+		w.drawing = nil
+		return
+	}
+	w.drawing = &annotationDraft{kind: kind}
+	w.placingMarker, w.placingWaypoint = false, false
+	w.ruler.active = false
+	w.setEditing(false)
+	fmt.Printf("✏️  Drawing a %s - click points, Enter finishes, Backspace undoes, Esc cancels\n", kind)
+}
+
+// updateAnnotations handles the keys while drawing.
+func (w *Window) updateAnnotations() {
+	if w.drawing == nil {
+		return
+	}
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		w.drawing = nil
+	case inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(w.drawing.points) > 0:
+		w.drawing.points = w.drawing.points[:len(w.drawing.points)-1]
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter):
+		w.finishAnnotation()
+	}
+}
+
+func (w *Window) addAnnotationPoint(worldX, worldY float64) {
+	w.drawing.points = append(w.drawing.points, [2]float64{worldX, worldY})
+}
+
+// finishAnnotation names the draft and saves it to the current zone.
+func (w *Window) finishAnnotation() {
+	d := w.drawing
+	need := 2
+	if d.kind == annotationPolygon {
+		need = 3
+	}
+	if len(d.points) < need {
+		fmt.Printf("✏️  A %s needs at least %d points\n", d.kind, need)
+		return
+	}
+	if w.CurrentZone == "" {
+		return
+	}
+
+	w.dialogOpen = true
+	label, err := zenity.Entry(
+		fmt.Sprintf("%s label (optional):", annotationKindName(d.kind)),
+		zenity.Title("New "+annotationKindName(d.kind)),
+	)
+	w.dialogOpen = false
+	w.lastMousePressed = true
+	if err != nil {
+		return // Keep drawing
+	}
+
+	a := config.Annotation{
+		ID:     config.NewMarkerID(),
+		Kind:   d.kind,
+		Label:  strings.TrimSpace(label),
+		Points: d.points,
+		Color:  config.HexColor(w.getMarkerColor(w.markerColor)),
+		Width:  defaultAnnotationWidth,
+	}
+	w.pushUndo(fmt.Sprintf("draw %s '%s'", a.Kind, a.Label))
+	if w.Config.Annotations == nil {
+		w.Config.Annotations = make(map[string][]config.Annotation)
+	}
+	w.Config.Annotations[w.CurrentZone] = append(w.Config.Annotations[w.CurrentZone], a)
+	w.drawing = nil
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving %s: %v\n", a.Kind, err)
+		return
+	}
+	fmt.Printf("✏️  Added %s '%s' (%d points) in %s\n", a.Kind, a.Label, len(a.Points), w.CurrentZone)
+}
+
+// annotationEdges calls fn for each edge; a polygon's closing edge included.
+func annotationEdges(a config.Annotation, fn func(p, q [2]float64)) {
+	for i := 1; i < len(a.Points); i++ {
+		fn(a.Points[i-1], a.Points[i])
+	}
+	if a.Kind == annotationPolygon && len(a.Points) > 2 {
+		fn(a.Points[len(a.Points)-1], a.Points[0])
+	}
+}
+
+// annotationAt returns the index of the topmost annotation with an edge
+// near a world position, or -1.
+func (w *Window) annotationAt(worldX, worldY float64) int {
+	list := w.Config.Annotations[w.CurrentZone]
+	limit := annotationPickPixels / w.Zoom
+	for i := len(list) - 1; i >= 0; i-- {
+		hit := false
+		annotationEdges(list[i], func(p, q [2]float64) {
+			if pointSegmentDistance(worldX, worldY, p[0], p[1], q[0], q[1]) <= limit {
+				hit = true
+			}
+		})
+		if hit {
+			return i
+		}
+	}
+	return -1
+}
+
+const (
+	annotationOptionColor = "Color..."
+	annotationOptionWidth = "Stroke Width..."
+)
+
+// editAnnotationAt relabels or restyles the annotation under a click.
+func (w *Window) editAnnotationAt(worldX, worldY float64) bool {
+	i := w.annotationAt(worldX, worldY)
+	if i < 0 {
+		return false
+	}
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	a := &w.Config.Annotations[w.CurrentZone][i]
+	before := w.snapshotZone(fmt.Sprintf("edit %s '%s'", a.Kind, a.Label), w.CurrentZone)
+	label, err := zenity.Entry(
+		fmt.Sprintf("%s label:", annotationKindName(a.Kind)),
+		zenity.Title("Edit "+annotationKindName(a.Kind)),
+		zenity.EntryText(a.Label),
+		zenity.ExtraButton("Style..."),
+	)
+	switch {
+	case errors.Is(err, zenity.ErrExtraButton):
+		if !w.styleAnnotation(a) {
+			return true
+		}
+	case err != nil:
+		return true
+	default:
+		a.Label = strings.TrimSpace(label)
+	}
+	w.pushUndoStep(before)
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error updating %s: %v\n", a.Kind, err)
+	}
+	return true
+}
+
+// styleAnnotation changes the color or stroke width; false if cancelled.
+func (w *Window) styleAnnotation(a *config.Annotation) bool {
+	choice, err := zenity.List(
+		fmt.Sprintf("%s style:", annotationKindName(a.Kind)),
+		[]string{annotationOptionColor, annotationOptionWidth},
+		zenity.Title("Edit "+annotationKindName(a.Kind)),
+	)
+	if err != nil {
+		return false
+	}
+	switch choice {
+	case annotationOptionColor:
+		picked, err := zenity.SelectColor(zenity.Title("Color"), zenity.Color(config.ParseColor(a.Color)))
+		if err != nil || picked == nil {
+			return false
+		}
+		a.Color = config.HexColor(picked)
+	case annotationOptionWidth:
+		entry, err := zenity.Entry(
+			"Stroke width in pixels:",
+			zenity.Title("Stroke Width"),
+			zenity.EntryText(strconv.FormatFloat(annotationWidth(*a), 'f', -1, 64)),
+		)
+		if err != nil {
+			return false
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(entry), 64)
+		if err != nil || v <= 0 || v > 20 {
+			zenity.Error("Stroke width must be a number from 1 to 20.", zenity.Title("Stroke Width"))
+			return false
+		}
+		a.Width = v
+	default:
+		return false
+	}
+	return true
+}
+
+// removeAnnotationAt deletes the annotation under a right-click, after
+// asking.
+func (w *Window) removeAnnotationAt(worldX, worldY float64) bool {
+	i := w.annotationAt(worldX, worldY)
+	if i < 0 {
+		return false
+	}
+	list := w.Config.Annotations[w.CurrentZone]
+	a := list[i]
+
+	w.dialogOpen = true
+	err := zenity.Question(
+		fmt.Sprintf("Delete %s '%s'?", a.Kind, a.Label),
+		zenity.Title("Confirm Delete"),
+		zenity.OKLabel("Delete"),
+		zenity.CancelLabel("Cancel"),
+	)
+	w.dialogOpen = false
+	w.lastMousePressed = true
+	if err != nil {
+		return false
+	}
+
+	w.pushUndo(fmt.Sprintf("delete %s '%s'", a.Kind, a.Label))
+	w.Config.Annotations[w.CurrentZone] = append(list[:i:i], list[i+1:]...)
+	if len(w.Config.Annotations[w.CurrentZone]) == 0 {
+		delete(w.Config.Annotations, w.CurrentZone)
+	}
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error deleting %s: %v\n", a.Kind, err)
+		return true
+	}
+	fmt.Printf("🗑️  Deleted %s '%s'\n", a.Kind, a.Label)
+	return true
+}
+
+func annotationWidth(a config.Annotation) float64 {
+	if a.Width > 0 {
+		return a.Width
+	}
+	return defaultAnnotationWidth
+}
+
+// drawAnnotations draws the zone's paths and polygons, then the one being
+// drawn with a rubber band to the cursor.
+func (w *Window) drawAnnotations(screen *ebiten.Image, cx, cy float64) {
+	for _, a := range w.Config.Annotations[w.CurrentZone] {
+		w.drawAnnotation(screen, cx, cy, a.Kind, a.Points, config.ParseColor(a.Color), float32(annotationWidth(a)))
+		if a.Label != "" && len(a.Points) > 0 {
+			x, y := annotationLabelPoint(a)
+			sx, sy := w.toScreen(x, y, cx, cy)
+			w.drawUIText(screen, a.Label, int(sx)+6, int(sy)-6, config.ParseColor(a.Color))
+		}
+	}
+
+	if w.drawing == nil || len(w.drawing.points) == 0 {
+		return
+	}
+	mx, my := ebiten.CursorPosition()
+	var cursor [2]float64
+	cursor[0], cursor[1] = w.toWorld(float64(mx), float64(my), cx, cy)
+	points := append(append([][2]float64(nil), w.drawing.points...), cursor)
+	c := w.getMarkerColor(w.markerColor)
+	w.drawAnnotation(screen, cx, cy, w.drawing.kind, points, color.RGBA{c.R, c.G, c.B, 180}, defaultAnnotationWidth)
+	for _, p := range w.drawing.points {
+		sx, sy := w.toScreen32(p[0], p[1], cx, cy)
+		vector.StrokeCircle(screen, sx, sy, 4, 1.5, c, w.antiAlias)
+	}
+}
+
+func (w *Window) drawAnnotation(screen *ebiten.Image, cx, cy float64, kind string, points [][2]float64, c color.RGBA, width float32) {
+	if len(points) < 2 {
+		return
+	}
+	var path vector.Path
+	for i, p := range points {
+		sx, sy := w.toScreen32(p[0], p[1], cx, cy)
+		if i == 0 {
+			path.MoveTo(sx, sy)
+		} else {
+			path.LineTo(sx, sy)
+		}
+	}
+	if kind == annotationPolygon {
+		path.Close()
+		fill := &vector.DrawPathOptions{AntiAlias: w.antiAlias}
+		fill.ColorScale.ScaleWithColor(color.RGBA{c.R, c.G, c.B, 50})
+		vector.FillPath(screen, &path, &vector.FillOptions{FillRule: vector.FillRuleEvenOdd}, fill)
+	}
+	stroke := &vector.DrawPathOptions{AntiAlias: w.antiAlias}
+	stroke.ColorScale.ScaleWithColor(c)
+	vector.StrokePath(screen, &path, &vector.StrokeOptions{Width: width, LineJoin: vector.LineJoinRound, LineCap: vector.LineCapRound}, stroke)
+}
+
+// annotationLabelPoint is where the label goes: a polygon's vertex average,
+// a path's middle point.
+func annotationLabelPoint(a config.Annotation) (float64, float64) {
+	if a.Kind != annotationPolygon {
+		p := a.Points[len(a.Points)/2]
+		return p[0], p[1]
+	}
+	var x, y float64
+	for _, p := range a.Points {
+		x += p[0]
+		y += p[1]
+	}
+	n := float64(len(a.Points))
+	return x / n, y / n
+}
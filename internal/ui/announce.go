@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/integrations/speech"
+	"github.com/devin-hart/nox-maps/internal/nav"
+)
+
+const defaultAnnounceEvery = 30 * time.Second
+
+// startAnnouncements starts the speech backend when announcements are on.
+func (w *Window) startAnnouncements() {
+	if !w.Config.Announcements || w.speaker != nil {
+		return
+	}
+	backend, err := speech.NewCommandBackend(w.Config.SpeechCommand)
+	if err != nil {
+		w.addNotice(fmt.Sprintf("Announcements disabled: %v", err))
+		return
+	}
+	w.speaker = speech.NewSpeaker(backend)
+	w.announcedZone = ""
+	w.lastAnnounce = time.Time{}
+}
+
+func (w *Window) stopAnnouncements() {
+	if w.speaker != nil {
+		w.speaker.Stop()
+		w.speaker = nil
+	}
+}
+
+// announce speaks a phrase and echoes it to the console.
+func (w *Window) announce(text string) {
+	if w.speaker == nil || text == "" {
+		return
+	}
+	if w.speaker.Say(text) {
+		fmt.Printf("🔊 %s\n", text)
+	}
+}
+
+// updateAnnouncements speaks zone changes right away and a status summary
+// (corpse, waypoint, nearest marker) periodically or on demand.
+func (w *Window) updateAnnouncements() {
+	onDemand := w.keyTriggered(ActionAnnounce)
+	if w.speaker == nil || w.LogReader == nil {
+		return
+	}
+	if w.logZone != "" && w.logZone != w.announcedZone {
+		w.announcedZone = w.logZone
+		w.announce("Entered " + w.logZone)
+	}
+
+	every := time.Duration(w.Config.AnnounceEvery) * time.Second
+	if every <= 0 {
+		every = defaultAnnounceEvery
+	}
+	if !onDemand && time.Since(w.lastAnnounce) < every {
+		return
+	}
+	w.lastAnnounce = time.Now()
+	status := w.statusAnnouncement()
+	if status == "" && onDemand {
+		status = "Nothing to report in " + w.logZone
+	}
+	w.announce(status)
+}
+
+// statusAnnouncement describes what's around the player in a sentence or
+// three, e.g. "Corpse 300 units north. Waypoint 120 units east."
+func (w *Window) statusAnnouncement() string {
+	s := w.LogReader.State()
+	var parts []string
+
+	nearest := math.Inf(1)
+	var corpseX, corpseY float64
+	for _, c := range s.Corpses {
+		if d := nav.Distance(s.X, s.Y, c.X, c.Y); c.Zone == w.logZone && d < nearest {
+			nearest, corpseX, corpseY = d, c.X, c.Y
+		}
+	}
+	if !math.IsInf(nearest, 1) {
+		parts = append(parts, "Corpse "+w.spokenOffset(s.X, s.Y, corpseX, corpseY))
+	}
+
+	if w.Nav.Active() {
+		label := "Waypoint"
+		if w.Nav.Target.Label != "" {
+			label = w.Nav.Target.Label
+		}
+		parts = append(parts, label+" "+w.spokenOffset(s.X, s.Y, w.Nav.Target.X, w.Nav.Target.Y))
+	}
+
+	nearest = math.Inf(1)
+	var marker string
+	var markerX, markerY float64
+	for _, m := range w.Config.ZoneMarkers(w.logZone) {
+		if w.Config.CategoryHidden(m.Category) || w.Config.MarkerHidden(w.logZone, m.ID) || m.Label == "" {
+			continue
+		}
+		if d := nav.Distance(s.X, s.Y, m.X, m.Y); d < nearest {
+			nearest, marker, markerX, markerY = d, m.Label, m.X, m.Y
+		}
+	}
+	if marker != "" {
+		parts = append(parts, "Nearest marker "+marker+", "+w.spokenOffset(s.X, s.Y, markerX, markerY))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, ". ") + "."
+}
+
+// spokenOffset reads a distance and direction aloud: "300 units north" or
+// "300 units, slightly left".
+func (w *Window) spokenOffset(fromX, fromY, toX, toY float64) string {
+	d := nav.Distance(fromX, fromY, toX, toY)
+	if d < 10 {
+		return "here"
+	}
+	dir := w.directionLabel(nav.Bearing(fromX, fromY, toX, toY), true)
+	if w.directionStyle() == directionRelative {
+		return fmt.Sprintf("%.0f units, %s", math.Round(d/10)*10, dir)
+	}
+	return fmt.Sprintf("%.0f units %s", math.Round(d/10)*10, dir)
+}
@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/maps"
+)
+
+// mapBackground is a map background choice. Light backgrounds darken map
+// colors that would wash out on them (most packs are drawn for black).
+type mapBackground struct {
+	name  string
+	color color.RGBA
+	light bool
+}
+
+var mapBackgrounds = []mapBackground{
+	{"dark", color.RGBA{0, 0, 0, 255}, false},
+	{"parchment", color.RGBA{236, 222, 190, 255}, true},
+	{"light", color.RGBA{245, 245, 245, 255}, true},
+}
+
+const (
+	// Colors brighter than this (relative luminance, 0-1) are darkened on a
+	// light background...
+	lightColorThreshold = 0.55
+	// ...to this luminance, keeping their hue.
+	darkenedLuminance = 0.2
+)
+
+// mapBackground returns the configured background, else the theme's.
+func (w *Window) mapBackground() mapBackground {
+	for _, bg := range mapBackgrounds {
+		if bg.name == w.Config.MapBackground {
+			return bg
+		}
+	}
+	c := w.theme().background
+	return mapBackground{name: "theme", color: c, light: luminance(c) > 0.5}
+}
+
+// cycleMapBackground steps through the theme's background and the fixed ones.
+func (w *Window) cycleMapBackground() {
+	next := mapBackgrounds[0].name
+	for i, bg := range mapBackgrounds {
+		if bg.name == w.Config.MapBackground {
+			next = "" // After the last comes the theme's again
+			if i+1 < len(mapBackgrounds) {
+				next = mapBackgrounds[i+1].name
+			}
+			break
+		}
+	}
+	w.Config.MapBackground = next
+	w.Config.Save()
+	fmt.Printf("🎨 Map background: %s\n", w.mapBackground().name)
+}
+
+func (w *Window) mapBackgroundLabel() string {
+	return strings.ToUpper(w.mapBackground().name)
+}
+
+// luminance is the relative luminance of an sRGB color (0 = black, 1 = white).
+func luminance(c color.RGBA) float64 {
+	linear := func(v uint8) float64 {
+		f := float64(v) / 255
+		if f <= 0.04045 {
+			return f / 12.92
+		}
+		return math.Pow((f+0.055)/1.055, 2.4)
+	}
+	return 0.2126*linear(c.R) + 0.7152*linear(c.G) + 0.0722*linear(c.B)
+}
+
+// contrastColor darkens a map color too light for a light background by
+// scaling it toward black until it reaches darkenedLuminance. Other colors
+// (and everything on a dark background) pass through.
+func contrastColor(c color.RGBA, light bool) color.RGBA {
+	if !light {
+		return c
+	}
+	lum := luminance(c)
+	if lum <= lightColorThreshold {
+		return c
+	}
+	// Luminance is roughly linear in the linearized channels, so scale those
+	scale := darkenedLuminance / lum
+	channel := func(v uint8) uint8 {
+		f := float64(v) / 255
+		if f <= 0.04045 {
+			f /= 12.92
+		} else {
+			f = math.Pow((f+0.055)/1.055, 2.4)
+		}
+		f *= scale
+		if f <= 0.0031308 {
+			f *= 12.92
+		} else {
+			f = 1.055*math.Pow(f, 1/2.4) - 0.055
+		}
+		return uint8(f*255 + 0.5)
+	}
+	return color.RGBA{channel(c.R), channel(c.G), channel(c.B), c.A}
+}
+
+// paletteSpec is everything that changes how a map color is drawn.
+type paletteSpec struct {
+	light  bool       // Light background
+	scheme string     // Map color scheme (see mapcolors.go)
+	tint   color.RGBA // The theme's line tint
+}
+
+func (w *Window) paletteSpec() paletteSpec {
+	return paletteSpec{light: w.mapBackground().light, scheme: w.Config.MapColors, tint: w.theme().lineTint}
+}
+
+// palette memoizes mapColor for one zone's colors; the mesh keeps one per
+// zone and drops it when the zone or anything in its spec changes.
+type palette struct {
+	paletteSpec
+	colors map[paletteKey]color.RGBA
+}
+
+type paletteKey struct {
+	color color.RGBA
+	kind  maps.LineKind
+}
+
+// get maps a label color.
+func (p *palette) get(c color.RGBA) color.RGBA {
+	return p.line(c, maps.KindWall)
+}
+
+// line maps the color of a line of the given kind.
+func (p *palette) line(c color.RGBA, kind maps.LineKind) color.RGBA {
+	if p.paletteSpec == (paletteSpec{}) {
+		return c
+	}
+	key := paletteKey{c, kind}
+	if mapped, ok := p.colors[key]; ok {
+		return mapped
+	}
+	if p.colors == nil {
+		p.colors = make(map[paletteKey]color.RGBA)
+	}
+	mapped := mapColor(c, kind, p.paletteSpec)
+	p.colors[key] = mapped
+	return mapped
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/widgets"
+)
+
+// Camera bookmarks are named views saved per zone ("AE camp", "Overview").
+// The first nine are on the number keys, in the order they were saved.
+
+var bookmarkActions = []string{
+	ActionBookmark1, ActionBookmark2, ActionBookmark3,
+	ActionBookmark4, ActionBookmark5, ActionBookmark6,
+	ActionBookmark7, ActionBookmark8, ActionBookmark9,
+}
+
+// updateBookmarkKeys jumps to the bookmark whose key was pressed.
+func (w *Window) updateBookmarkKeys() {
+	bookmarks := w.Config.CameraBookmarks[w.CurrentZone]
+	for i, action := range bookmarkActions {
+		if i < len(bookmarks) && w.keyTriggered(action) {
+			w.applyBookmark(bookmarks[i])
+		}
+	}
+}
+
+// bookmarkSubmenu lists the zone's bookmarks, then saving and deleting them.
+func (w *Window) bookmarkSubmenu() []MenuItem {
+	var items []MenuItem
+	for i, b := range w.Config.CameraBookmarks[w.CurrentZone] {
+		label := b.Name
+		if i < len(bookmarkActions) {
+			if key := w.hotkeyLabel(bookmarkActions[i]); key != "" {
+				label = fmt.Sprintf("%s (%s)", b.Name, key)
+			}
+		}
+		items = append(items, MenuItem{
+			Label: label,
+			Action: func() {
+				w.applyBookmark(b)
+				w.openMenu = ""
+			},
+		})
+	}
+	items = append(items, MenuItem{
+		Label: "Save Current View...",
+		Action: func() {
+			w.openMenu = ""
+			w.saveBookmark()
+		},
+	})
+	if len(w.Config.CameraBookmarks[w.CurrentZone]) > 0 {
+		items = append(items, MenuItem{
+			Label: "Delete Bookmark...",
+			Action: func() {
+				w.openMenu = ""
+				w.deleteBookmark()
+			},
+		})
+	}
+	return items
+}
+
+// applyBookmark moves the camera to a saved view. Follow mode would pull it
+// straight back to the player, so it's turned off.
+func (w *Window) applyBookmark(b config.CameraBookmark) {
+	w.setFollow(false)
+	w.CamX, w.CamY = b.X, b.Y
+	if b.Zoom > 0 {
+		w.Zoom = b.Zoom
+	}
+	fmt.Printf("📌 Camera bookmark '%s'\n", b.Name)
+}
+
+// saveBookmark names the current view; a bookmark of the same name is
+// updated in place, keeping its number key.
+func (w *Window) saveBookmark() {
+	zone := w.CurrentZone
+	if zone == "" {
+		return
+	}
+	b := config.CameraBookmark{X: w.CamX, Y: w.CamY, Zoom: w.Zoom}
+	prompt := fmt.Sprintf("Name for this view of %s:", zone)
+	input := widgets.NewTextInput("Save Camera Bookmark", prompt, fmt.Sprintf("View %d", len(w.Config.CameraBookmarks[zone])+1))
+	w.showModal(input, func(r widgets.Result) {
+		if r != widgets.OK || input.Value() == "" {
+			return
+		}
+		b.Name = input.Value()
+		if w.Config.CameraBookmarks == nil {
+			w.Config.CameraBookmarks = make(map[string][]config.CameraBookmark)
+		}
+		bookmarks := w.Config.CameraBookmarks[zone]
+		if i := slices.IndexFunc(bookmarks, func(o config.CameraBookmark) bool { return o.Name == b.Name }); i >= 0 {
+			bookmarks[i] = b
+		} else {
+			w.Config.CameraBookmarks[zone] = append(bookmarks, b)
+		}
+		w.saveBookmarks()
+		fmt.Printf("📌 Saved camera bookmark '%s'\n", b.Name)
+	})
+}
+
+func (w *Window) deleteBookmark() {
+	zone := w.CurrentZone
+	bookmarks := w.Config.CameraBookmarks[zone]
+	names := make([]string, len(bookmarks))
+	for i, b := range bookmarks {
+		names[i] = b.Name
+	}
+	choose := widgets.NewDropdown("Delete Camera Bookmark", "Bookmark to delete:", names, 0)
+	w.showModal(choose, func(r widgets.Result) {
+		i, _ := choose.Selected()
+		if r != widgets.OK || i < 0 || i >= len(w.Config.CameraBookmarks[zone]) {
+			return
+		}
+		w.Config.CameraBookmarks[zone] = slices.Delete(w.Config.CameraBookmarks[zone], i, i+1)
+		if len(w.Config.CameraBookmarks[zone]) == 0 {
+			delete(w.Config.CameraBookmarks, zone)
+		}
+		w.saveBookmarks()
+	})
+}
+
+func (w *Window) saveBookmarks() {
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving camera bookmarks: %v\n", err)
+	}
+}
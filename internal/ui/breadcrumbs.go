@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"path/filepath"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/maps"
+	"github.com/ncruces/zenity"
+)
+
+// breadcrumbSaveEvery controls how many new breadcrumbs accumulate before
+// the current zone's trail is written to disk.
+const breadcrumbSaveEvery = 10
+
+func (w *Window) saveBreadcrumbs() {
+	if w.simulated {
+		w.unsavedBreadcrumbs = 0
+		return
+	}
+	if err := config.SaveBreadcrumbs(w.trailCharacter, w.CurrentZone, w.Breadcrumbs); err != nil {
+		fmt.Printf("❌ Error saving breadcrumbs: %v\n", err)
+	}
+	w.unsavedBreadcrumbs = 0
+}
+
+func (w *Window) clearBreadcrumbs() {
+	w.Breadcrumbs = w.Breadcrumbs[:0]
+	w.saveBreadcrumbs()
+}
+
+// exportBreadcrumbs writes the current trail as an EQ map layer file
+// (consecutive breadcrumbs joined by L lines) so it can be shared.
+func (w *Window) exportBreadcrumbs() {
+	if len(w.Breadcrumbs) < 2 {
+		w.dialogOpen = true
+		zenity.Info("Not enough breadcrumbs to export in this zone.", zenity.Title("Export Breadcrumbs"))
+		w.dialogOpen = false
+		w.lastMousePressed = true
+		return
+	}
+
+	fileCode := maps.GetZoneFileName(w.CurrentZone)
+	if fileCode == "" {
+		fileCode = w.CurrentZone
+	}
+
+	w.dialogOpen = true
+	path, err := zenity.SelectFileSave(
+		zenity.Title("Export Breadcrumbs as Map Layer"),
+		zenity.Filename(filepath.Join(w.MapDir, fileCode+"_trail.txt")),
+		zenity.ConfirmOverwrite(),
+		zenity.FileFilter{Name: "EQ map files", Patterns: []string{"*.txt"}},
+	)
+	w.dialogOpen = false
+	w.lastMousePressed = true
+	if err != nil || path == "" {
+		return
+	}
+
+	trailColor := color.RGBA{255, 255, 0, 255}
+	lines := make([]maps.MapLine, 0, len(w.Breadcrumbs)-1)
+	for i := 1; i < len(w.Breadcrumbs); i++ {
+		a, b := w.Breadcrumbs[i-1], w.Breadcrumbs[i]
+		lines = append(lines, maps.MapLine{
+			X1: a.X, Y1: a.Y, Z1: a.Z,
+			X2: b.X, Y2: b.Y, Z2: b.Z,
+			Color: trailColor,
+		})
+	}
+
+	if !backupMapFiles("Breadcrumb export: "+w.CurrentZone, path) {
+		return
+	}
+	if err := maps.WriteLayer(path, lines); err != nil {
+		fmt.Printf("❌ Error exporting breadcrumbs: %v\n", err)
+		return
+	}
+	fmt.Printf("💾 Exported %d trail segments to %s\n", len(lines), path)
+}
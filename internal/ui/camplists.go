@@ -0,0 +1,239 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/parser"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/ncruces/zenity"
+)
+
+var (
+	// Anyone's OOC: "list for Orc Hill", "off list for Orc Hill"
+	listAddMessage  = regexp.MustCompile(`(?i)^\s*(?:list(?:ing)?|add me to (?:the )?list|put me on (?:the )?list)\s+for\s+(.+?)[\s.!]*$`)
+	listDropMessage = regexp.MustCompile(`(?i)^\s*(?:off|remove me from|drop me from|drop)\s+(?:the\s+)?list\s+for\s+(.+?)[\s.!]*$`)
+)
+
+// handleListMessage adds or drops the speaker from a camp's list.
+func (w *Window) handleListMessage(msg parser.ChatMessage) {
+	name := msg.Speaker
+	if msg.Self {
+		name = msg.Character
+	}
+	if name == "" || w.logZone == "" {
+		return
+	}
+	if m := listAddMessage.FindStringSubmatch(msg.Text); m != nil {
+		w.addToCampList(w.logZone, m[1], name, msg.Time)
+	} else if m := listDropMessage.FindStringSubmatch(msg.Text); m != nil {
+		w.removeFromCampList(w.logZone, m[1], name)
+	}
+}
+
+// campList finds a zone's list for a camp, creating it if asked.
+func (w *Window) campList(zone, camp string, create bool) *config.CampList {
+	lists := w.Config.CampLists[zone]
+	for i := range lists {
+		if strings.EqualFold(lists[i].Camp, camp) {
+			return &lists[i]
+		}
+	}
+	if !create {
+		return nil
+	}
+	w.Config.CampLists[zone] = append(lists, config.CampList{Camp: camp})
+	return &w.Config.CampLists[zone][len(lists)]
+}
+
+func (w *Window) addToCampList(zone, camp, name string, added time.Time) {
+	list := w.campList(zone, camp, true)
+	for _, e := range list.Entries {
+		if strings.EqualFold(e.Name, name) {
+			return // Already waiting
+		}
+	}
+	list.Entries = append(list.Entries, config.CampListEntry{Name: name, Added: added})
+	w.Config.Save()
+	fmt.Printf("📋 %s listed for %s (#%d)\n", name, list.Camp, len(list.Entries))
+}
+
+func (w *Window) removeFromCampList(zone, camp, name string) {
+	list := w.campList(zone, camp, false)
+	if list == nil {
+		return
+	}
+	for i, e := range list.Entries {
+		if strings.EqualFold(e.Name, name) {
+			list.Entries = append(list.Entries[:i:i], list.Entries[i+1:]...)
+			w.Config.Save()
+			fmt.Printf("📋 %s dropped from the %s list\n", e.Name, list.Camp)
+			return
+		}
+	}
+}
+
+func (w *Window) deleteCampList(zone, camp string) {
+	lists := w.Config.CampLists[zone]
+	for i := range lists {
+		if strings.EqualFold(lists[i].Camp, camp) {
+			w.Config.CampLists[zone] = append(lists[:i:i], lists[i+1:]...)
+			w.Config.Save()
+			return
+		}
+	}
+}
+
+// manageCampLists edits the shown zone's lists by hand.
+func (w *Window) manageCampLists() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	const newList = "New list..."
+	zone := w.CurrentZone
+	items := []string{newList}
+	for _, l := range w.Config.CampLists[zone] {
+		items = append(items, l.Camp)
+	}
+	camp, err := zenity.List("Camp:", items, zenity.Title("Camp Lists"))
+	if err != nil || camp == "" {
+		return
+	}
+	if camp == newList {
+		camp, err = zenity.Entry("Camp name:", zenity.Title("Camp Lists"))
+		if err != nil || strings.TrimSpace(camp) == "" {
+			return
+		}
+		camp = strings.TrimSpace(camp)
+		w.campList(zone, camp, true)
+		w.Config.Save()
+	}
+
+	for {
+		list := w.campList(zone, camp, false)
+		if list == nil {
+			return
+		}
+		const (
+			addName    = "Add name..."
+			nextUp     = "Next up (remove #1)"
+			deleteList = "Delete this list"
+		)
+		actions := []string{addName}
+		if len(list.Entries) > 0 {
+			actions = append(actions, nextUp)
+		}
+		for _, e := range list.Entries {
+			actions = append(actions, "Remove "+e.Name)
+		}
+		actions = append(actions, deleteList)
+
+		action, err := zenity.List(
+			fmt.Sprintf("%s list (%d waiting):", list.Camp, len(list.Entries)),
+			actions,
+			zenity.Title("Camp Lists"),
+			zenity.Height(400),
+		)
+		if err != nil || action == "" {
+			return
+		}
+		switch {
+		case action == addName:
+			name, err := zenity.Entry("Name:", zenity.Title("Camp Lists"))
+			if err == nil && strings.TrimSpace(name) != "" {
+				w.addToCampList(zone, camp, strings.TrimSpace(name), time.Now())
+			}
+		case action == nextUp:
+			w.removeFromCampList(zone, camp, list.Entries[0].Name)
+		case action == deleteList:
+			w.deleteCampList(zone, camp)
+			return
+		case strings.HasPrefix(action, "Remove "):
+			w.removeFromCampList(zone, camp, strings.TrimPrefix(action, "Remove "))
+		}
+	}
+}
+
+// campListRow ties a panel row to a list entry (Entry < 0 for camp headers).
+type campListRow struct {
+	Camp  string
+	Entry int
+}
+
+// campListPanelLines returns the panel text (title first) and what each
+// following row shows.
+func (w *Window) campListPanelLines() ([]string, []campListRow) {
+	lists := w.Config.CampLists[w.CurrentZone]
+	if !w.showCampLists || len(lists) == 0 {
+		return nil, nil
+	}
+	lines := []string{"Camp lists (right-click removes)"}
+	var rows []campListRow
+	for _, l := range lists {
+		lines = append(lines, fmt.Sprintf("%s: %d waiting", l.Camp, len(l.Entries)))
+		rows = append(rows, campListRow{Camp: l.Camp, Entry: -1})
+		for i, e := range l.Entries {
+			lines = append(lines, fmt.Sprintf("  %d. %s (%s)", i+1, e.Name, formatHeld(time.Since(e.Added))))
+			rows = append(rows, campListRow{Camp: l.Camp, Entry: i})
+		}
+	}
+	return lines, rows
+}
+
+// campListPanelRect places the panel in the bottom-right corner, above the
+// minimap if it's showing.
+func (w *Window) campListPanelRect(lines []string) (px, py, width, height int) {
+	width, height = w.panelSize(lines)
+	bottom := w.Height - 8
+	if _, my, _, _, ok := w.minimapRect(); ok {
+		bottom = my - 8
+	}
+	return w.Width - width - 8, bottom - height, width, height
+}
+
+// handleCampListPanelClick removes the right-clicked entry.
+func (w *Window) handleCampListPanelClick(mx, my int) bool {
+	lines, rows := w.campListPanelLines()
+	if len(lines) == 0 {
+		return false
+	}
+	px, py, width, height := w.campListPanelRect(lines)
+	if mx < px || mx >= px+width || my < py || my >= py+height {
+		return false
+	}
+
+	row := w.panelRow(my, py)
+	if row >= 1 && row <= len(rows) && rows[row-1].Entry >= 0 {
+		r := rows[row-1]
+		if list := w.campList(w.CurrentZone, r.Camp, false); list != nil && r.Entry < len(list.Entries) {
+			w.removeFromCampList(w.CurrentZone, r.Camp, list.Entries[r.Entry].Name)
+		}
+	}
+	return true
+}
+
+// drawCampListPanel draws the shown zone's waiting lists.
+func (w *Window) drawCampListPanel(screen *ebiten.Image) {
+	lines, rows := w.campListPanelLines()
+	if len(lines) == 0 {
+		return
+	}
+	px, py, width, height := w.campListPanelRect(lines)
+
+	vector.DrawFilledRect(screen, float32(px), float32(py), float32(width), float32(height), color.RGBA{0, 0, 0, 180}, false)
+	vector.StrokeRect(screen, float32(px), float32(py), float32(width), float32(height), 1, claimColor, false)
+	w.drawPanelLines(screen, lines, px, py, func(i int) color.Color {
+		if i == 0 || rows[i-1].Entry < 0 {
+			return claimColor
+		}
+		return color.RGBA{255, 255, 255, 255}
+	})
+}
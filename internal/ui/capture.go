@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// cleanFrame reports whether this frame is drawn for a capture: map and
+// markers only, no player, trail or UI.
+func (w *Window) cleanFrame() bool {
+	return w.cleanCapture || w.captureRequested
+}
+
+func (w *Window) toggleCleanCapture() {
+	w.cleanCapture = !w.cleanCapture
+	fmt.Printf("📸 Clean capture view %s\n", map[bool]string{true: "ON", false: "OFF"}[w.cleanCapture])
+}
+
+// drawCaption draws the caption bar (zone and date) along the bottom.
+func (w *Window) drawCaption(screen *ebiten.Image) {
+	height := max(24, w.uiLineHeight()+9)
+	y := float32(w.Height - height)
+	vector.DrawFilledRect(screen, 0, y, float32(w.Width), float32(height), color.RGBA{0, 0, 0, 220}, false)
+	vector.StrokeLine(screen, 0, y, float32(w.Width), y, 1, color.RGBA{120, 120, 120, 255}, false)
+
+	zone := w.CurrentZone
+	if zone == "" {
+		zone = w.mapFileCode
+	}
+	w.drawUITextIn(screen, zone, 10, int(y), height, color.White)
+	date := time.Now().Format("January 2, 2006")
+	w.drawUITextIn(screen, date, w.Width-w.uiTextWidth(date)-10, int(y), height, color.RGBA{180, 180, 180, 255})
+}
+
+// drawCleanFrame composites the map at full opacity with the caption bar,
+// saving it if a capture was requested.
+func (w *Window) drawCleanFrame(screen, offscreen *ebiten.Image) {
+	opts := &ebiten.DrawImageOptions{}
+	opts.Filter = ebiten.FilterLinear
+	screen.DrawImage(offscreen, opts)
+	w.drawCaption(screen)
+	if w.captureRequested {
+		w.saveCapture(screen)
+	}
+}
+
+// saveCapture writes the frame just drawn to <config dir>/captures as PNG.
+// Encoding happens off the game loop.
+func (w *Window) saveCapture(screen *ebiten.Image) {
+	w.captureRequested = false
+
+	bounds := screen.Bounds()
+	img := image.NewRGBA(bounds)
+	screen.ReadPixels(img.Pix)
+
+	zone := strings.ReplaceAll(strings.ToLower(w.CurrentZone), " ", "_")
+	if zone == "" {
+		zone = "map"
+	}
+	dir := filepath.Join(filepath.Dir(config.GetConfigPath()), "captures")
+	path := filepath.Join(dir, fmt.Sprintf("%s_%s.png", zone, time.Now().Format("20060102-150405")))
+
+	go func() {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			fmt.Printf("❌ Error saving capture: %v\n", err)
+			return
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			fmt.Printf("❌ Error saving capture: %v\n", err)
+			return
+		}
+		defer f.Close()
+		if err := png.Encode(f, img); err != nil {
+			fmt.Printf("❌ Error saving capture: %v\n", err)
+			return
+		}
+		fmt.Printf("📸 Saved capture to %s\n", path)
+	}()
+}
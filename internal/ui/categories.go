@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ncruces/zenity"
+)
+
+const newCategoryItem = "New Category..."
+
+func categoryName(category string) string {
+	if category == "" {
+		return "Uncategorized"
+	}
+	return strings.ToUpper(category[:1]) + category[1:]
+}
+
+// categorySubmenu picks the category new markers are placed in.
+func (w *Window) categorySubmenu() []MenuItem {
+	items := []MenuItem{{
+		Label: categoryName(""),
+		Action: func() {
+			w.markerCategory = ""
+			w.openMenu = ""
+		},
+	}}
+	for _, cat := range w.Config.Categories() {
+		cat := cat
+		items = append(items, MenuItem{
+			Label: categoryName(cat),
+			Action: func() {
+				w.markerCategory = cat
+				w.openMenu = ""
+			},
+		})
+	}
+	items = append(items, MenuItem{
+		Label: newCategoryItem,
+		Action: func() {
+			w.openMenu = ""
+			w.dialogOpen = true
+			if cat, ok := w.chooseCategory(w.markerCategory, true); ok {
+				w.markerCategory = cat
+			}
+			w.dialogOpen = false
+			w.lastMousePressed = true
+		},
+	})
+	return items
+}
+
+// categoryVisibilitySubmenu toggles which categories are drawn.
+func (w *Window) categoryVisibilitySubmenu() []MenuItem {
+	cats := append([]string{""}, w.Config.Categories()...)
+	items := make([]MenuItem, 0, len(cats))
+	for _, cat := range cats {
+		cat := cat
+		items = append(items, MenuItem{
+			Label: fmt.Sprintf("%s: %s", categoryName(cat), map[bool]string{true: "OFF", false: "ON"}[w.Config.CategoryHidden(cat)]),
+			Action: func() {
+				w.Config.ToggleCategory(cat)
+				if err := w.Config.Save(); err != nil {
+					fmt.Printf("❌ Error saving config: %v\n", err)
+				}
+				w.openMenu = ""
+			},
+		})
+	}
+	return items
+}
+
+// chooseCategory asks for a category from the known list (or a new one when
+// onlyNew is set). The caller handles dialogOpen.
+func (w *Window) chooseCategory(current string, onlyNew bool) (string, bool) {
+	if !onlyNew {
+		items := []string{categoryName("")}
+		for _, cat := range w.Config.Categories() {
+			items = append(items, categoryName(cat))
+		}
+		items = append(items, newCategoryItem)
+
+		choice, err := zenity.List(
+			"Marker category:",
+			items,
+			zenity.Title("Marker Category"),
+			zenity.DefaultItems(categoryName(current)),
+		)
+		if err != nil {
+			return current, false
+		}
+		if choice != newCategoryItem {
+			if choice == categoryName("") {
+				return "", true
+			}
+			return strings.ToLower(choice), true
+		}
+	}
+
+	name, err := zenity.Entry("New category name:", zenity.Title("Marker Category"))
+	name = strings.ToLower(strings.TrimSpace(name))
+	if err != nil || name == "" {
+		return current, false
+	}
+	return name, true
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/widgets"
+)
+
+// followCharacter switches the trail to the primary character's when the
+// log being followed changes hands, so boxes don't draw over each other's
+// trails. Corpses are already kept per character by the parser.
+func (w *Window) followCharacter(name string) {
+	if name == w.trailCharacter {
+		return
+	}
+	if w.unsavedBreadcrumbs > 0 {
+		w.saveBreadcrumbs()
+	}
+	w.trailCharacter = name
+	w.Breadcrumbs = append(w.Breadcrumbs[:0], config.LoadBreadcrumbs(name, w.CurrentZone)...)
+	w.unsavedBreadcrumbs = 0
+	if name != "" {
+		fmt.Printf("👤 Following %s\n", name)
+	}
+}
+
+// characterChoiceLabel names the log being followed for the File menu.
+func (w *Window) characterChoiceLabel() string {
+	if w.Config.Character != "" {
+		return w.Config.Character
+	}
+	return "Newest Log"
+}
+
+// openCharacterPicker lists the characters with logs in the EQ directory;
+// the one picked is followed even while another box's log is newer.
+func (w *Window) openCharacterPicker() {
+	if w.logSource == nil {
+		w.showModal(widgets.NewConfirm("Character", "No EQ logs are being read (set the EQ path first).", "OK", ""), nil)
+		return
+	}
+
+	const newest = "Newest log (whoever played last)"
+	items := []string{newest}
+	names := []string{""}
+	selected := 0
+	seen := make(map[string]bool)
+	for _, l := range w.logSource.Logs() {
+		key := strings.ToLower(l.Character + "_" + l.Server)
+		if l.Character == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		item := l.Character
+		if l.Server != "" {
+			item += " (" + l.Server + ")"
+		}
+		items = append(items, fmt.Sprintf("%s - last played %s", item, playedAgo(l.ModTime)))
+		names = append(names, l.Character)
+		if selected == 0 && strings.EqualFold(l.Character, w.Config.Character) {
+			selected = len(items) - 1
+		}
+	}
+
+	choose := widgets.NewDropdown("Character", "Follow which character's log?", items, selected)
+	w.showModal(choose, func(r widgets.Result) {
+		i, _ := choose.Selected()
+		if r != widgets.OK || i < 0 {
+			return
+		}
+		w.setCharacter(names[i])
+	})
+}
+
+// setCharacter follows name's log from now on ("" = the newest log).
+func (w *Window) setCharacter(name string) {
+	w.Config.Character = name
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving character choice: %v\n", err)
+	}
+	w.logSource.SetCharacter(name)
+	fmt.Printf("👤 Log to follow: %s\n", w.characterChoiceLabel())
+}
+
+// playedAgo is a short "how long ago" for a log's last write.
+func playedAgo(t time.Time) string {
+	switch d := time.Since(t); {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"regexp"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/parser"
+	"github.com/devin-hart/nox-maps/pkg/eqlogparse"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// defaultChatLocPattern finds "loc: -1234, 567" (or "loc -1234, 567, 12"),
+// in /loc order.
+const defaultChatLocPattern = `(?i)\bloc:?\s*(-?[0-9]+(?:\.[0-9]+)?),\s*(-?[0-9]+(?:\.[0-9]+)?)`
+
+const defaultChatLocTimeout = 60 * time.Second
+
+var chatLocColor = color.RGBA{120, 220, 255, 255}
+
+// chatLoc is a position someone posted in group or guild chat. Chat doesn't
+// say which zone, so it's assumed to be the zone we were in at the time.
+type chatLoc struct {
+	zone   string
+	x, y   float64 // Map space
+	posted time.Time
+}
+
+// chatLocPattern compiles the configured pattern once, falling back to the
+// default if it's invalid or doesn't capture two values.
+func (w *Window) chatLocPattern() *regexp.Regexp {
+	source := w.Config.ChatLocPattern
+	if source == "" {
+		source = defaultChatLocPattern
+	}
+	if w.chatLocRegexp != nil && w.chatLocSource == source {
+		return w.chatLocRegexp
+	}
+	re, err := regexp.Compile(source)
+	if err == nil && re.NumSubexp() < 2 {
+		err = fmt.Errorf("needs 2 capture groups, has %d", re.NumSubexp())
+	}
+	if err != nil {
+		fmt.Printf("⚠️  Ignoring chat_loc_pattern %q: %v\n", source, err)
+		re = regexp.MustCompile(defaultChatLocPattern)
+	}
+	w.chatLocRegexp, w.chatLocSource = re, source
+	return re
+}
+
+func (w *Window) chatLocTimeout() time.Duration {
+	if w.Config.ChatLocTimeout > 0 {
+		return time.Duration(w.Config.ChatLocTimeout) * time.Second
+	}
+	return defaultChatLocTimeout
+}
+
+// handleChatLoc records a loc posted by someone else in group or guild chat.
+func (w *Window) handleChatLoc(msg parser.ChatMessage) {
+	if msg.Self || w.logZone == "" {
+		return
+	}
+	m := w.chatLocPattern().FindStringSubmatch(msg.Text)
+	if m == nil {
+		return
+	}
+	numbers := w.Config.ServerProfile.Parser.Numbers
+	locY, errY := eqlogparse.ParseNumber(m[1], numbers)
+	locX, errX := eqlogparse.ParseNumber(m[2], numbers)
+	if errY != nil || errX != nil {
+		return
+	}
+	if w.chatLocs == nil {
+		w.chatLocs = make(map[string]chatLoc)
+	}
+	w.chatLocs[msg.Speaker] = chatLoc{zone: w.logZone, x: -locX, y: -locY, posted: time.Now()}
+	fmt.Printf("💬 %s posted loc (%.0f, %.0f) in %s chat\n", msg.Speaker, locY, locX, msg.Channel)
+}
+
+// drawChatLocs draws posted locs in the shown zone, fading as they age, and
+// forgets them once they're stale.
+func (w *Window) drawChatLocs(screen *ebiten.Image, cx, cy float64) {
+	timeout := w.chatLocTimeout()
+	for name, loc := range w.chatLocs {
+		age := time.Since(loc.posted)
+		if age > timeout {
+			delete(w.chatLocs, name)
+			continue
+		}
+		if loc.zone != w.CurrentZone {
+			continue
+		}
+		c := chatLocColor
+		c.A = uint8(255 - 175*age/timeout)
+		x, y := w.toScreen32(loc.x, loc.y, cx, cy)
+		vector.DrawFilledCircle(screen, x, y, 5, c, w.antiAlias)
+		vector.StrokeCircle(screen, x, y, 8, 1, c, w.antiAlias)
+		w.drawUIText(screen, fmt.Sprintf("%s (%ds)", name, int(age.Seconds())), int(x)+11, int(y)+4, c)
+	}
+}
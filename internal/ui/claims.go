@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/parser"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/ncruces/zenity"
+)
+
+const campClaimRadius = 150.0 // Map units around the claimer
+
+var (
+	claimColor = color.RGBA{255, 170, 0, 255}
+
+	// Your own OOC announcements: "claiming Orc Hill", "releasing Orc Hill"
+	claimMessage   = regexp.MustCompile(`(?i)^\s*(?:claiming|claimed|camping)\s+(.+?)[\s.!]*$`)
+	releaseMessage = regexp.MustCompile(`(?i)^\s*(?:releasing|released|done with|leaving)\s+(.+?)[\s.!]*$`)
+)
+
+// updateChat handles chat lines the parser picked up.
+func (w *Window) updateChat() {
+	if w.LogReader == nil {
+		return
+	}
+	for _, msg := range w.LogReader.DrainChat() {
+		if msg.Character != w.LogReader.State().Character {
+			continue // Boxed characters hear the same lines
+		}
+		switch msg.Channel {
+		case parser.ChannelRepop:
+			w.repopAt = msg.Time
+			continue
+		case parser.ChannelGroup, parser.ChannelGuild:
+			w.handleChatLoc(msg)
+			continue
+		}
+		if msg.Channel != parser.ChannelOOC {
+			continue
+		}
+		w.handleListMessage(msg)
+		if !msg.Self {
+			continue
+		}
+		if m := claimMessage.FindStringSubmatch(msg.Text); m != nil {
+			w.claimCamp(m[1], msg.Time)
+		} else if m := releaseMessage.FindStringSubmatch(msg.Text); m != nil {
+			w.releaseCamp(w.logZone, m[1])
+		}
+	}
+}
+
+// claimCamp records a claim at the player's position. Repeating a claim
+// you already hold keeps the original start time.
+func (w *Window) claimCamp(camp string, started time.Time) {
+	s := w.LogReader.State()
+	zone := w.logZone
+	if zone == "" {
+		return
+	}
+	claims := w.Config.CampClaims[zone]
+	for _, c := range claims {
+		if strings.EqualFold(c.Camp, camp) && c.Claimer == s.Character {
+			return
+		}
+	}
+	w.Config.CampClaims[zone] = append(claims, config.CampClaim{
+		Camp:    camp,
+		X:       s.X,
+		Y:       s.Y,
+		Radius:  campClaimRadius,
+		Claimer: s.Character,
+		Started: started,
+	})
+	w.Config.Save()
+	fmt.Printf("⛺ Claimed '%s' in %s at (%.1f, %.1f)\n", camp, zone, -s.Y, -s.X)
+}
+
+func (w *Window) releaseCamp(zone, camp string) {
+	claims := w.Config.CampClaims[zone]
+	for i, c := range claims {
+		if strings.EqualFold(c.Camp, camp) {
+			w.Config.CampClaims[zone] = append(claims[:i:i], claims[i+1:]...)
+			w.Config.Save()
+			fmt.Printf("⛺ Released '%s' after %s\n", c.Camp, formatHeld(time.Since(c.Started)))
+			return
+		}
+	}
+}
+
+// promptClaimCamp claims a camp at the player's position by hand.
+func (w *Window) promptClaimCamp() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	camp, err := zenity.Entry("Camp name:", zenity.Title("Claim Camp"))
+	if err != nil || strings.TrimSpace(camp) == "" {
+		return
+	}
+	w.claimCamp(strings.TrimSpace(camp), time.Now())
+}
+
+// campMenuItems are the Tools menu entries for camp claims and lists.
+func (w *Window) campMenuItems() []MenuItem {
+	var items []MenuItem
+	if w.LogReader != nil && !w.browsing() {
+		items = append(items, MenuItem{
+			Label: "Claim Camp Here...",
+			Action: func() {
+				w.openMenu = ""
+				w.promptClaimCamp()
+			},
+		})
+	}
+	if w.CurrentZone != "" {
+		items = append(items, MenuItem{
+			Label: "Camp Lists...",
+			Action: func() {
+				w.openMenu = ""
+				w.manageCampLists()
+			},
+		})
+	}
+	for _, c := range w.Config.CampClaims[w.CurrentZone] {
+		camp := c.Camp
+		items = append(items, MenuItem{
+			Label: fmt.Sprintf("Release Claim: %s (%s)", camp, formatHeld(time.Since(c.Started))),
+			Action: func() {
+				w.releaseCamp(w.CurrentZone, camp)
+				w.openMenu = ""
+			},
+		})
+	}
+	return items
+}
+
+// drawCampClaims outlines each claimed camp in the shown zone with how long
+// it has been held.
+func (w *Window) drawCampClaims(screen *ebiten.Image, cx, cy float64) {
+	for _, c := range w.Config.CampClaims[w.CurrentZone] {
+		x, y := w.toScreen32(c.X, c.Y, cx, cy)
+		r := float32(c.Radius * w.Zoom)
+		vector.DrawFilledCircle(screen, x, y, r, color.RGBA{255, 170, 0, 30}, w.antiAlias)
+		vector.StrokeCircle(screen, x, y, r, 1.5, claimColor, w.antiAlias)
+
+		label := fmt.Sprintf("%s - %s held %s", c.Camp, c.Claimer, formatHeld(time.Since(c.Started)))
+		w.drawUIText(screen, label, int(x)-w.uiTextWidth(label)/2, int(y-r)-6, claimColor)
+	}
+}
+
+// formatHeld formats a hold time as "1h05m" or "12m".
+func formatHeld(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d >= time.Hour {
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes()))
+}
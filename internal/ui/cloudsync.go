@@ -0,0 +1,223 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/widgets"
+)
+
+// Cloud Sync (File > Cloud Sync) shares markers and zone notes between
+// machines through a WebDAV server (see config/cloudsync.go). While it's on
+// it syncs at startup, every few minutes and on exit; Sync Now runs one
+// straight away. The network part runs on its own goroutine and the result
+// is applied in Update.
+
+const defaultCloudSyncEvery = 10 * time.Minute
+
+type cloudSync struct {
+	done   chan config.SyncResult // Set while a sync runs
+	manual bool                   // Started from the menu; the outcome is shown
+	last   time.Time              // When the last sync started
+	err    error                  // How the last sync failed, if it did
+}
+
+func (w *Window) cloudSyncEvery() time.Duration {
+	if w.Config.CloudSync.Every > 0 {
+		return time.Duration(w.Config.CloudSync.Every) * time.Minute
+	}
+	return defaultCloudSyncEvery
+}
+
+// startCloudSync starts a sync unless one is running.
+func (w *Window) startCloudSync(manual bool) {
+	s := &w.cloudSync
+	if s.done != nil {
+		return
+	}
+	s.last = time.Now()
+	p, err := config.NewSyncProvider(w.Config.CloudSync)
+	if err != nil {
+		s.err = err
+		fmt.Printf("❌ Cloud sync: %v\n", err)
+		if manual {
+			w.notifyCloudSync(fmt.Sprintf("Couldn't sync: %v", err))
+		}
+		return
+	}
+	s.done, s.manual = make(chan config.SyncResult, 1), manual
+	local := w.Config.SyncData()
+	go func() { s.done <- config.Sync(p, local) }()
+	fmt.Printf("☁️  Syncing markers and notes with %s\n", p)
+}
+
+// updateCloudSync applies a finished sync and starts the next one when due.
+func (w *Window) updateCloudSync() {
+	s := &w.cloudSync
+	if s.done == nil {
+		if w.Config.CloudSync.Enabled && w.Config.CloudSync.URL != "" && time.Since(s.last) >= w.cloudSyncEvery() {
+			w.startCloudSync(false)
+		}
+		return
+	}
+	select {
+	case res := <-s.done:
+		s.done = nil
+		w.finishCloudSync(res, s.manual)
+	default:
+	}
+}
+
+func (w *Window) finishCloudSync(res config.SyncResult, manual bool) {
+	w.cloudSync.err = res.Err
+	if res.Err != nil {
+		fmt.Printf("❌ Cloud sync: %v\n", res.Err)
+		if manual {
+			w.notifyCloudSync(fmt.Sprintf("Couldn't sync: %v", res.Err))
+		}
+		return
+	}
+
+	zones := w.Config.ApplySync(res)
+	for _, c := range res.Conflicts {
+		fmt.Printf("⚠️  Cloud sync: %s\n", c)
+	}
+	msg := "Markers and notes are up to date."
+	switch {
+	case len(zones) > 0 && res.Pushed:
+		msg = fmt.Sprintf("Sent this machine's changes and updated markers in %d zones.", len(zones))
+	case len(zones) > 0:
+		msg = fmt.Sprintf("Updated markers in %d zones.", len(zones))
+	case res.Pushed:
+		msg = "Sent this machine's changes."
+	}
+	if len(res.Conflicts) > 0 {
+		msg += fmt.Sprintf(" %d edits were made on both sides (see the console).", len(res.Conflicts))
+	}
+	fmt.Printf("☁️  %s\n", msg)
+	if manual {
+		w.notifyCloudSync(msg)
+	}
+}
+
+// finishCloudSyncOnExit sends the last changes before the app closes,
+// unless the server couldn't be reached last time.
+func (w *Window) finishCloudSyncOnExit() {
+	s := &w.cloudSync
+	if s.done != nil {
+		w.finishCloudSync(<-s.done, false)
+	}
+	if !w.Config.CloudSync.Enabled || w.Config.CloudSync.URL == "" || s.err != nil {
+		return
+	}
+	w.startCloudSync(false)
+	if s.done != nil {
+		w.finishCloudSync(<-s.done, false)
+	}
+}
+
+// setupCloudSync asks for the server address and login, then syncs.
+func (w *Window) setupCloudSync() {
+	opts := w.Config.CloudSync
+	addr := widgets.NewTextInput("Cloud Sync", "WebDAV folder or file URL:", opts.URL)
+	w.showModal(addr, func(r widgets.Result) {
+		url := strings.TrimSpace(addr.Value())
+		if r != widgets.OK || url == "" {
+			return
+		}
+		user := widgets.NewTextInput("Cloud Sync", "Username (empty if none):", opts.Username)
+		w.showModal(user, func(r widgets.Result) {
+			if r != widgets.OK {
+				return
+			}
+			pass := widgets.NewTextInput("Cloud Sync", "Password or app password:", opts.Password).Masked()
+			w.showModal(pass, func(r widgets.Result) {
+				if r != widgets.OK {
+					return
+				}
+				opts.URL = url
+				opts.Username = strings.TrimSpace(user.Value())
+				opts.Password = pass.Value()
+				opts.Enabled = true
+				opts.AllowHTTP = false
+				_, err := config.NewSyncProvider(opts)
+				if errors.Is(err, config.ErrSyncInsecure) {
+					ask := widgets.NewConfirm("Cloud Sync", "That address is http://, so the password and markers would\ntravel unencrypted. Use it anyway?", "Use http://", "Cancel")
+					w.showModal(ask, func(r widgets.Result) {
+						if r == widgets.OK {
+							opts.AllowHTTP = true
+							w.enableCloudSync(opts)
+						}
+					})
+					return
+				}
+				if err != nil {
+					w.notifyCloudSync(fmt.Sprintf("Couldn't use that address: %v", err))
+					return
+				}
+				w.enableCloudSync(opts)
+			})
+		})
+	})
+}
+
+// enableCloudSync saves the new settings and syncs.
+func (w *Window) enableCloudSync(opts config.CloudSyncOptions) {
+	w.Config.CloudSync = opts
+	w.Config.Save()
+	fmt.Println("☁️  Cloud sync ON")
+	w.startCloudSync(true)
+}
+
+func (w *Window) toggleCloudSync() {
+	w.Config.CloudSync.Enabled = !w.Config.CloudSync.Enabled
+	w.Config.Save()
+	fmt.Printf("☁️  Cloud sync %s\n", map[bool]string{true: "ON", false: "OFF"}[w.Config.CloudSync.Enabled])
+}
+
+// notifyCloudSync shows a sync's outcome unless a dialog is already up.
+func (w *Window) notifyCloudSync(msg string) {
+	if w.modal == nil && !w.dialogOpen {
+		w.showModal(widgets.NewConfirm("Cloud Sync", msg, "OK", ""), nil)
+	}
+}
+
+// cloudSyncMenuItems are the File menu's Cloud Sync entries.
+func (w *Window) cloudSyncMenuItems() []MenuItem {
+	items := []MenuItem{
+		{
+			Label: "Cloud Sync Setup...",
+			Action: func() {
+				w.openMenu = ""
+				w.setupCloudSync()
+			},
+		},
+	}
+	if w.Config.CloudSync.URL == "" {
+		return items
+	}
+
+	now := "Cloud Sync Now"
+	if w.cloudSync.done != nil {
+		now = "Cloud Sync Now (syncing...)"
+	}
+	return append(items,
+		MenuItem{
+			Label: fmt.Sprintf("Cloud Sync: %s", map[bool]string{true: "ON", false: "OFF"}[w.Config.CloudSync.Enabled]),
+			Action: func() {
+				w.toggleCloudSync()
+				w.openMenu = ""
+			},
+		},
+		MenuItem{
+			Label: now,
+			Action: func() {
+				w.startCloudSync(true)
+				w.openMenu = ""
+			},
+		},
+	)
+}
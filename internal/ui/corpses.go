@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/devin-hart/nox-maps/internal/nav"
+	"github.com/devin-hart/nox-maps/internal/parser"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// corpseEntry is one outstanding corpse and whose it is.
+type corpseEntry struct {
+	Character string
+	parser.Corpse
+}
+
+// label names the corpse on the map and in the panel.
+func (c corpseEntry) label() string {
+	name := c.Character
+	if name == "" {
+		name = "You"
+	}
+	return fmt.Sprintf("%s (%s)", name, c.Time.Format("15:04"))
+}
+
+// outstandingCorpses lists every corpse of every tracked character, in any zone.
+func (w *Window) outstandingCorpses() []corpseEntry {
+	if w.LogReader == nil {
+		return nil
+	}
+	var corpses []corpseEntry
+	for _, s := range w.LogReader.AllCharacters() {
+		for _, c := range s.Corpses {
+			corpses = append(corpses, corpseEntry{Character: s.Character, Corpse: c})
+		}
+	}
+	return corpses
+}
+
+// drawCorpses draws every corpse in the current zone.
+func (w *Window) drawCorpses(screen *ebiten.Image, cx, cy float64) {
+	if w.LogReader == nil {
+		return
+	}
+	primary := w.LogReader.State().Character
+	for _, c := range w.outstandingCorpses() {
+		if c.Zone != w.CurrentZone || (!w.ShowParty && c.Character != primary) {
+			continue
+		}
+		w.drawCorpseAt(screen, cx, cy, c.X, c.Y, c.label())
+	}
+}
+
+// corpsePanelLines returns the panel text (header first) and the corpse for each row.
+func (w *Window) corpsePanelLines() ([]string, []corpseEntry) {
+	corpses := w.outstandingCorpses()
+	if !w.showCorpses || len(corpses) == 0 {
+		return nil, nil
+	}
+	lines := []string{fmt.Sprintf("Corpses outstanding: %d (right-click clears)", len(corpses))}
+	for _, c := range corpses {
+		// Show the corpse position as an EQ /loc (Y, X)
+		line := fmt.Sprintf("%s - %s (%.0f, %.0f)", c.label(), c.Zone, -c.Y, -c.X)
+		if c.Zone == w.logZone {
+			s := w.LogReader.State()
+			line += fmt.Sprintf(" %.0f %s", nav.Distance(s.X, s.Y, c.X, c.Y), w.directionLabel(nav.Bearing(s.X, s.Y, c.X, c.Y), false))
+		}
+		lines = append(lines, line)
+	}
+	return lines, corpses
+}
+
+// corpsePanelRect places the panel in the bottom-left corner.
+func (w *Window) corpsePanelRect(lines []string) (px, py, width, height int) {
+	width, height = w.panelSize(lines)
+	return 8, w.Height - height - 8, width, height
+}
+
+// handleCorpsePanelClick clears the corpse on a right-clicked row.
+func (w *Window) handleCorpsePanelClick(mx, my int) bool {
+	lines, corpses := w.corpsePanelLines()
+	if len(lines) == 0 {
+		return false
+	}
+	px, py, width, height := w.corpsePanelRect(lines)
+	if mx < px || mx >= px+width || my < py || my >= py+height {
+		return false
+	}
+
+	row := w.panelRow(my, py)
+	if row >= 1 && row <= len(corpses) {
+		c := corpses[row-1]
+		w.LogReader.ClearCorpse(c.Character, c.Corpse)
+		fmt.Printf("💀 Cleared corpse: %s in %s\n", c.label(), c.Zone)
+	}
+	return true
+}
+
+// drawCorpsePanel draws the "corpses outstanding" summary in the bottom-left corner.
+func (w *Window) drawCorpsePanel(screen *ebiten.Image) {
+	lines, _ := w.corpsePanelLines()
+	if len(lines) == 0 {
+		return
+	}
+	px, py, width, height := w.corpsePanelRect(lines)
+
+	vector.DrawFilledRect(screen, float32(px), float32(py), float32(width), float32(height), color.RGBA{0, 0, 0, 180}, false)
+	vector.StrokeRect(screen, float32(px), float32(py), float32(width), float32(height), 1, color.RGBA{255, 0, 0, 255}, false)
+	w.drawPanelLines(screen, lines, px, py, func(i int) color.Color {
+		if i == 0 {
+			return color.RGBA{255, 80, 80, 255}
+		}
+		return color.RGBA{255, 255, 255, 255}
+	})
+}
@@ -0,0 +1,229 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/ncruces/zenity"
+)
+
+// drawLayer is one overlay in the map view. The user can reorder them and
+// fade each one (Layers > Draw Order...).
+type drawLayer struct {
+	id    string // Config name
+	label string
+	clean bool // Kept in clean captures
+	draw  func(w *Window, dst *ebiten.Image, cx, cy float64)
+}
+
+// defaultDrawLayers is the default order, bottom first.
+var defaultDrawLayers = []drawLayer{
+	{id: "heatmap", label: "Heatmap", draw: (*Window).drawHeatmap},
+	{id: "map_lines", label: "Map Lines", clean: true, draw: (*Window).drawMapLines},
+	{id: "map_labels", label: "Map Labels", clean: true, draw: (*Window).drawMapLabels},
+	{id: "breadcrumbs", label: "Breadcrumbs", draw: (*Window).drawBreadcrumbs},
+	{id: "annotations", label: "Paths & Polygons", clean: true, draw: (*Window).drawAnnotations},
+	{id: "markers", label: "Markers", clean: true, draw: (*Window).drawMarkers},
+	{id: "highlights", label: "Find & Editor", draw: func(w *Window, dst *ebiten.Image, cx, cy float64) {
+		w.drawFindHighlight(dst, cx, cy)
+		w.drawEditor(dst, cx, cy)
+		w.drawMarkerDrag(dst, cx, cy)
+	}},
+	{id: "waypoint", label: "Waypoint", draw: (*Window).drawWaypoint},
+	{id: "ruler", label: "Ruler", draw: (*Window).drawRuler},
+	{id: "corpses", label: "Corpses & Target", draw: func(w *Window, dst *ebiten.Image, cx, cy float64) {
+		w.drawCorpses(dst, cx, cy)
+		w.drawTarget(dst, cx, cy)
+	}},
+	{id: "camps", label: "Camp Claims", draw: (*Window).drawCampClaims},
+	{id: "others", label: "Party & Peers", draw: (*Window).drawOthers},
+	{id: "player", label: "Player", draw: func(w *Window, dst *ebiten.Image, cx, cy float64) {
+		if w.LogReader != nil && !w.browsing() {
+			w.drawPlayerArrow(dst, cx, cy)
+		}
+	}},
+}
+
+type orderedLayer struct {
+	*drawLayer
+	opacity float64
+}
+
+// drawOrder resolves the configured order, bottom first. Unknown names are
+// dropped and layers missing from the config (e.g. added by a newer
+// version) go back in above their default neighbour.
+func (w *Window) drawOrder() []orderedLayer {
+	var order []orderedLayer
+	placed := make(map[string]bool)
+	for _, cl := range w.Config.DrawLayers {
+		for i := range defaultDrawLayers {
+			l := &defaultDrawLayers[i]
+			if l.id == cl.Name && !placed[l.id] {
+				order = append(order, orderedLayer{l, clampOpacity(cl.Opacity)})
+				placed[l.id] = true
+			}
+		}
+	}
+
+	for i := range defaultDrawLayers {
+		l := &defaultDrawLayers[i]
+		if placed[l.id] {
+			continue
+		}
+		at := 0
+		if i > 0 {
+			for j, o := range order {
+				if o.id == defaultDrawLayers[i-1].id {
+					at = j + 1
+				}
+			}
+		}
+		order = append(order[:at], append([]orderedLayer{{l, 1}}, order[at:]...)...)
+		placed[l.id] = true
+	}
+	return order
+}
+
+func clampOpacity(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+func (w *Window) saveDrawOrder(order []orderedLayer) {
+	w.Config.DrawLayers = make([]config.DrawLayer, len(order))
+	for i, l := range order {
+		w.Config.DrawLayers[i] = config.DrawLayer{Name: l.id, Opacity: l.opacity}
+	}
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving draw order: %v\n", err)
+	}
+}
+
+// drawLayers draws every overlay in order. Faded layers go through a
+// scratch image so overlapping shapes within a layer don't stack up.
+func (w *Window) drawLayers(offscreen *ebiten.Image, cx, cy float64, clean bool) {
+	for _, l := range w.drawOrder() {
+		if (clean && !l.clean) || l.opacity <= 0 {
+			continue
+		}
+		if l.opacity >= 1 {
+			l.draw(w, offscreen, cx, cy)
+			continue
+		}
+
+		if w.layerScratch == nil {
+			w.layerScratch = ebiten.NewImage(w.Width, w.Height)
+		}
+		w.layerScratch.Clear()
+		l.draw(w, w.layerScratch, cx, cy)
+
+		opts := &ebiten.DrawImageOptions{}
+		opts.ColorScale.ScaleAlpha(float32(l.opacity))
+		offscreen.DrawImage(w.layerScratch, opts)
+	}
+}
+
+// openDrawOrder lists the layers top first and moves or fades the one picked.
+func (w *Window) openDrawOrder() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	for {
+		order := w.drawOrder()
+		items := make([]string, len(order))
+		for i := range order {
+			l := order[len(order)-1-i]
+			items[i] = fmt.Sprintf("%d. %s (%.0f%%)", i+1, l.label, l.opacity*100)
+		}
+
+		choice, err := zenity.List(
+			"Layers from top (drawn last) to bottom:",
+			items,
+			zenity.Title("Draw Order"),
+			zenity.Height(420),
+			zenity.ExtraButton("Reset"),
+		)
+		if errors.Is(err, zenity.ErrExtraButton) {
+			w.Config.DrawLayers = nil
+			w.Config.Save()
+			fmt.Println("🗂️  Draw order reset")
+			continue
+		}
+		if err != nil || choice == "" {
+			return
+		}
+		n, err := strconv.Atoi(choice[:strings.Index(choice, ".")])
+		if err != nil {
+			return
+		}
+		index := len(order) - n // Back to bottom-first
+		w.editDrawLayer(order, index)
+	}
+}
+
+const (
+	drawLayerUp      = "Move Up"
+	drawLayerDown    = "Move Down"
+	drawLayerTop     = "Bring to Top"
+	drawLayerBottom  = "Send to Bottom"
+	drawLayerOpacity = "Set Opacity..."
+)
+
+func (w *Window) editDrawLayer(order []orderedLayer, index int) {
+	l := order[index]
+	action, err := zenity.List(
+		fmt.Sprintf("%s (%.0f%%):", l.label, l.opacity*100),
+		[]string{drawLayerUp, drawLayerDown, drawLayerTop, drawLayerBottom, drawLayerOpacity},
+		zenity.Title("Draw Order"),
+	)
+	if err != nil {
+		return
+	}
+
+	to := index
+	switch action {
+	case drawLayerUp:
+		to = min(index+1, len(order)-1)
+	case drawLayerDown:
+		to = max(index-1, 0)
+	case drawLayerTop:
+		to = len(order) - 1
+	case drawLayerBottom:
+		to = 0
+	case drawLayerOpacity:
+		entry, err := zenity.Entry(
+			fmt.Sprintf("%s opacity (0-100%%):", l.label),
+			zenity.Title("Draw Order"),
+			zenity.EntryText(strconv.FormatFloat(l.opacity*100, 'f', 0, 64)),
+		)
+		if err != nil {
+			return
+		}
+		v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(entry), "%"), 64)
+		if err != nil {
+			zenity.Error("Opacity must be a number from 0 to 100.", zenity.Title("Draw Order"))
+			return
+		}
+		order[index].opacity = clampOpacity(v / 100)
+	default:
+		return
+	}
+
+	if to != index {
+		order = append(order[:index], order[index+1:]...)
+		order = append(order[:to], append([]orderedLayer{l}, order[to:]...)...)
+	}
+	w.saveDrawOrder(order)
+}
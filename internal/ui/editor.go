@@ -0,0 +1,252 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/devin-hart/nox-maps/internal/maps"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/ncruces/zenity"
+)
+
+const (
+	editSnapPixels = 8.0 // Snap to an existing endpoint within this many screen pixels
+	editPickPixels = 6.0 // Right-click deletes a line within this many screen pixels
+	editLineLayer  = 0   // New lines go into the zone's base file
+)
+
+var (
+	editLineColor  = color.RGBA{200, 200, 200, 255}
+	editHoverColor = color.RGBA{255, 60, 60, 255}
+)
+
+// mapEditor is the in-app map editor's state.
+type mapEditor struct {
+	active  bool
+	drawing bool    // A line has been started
+	startX  float64 // World position of the line being drawn
+	startY  float64
+	startZ  float64
+	dirty   map[int]bool // Layers with unsaved edits
+}
+
+// setEditing turns edit mode on or off, offering to save on the way out.
+func (w *Window) setEditing(on bool) {
+	if on == w.editor.active {
+		return
+	}
+	if on && w.MapData == nil {
+		fmt.Println("✏️  No map loaded to edit")
+		return
+	}
+	if !on && len(w.editor.dirty) > 0 {
+		w.dialogOpen = true
+		err := zenity.Question("Save your map edits?", zenity.Title("Edit Map"), zenity.OKLabel("Save"), zenity.CancelLabel("Discard"))
+		w.dialogOpen = false
+		w.lastMousePressed = true
+		if err == nil {
+			w.saveMapEdits()
+		} else {
+			w.editor.dirty = nil
+			w.reloadMap()
+		}
+	}
+	w.editor.active = on
+	w.editor.drawing = false
+	w.placingMarker, w.placingWaypoint = false, false
+	fmt.Printf("✏️  Map edit mode %s\n", map[bool]string{true: "ON - left-click to draw, right-click deletes a line", false: "OFF"}[on])
+}
+
+// updateEditor handles keyboard input in edit mode.
+func (w *Window) updateEditor() {
+	if !w.editor.active {
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		w.editor.drawing = false
+	}
+	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta)
+	if ctrl && inpututil.IsKeyJustPressed(ebiten.KeyS) {
+		w.saveMapEdits()
+	}
+}
+
+// editorSnap returns the endpoint of a visible line nearest a world
+// position, if one is close enough on screen.
+func (w *Window) editorSnap(worldX, worldY float64) (x, y, z float64, ok bool) {
+	best := editSnapPixels / w.Zoom
+	hidden := w.hiddenLayerMask()
+	for _, l := range w.MapData.Lines {
+		if hidden&(1<<l.Layer) != 0 {
+			continue
+		}
+		for _, p := range [2][3]float64{{l.X1, l.Y1, l.Z1}, {l.X2, l.Y2, l.Z2}} {
+			if d := math.Hypot(p[0]-worldX, p[1]-worldY); d <= best {
+				best = d
+				x, y, z, ok = p[0], p[1], p[2], true
+			}
+		}
+	}
+	return x, y, z, ok
+}
+
+// editorPoint is where a click at a world position lands: a snapped
+// endpoint, or the raw position at the player's (or last point's) height.
+func (w *Window) editorPoint(worldX, worldY float64) (float64, float64, float64) {
+	if x, y, z, ok := w.editorSnap(worldX, worldY); ok {
+		return x, y, z
+	}
+	z := w.editor.startZ
+	if !w.editor.drawing && w.LogReader != nil {
+		z = w.LogReader.State().Z
+	}
+	return worldX, worldY, z
+}
+
+// editorClick starts a line or finishes it. Lines chain: the end of one
+// is the start of the next until Escape.
+func (w *Window) editorClick(worldX, worldY float64) {
+	x, y, z := w.editorPoint(worldX, worldY)
+	if w.editor.drawing && (x != w.editor.startX || y != w.editor.startY) {
+		w.MapData.Lines = append(w.MapData.Lines, maps.MapLine{
+			X1: w.editor.startX, Y1: w.editor.startY, Z1: w.editor.startZ,
+			X2: x, Y2: y, Z2: z,
+			Color: editLineColor,
+			Layer: editLineLayer,
+		})
+		w.markEdited(editLineLayer)
+	}
+	w.editor.drawing = true
+	w.editor.startX, w.editor.startY, w.editor.startZ = x, y, z
+}
+
+// editorLineAt finds the visible line under a world position (-1 if none).
+func (w *Window) editorLineAt(worldX, worldY float64) int {
+	best, index := editPickPixels/w.Zoom, -1
+	hidden := w.hiddenLayerMask()
+	for i, l := range w.MapData.Lines {
+		if hidden&(1<<l.Layer) != 0 {
+			continue
+		}
+		if d := pointSegmentDistance(worldX, worldY, l.X1, l.Y1, l.X2, l.Y2); d <= best {
+			best, index = d, i
+		}
+	}
+	return index
+}
+
+// editorRightClick cancels the line being drawn, or deletes the line under
+// the cursor. Reports whether the click was used.
+func (w *Window) editorRightClick(worldX, worldY float64) bool {
+	if w.editor.drawing {
+		w.editor.drawing = false
+		return true
+	}
+	i := w.editorLineAt(worldX, worldY)
+	if i < 0 {
+		return false
+	}
+	layer := w.MapData.Lines[i].Layer
+	w.MapData.Lines = append(w.MapData.Lines[:i:i], w.MapData.Lines[i+1:]...)
+	w.markEdited(layer)
+	return true
+}
+
+func (w *Window) markEdited(layer int) {
+	if w.editor.dirty == nil {
+		w.editor.dirty = make(map[int]bool)
+	}
+	w.editor.dirty[layer] = true
+	w.mesh.invalidate()
+}
+
+// saveMapEdits writes every edited layer back to its map file, backing the
+// files up first (see mapbackup.go).
+func (w *Window) saveMapEdits() {
+	if w.MapData == nil || len(w.editor.dirty) == 0 {
+		return
+	}
+	layers := make([]int, 0, len(w.editor.dirty))
+	for layer := range w.editor.dirty {
+		layers = append(layers, layer)
+	}
+	sort.Ints(layers)
+
+	paths := make([]string, len(layers))
+	for i, layer := range layers {
+		paths[i] = maps.LayerPath(w.MapDir, w.mapFileCode, layer)
+	}
+	if !backupMapFiles("Map edit: "+w.CurrentZone, paths...) {
+		return
+	}
+
+	for i, layer := range layers {
+		var lines []maps.MapLine
+		var labels []maps.MapLabel
+		for _, l := range w.MapData.Lines {
+			if l.Layer == layer {
+				lines = append(lines, l)
+			}
+		}
+		for _, p := range w.MapData.Labels {
+			if p.Layer == layer {
+				labels = append(labels, p)
+			}
+		}
+
+		path := paths[i]
+		if err := maps.WriteZoneFile(path, lines, labels); err != nil {
+			fmt.Printf("❌ Error saving %s: %v\n", path, err)
+			continue
+		}
+		delete(w.editor.dirty, layer)
+		fmt.Printf("💾 Saved %d lines, %d labels to %s\n", len(lines), len(labels), path)
+	}
+}
+
+// drawEditor draws the line being drawn, the snap target and the line a
+// right-click would delete.
+func (w *Window) drawEditor(screen *ebiten.Image, cx, cy float64) {
+	if !w.editor.active || w.MapData == nil {
+		return
+	}
+	mx, my := ebiten.CursorPosition()
+	worldX, worldY := w.toWorld(float64(mx), float64(my), cx, cy)
+	toScreen := func(x, y float64) (float32, float32) {
+		return w.toScreen32(x, y, cx, cy)
+	}
+
+	if !w.editor.drawing {
+		if i := w.editorLineAt(worldX, worldY); i >= 0 {
+			l := w.MapData.Lines[i]
+			x1, y1 := toScreen(l.X1, l.Y1)
+			x2, y2 := toScreen(l.X2, l.Y2)
+			vector.StrokeLine(screen, x1, y1, x2, y2, 3, editHoverColor, w.antiAlias)
+		}
+	}
+
+	x, y, _ := w.editorPoint(worldX, worldY)
+	px, py := toScreen(x, y)
+	if _, _, _, snapped := w.editorSnap(worldX, worldY); snapped {
+		vector.StrokeCircle(screen, px, py, 6, 1.5, color.RGBA{0, 255, 255, 255}, w.antiAlias)
+	}
+	if w.editor.drawing {
+		sx, sy := toScreen(w.editor.startX, w.editor.startY)
+		vector.StrokeLine(screen, sx, sy, px, py, 2, editLineColor, w.antiAlias)
+	}
+}
+
+// pointSegmentDistance is the distance from (px, py) to the segment a-b.
+func pointSegmentDistance(px, py, ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	if dx == 0 && dy == 0 {
+		return math.Hypot(px-ax, py-ay)
+	}
+	t := ((px-ax)*dx + (py-ay)*dy) / (dx*dx + dy*dy)
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(px-(ax+t*dx), py-(ay+t*dy))
+}
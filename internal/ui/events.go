@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/devin-hart/nox-maps/internal/parser"
+)
+
+// breadcrumbSpacing is how far (map units) the player moves between
+// breadcrumbs.
+const breadcrumbSpacing = 50.0
+
+// updateEvents acts on what the parser has seen since the last frame, in
+// the order it was logged.
+func (w *Window) updateEvents() {
+	if w.events == nil {
+		return
+	}
+	for _, ev := range w.events.Drain() {
+		switch ev := ev.(type) {
+		case parser.ZoneChanged:
+			if ev.Primary {
+				w.followCharacter(ev.Character)
+				w.enterZone(ev)
+			}
+		case parser.PositionUpdated:
+			if ev.Primary {
+				w.followCharacter(ev.Character)
+				w.trackPosition(ev)
+			}
+		case parser.Died:
+			w.playAlert(alertDeath)
+		}
+	}
+}
+
+// enterZone follows the player into a new zone.
+func (w *Window) enterZone(ev parser.ZoneChanged) {
+	if ev.To == w.logZone {
+		return
+	}
+	if w.logZone != "" && ev.To != "" {
+		w.playAlert(alertZone)
+	}
+	w.logZone = ev.To
+	w.viewZone(w.logZone)
+	w.zoneEntryX, w.zoneEntryY = ev.X, ev.Y
+	w.outOfBoundsLogged = false
+	w.notesExpanded = true // Show the zone's notes on entry
+	// Note: Corpse marker persists across zone changes intentionally
+}
+
+// trackPosition checks waypoint arrival and drops a breadcrumb every
+// breadcrumbSpacing units moved.
+func (w *Window) trackPosition(ev parser.PositionUpdated) {
+	if w.browsing() {
+		return
+	}
+	if w.Nav.Active() && w.Nav.Update(ev.X, ev.Y) {
+		fmt.Println("🏁 Arrived at waypoint")
+	}
+
+	if n := len(w.Breadcrumbs); n > 0 {
+		last := w.Breadcrumbs[n-1]
+		if math.Hypot(ev.X-last.X, ev.Y-last.Y) <= breadcrumbSpacing {
+			return
+		}
+	}
+	w.Breadcrumbs = append(w.Breadcrumbs, BreadcrumbPoint{X: ev.X, Y: ev.Y, Z: ev.Z})
+	if n := len(w.Breadcrumbs); n > 1 {
+		w.checkWallCrossing(w.Breadcrumbs[n-2], w.Breadcrumbs[n-1])
+	}
+	// Limit to last 500 breadcrumbs
+	if len(w.Breadcrumbs) > 500 {
+		w.Breadcrumbs = w.Breadcrumbs[1:]
+	}
+	w.unsavedBreadcrumbs++
+	if w.unsavedBreadcrumbs >= breadcrumbSaveEvery {
+		w.saveBreadcrumbs()
+	}
+}
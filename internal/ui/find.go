@@ -0,0 +1,236 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/ncruces/zenity"
+)
+
+const (
+	findZoom          = 3.0             // Minimum zoom when jumping to a result
+	findHighlightTime = 3 * time.Second // How long the found spot keeps pulsing
+)
+
+type findResult struct {
+	Label string
+	X, Y  float64
+	Zone  string // Set by the all-zones search
+}
+
+// updateFind opens the search dialog on Ctrl+F (Ctrl+Shift+F searches every zone).
+func (w *Window) updateFind() {
+	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta)
+	if ctrl && inpututil.IsKeyJustPressed(ebiten.KeyF) && !w.dialogOpen {
+		if ebiten.IsKeyPressed(ebiten.KeyShift) {
+			w.openGlobalFind()
+		} else {
+			w.openFind()
+		}
+	}
+}
+
+// searchZone matches map labels and custom markers of the current zone
+// (case-insensitive substring).
+func (w *Window) searchZone(query string) []findResult {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+
+	var results []findResult
+	for _, m := range w.Config.ZoneMarkers(w.CurrentZone) {
+		if strings.Contains(strings.ToLower(m.Label), query) {
+			results = append(results, findResult{Label: "📍 " + m.Label, X: m.X, Y: m.Y})
+		}
+	}
+	if w.MapData != nil {
+		for _, lbl := range w.MapData.Labels {
+			if strings.Contains(strings.ToLower(lbl.Text), query) {
+				results = append(results, findResult{Label: lbl.Text, X: lbl.X, Y: lbl.Y})
+			}
+		}
+	}
+	return results
+}
+
+// openFind asks for a search term, lists the matches and jumps to the chosen one.
+func (w *Window) openFind() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	query, err := zenity.Entry(
+		fmt.Sprintf("Search labels and markers in %s:", w.CurrentZone),
+		zenity.Title("Find"),
+		zenity.EntryText(w.lastFind),
+	)
+	if err != nil || strings.TrimSpace(query) == "" {
+		return
+	}
+	w.lastFind = query
+
+	results := w.searchZone(query)
+	if len(results) == 0 {
+		zenity.Info(fmt.Sprintf("No labels or markers match '%s'.", query), zenity.Title("Find"))
+		return
+	}
+
+	chosen := results[0]
+	if len(results) > 1 {
+		items := make([]string, len(results))
+		for i, r := range results {
+			// Numbered so duplicate label texts stay distinguishable
+			items[i] = fmt.Sprintf("%d. %s (%.0f, %.0f)", i+1, r.Label, -r.Y, -r.X)
+		}
+		choice, err := zenity.List(
+			fmt.Sprintf("%d matches for '%s':", len(results), query),
+			items,
+			zenity.Title("Find"),
+			zenity.Height(400),
+		)
+		if err != nil {
+			return
+		}
+		for i, item := range items {
+			if item == choice {
+				chosen = results[i]
+				break
+			}
+		}
+	}
+
+	w.jumpTo(chosen.X, chosen.Y)
+	fmt.Printf("🔍 Found '%s' at (%.1f, %.1f)\n", chosen.Label, -chosen.Y, -chosen.X)
+}
+
+// jumpTo centers the camera on a map position, zooms in if needed and
+// briefly highlights the spot.
+func (w *Window) jumpTo(x, y float64) {
+	w.CamX = x
+	w.CamY = y
+	if w.Zoom < findZoom {
+		w.Zoom = findZoom
+	}
+	w.findHighlightX = x
+	w.findHighlightY = y
+	w.findHighlightUntil = time.Now().Add(findHighlightTime)
+}
+
+// drawFindHighlight draws a pulsing ring around the last search result.
+func (w *Window) drawFindHighlight(screen *ebiten.Image, cx, cy float64) {
+	remaining := time.Until(w.findHighlightUntil)
+	if remaining <= 0 {
+		return
+	}
+
+	hx, hy := w.toScreen32(w.findHighlightX, w.findHighlightY, cx, cy)
+	phase := float32(remaining.Milliseconds()%1000) / 1000
+	radius := 12 + 18*phase
+	alpha := uint8(255 * float64(remaining) / float64(findHighlightTime))
+	vector.StrokeCircle(screen, hx, hy, radius, 2.0, color.RGBA{0, 255, 255, alpha}, w.antiAlias)
+}
+
+// searchAllZones matches markers of every zone and labels of every indexed map.
+func (w *Window) searchAllZones(query string) []findResult {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+
+	var results []findResult
+	all := w.Config.AllMarkers()
+	zones := make([]string, 0, len(all))
+	for zone := range all {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	for _, zone := range zones {
+		for _, m := range all[zone] {
+			if strings.Contains(strings.ToLower(m.Label), query) {
+				results = append(results, findResult{Label: "📍 " + m.Label, X: m.X, Y: m.Y, Zone: zone})
+			}
+		}
+	}
+	for _, b := range w.boundsIndex.Zones {
+		zone := ""
+		for _, lbl := range b.Labels {
+			if strings.Contains(strings.ToLower(lbl.Text), query) {
+				if zone == "" {
+					zone = w.zoneNameForCode(b.FileCode)
+				}
+				results = append(results, findResult{Label: lbl.Text, X: lbl.X, Y: lbl.Y, Zone: zone})
+			}
+		}
+	}
+	return results
+}
+
+// openGlobalFind searches every zone and opens the chosen result's zone
+// with the spot highlighted.
+func (w *Window) openGlobalFind() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	if !w.zoneIndexReady("Find in All Zones") {
+		return
+	}
+
+	query, err := zenity.Entry(
+		"Search labels and markers in every zone:",
+		zenity.Title("Find in All Zones"),
+		zenity.EntryText(w.lastFind),
+	)
+	if err != nil || strings.TrimSpace(query) == "" {
+		return
+	}
+	w.lastFind = query
+
+	results := w.searchAllZones(query)
+	if len(results) == 0 {
+		zenity.Info(fmt.Sprintf("Nothing in any zone matches '%s'.", query), zenity.Title("Find in All Zones"))
+		return
+	}
+
+	items := make([]string, len(results))
+	for i, r := range results {
+		items[i] = fmt.Sprintf("%d. %s - %s (%.0f, %.0f)", i+1, r.Label, r.Zone, -r.Y, -r.X)
+	}
+	choice, err := zenity.List(
+		fmt.Sprintf("%d matches for '%s':", len(results), query),
+		items,
+		zenity.Title("Find in All Zones"),
+		zenity.Height(400),
+	)
+	if err != nil {
+		return
+	}
+
+	for i, item := range items {
+		if item != choice {
+			continue
+		}
+		r := results[i]
+		zone := r.Zone
+		if strings.EqualFold(zone, w.logZone) {
+			zone = w.logZone // Back to the player's own zone rather than browsing a copy
+		}
+		if zone != w.CurrentZone {
+			w.viewZone(zone)
+		}
+		w.jumpTo(r.X, r.Y)
+		fmt.Printf("🔍 Found '%s' in %s at (%.1f, %.1f)\n", r.Label, zone, -r.Y, -r.X)
+		return
+	}
+}
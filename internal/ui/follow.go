@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	followRate = 6.0    // How quickly the camera catches up (per second)
+	followSnap = 2000.0 // Jumps further than this (zoning, /loc after a port) aren't animated
+)
+
+// setFollow turns follow mode on or off.
+func (w *Window) setFollow(on bool) {
+	if w.FollowPlayer == on {
+		return
+	}
+	w.FollowPlayer = on
+	fmt.Printf("🎥 Follow player %s\n", map[bool]string{true: "ON", false: "OFF"}[on])
+}
+
+// updateFollow eases the camera toward the player each frame. /loc only
+// arrives every second or so, so moving a fraction of the remaining gap per
+// frame turns those jumps into a glide.
+func (w *Window) updateFollow() {
+	if !w.FollowPlayer || w.LogReader == nil || w.browsing() {
+		return
+	}
+	x, y := w.playerPosition()
+	dx := x - w.CamX
+	dy := y - w.CamY
+	if math.Hypot(dx, dy) > followSnap {
+		w.CamX, w.CamY = x, y
+		return
+	}
+	t := 1 - math.Exp(-followRate/float64(ebiten.TPS()))
+	w.CamX += dx * t
+	w.CamY += dy * t
+}
@@ -0,0 +1,225 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// All text is drawn in Go's TTF: map labels at the label size, everything
+// else (menus, panels, toasts, dialogs) at the UI size. The 7x13 bitmap font
+// is only a fallback for when the TTF can't be loaded.
+const uiBaseSize = 13.0 // Pixels at "medium", the bitmap font's height
+
+// fontSizes are the choices for ui_font_size and label_font_size.
+var fontSizes = []struct {
+	name, label string
+	scale       float64
+}{
+	{"small", "Small", 0.85},
+	{"", "Medium", 1.0},
+	{"large", "Large", 1.3},
+}
+
+var bitmapFace = text.NewGoXFace(basicfont.Face7x13)
+
+// uiFonts holds the parsed font; text/v2 caches glyphs per source and size.
+type uiFonts struct {
+	source    *text.GoTextFaceSource
+	err       bool // Parsing failed; everything falls back to the bitmap font
+	faces     map[float64]text.Face
+	autoScale float64 // Measured on first use
+}
+
+func fontSizeScale(name string) float64 {
+	for _, s := range fontSizes {
+		if s.name == name {
+			return s.scale
+		}
+	}
+	return 1.0
+}
+
+func fontSizeLabel(name string) string {
+	for _, s := range fontSizes {
+		if s.name == name {
+			return s.label
+		}
+	}
+	return fontSizes[1].label
+}
+
+// nextFontSize is the size after name in the cycle.
+func nextFontSize(name string) string {
+	for i, s := range fontSizes {
+		if s.name == name {
+			return fontSizes[(i+1)%len(fontSizes)].name
+		}
+	}
+	return fontSizes[0].name
+}
+
+// fontAutoScale grows text on monitors wider than 1080p that aren't
+// scaled by the OS (a scaled desktop already enlarges the whole window),
+// in quarter steps up to double.
+func (w *Window) fontAutoScale() float64 {
+	if w.Config.DisableFontAutoScale {
+		return 1.0
+	}
+	if w.fonts.autoScale == 0 {
+		w.fonts.autoScale = 1.0
+		if m := ebiten.Monitor(); m != nil {
+			width, _ := m.Size()
+			scale := math.Round(float64(width)/1920*4) / 4
+			w.fonts.autoScale = min(max(scale, 1.0), 2.0)
+		}
+	}
+	return w.fonts.autoScale
+}
+
+// face returns the TTF face at a pixel size, or the bitmap font if the TTF
+// couldn't be loaded.
+func (w *Window) face(px float64) text.Face {
+	f := &w.fonts
+	if f.err {
+		return bitmapFace
+	}
+	if f.source == nil {
+		source, err := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
+		if err != nil {
+			fmt.Printf("⚠️  Failed to load the UI font, using the bitmap one: %v\n", err)
+			f.err = true
+			return bitmapFace
+		}
+		f.source = source
+		f.faces = make(map[float64]text.Face)
+	}
+	if face, ok := f.faces[px]; ok {
+		return face
+	}
+	face := &text.GoTextFace{Source: f.source, Size: px}
+	f.faces[px] = face
+	return face
+}
+
+// uiFace is the face for menus, the info panel and names on the map.
+func (w *Window) uiFace() text.Face {
+	return w.face(w.uiSize())
+}
+
+// uiSize is the UI face's size in pixels.
+func (w *Window) uiSize() float64 {
+	return math.Round(uiBaseSize * fontSizeScale(w.Config.UIFontSize) * w.fontAutoScale())
+}
+
+// uiTextWidth is how many pixels s takes in the UI face.
+func (w *Window) uiTextWidth(s string) int {
+	return int(math.Ceil(text.Advance(s, w.uiFace())))
+}
+
+// uiLineHeight is the distance between lines of UI text.
+func (w *Window) uiLineHeight() int {
+	m := w.uiFace().Metrics()
+	return int(math.Ceil(m.HAscent + m.HDescent + m.HLineGap))
+}
+
+// menuRowHeight is the height of a dropdown entry (20 at the medium size).
+func (w *Window) menuRowHeight() int {
+	return max(20, w.uiLineHeight()+5)
+}
+
+// drawText draws s with its baseline at y, the way text.Draw from text v1
+// (and the rest of the UI) positions text.
+func drawText(dst *ebiten.Image, s string, face text.Face, x, y int, c color.Color) {
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(float64(x), float64(y)-face.Metrics().HAscent)
+	op.ColorScale.ScaleWithColor(c)
+	text.Draw(dst, s, face, op)
+}
+
+// drawUIText draws s in the UI face with its baseline at y.
+func (w *Window) drawUIText(dst *ebiten.Image, s string, x, y int, c color.Color) {
+	drawText(dst, s, w.uiFace(), x, y, c)
+}
+
+// drawUITextIn draws s in the UI face from x, centered vertically in the
+// row from top down height pixels.
+func (w *Window) drawUITextIn(dst *ebiten.Image, s string, x, top, height int, c color.Color) {
+	face := w.uiFace()
+	m := face.Metrics()
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(float64(x), float64(top)+(float64(height)-m.HAscent-m.HDescent)/2)
+	op.ColorScale.ScaleWithColor(c)
+	text.Draw(dst, s, face, op)
+}
+
+// clipUIText shortens s with "..." to fit width pixels in the UI face.
+func (w *Window) clipUIText(s string, width int) string {
+	if w.uiTextWidth(s) <= width {
+		return s
+	}
+	r := []rune(s)
+	for len(r) > 0 && w.uiTextWidth(string(r)+"...") > width {
+		r = r[:len(r)-1]
+	}
+	return string(r) + "..."
+}
+
+// panelRowHeight is the row height in the corner panels (corpses, stats,
+// waiting lists): 14 at the medium size.
+func (w *Window) panelRowHeight() int {
+	return max(14, w.uiLineHeight())
+}
+
+// panelSize is the size of a corner panel listing lines, one per row.
+func (w *Window) panelSize(lines []string) (width, height int) {
+	for _, l := range lines {
+		width = max(width, w.uiTextWidth(l))
+	}
+	return width + 12, len(lines)*w.panelRowHeight() + 8
+}
+
+// drawPanelLines draws a corner panel's lines from its top-left corner,
+// colored by colorOf(i).
+func (w *Window) drawPanelLines(dst *ebiten.Image, lines []string, px, py int, colorOf func(i int) color.Color) {
+	rowH := w.panelRowHeight()
+	for i, l := range lines {
+		w.drawUITextIn(dst, l, px+6, py+4+i*rowH, rowH, colorOf(i))
+	}
+}
+
+// panelRow is the row under y in a corner panel whose top is py.
+func (w *Window) panelRow(y, py int) int {
+	return (y - py - 4) / w.panelRowHeight()
+}
+
+// drawInfoText draws the info panel's lines from the top-left at (x, y),
+// shadowed so they read over any map.
+func (w *Window) drawInfoText(dst *ebiten.Image, lines []string, x, y int) {
+	face := w.uiFace()
+	step := w.uiLineHeight()
+	baseline := y + int(math.Ceil(face.Metrics().HAscent))
+	for _, line := range lines {
+		drawText(dst, line, face, x+1, baseline+1, color.RGBA{0, 0, 0, 200})
+		drawText(dst, line, face, x, baseline, color.White)
+		baseline += step
+	}
+}
+
+func (w *Window) cycleUIFontSize() {
+	w.Config.UIFontSize = nextFontSize(w.Config.UIFontSize)
+	w.Config.Save()
+	fmt.Printf("🔤 UI font size: %s\n", fontSizeLabel(w.Config.UIFontSize))
+}
+
+func (w *Window) cycleLabelFontSize() {
+	w.Config.LabelFontSize = nextFontSize(w.Config.LabelFontSize)
+	w.Config.Save()
+	fmt.Printf("🔤 Label font size: %s\n", fontSizeLabel(w.Config.LabelFontSize))
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Frameless mode hides the OS title bar and borders. The empty part of the
+// menu bar then moves the window, and the right and bottom edges resize it.
+
+const (
+	resizeEdge       = 6  // Pixels along the right and bottom edges that resize
+	resizeCorner     = 16 // Size of the bottom-right grip
+	minFramelessSize = 240
+)
+
+type windowDragKind int
+
+const (
+	dragNone windowDragKind = iota
+	dragMove
+	dragResizeRight
+	dragResizeBottom
+	dragResizeCorner
+)
+
+// windowDrag is a move or resize of the frameless window in progress.
+type windowDrag struct {
+	kind           windowDragKind
+	startX, startY int // Cursor when the drag started, in the window
+	offX, offY     int // Window size minus the cursor position, for resizes
+	cursor         ebiten.CursorShapeType
+}
+
+// windowDragAt is what pressing the mouse at (mx, my) would start.
+func (w *Window) windowDragAt(mx, my int) windowDragKind {
+	if !w.Config.Frameless || ebiten.IsWindowMaximized() {
+		return dragNone
+	}
+	right, bottom := mx >= w.Width-resizeEdge, my >= w.Height-resizeEdge
+	switch {
+	case mx >= w.Width-resizeCorner && my >= w.Height-resizeCorner:
+		return dragResizeCorner
+	case right:
+		return dragResizeRight
+	case bottom:
+		return dragResizeBottom
+	case my < w.menuBarHeight && mx >= w.menuTitlesEnd && w.openMenu == "":
+		return dragMove
+	}
+	return dragNone
+}
+
+// startWindowDrag begins moving or resizing the window if the click was on
+// the drag region or a resize edge.
+func (w *Window) startWindowDrag(mx, my int) bool {
+	kind := w.windowDragAt(mx, my)
+	if kind == dragNone {
+		return false
+	}
+	w.windowDrag.kind = kind
+	w.windowDrag.startX, w.windowDrag.startY = mx, my
+	w.windowDrag.offX, w.windowDrag.offY = w.Width-mx, w.Height-my
+	return true
+}
+
+// updateWindowDrag follows the cursor while a drag is held and shows the
+// matching cursor while hovering the drag region and edges.
+func (w *Window) updateWindowDrag(mx, my int) {
+	d := &w.windowDrag
+	if d.kind != dragNone && !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		d.kind = dragNone
+	}
+
+	switch d.kind {
+	case dragMove:
+		// The cursor is relative to the window, so moving the window by the
+		// cursor's offset puts it back at the start point
+		if dx, dy := mx-d.startX, my-d.startY; dx != 0 || dy != 0 {
+			x, y := ebiten.WindowPosition()
+			ebiten.SetWindowPosition(x+dx, y+dy)
+		}
+	case dragResizeRight, dragResizeBottom, dragResizeCorner:
+		width, height := w.Width, w.Height
+		if d.kind != dragResizeBottom {
+			width = max(mx+d.offX, minFramelessSize)
+		}
+		if d.kind != dragResizeRight {
+			height = max(my+d.offY, minFramelessSize)
+		}
+		if width != w.Width || height != w.Height {
+			ebiten.SetWindowSize(width, height)
+		}
+	}
+
+	kind := d.kind
+	if kind == dragNone && w.modal == nil {
+		kind = w.windowDragAt(mx, my)
+	}
+	shape := ebiten.CursorShapeDefault
+	switch kind {
+	case dragMove:
+		shape = ebiten.CursorShapeMove
+	case dragResizeRight:
+		shape = ebiten.CursorShapeEWResize
+	case dragResizeBottom:
+		shape = ebiten.CursorShapeNSResize
+	case dragResizeCorner:
+		shape = ebiten.CursorShapeNWSEResize
+	}
+	if shape != d.cursor {
+		ebiten.SetCursorShape(shape)
+		d.cursor = shape
+	}
+}
+
+// drawResizeGrip draws the diagonal lines in the bottom-right corner.
+func (w *Window) drawResizeGrip(screen *ebiten.Image) {
+	if !w.Config.Frameless || ebiten.IsWindowMaximized() {
+		return
+	}
+	x, y := float32(w.Width), float32(w.Height)
+	c := color.RGBA{160, 160, 160, 200}
+	for _, inset := range []float32{4, 8, 12} {
+		vector.StrokeLine(screen, x-inset, y-2, x-2, y-inset, 1, c, w.antiAlias)
+	}
+}
+
+func (w *Window) toggleFrameless() {
+	w.Config.Frameless = !w.Config.Frameless
+	w.windowDrag.kind = dragNone
+	w.applyOverlayMode()
+	w.Config.Save()
+	fmt.Printf("🪟 Frameless window %s\n", map[bool]string{true: "ON", false: "OFF"}[w.Config.Frameless])
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// runHealthCheck probes the rendering features the overlay relies on and
+// turns off whatever the GPU/driver can't handle. It has to run inside the
+// game loop (ReadPixels isn't available before RunGame), so Update calls it
+// once on the first frame.
+func (w *Window) runHealthCheck() {
+	w.healthChecked = true
+
+	if w.antiAlias && !probeAntiAlias() {
+		w.antiAlias = false
+		w.addNotice("Anti-aliasing disabled (not supported by GPU/driver)")
+	}
+
+	if w.transparent && (!ebiten.IsScreenTransparent() || !probeAlphaBlending()) {
+		w.transparent = false
+		w.Opacity = 1.0
+		w.addNotice("Transparency disabled (not supported by GPU/driver)")
+	}
+}
+
+// addNotice records a message shown in the info panel and echoes it to the console.
+func (w *Window) addNotice(msg string) {
+	fmt.Printf("⚠️  %s\n", msg)
+	w.notices = append(w.notices, msg)
+}
+
+// probeAntiAlias draws an anti-aliased circle offscreen and checks that the
+// result looks like a circle: solid in the middle, empty in the corners.
+// Broken drivers tend to either panic or smear garbage across the image.
+func probeAntiAlias() (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("❌ Anti-alias probe failed: %v\n", r)
+			ok = false
+		}
+	}()
+
+	const size = 16
+	img := ebiten.NewImage(size, size)
+	defer img.Deallocate()
+	vector.DrawFilledCircle(img, size/2, size/2, size/2-2, color.White, true)
+
+	pix := make([]byte, 4*size*size)
+	img.ReadPixels(pix)
+
+	alphaAt := func(x, y int) byte { return pix[(y*size+x)*4+3] }
+	if alphaAt(size/2, size/2) < 250 {
+		return false
+	}
+	if alphaAt(0, 0) != 0 || alphaAt(size-1, size-1) != 0 {
+		return false
+	}
+	return true
+}
+
+// probeAlphaBlending checks that a half-transparent fill keeps its alpha,
+// which the opacity control depends on.
+func probeAlphaBlending() (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("❌ Transparency probe failed: %v\n", r)
+			ok = false
+		}
+	}()
+
+	img := ebiten.NewImage(4, 4)
+	defer img.Deallocate()
+	img.Fill(color.RGBA{128, 128, 128, 128})
+
+	pix := make([]byte, 4*4*4)
+	img.ReadPixels(pix)
+
+	alpha := int(pix[3])
+	return alpha > 100 && alpha < 160
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/ncruces/zenity"
+)
+
+// heatHistory is the aggregate cmd/analyze built from old logs. It is read
+// again each time it's switched on, so a fresh run shows up without a
+// restart.
+type heatHistory struct {
+	dir      string
+	history  *config.History
+	heatmaps map[string]*config.Heatmap
+}
+
+func (h *heatHistory) heatmapFor(zone string) *config.Heatmap {
+	m, ok := h.heatmaps[zone]
+	if !ok {
+		m = config.LoadHistoryHeatmap(h.dir, zone, heatCellSize)
+		h.heatmaps[zone] = m
+	}
+	return m
+}
+
+func (w *Window) toggleHeatHistory() {
+	if w.heatHistory != nil {
+		w.heatHistory = nil
+		fmt.Println("🔥 Heatmap shows live data")
+		return
+	}
+
+	dir := config.GetHistoryDir()
+	history, err := config.LoadHistory(dir)
+	if err != nil {
+		w.dialogOpen = true
+		zenity.Info(
+			"No history yet. Build it from your old logs with:\n\ngo run ./cmd/analyze -logs <EQ>/Logs",
+			zenity.Title("Heatmap History"),
+		)
+		w.dialogOpen = false
+		w.lastMousePressed = true
+		return
+	}
+	w.heatHistory = &heatHistory{dir: dir, history: history, heatmaps: make(map[string]*config.Heatmap)}
+	w.showHeatmap = true
+	fmt.Printf("🔥 Heatmap shows history: %d logs, %s to %s\n", history.Logs,
+		history.From.Format("2006-01-02"), history.To.Format("2006-01-02"))
+}
+
+// heatHistoryInfo sums up the shown zone's history for the info panel.
+func (w *Window) heatHistoryInfo() string {
+	if w.heatHistory == nil || !w.showHeatmap {
+		return ""
+	}
+	z, ok := w.heatHistory.history.Zones[w.CurrentZone]
+	if !ok {
+		return "History: never here"
+	}
+	return fmt.Sprintf("History: %s here, %d kills, %d deaths", formatSessionTime(time.Duration(z.Seconds*float64(time.Second))), z.Kills, z.Deaths)
+}
+
+// drawHistoryDeaths marks every recorded death in the shown zone with a
+// small grey cross.
+func (w *Window) drawHistoryDeaths(screen *ebiten.Image, cx, cy float64) {
+	z, ok := w.heatHistory.history.Zones[w.CurrentZone]
+	if !ok {
+		return
+	}
+	c := color.RGBA{200, 200, 200, 200}
+	for _, d := range z.DeathLocs {
+		x, y := w.toScreen32(d[0], d[1], cx, cy)
+		vector.StrokeLine(screen, x-4, y-4, x+4, y+4, 1.5, c, w.antiAlias)
+		vector.StrokeLine(screen, x-4, y+4, x+4, y-4, 1.5, c, w.antiAlias)
+	}
+}
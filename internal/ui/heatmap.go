@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	heatCellSize  = 50 // Map units
+	heatSaveEvery = time.Minute
+
+	// heatStaleAfter stops counting when the position hasn't been updated
+	// for a while (the game was probably closed with the overlay left open).
+	heatStaleAfter = 10 * time.Minute
+)
+
+// heatmapFor returns the heatmap of a zone, loading it on first use.
+func (w *Window) heatmapFor(zone string) *config.Heatmap {
+	if w.heatmaps == nil {
+		w.heatmaps = make(map[string]*config.Heatmap)
+	}
+	h, ok := w.heatmaps[zone]
+	if !ok {
+		h = config.LoadHeatmap(zone, heatCellSize)
+		w.heatmaps[zone] = h
+	}
+	return h
+}
+
+// saveHeatmaps writes every heatmap loaded this session.
+func (w *Window) saveHeatmaps() {
+	w.heatSaved = time.Now()
+	if w.simulated {
+		return
+	}
+	for zone, h := range w.heatmaps {
+		if err := config.SaveHeatmap(zone, h); err != nil {
+			fmt.Printf("❌ Error saving heatmap: %v\n", err)
+		}
+	}
+}
+
+// updateHeatmap adds the time since the last frame to the player's cell.
+func (w *Window) updateHeatmap() {
+	now := time.Now()
+	elapsed := now.Sub(w.heatSampled).Seconds()
+	w.heatSampled = now
+	if w.LogReader == nil || w.logZone == "" {
+		return
+	}
+	s := w.LogReader.State()
+	if s.LocTime.IsZero() || now.Sub(s.LocTime) > heatStaleAfter {
+		return
+	}
+	if elapsed > 1 {
+		elapsed = 1 // The game loop was paused (dialog, minimized)
+	}
+	w.heatmapFor(w.logZone).Add(s.X, s.Y, elapsed)
+
+	if now.Sub(w.heatSaved) >= heatSaveEvery {
+		w.saveHeatmaps()
+	}
+}
+
+func (w *Window) clearHeatmap() {
+	w.heatmapFor(w.CurrentZone).Seconds = make(map[config.HeatCell]float64)
+	w.saveHeatmaps()
+	fmt.Printf("🔥 Cleared heatmap for %s\n", w.CurrentZone)
+}
+
+// heatColor ramps from blue (a little time) through green and yellow to red
+// (the most-visited cell).
+func heatColor(f float64) color.RGBA {
+	const alpha = 110
+	stops := []color.RGBA{{0, 80, 255, alpha}, {0, 220, 80, alpha}, {255, 230, 0, alpha}, {255, 40, 0, alpha}}
+	f *= float64(len(stops) - 1)
+	i := int(f)
+	if i >= len(stops)-1 {
+		return stops[len(stops)-1]
+	}
+	t := f - float64(i)
+	a, b := stops[i], stops[i+1]
+	lerp := func(x, y uint8) uint8 { return uint8(float64(x) + (float64(y)-float64(x))*t) }
+	return color.RGBA{lerp(a.R, b.R), lerp(a.G, b.G), lerp(a.B, b.B), alpha}
+}
+
+// drawHeatmap draws the shown zone's cells (live, or the history from
+// cmd/analyze) as translucent squares. Cells with under 1% of the busiest
+// cell's time are left out.
+func (w *Window) drawHeatmap(screen *ebiten.Image, cx, cy float64) {
+	if !w.showHeatmap || w.CurrentZone == "" {
+		return
+	}
+	if w.heatHistory != nil {
+		w.drawHeatCells(screen, cx, cy, w.heatHistory.heatmapFor(w.CurrentZone))
+		w.drawHistoryDeaths(screen, cx, cy)
+		return
+	}
+	w.drawHeatCells(screen, cx, cy, w.heatmapFor(w.CurrentZone))
+}
+
+func (w *Window) drawHeatCells(screen *ebiten.Image, cx, cy float64, h *config.Heatmap) {
+	max := h.Max()
+	if max <= 0 {
+		return
+	}
+	size := float32(h.CellSize * w.Zoom)
+	for cell, seconds := range h.Seconds {
+		f := seconds / max
+		if f < 0.01 {
+			continue
+		}
+		x, y := float64(cell.X)*h.CellSize, float64(cell.Y)*h.CellSize
+		if w.rotation.angle != 0 {
+			w.fillMapSquare(screen, cx, cy, x, y, h.CellSize, heatColor(f))
+			continue
+		}
+		sx, sy := w.toScreen32(x, y, cx, cy)
+		if sx > float32(w.Width) || sy > float32(w.Height) || sx+size < 0 || sy+size < 0 {
+			continue
+		}
+		vector.DrawFilledRect(screen, sx, sy, size, size, heatColor(f), false)
+	}
+}
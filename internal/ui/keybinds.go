@@ -0,0 +1,260 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/ncruces/zenity"
+)
+
+// Bindable actions. The string values are the keys used in config.json's
+// "key_bindings" section.
+const (
+	ActionPanUp             = "pan_up"
+	ActionPanDown           = "pan_down"
+	ActionPanLeft           = "pan_left"
+	ActionPanRight          = "pan_right"
+	ActionCenterPlayer      = "center_player"
+	ActionOpacityDown       = "opacity_down"
+	ActionOpacityUp         = "opacity_up"
+	ActionCycleLabels       = "cycle_labels"
+	ActionToggleBreadcrumbs = "toggle_breadcrumbs"
+	ActionClearBreadcrumbs  = "clear_breadcrumbs"
+	ActionClearCorpse       = "clear_corpse"
+	ActionCycleZLevel       = "cycle_zlevel"
+	ActionZLevelUp          = "zlevel_up"
+	ActionZLevelDown        = "zlevel_down"
+	ActionZRangeUp          = "zrange_up"
+	ActionZRangeDown        = "zrange_down"
+	ActionFitMap            = "fit_map"
+	ActionPlaceMarker       = "place_marker"
+	ActionToggleMarkers     = "toggle_markers"
+	ActionSetWaypoint       = "set_waypoint"
+	ActionRuler             = "ruler"
+	ActionFollowPlayer      = "follow_player"
+	ActionEditMap           = "edit_map"
+	ActionAnnounce          = "announce"
+	ActionClickThrough      = "click_through"
+	ActionCleanCapture      = "clean_capture"
+	ActionSaveCapture       = "save_capture"
+	ActionResetUI           = "reset_ui"
+	ActionRotateLeft        = "rotate_left"
+	ActionRotateRight       = "rotate_right"
+	ActionHeadingUp         = "heading_up"
+
+	// Camera bookmarks by position in the zone's list (see bookmarks.go)
+	ActionBookmark1 = "bookmark_1"
+	ActionBookmark2 = "bookmark_2"
+	ActionBookmark3 = "bookmark_3"
+	ActionBookmark4 = "bookmark_4"
+	ActionBookmark5 = "bookmark_5"
+	ActionBookmark6 = "bookmark_6"
+	ActionBookmark7 = "bookmark_7"
+	ActionBookmark8 = "bookmark_8"
+	ActionBookmark9 = "bookmark_9"
+)
+
+type keyAction struct {
+	Name       string
+	Label      string
+	DefaultKey ebiten.Key
+}
+
+// keyActions is the full keybinding table, in the order shown in the
+// Key Bindings dialog.
+var keyActions = []keyAction{
+	{ActionPanUp, "Pan Up", ebiten.KeyW},
+	{ActionPanDown, "Pan Down", ebiten.KeyS},
+	{ActionPanLeft, "Pan Left", ebiten.KeyA},
+	{ActionPanRight, "Pan Right", ebiten.KeyD},
+	{ActionCenterPlayer, "Center on Player", ebiten.KeySpace},
+	{ActionOpacityDown, "Opacity -", ebiten.KeyMinus},
+	{ActionOpacityUp, "Opacity +", ebiten.KeyEqual},
+	{ActionCycleLabels, "Cycle Labels", ebiten.KeyL},
+	{ActionToggleBreadcrumbs, "Toggle Breadcrumbs", ebiten.KeyB},
+	{ActionClearBreadcrumbs, "Clear Breadcrumbs", ebiten.KeyC},
+	{ActionClearCorpse, "Clear Corpse Marker", ebiten.KeyK},
+	{ActionCycleZLevel, "Cycle Z-Level Mode", ebiten.KeyZ},
+	{ActionZLevelUp, "Z-Level Up", ebiten.KeyPageUp},
+	{ActionZLevelDown, "Z-Level Down", ebiten.KeyPageDown},
+	{ActionZRangeUp, "Z-Range Increase", ebiten.KeyInsert},
+	{ActionZRangeDown, "Z-Range Decrease", ebiten.KeyDelete},
+	{ActionFitMap, "Fit Map to Window", ebiten.KeyHome},
+	{ActionPlaceMarker, "Place Marker", ebiten.KeyM},
+	{ActionToggleMarkers, "Toggle Markers", ebiten.KeyR},
+	{ActionSetWaypoint, "Set Waypoint", ebiten.KeyN},
+	{ActionRuler, "Ruler", ebiten.KeyU},
+	{ActionFollowPlayer, "Follow Player", ebiten.KeyF},
+	{ActionEditMap, "Edit Map", ebiten.KeyE},
+	{ActionAnnounce, "Speak Status", ebiten.KeyI},
+	{ActionClickThrough, "Toggle Click-Through", ebiten.KeyP},
+	{ActionCleanCapture, "Clean Capture View", ebiten.KeyO},
+	{ActionSaveCapture, "Save Map Capture", ebiten.KeyF12},
+	{ActionResetUI, "Reset UI State", ebiten.KeyF9},
+	{ActionRotateLeft, "Rotate Map Left", ebiten.KeyComma},
+	{ActionRotateRight, "Rotate Map Right", ebiten.KeyPeriod},
+	{ActionHeadingUp, "Toggle Heading Up", ebiten.KeyH},
+	{ActionBookmark1, "Camera Bookmark 1", ebiten.Key1},
+	{ActionBookmark2, "Camera Bookmark 2", ebiten.Key2},
+	{ActionBookmark3, "Camera Bookmark 3", ebiten.Key3},
+	{ActionBookmark4, "Camera Bookmark 4", ebiten.Key4},
+	{ActionBookmark5, "Camera Bookmark 5", ebiten.Key5},
+	{ActionBookmark6, "Camera Bookmark 6", ebiten.Key6},
+	{ActionBookmark7, "Camera Bookmark 7", ebiten.Key7},
+	{ActionBookmark8, "Camera Bookmark 8", ebiten.Key8},
+	{ActionBookmark9, "Camera Bookmark 9", ebiten.Key9},
+}
+
+// binding returns an action's keys, honoring config overrides (see
+// keychords.go for the format).
+func (w *Window) binding(action string) keyBinding {
+	if name, ok := w.Config.KeyBindings[action]; ok {
+		if b, err := parseKeyBinding(name); err == nil {
+			return b
+		}
+	}
+	for _, a := range keyActions {
+		if a.Name == action {
+			return keyBinding{{Key: a.DefaultKey}}
+		}
+	}
+	return nil
+}
+
+// keyHeld reports whether an action's key is currently down with exactly
+// its modifiers. A chord can't be held; it counts for the frame it ends.
+func (w *Window) keyHeld(action string) bool {
+	b := w.binding(action)
+	if len(b) != 1 {
+		return w.chords.fired[action]
+	}
+	mods := heldModifiers()
+	mods.Key = b[0].Key
+	return mods == b[0] && ebiten.IsKeyPressed(b[0].Key)
+}
+
+// keyTriggered reports whether an action's key went down this frame, or
+// its chord was completed.
+func (w *Window) keyTriggered(action string) bool {
+	b := w.binding(action)
+	if len(b) != 1 {
+		return w.chords.fired[action]
+	}
+	return inpututil.IsKeyJustPressed(b[0].Key) && w.keyHeld(action) && !slices.Contains(w.chords.consumed, b[0])
+}
+
+// shortKeyNames keeps menu hotkey hints compact.
+var shortKeyNames = map[ebiten.Key]string{
+	ebiten.KeyEqual:    "=",
+	ebiten.KeyMinus:    "-",
+	ebiten.KeyPageUp:   "PgUp",
+	ebiten.KeyPageDown: "PgDn",
+	ebiten.KeyInsert:   "Ins",
+	ebiten.KeyDelete:   "Del",
+	ebiten.KeyComma:    ",",
+	ebiten.KeyPeriod:   ".",
+}
+
+// hotkeyLabel is the hint shown next to a menu item for an action.
+func (w *Window) hotkeyLabel(action string) string {
+	return w.binding(action).label()
+}
+
+// openKeyBindings shows the bindings list; picking one waits for a new key.
+func (w *Window) openKeyBindings() {
+	const resetItem = "Reset All to Defaults"
+	items := make([]string, 0, len(keyActions)+1)
+	for _, a := range keyActions {
+		items = append(items, fmt.Sprintf("%s (%s)", a.Label, w.hotkeyLabel(a.Name)))
+	}
+	items = append(items, resetItem)
+
+	w.dialogOpen = true
+	choice, err := zenity.List("Select an action to rebind:", items, zenity.Title("Key Bindings"), zenity.Height(520))
+	w.dialogOpen = false
+	w.lastMousePressed = true
+	if err != nil || choice == "" {
+		return
+	}
+
+	if choice == resetItem {
+		w.Config.KeyBindings = make(map[string]string)
+		w.saveKeyBindings()
+		return
+	}
+	for i, item := range items {
+		if item == choice && i < len(keyActions) {
+			w.rebindingAction = keyActions[i].Name
+			w.rebindSteps = nil
+			return
+		}
+	}
+}
+
+// updateRebinding captures the new binding for the action being rebound:
+// one key with any modifiers, or a chord if a second key follows within
+// chordTimeout.
+func (w *Window) updateRebinding() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		w.rebindingAction = ""
+		return
+	}
+	if combos := pressedCombos(); len(combos) > 0 {
+		w.rebindSteps = append(w.rebindSteps, combos[0])
+		w.rebindDeadline = time.Now().Add(chordTimeout)
+	}
+	if len(w.rebindSteps) == 0 || (len(w.rebindSteps) < maxChordSteps && time.Now().Before(w.rebindDeadline)) {
+		return
+	}
+
+	b := w.rebindSteps
+	for _, a := range keyActions {
+		if a.Name != w.rebindingAction && bindingsClash(w.binding(a.Name), b) {
+			fmt.Printf("⚠️  %s is also bound to %s; the chord wins when one starts the other\n", b, a.Label)
+		}
+	}
+	w.Config.KeyBindings[w.rebindingAction] = b.String()
+	fmt.Printf("⌨️  Bound %s to %s\n", w.rebindingAction, b)
+	w.rebindingAction = ""
+	w.rebindSteps = nil
+	w.saveKeyBindings()
+}
+
+// bindingsClash reports whether two bindings are the same or one is the
+// start of the other.
+func bindingsClash(a, b keyBinding) bool {
+	n := min(len(a), len(b))
+	return n > 0 && slices.Equal(a[:n], b[:n])
+}
+
+func (w *Window) saveKeyBindings() {
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving key bindings: %v\n", err)
+	}
+}
+
+// drawRebindPrompt overlays the "press a key" prompt while rebinding.
+func (w *Window) drawRebindPrompt(screen *ebiten.Image) {
+	label := w.rebindingAction
+	for _, a := range keyActions {
+		if a.Name == w.rebindingAction {
+			label = a.Label
+		}
+	}
+	msg := fmt.Sprintf("Press a key or combo for '%s', then another for a chord (Esc to cancel)", label)
+	if len(w.rebindSteps) > 0 {
+		msg = fmt.Sprintf("'%s': %s ... (another key makes a chord)", label, w.rebindSteps.label())
+	}
+	boxW := w.uiTextWidth(msg) + 24
+	boxH := w.uiLineHeight() + 21
+	bx := (w.Width - boxW) / 2
+	by := (w.Height - boxH) / 2
+	vector.DrawFilledRect(screen, float32(bx), float32(by), float32(boxW), float32(boxH), color.RGBA{20, 20, 20, 235}, false)
+	vector.StrokeRect(screen, float32(bx), float32(by), float32(boxW), float32(boxH), 1, color.RGBA{180, 180, 180, 255}, false)
+	w.drawUITextIn(screen, msg, bx+12, by, boxH, color.White)
+}
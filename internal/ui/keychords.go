@@ -0,0 +1,212 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// A binding is a key with modifiers ("Ctrl+Shift+M"), or a chord of up to
+// maxChordSteps of them pressed one after the other ("Ctrl+K M"). In
+// config.json steps are separated by spaces and modifiers joined with +.
+// Modifiers must match exactly, so M doesn't fire while Ctrl+M is pressed.
+const (
+	maxChordSteps = 2
+	chordTimeout  = 1500 * time.Millisecond // To press the next step of a chord
+)
+
+type keyCombo struct {
+	Key              ebiten.Key
+	Ctrl, Shift, Alt bool // Ctrl includes Cmd (Meta) on macOS
+}
+
+type keyBinding []keyCombo
+
+// modifierKeys never make a step on their own.
+var modifierKeys = []ebiten.Key{
+	ebiten.KeyControl, ebiten.KeyControlLeft, ebiten.KeyControlRight,
+	ebiten.KeyShift, ebiten.KeyShiftLeft, ebiten.KeyShiftRight,
+	ebiten.KeyAlt, ebiten.KeyAltLeft, ebiten.KeyAltRight,
+	ebiten.KeyMeta, ebiten.KeyMetaLeft, ebiten.KeyMetaRight,
+}
+
+// parseKeyBinding reads a binding such as "M", "Ctrl+Shift+M" or "Ctrl+K M".
+func parseKeyBinding(s string) (keyBinding, error) {
+	steps := strings.Fields(s)
+	if len(steps) == 0 || len(steps) > maxChordSteps {
+		return nil, fmt.Errorf("key binding %q: want 1 to %d keys", s, maxChordSteps)
+	}
+	var b keyBinding
+	for _, step := range steps {
+		parts := strings.Split(step, "+")
+		var c keyCombo
+		for _, mod := range parts[:len(parts)-1] {
+			switch strings.ToLower(mod) {
+			case "ctrl", "control", "cmd", "meta":
+				c.Ctrl = true
+			case "shift":
+				c.Shift = true
+			case "alt":
+				c.Alt = true
+			default:
+				return nil, fmt.Errorf("key binding %q: unknown modifier %q", s, mod)
+			}
+		}
+		if err := c.Key.UnmarshalText([]byte(parts[len(parts)-1])); err != nil || slices.Contains(modifierKeys, c.Key) {
+			return nil, fmt.Errorf("key binding %q: unknown key %q", s, parts[len(parts)-1])
+		}
+		b = append(b, c)
+	}
+	return b, nil
+}
+
+func (c keyCombo) String() string {
+	return c.format(c.Key.String())
+}
+
+// label is the compact form used in menu hints.
+func (c keyCombo) label() string {
+	if short, ok := shortKeyNames[c.Key]; ok {
+		return c.format(short)
+	}
+	return c.String()
+}
+
+func (c keyCombo) format(key string) string {
+	var sb strings.Builder
+	if c.Ctrl {
+		sb.WriteString("Ctrl+")
+	}
+	if c.Shift {
+		sb.WriteString("Shift+")
+	}
+	if c.Alt {
+		sb.WriteString("Alt+")
+	}
+	sb.WriteString(key)
+	return sb.String()
+}
+
+func (b keyBinding) String() string {
+	steps := make([]string, len(b))
+	for i, c := range b {
+		steps[i] = c.String()
+	}
+	return strings.Join(steps, " ")
+}
+
+func (b keyBinding) label() string {
+	steps := make([]string, len(b))
+	for i, c := range b {
+		steps[i] = c.label()
+	}
+	return strings.Join(steps, " ")
+}
+
+// heldModifiers returns the modifiers down right now.
+func heldModifiers() keyCombo {
+	return keyCombo{
+		Ctrl:  ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta),
+		Shift: ebiten.IsKeyPressed(ebiten.KeyShift),
+		Alt:   ebiten.IsKeyPressed(ebiten.KeyAlt),
+	}
+}
+
+// pressedCombos returns the keys that went down this frame, each with the
+// modifiers held.
+func pressedCombos() []keyCombo {
+	mods := heldModifiers()
+	var combos []keyCombo
+	for _, key := range inpututil.AppendJustPressedKeys(nil) {
+		if slices.Contains(modifierKeys, key) {
+			continue
+		}
+		c := mods
+		c.Key = key
+		combos = append(combos, c)
+	}
+	return combos
+}
+
+// chordState tracks a chord in progress and what finished this frame.
+type chordState struct {
+	pending  keyBinding // Steps pressed so far
+	deadline time.Time
+	fired    map[string]bool // Chord actions completed this frame
+	consumed []keyCombo      // Keys that went to a chord this frame
+}
+
+// updateChords advances any chord in progress with this frame's keys. It
+// runs once per frame before actions are checked; keyTriggered reads the
+// result. A key that continues a chord isn't also a single-key action.
+// While a dialog has the keyboard (active false) typing doesn't count.
+func (w *Window) updateChords(active bool) {
+	cs := &w.chords
+	clear(cs.fired)
+	cs.consumed = cs.consumed[:0]
+	if !active || (len(cs.pending) > 0 && time.Now().After(cs.deadline)) {
+		cs.pending = nil
+	}
+	if !active {
+		return
+	}
+
+	for _, c := range pressedCombos() {
+		// A key that breaks off a chord may start another
+		if !w.advanceChord(c) && len(cs.pending) > 0 {
+			cs.pending = nil
+			w.advanceChord(c)
+		}
+	}
+}
+
+// advanceChord adds a step to the chord in progress, reporting whether any
+// chord binding starts with the steps so far.
+func (w *Window) advanceChord(c keyCombo) bool {
+	cs := &w.chords
+	steps := append(slices.Clone(cs.pending), c)
+	matched, done := false, false
+	for _, a := range keyActions {
+		b := w.binding(a.Name)
+		if len(b) < 2 || len(b) < len(steps) || !slices.Equal(b[:len(steps)], steps) {
+			continue
+		}
+		matched = true
+		if len(b) == len(steps) {
+			if cs.fired == nil {
+				cs.fired = make(map[string]bool)
+			}
+			cs.fired[a.Name] = true
+			done = true
+		}
+	}
+	if !matched {
+		return false
+	}
+	cs.consumed = append(cs.consumed, c)
+	if done {
+		cs.pending = nil
+	} else {
+		cs.pending = steps
+		cs.deadline = time.Now().Add(chordTimeout)
+	}
+	return true
+}
+
+// drawChordHint shows the steps of a chord in progress.
+func (w *Window) drawChordHint(screen *ebiten.Image) {
+	if len(w.chords.pending) == 0 {
+		return
+	}
+	msg := w.chords.pending.label() + " ..."
+	boxW, boxH := w.uiTextWidth(msg)+16, w.bannerHeight()
+	bx, by := (w.Width-boxW)/2, w.Height-18-boxH
+	vector.DrawFilledRect(screen, float32(bx), float32(by), float32(boxW), float32(boxH), color.RGBA{20, 20, 20, 220}, false)
+	w.drawUITextIn(screen, msg, bx+8, by, boxH, color.White)
+}
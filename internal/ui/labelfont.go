@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// Map labels grow and shrink with the zoom, between the configured sizes,
+// so zone names stay readable zoomed out without covering the map zoomed
+// in. label_font_size and the monitor autoscale (see fonts.go) scale the
+// result, limits included.
+const (
+	labelBaseSize       = 13.0 // Pixels at zoom 1, as big as the old bitmap labels
+	defaultLabelMinSize = 10
+	defaultLabelMaxSize = 22
+)
+
+// labelWeight turns a map label's size field (1 small, 2 normal, 3 large)
+// into a scale on the base size.
+func labelWeight(size int) float64 {
+	switch {
+	case size <= 1:
+		return 0.85
+	case size == 2:
+		return 1.0
+	default:
+		return 1.2
+	}
+}
+
+// labelPixelSize is the size a label of the given weight is drawn at the
+// current zoom. It follows the square root of the zoom: text scaled fully
+// with the map would be unreadable by the time a whole zone fits.
+func (w *Window) labelPixelSize(size int) int {
+	lo, hi := w.Config.LabelMinSize, w.Config.LabelMaxSize
+	if lo <= 0 {
+		lo = defaultLabelMinSize
+	}
+	if hi <= 0 {
+		hi = defaultLabelMaxSize
+	}
+	hi = max(hi, lo)
+	px := labelBaseSize * labelWeight(size) * math.Sqrt(w.Zoom)
+	px = min(max(px, float64(lo)), float64(hi))
+	return int(math.Round(px * fontSizeScale(w.Config.LabelFontSize) * w.fontAutoScale()))
+}
+
+// labelFace returns the label face for a pixel size.
+func (w *Window) labelFace(px int) text.Face {
+	return w.face(float64(px))
+}
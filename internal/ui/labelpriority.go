@@ -0,0 +1,333 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/maps"
+	"github.com/ncruces/zenity"
+)
+
+// Map labels get a priority from the user's rules for the maps directory in
+// use (a map pack labels things its own way). Without a matching rule, zone
+// lines are important and everything else is normal.
+
+// minorLabelZoom is how far to zoom in before minor labels show.
+const minorLabelZoom = 1.0
+
+var labelPriorityNames = []string{config.LabelImportant, config.LabelNormal, config.LabelMinor, config.LabelHidden}
+
+var labelPriorityHelp = map[string]string{
+	config.LabelImportant: "important - always shown",
+	config.LabelNormal:    "normal - shown with Labels: All",
+	config.LabelMinor:     "minor - shown with Labels: All when zoomed in",
+	config.LabelHidden:    "hidden - never shown",
+}
+
+// labelPriorities caches each label's priority for the zone shown.
+type labelPriorities struct {
+	data  *maps.ZoneMap
+	stale bool
+	prio  []string
+}
+
+func (w *Window) labelRules() []config.LabelRule {
+	return w.Config.LabelRules[filepath.Clean(w.MapDir)]
+}
+
+func (w *Window) setLabelRules(rules []config.LabelRule) {
+	key := filepath.Clean(w.MapDir)
+	if len(rules) == 0 {
+		delete(w.Config.LabelRules, key)
+	} else {
+		if w.Config.LabelRules == nil {
+			w.Config.LabelRules = make(map[string][]config.LabelRule)
+		}
+		w.Config.LabelRules[key] = rules
+	}
+	w.labelPrio.stale = true
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving label priorities: %v\n", err)
+	}
+}
+
+func labelRuleMatches(r config.LabelRule, lbl maps.MapLabel) bool {
+	if r.Match != "" && !strings.Contains(strings.ToLower(lbl.Text), strings.ToLower(r.Match)) {
+		return false
+	}
+	if r.Color != "" && config.ParseColor(r.Color) != (color.RGBA{lbl.Color.R, lbl.Color.G, lbl.Color.B, 255}) {
+		return false
+	}
+	return true
+}
+
+func labelPriority(rules []config.LabelRule, lbl maps.MapLabel) string {
+	for _, r := range rules {
+		if labelRuleMatches(r, lbl) {
+			return r.Priority
+		}
+	}
+	// Zone lines start with "to " (underscores were replaced with spaces)
+	if strings.HasPrefix(lbl.Text, "to ") {
+		return config.LabelImportant
+	}
+	return config.LabelNormal
+}
+
+// mapLabelPriorities returns the priority of each of the zone's labels.
+func (w *Window) mapLabelPriorities() []string {
+	p := &w.labelPrio
+	if p.data != w.MapData || p.stale {
+		rules := w.labelRules()
+		p.prio = p.prio[:0]
+		for _, lbl := range w.MapData.Labels {
+			p.prio = append(p.prio, labelPriority(rules, lbl))
+		}
+		p.data, p.stale = w.MapData, false
+	}
+	return p.prio
+}
+
+// labelShown says whether a label of this priority is drawn in the current
+// label mode and zoom.
+func (w *Window) labelShown(priority string) bool {
+	switch priority {
+	case config.LabelImportant:
+		return w.LabelMode < 3
+	case config.LabelNormal:
+		return w.LabelMode == 0
+	case config.LabelMinor:
+		return w.LabelMode == 0 && w.Zoom >= minorLabelZoom
+	}
+	return false
+}
+
+func describeLabelRule(r config.LabelRule) string {
+	var what []string
+	if r.Match != "" {
+		what = append(what, fmt.Sprintf("text contains '%s'", r.Match))
+	}
+	if r.Color != "" {
+		what = append(what, "color "+r.Color)
+	}
+	if len(what) == 0 {
+		what = append(what, "every label")
+	}
+	return fmt.Sprintf("%s: %s", r.Priority, strings.Join(what, " and "))
+}
+
+const labelRuleAdd = "Add Rule..."
+
+// openLabelPriorities lists the rules for the current maps and edits the one
+// picked.
+func (w *Window) openLabelPriorities() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	for {
+		rules := w.labelRules()
+		items := make([]string, 0, len(rules)+1)
+		for i, r := range rules {
+			items = append(items, fmt.Sprintf("%d. %s", i+1, describeLabelRule(r)))
+		}
+		items = append(items, labelRuleAdd)
+
+		choice, err := zenity.List(
+			"Rules from first to last; the first that matches a label wins.\nUnmatched zone lines are important, other labels normal.",
+			items,
+			zenity.Title("Label Priorities"),
+			zenity.Height(420),
+			zenity.ExtraButton("Reset"),
+		)
+		if errors.Is(err, zenity.ErrExtraButton) {
+			w.setLabelRules(nil)
+			fmt.Println("🏷️  Label priorities reset")
+			continue
+		}
+		if err != nil || choice == "" {
+			return
+		}
+		if choice == labelRuleAdd {
+			if r, ok := w.newLabelRule(); ok {
+				w.setLabelRules(append(rules, r))
+			}
+			continue
+		}
+		n, err := strconv.Atoi(choice[:strings.Index(choice, ".")])
+		if err != nil {
+			return
+		}
+		w.editLabelRule(rules, n-1)
+	}
+}
+
+const (
+	labelRuleByText  = "Match text..."
+	labelRuleByColor = "Match color..."
+)
+
+// newLabelRule asks what a rule matches and what priority it gives.
+func (w *Window) newLabelRule() (config.LabelRule, bool) {
+	var r config.LabelRule
+	by, err := zenity.List("Match labels by:", []string{labelRuleByText, labelRuleByColor}, zenity.Title("Add Rule"))
+	if err != nil {
+		return r, false
+	}
+	switch by {
+	case labelRuleByText:
+		match, ok := askLabelMatch("")
+		if !ok {
+			return r, false
+		}
+		r.Match = match
+	case labelRuleByColor:
+		c, ok := w.pickLabelColor()
+		if !ok {
+			return r, false
+		}
+		r.Color = c
+	default:
+		return r, false
+	}
+	r.Priority, err = askLabelPriority(describeLabelRule(r), config.LabelImportant)
+	return r, err == nil
+}
+
+func askLabelMatch(current string) (string, bool) {
+	match, err := zenity.Entry(
+		"Labels containing (case doesn't matter):",
+		zenity.Title("Label Priorities"),
+		zenity.EntryText(current),
+	)
+	match = strings.TrimSpace(match)
+	return match, err == nil && match != ""
+}
+
+func askLabelPriority(what, current string) (string, error) {
+	items := make([]string, len(labelPriorityNames))
+	for i, p := range labelPriorityNames {
+		items[i] = labelPriorityHelp[p]
+	}
+	choice, err := zenity.List(
+		what,
+		items,
+		zenity.Title("Label Priorities"),
+		zenity.DefaultItems(labelPriorityHelp[current]),
+	)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range labelPriorityNames {
+		if labelPriorityHelp[p] == choice {
+			return p, nil
+		}
+	}
+	return "", zenity.ErrCanceled
+}
+
+// pickLabelColor lists the label colors in the shown zone, most used first,
+// with an example of each.
+func (w *Window) pickLabelColor() (string, bool) {
+	if w.MapData == nil || len(w.MapData.Labels) == 0 {
+		zenity.Info("The zone shown has no labels to take colors from.", zenity.Title("Label Priorities"))
+		return "", false
+	}
+	count := make(map[string]int)
+	example := make(map[string]string)
+	for _, lbl := range w.MapData.Labels {
+		hex := config.HexColor(lbl.Color)
+		if count[hex] == 0 {
+			example[hex] = lbl.Text
+		}
+		count[hex]++
+	}
+	colors := make([]string, 0, len(count))
+	for hex := range count {
+		colors = append(colors, hex)
+	}
+	sort.Slice(colors, func(i, j int) bool {
+		if count[colors[i]] != count[colors[j]] {
+			return count[colors[i]] > count[colors[j]]
+		}
+		return colors[i] < colors[j]
+	})
+
+	items := make([]string, len(colors))
+	for i, hex := range colors {
+		items[i] = fmt.Sprintf("%s  %d labels, e.g. '%s'", hex, count[hex], example[hex])
+	}
+	choice, err := zenity.List(
+		fmt.Sprintf("Label colors in %s:", w.CurrentZone),
+		items,
+		zenity.Title("Label Priorities"),
+		zenity.Height(420),
+	)
+	if err != nil || choice == "" {
+		return "", false
+	}
+	return strings.Fields(choice)[0], true
+}
+
+const (
+	labelRulePriority = "Set Priority..."
+	labelRuleMatch    = "Edit Text..."
+	labelRuleUp       = "Move Up"
+	labelRuleDown     = "Move Down"
+	labelRuleDelete   = "Delete"
+)
+
+func (w *Window) editLabelRule(rules []config.LabelRule, index int) {
+	if index < 0 || index >= len(rules) {
+		return
+	}
+	rules = append([]config.LabelRule(nil), rules...)
+	r := &rules[index]
+
+	actions := []string{labelRulePriority, labelRuleMatch, labelRuleUp, labelRuleDown, labelRuleDelete}
+	action, err := zenity.List(describeLabelRule(*r)+":", actions, zenity.Title("Label Priorities"))
+	if err != nil {
+		return
+	}
+	switch action {
+	case labelRulePriority:
+		p, err := askLabelPriority(describeLabelRule(*r), r.Priority)
+		if err != nil {
+			return
+		}
+		r.Priority = p
+	case labelRuleMatch:
+		match, err := zenity.Entry(
+			"Labels containing (case doesn't matter, empty = any text):",
+			zenity.Title("Label Priorities"),
+			zenity.EntryText(r.Match),
+		)
+		if err != nil {
+			return
+		}
+		r.Match = strings.TrimSpace(match)
+	case labelRuleUp:
+		if index == 0 {
+			return
+		}
+		rules[index-1], rules[index] = rules[index], rules[index-1]
+	case labelRuleDown:
+		if index == len(rules)-1 {
+			return
+		}
+		rules[index+1], rules[index] = rules[index], rules[index+1]
+	case labelRuleDelete:
+		rules = append(rules[:index], rules[index+1:]...)
+	default:
+		return
+	}
+	w.setLabelRules(rules)
+}
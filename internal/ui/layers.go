@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Map layers are the zone's base file (0) and its _1/_2/_3 files. Hidden
+// layers are remembered per zone in the config.
+
+func (w *Window) layerHidden(layer int) bool {
+	for _, l := range w.Config.HiddenLayers[w.CurrentZone] {
+		if l == layer {
+			return true
+		}
+	}
+	return false
+}
+
+// hiddenLayerMask packs the current zone's hidden layers into a bitmask for the mesh cache.
+func (w *Window) hiddenLayerMask() uint8 {
+	var mask uint8
+	for _, l := range w.Config.HiddenLayers[w.CurrentZone] {
+		mask |= 1 << l
+	}
+	return mask
+}
+
+func (w *Window) toggleLayer(layer int) {
+	var hidden []int
+	for _, l := range w.Config.HiddenLayers[w.CurrentZone] {
+		if l != layer {
+			hidden = append(hidden, l)
+		}
+	}
+	if !w.layerHidden(layer) {
+		hidden = append(hidden, layer)
+		sort.Ints(hidden)
+	}
+
+	if len(hidden) == 0 {
+		delete(w.Config.HiddenLayers, w.CurrentZone)
+	} else {
+		w.Config.HiddenLayers[w.CurrentZone] = hidden
+	}
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving layer visibility: %v\n", err)
+	}
+}
+
+func layerName(layer int) string {
+	if layer == 0 {
+		return "Base"
+	}
+	return fmt.Sprintf("Layer %d", layer)
+}
+
+// layersMenu lists the layers the current map actually has.
+func (w *Window) layersMenu() Menu {
+	menu := Menu{Label: "Layers"}
+	drawOrder := MenuItem{
+		Label: "Draw Order...",
+		Action: func() {
+			w.openMenu = ""
+			w.openDrawOrder()
+		},
+	}
+	if w.MapData == nil {
+		menu.Items = append(menu.Items, MenuItem{
+			Label:  "(no map loaded)",
+			Action: func() { w.openMenu = "" },
+		}, drawOrder)
+		return menu
+	}
+
+	for _, layer := range w.MapData.Layers {
+		layer := layer
+		menu.Items = append(menu.Items, MenuItem{
+			Label: fmt.Sprintf("%s: %s", layerName(layer), map[bool]string{true: "OFF", false: "ON"}[w.layerHidden(layer)]),
+			Action: func() {
+				w.toggleLayer(layer)
+				w.openMenu = ""
+			},
+		})
+	}
+	menu.Items = append(menu.Items, drawOrder)
+	return menu
+}
@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/links"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/ncruces/zenity"
+)
+
+// QueueLink opens a nox:// link once the log has reported the player's zone
+// (used for links passed on the command line by the OS URI handler).
+func (w *Window) QueueLink(uri string) {
+	w.pendingLink = uri
+	w.pendingLinkWait = 120 // Frames to wait for the initial zone
+}
+
+// updateLinks handles Ctrl+V (open a link from the clipboard) and any link
+// queued at startup.
+func (w *Window) updateLinks() {
+	if w.pendingLink != "" {
+		w.pendingLinkWait--
+		if w.logZone != "" || w.pendingLinkWait <= 0 {
+			uri := w.pendingLink
+			w.pendingLink = ""
+			w.openLinkURI(uri)
+		}
+	}
+
+	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta)
+	if ctrl && inpututil.IsKeyJustPressed(ebiten.KeyV) && !w.dialogOpen {
+		text, err := links.ReadClipboard()
+		if err != nil {
+			fmt.Printf("❌ Error reading clipboard: %v\n", err)
+			return
+		}
+		w.openLinkURI(text)
+	}
+}
+
+// openLinkDialog asks for a link, pre-filled from the clipboard when it holds one.
+func (w *Window) openLinkDialog() {
+	initial := ""
+	if text, err := links.ReadClipboard(); err == nil {
+		if _, err := links.Parse(text); err == nil {
+			initial = text
+		}
+	}
+
+	w.dialogOpen = true
+	uri, err := zenity.Entry(
+		"Paste a nox:// link:",
+		zenity.Title("Open Link"),
+		zenity.EntryText(initial),
+	)
+	w.dialogOpen = false
+	w.lastMousePressed = true
+
+	if err != nil || uri == "" {
+		return
+	}
+	w.openLinkURI(uri)
+}
+
+// openLinkURI shows the linked zone, centers on the location and sets a
+// waypoint there so the compass guides the player to it.
+func (w *Window) openLinkURI(uri string) {
+	link, err := links.Parse(uri)
+	if err != nil {
+		fmt.Printf("❌ Invalid link: %v\n", err)
+		return
+	}
+
+	if link.Zone != w.CurrentZone {
+		w.viewZone(link.Zone)
+	}
+	x, y := link.MapXY()
+	w.CamX = x
+	w.CamY = y
+	w.Nav.Set(x, y, link.Label)
+
+	fmt.Printf("🔗 Opened link: %s (%.1f, %.1f) %s\n", link.Zone, link.LocY, link.LocX, link.Label)
+}
+
+// copyMarkerLink copies a link to the marker; if no clipboard tool is
+// available the link is shown in a dialog so it can be copied by hand.
+func (w *Window) copyMarkerLink(marker config.Marker) {
+	uri := links.FromMap(w.CurrentZone, marker.X, marker.Y, marker.Label).String()
+	err := links.CopyToClipboard(uri)
+	if err == nil {
+		fmt.Printf("🔗 Copied link: %s\n", uri)
+		return
+	}
+	fmt.Printf("⚠️  Clipboard unavailable: %v\n", err)
+
+	w.dialogOpen = true
+	zenity.Entry(
+		"Copy this link:",
+		zenity.Title("Marker Link"),
+		zenity.EntryText(uri),
+	)
+	w.dialogOpen = false
+	w.lastMousePressed = true
+}
+
+// copyMarkerLinkAt copies the link of the marker under the cursor (Shift+click).
+func (w *Window) copyMarkerLinkAt(worldX, worldY float64) {
+	clickRadius := 15.0 / w.Zoom
+	for _, marker := range w.Config.ZoneMarkers(w.CurrentZone) {
+		if math.Hypot(worldX-marker.X, worldY-marker.Y) <= clickRadius {
+			w.copyMarkerLink(marker)
+			return
+		}
+	}
+}
+
+// chooseMarkerLink lists the zone's markers and copies the chosen one's link.
+func (w *Window) chooseMarkerLink() {
+	markers := w.Config.ZoneMarkers(w.CurrentZone)
+	if len(markers) == 0 {
+		return
+	}
+
+	items := make([]string, len(markers))
+	for i, m := range markers {
+		items[i] = fmt.Sprintf("%d. %s", i+1, m.Label)
+	}
+
+	w.dialogOpen = true
+	choice, err := zenity.List(
+		"Copy a link to which marker?",
+		items,
+		zenity.Title("Copy Marker Link"),
+	)
+	w.dialogOpen = false
+	w.lastMousePressed = true
+	if err != nil {
+		return
+	}
+
+	for i, item := range items {
+		if item == choice {
+			w.copyMarkerLink(markers[i])
+			return
+		}
+	}
+}
+
+func (w *Window) registerLinkHandler() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	if err := links.RegisterHandler(); err != nil {
+		fmt.Printf("❌ Error registering link handler: %v\n", err)
+		zenity.Error(err.Error(), zenity.Title("Register nox:// Links"))
+		return
+	}
+	fmt.Println("🔗 Registered as the nox:// link handler")
+	zenity.Info("nox:// links will now open in Nox Maps.", zenity.Title("Register nox:// Links"))
+}
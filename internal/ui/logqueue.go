@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/devin-hart/nox-maps/internal/eqlog"
+)
+
+// SetLogSource gives the window the log reader (nil if no EQ path is set)
+// for the low-latency toggle and queue diagnostics.
+func (w *Window) SetLogSource(r *eqlog.Reader) {
+	w.logSource = r
+}
+
+// SetSimulated marks a session fed by -demo or -replay rather than a live
+// log (kind goes in the title): the trail, heatmaps and stats it produces
+// aren't saved over the real ones. Call before Init.
+func (w *Window) SetSimulated(kind string) {
+	w.simulated = true
+	w.Title += " (" + kind + ")"
+}
+
+func (w *Window) toggleLowLatency() {
+	w.Config.LowLatency = !w.Config.LowLatency
+	if w.logSource != nil {
+		w.logSource.SetLowLatency(w.Config.LowLatency)
+	}
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("Error saving config: %v\n", err)
+	}
+}
+
+// logQueueInfo is the info panel's backpressure line, shown in low-latency
+// mode or once lines have been dropped.
+func (w *Window) logQueueInfo() string {
+	if w.logSource == nil {
+		return ""
+	}
+	st := w.logSource.Stats()
+	if !st.LowLatency && st.Dropped == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Log queue: %d/%d (peak %d), %d lines dropped, %d stale locs dropped",
+		st.Queued, st.Capacity, st.PeakQueued, st.Dropped, st.StaleLocs)
+}
@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/maps"
+	"github.com/ncruces/zenity"
+)
+
+// backupMapFiles must succeed before anything writes into map files; false
+// means the write should not go ahead.
+func backupMapFiles(reason string, paths ...string) bool {
+	b, err := maps.BackupFiles(config.GetMapBackupDir(), reason, paths...)
+	if err != nil {
+		fmt.Printf("❌ Map backup failed, not writing: %v\n", err)
+		return false
+	}
+	fmt.Printf("🛟 Backed up %d map files (%s)\n", len(b.Files), b.ID)
+	return true
+}
+
+func describeBackup(b maps.Backup) string {
+	return fmt.Sprintf("%s  %s (%d files)", b.Time.Format("2006-01-02 15:04:05"), b.Reason, len(b.Files))
+}
+
+// openMapBackups lists the backups newest first and restores the one picked.
+func (w *Window) openMapBackups() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	root := config.GetMapBackupDir()
+	list, err := maps.ListBackups(root)
+	if err != nil {
+		fmt.Printf("❌ Error reading map backups: %v\n", err)
+		return
+	}
+	if len(list) == 0 {
+		zenity.Info("No map files have been backed up yet. A backup is made every time nox-maps is about to change a map file.",
+			zenity.Title("Map Backups"))
+		return
+	}
+
+	items := make([]string, len(list))
+	for i, b := range list {
+		items[i] = describeBackup(b)
+	}
+	choice, err := zenity.List(
+		"Backups, newest first:",
+		items,
+		zenity.Title("Map Backups"),
+		zenity.Height(420),
+	)
+	if err != nil || choice == "" {
+		return
+	}
+	var b maps.Backup
+	for i, item := range items {
+		if item == choice {
+			b = list[i]
+		}
+	}
+
+	var files []string
+	for _, f := range b.Files {
+		if f.Created {
+			files = append(files, "    remove "+f.Path)
+		} else {
+			files = append(files, "    restore "+f.Path)
+		}
+	}
+	if err := zenity.Question(
+		fmt.Sprintf("%s\n\n%s\n\nThe files as they are now are backed up first.", describeBackup(b), strings.Join(files, "\n")),
+		zenity.Title("Restore Map Backup"),
+		zenity.OKLabel("Restore"),
+		zenity.NoIcon,
+	); err != nil {
+		return
+	}
+
+	if err := maps.RestoreBackup(root, b); err != nil {
+		fmt.Printf("❌ Error restoring map backup %s: %v\n", b.ID, err)
+		zenity.Error(err.Error(), zenity.Title("Restore Map Backup"))
+		return
+	}
+	// The zone watcher reloads the map if its files were among them
+	fmt.Printf("🛟 Restored %d map files from %s\n", len(b.Files), b.ID)
+}
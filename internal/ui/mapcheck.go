@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/links"
+	"github.com/devin-hart/nox-maps/internal/maps"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/ncruces/zenity"
+)
+
+// mapReportRows caps how many files or zones each report section lists.
+const mapReportRows = 8
+
+// mapDirKey identifies the maps directory for the first-run check.
+func (w *Window) mapDirKey() string {
+	if abs, err := filepath.Abs(w.MapDir); err == nil {
+		return abs
+	}
+	return w.MapDir
+}
+
+// startMapCheck scans the map files in the background. At startup it only
+// runs the first time a maps directory is seen.
+func (w *Window) startMapCheck(startup bool) {
+	if w.mapCheck != nil || (startup && w.Config.MapsChecked == w.mapDirKey()) {
+		return
+	}
+	w.mapCheck = maps.StartIntegrityScan(w.MapDir)
+}
+
+// updateMapCheck shows the report once the scan finishes.
+func (w *Window) updateMapCheck() {
+	if w.mapCheck == nil {
+		return
+	}
+	select {
+	case report := <-w.mapCheck.Done:
+		w.mapCheck = nil
+		if report == nil {
+			return
+		}
+		w.Config.MapsChecked = w.mapDirKey()
+		w.Config.Save()
+		w.showMapReport(report)
+	default:
+	}
+}
+
+// showMapReport summarizes the scan with buttons for what can be fixed.
+func (w *Window) showMapReport(report *maps.IntegrityReport) {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	for {
+		opts := []zenity.Option{zenity.Title("Map Check"), zenity.NoIcon}
+		if len(report.Broken) > 0 {
+			opts = append(opts, zenity.OKLabel("Move Broken Files"), zenity.CancelLabel("Close"))
+		} else {
+			opts = append(opts, zenity.OKLabel("Close"))
+		}
+		if len(report.Missing) > 0 {
+			opts = append(opts, zenity.ExtraButton("Copy Missing List"))
+		}
+
+		err := zenity.Question(mapReportText(report), opts...)
+		switch {
+		case errors.Is(err, zenity.ErrExtraButton):
+			if err := links.CopyToClipboard(strings.Join(report.Missing, "\n")); err != nil {
+				fmt.Printf("❌ Error copying to clipboard: %v\n", err)
+			} else {
+				fmt.Printf("📋 Copied %d missing zones to the clipboard\n", len(report.Missing))
+			}
+			continue
+		case err == nil && len(report.Broken) > 0:
+			broken := make([]string, len(report.Broken))
+			for i, p := range report.Broken {
+				broken[i] = p.Path
+			}
+			if !backupMapFiles("Move broken map files", broken...) {
+				return
+			}
+			moved, err := report.MoveBroken()
+			if err != nil {
+				fmt.Printf("❌ Error moving broken map files: %v\n", err)
+			}
+			fmt.Printf("🩺 Moved %d broken map files to %s\n", moved, filepath.Join(report.MapDir, "broken"))
+			if moved > 0 && w.indexer == nil {
+				w.startZoneIndex()
+			}
+			continue
+		}
+		return
+	}
+}
+
+func mapReportText(r *maps.IntegrityReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n%d zones in %d files (%d lines, %d labels).\n", r.MapDir, r.Zones, r.Files, r.Lines, r.Labels)
+
+	section := func(title string, rows []string) {
+		if len(rows) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n%s (%d):\n", title, len(rows))
+		for i, row := range rows {
+			if i == mapReportRows {
+				fmt.Fprintf(&b, "  ...and %d more\n", len(rows)-i)
+				break
+			}
+			fmt.Fprintf(&b, "  %s\n", row)
+		}
+	}
+	problems := func(list []maps.MapProblem) []string {
+		rows := make([]string, len(list))
+		for i, p := range list {
+			rows[i] = fmt.Sprintf("%s: %s", filepath.Base(p.Path), p.Problem)
+		}
+		return rows
+	}
+	section("Broken", problems(r.Broken))
+	section("Warnings", problems(r.Warnings))
+	section("Known zones without maps", r.Missing)
+	if len(r.Broken)+len(r.Warnings)+len(r.Missing) == 0 {
+		b.WriteString("\nEverything looks good.")
+	}
+	return b.String()
+}
+
+// drawMapCheckProgress shows a progress bar at the bottom while scanning.
+func (w *Window) drawMapCheckProgress(screen *ebiten.Image) {
+	if w.mapCheck == nil {
+		return
+	}
+	done, total := w.mapCheck.Progress()
+	width, height := w.progressBarSize()
+	x, y := (float32(w.Width)-width)/2, float32(w.Height)-height-8
+	vector.DrawFilledRect(screen, x, y, width, height, color.RGBA{0, 0, 0, 200}, false)
+	if total > 0 {
+		vector.DrawFilledRect(screen, x, y, width*float32(done)/float32(total), height, color.RGBA{40, 110, 60, 230}, false)
+	}
+	vector.StrokeRect(screen, x, y, width, height, 1, color.RGBA{120, 120, 120, 255}, false)
+	w.drawUITextIn(screen, fmt.Sprintf("Checking map files... %d/%d", done, total), int(x)+8, int(y), int(height), color.White)
+}
+
+// progressBarSize is the size of the map check and map pack download bars.
+func (w *Window) progressBarSize() (width, height float32) {
+	return float32(math.Round(260 * w.uiSize() / uiBaseSize)), float32(max(18, w.uiLineHeight()+3))
+}
@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/devin-hart/nox-maps/internal/maps"
+)
+
+// Map color schemes remap a pack's colors when they're drawn; the files are
+// left alone.
+const (
+	mapColorsOriginal     = ""
+	mapColorsDark         = "dark"          // Muted, for an overlay that shouldn't glare
+	mapColorsHighContrast = "high_contrast" // Vivid hues, with water and zone lines set apart
+)
+
+var mapColorSchemes = []struct{ name, label string }{
+	{mapColorsOriginal, "Original"},
+	{mapColorsDark, "Dark"},
+	{mapColorsHighContrast, "High Contrast"},
+}
+
+func (w *Window) mapColorsLabel() string {
+	for _, s := range mapColorSchemes {
+		if s.name == w.Config.MapColors {
+			return s.label
+		}
+	}
+	return mapColorSchemes[0].label
+}
+
+func (w *Window) cycleMapColors() {
+	next := mapColorSchemes[0]
+	for i, s := range mapColorSchemes {
+		if s.name == w.Config.MapColors {
+			next = mapColorSchemes[(i+1)%len(mapColorSchemes)]
+			break
+		}
+	}
+	w.Config.MapColors = next.name
+	w.Config.Save()
+	fmt.Printf("🎨 Map colors: %s\n", next.label)
+}
+
+// mapColor is how a line of the given kind and color is drawn under a
+// scheme, background and theme tint.
+func mapColor(c color.RGBA, kind maps.LineKind, spec paletteSpec) color.RGBA {
+	switch spec.scheme {
+	case mapColorsDark:
+		c = contrastColor(mutedColor(c), spec.light)
+	case mapColorsHighContrast:
+		c = highContrastColor(c, kind, spec.light)
+	default:
+		c = contrastColor(c, spec.light)
+	}
+	if spec.tint.A == 0 {
+		return c
+	}
+	mix := func(v, t uint8) uint8 {
+		return uint8(float64(v)*(1-lineTintAmount) + float64(t)*lineTintAmount)
+	}
+	return color.RGBA{mix(c.R, spec.tint.R), mix(c.G, spec.tint.G), mix(c.B, spec.tint.B), c.A}
+}
+
+// mutedColor pulls a color partway to gray and dims it.
+func mutedColor(c color.RGBA) color.RGBA {
+	gray := (float64(c.R) + float64(c.G) + float64(c.B)) / 3
+	channel := func(v uint8) uint8 {
+		return uint8((float64(v)*0.7 + gray*0.3) * 0.55)
+	}
+	return color.RGBA{channel(c.R), channel(c.G), channel(c.B), c.A}
+}
+
+// highContrastColor gives water and zone lines fixed colors, draws grays
+// (most walls) in the opposite of the background, and brightens other
+// colors to full strength.
+func highContrastColor(c color.RGBA, kind maps.LineKind, light bool) color.RGBA {
+	switch {
+	case kind == maps.KindWater && light:
+		return color.RGBA{0, 60, 220, c.A}
+	case kind == maps.KindWater:
+		return color.RGBA{0, 170, 255, c.A}
+	case kind == maps.KindZoneLine && light:
+		return color.RGBA{200, 0, 140, c.A}
+	case kind == maps.KindZoneLine:
+		return color.RGBA{255, 230, 0, c.A}
+	}
+
+	hi := max(c.R, c.G, c.B)
+	lo := min(c.R, c.G, c.B)
+	if hi-lo < 40 {
+		if light {
+			return color.RGBA{0, 0, 0, c.A}
+		}
+		return color.RGBA{255, 255, 255, c.A}
+	}
+	scale := 255 / float64(hi)
+	bright := color.RGBA{uint8(float64(c.R) * scale), uint8(float64(c.G) * scale), uint8(float64(c.B) * scale), c.A}
+	return contrastColor(bright, light)
+}
@@ -0,0 +1,261 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/mappack"
+	"github.com/devin-hart/nox-maps/internal/widgets"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/ncruces/zenity"
+)
+
+const addMapPackSource = "Add Source..."
+
+// manageMapPacks lists the configured map pack sources with what's
+// installed from each; picking one offers to install or update it.
+func (w *Window) manageMapPacks() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	for {
+		items := make([]string, 0, len(w.Config.MapPackSources)+1)
+		for _, src := range w.Config.MapPackSources {
+			items = append(items, w.mapPackLabel(src))
+		}
+		items = append(items, addMapPackSource)
+		choice, err := zenity.List(
+			"Map packs are downloaded into "+w.MapDir+":",
+			items,
+			zenity.Title("Manage Map Packs"),
+			zenity.Height(360),
+		)
+		if err != nil || choice == "" {
+			return
+		}
+		if choice == addMapPackSource {
+			w.addMapPackSource()
+			continue
+		}
+		i := slices.Index(items, choice)
+		if i < 0 || i >= len(w.Config.MapPackSources) {
+			return
+		}
+		if w.mapPackSourceActions(w.Config.MapPackSources[i]) {
+			return
+		}
+	}
+}
+
+func (w *Window) mapPackLabel(src config.MapPackSource) string {
+	p, ok := w.Config.MapPacks[src.Name]
+	if !ok {
+		return src.Name + " - not installed"
+	}
+	return fmt.Sprintf("%s - %s, installed %s (%d files)", src.Name, p.Version, p.Installed.Format("2006-01-02"), len(p.Files))
+}
+
+// mapPackSourceActions asks what to do with one source, reporting whether
+// the dialog should close (an install started).
+func (w *Window) mapPackSourceActions(src config.MapPackSource) bool {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "%s\n%s\n", src.Name, src.URL)
+	if p, ok := w.Config.MapPacks[src.Name]; ok {
+		verified := "checksum not published"
+		if p.Verified {
+			verified = "checksum verified"
+		}
+		fmt.Fprintf(&msg, "\nInstalled: %s on %s, %d files (%s)\n", p.Version, p.Installed.Format("2006-01-02 15:04"), len(p.Files), verified)
+	}
+	msg.WriteString("\nInstalling downloads the pack and copies its map files into the maps directory; files it replaces are backed up first.")
+
+	err := zenity.Question(msg.String(),
+		zenity.Title("Manage Map Packs"),
+		zenity.OKLabel("Install / Update"),
+		zenity.CancelLabel("Back"),
+		zenity.ExtraButton("Remove Source"),
+		zenity.NoIcon,
+	)
+	switch {
+	case errors.Is(err, zenity.ErrExtraButton):
+		w.Config.MapPackSources = slices.DeleteFunc(w.Config.MapPackSources, func(s config.MapPackSource) bool { return s.Name == src.Name })
+		w.saveMapPacks()
+		return false
+	case err != nil:
+		return false
+	}
+	if w.mapPackInstall != nil {
+		zenity.Info("Another map pack is still downloading.", zenity.Title("Manage Map Packs"))
+		return false
+	}
+	w.installMapPack(src, false)
+	return true
+}
+
+// addMapPackSource asks for a name and a manifest or zip URL.
+func (w *Window) addMapPackSource() {
+	name, err := zenity.Entry("Name (e.g. Brewall):", zenity.Title("Add Map Pack Source"))
+	name = strings.TrimSpace(name)
+	if err != nil || name == "" {
+		return
+	}
+	rawURL, err := zenity.Entry("Manifest (.json) or zip URL:", zenity.Title("Add Map Pack Source"))
+	rawURL = strings.TrimSpace(rawURL)
+	if err != nil || rawURL == "" {
+		return
+	}
+	src := config.MapPackSource{Name: name, URL: rawURL}
+	if strings.HasSuffix(strings.ToLower(rawURL), ".zip") {
+		sum, err := zenity.Entry("SHA-256 of the zip, if the publisher lists one (without it you'll be asked before installing):",
+			zenity.Title("Add Map Pack Source"))
+		if err != nil {
+			return
+		}
+		src.SHA256 = strings.ToLower(strings.TrimSpace(sum))
+	}
+
+	i := slices.IndexFunc(w.Config.MapPackSources, func(s config.MapPackSource) bool { return strings.EqualFold(s.Name, name) })
+	if i >= 0 {
+		w.Config.MapPackSources[i] = src
+	} else {
+		w.Config.MapPackSources = append(w.Config.MapPackSources, src)
+	}
+	w.saveMapPacks()
+}
+
+// installMapPack starts downloading a pack; updateMapPacks finishes up.
+// unverified allows a pack with no checksum (the user has agreed to it).
+func (w *Window) installMapPack(src config.MapPackSource, unverified bool) {
+	fmt.Printf("📦 Downloading map pack %s from %s\n", src.Name, src.URL)
+	installed := w.Config.MapPacks[src.Name].Version
+	w.mapPackInstall = mappack.StartInstall(src, w.MapDir, installed, unverified, func(paths []string) error {
+		if !backupMapFiles("Map pack: "+src.Name, paths...) {
+			return errors.New("couldn't back up the map files it replaces")
+		}
+		return nil
+	})
+}
+
+// updateMapPacks records a finished install and picks up the new maps.
+func (w *Window) updateMapPacks() {
+	in := w.mapPackInstall
+	if in == nil {
+		return
+	}
+	var res mappack.Result
+	select {
+	case res = <-in.Done:
+	default:
+		return
+	}
+	w.mapPackInstall = nil
+
+	var msg string
+	switch {
+	case errors.Is(res.Err, mappack.ErrUnverified):
+		w.confirmUnverifiedMapPack(res.Source)
+		return
+	case res.Err != nil && len(res.Files) == 0:
+		fmt.Printf("❌ Map pack %s: %v\n", res.Source, res.Err)
+		w.notifyMapPack(fmt.Sprintf("%s wasn't installed: %v", res.Source, res.Err))
+		return
+	case res.UpToDate:
+		fmt.Printf("📦 Map pack %s is up to date (%s)\n", res.Source, res.Version)
+		w.notifyMapPack(fmt.Sprintf("%s %s is already installed.", res.Source, res.Version))
+		return
+	case res.Err != nil:
+		// Stopped partway; the files written so far are recorded below
+		msg = fmt.Sprintf("%s was only partly installed (%d files): %v", res.Source, len(res.Files), res.Err)
+	default:
+		check := "no checksum was published to verify it against"
+		if res.Verified {
+			check = "checksum verified"
+		}
+		msg = fmt.Sprintf("Installed %s %s: %d map files (%s).", res.Source, res.Version, len(res.Files), check)
+	}
+	fmt.Printf("📦 %s\n", msg)
+
+	if w.Config.MapPacks == nil {
+		w.Config.MapPacks = make(map[string]config.InstalledMapPack)
+	}
+	w.Config.MapPacks[res.Source] = config.InstalledMapPack{
+		Version:   res.Version,
+		SHA256:    res.SHA256,
+		Verified:  res.Verified,
+		Installed: time.Now(),
+		Files:     res.Files,
+	}
+	w.saveMapPacks()
+
+	if w.indexer == nil {
+		w.startZoneIndex()
+	}
+	if w.CurrentZone != "" && len(w.editor.dirty) == 0 {
+		w.loadMapForZone(w.CurrentZone)
+	}
+	w.notifyMapPack(msg)
+}
+
+// confirmUnverifiedMapPack asks before installing a pack that has no
+// checksum, since nothing shows the download is what the publisher made.
+func (w *Window) confirmUnverifiedMapPack(name string) {
+	i := slices.IndexFunc(w.Config.MapPackSources, func(s config.MapPackSource) bool { return s.Name == name })
+	if i < 0 {
+		return
+	}
+	src := w.Config.MapPackSources[i]
+	fmt.Printf("⚠️  Map pack %s publishes no checksum\n", name)
+	if w.modal != nil || w.dialogOpen {
+		return
+	}
+	msg := fmt.Sprintf("%s publishes no SHA-256 checksum, so there's no way to tell\nthe download is the one its publisher made.\n\nInstall it anyway from %s?", name, src.URL)
+	w.showModal(widgets.NewConfirm("Unverified Map Pack", msg, "Install Anyway", "Cancel"), func(r widgets.Result) {
+		if r == widgets.OK && w.mapPackInstall == nil {
+			w.installMapPack(src, true)
+		}
+	})
+}
+
+// notifyMapPack shows an install's outcome in the window unless a dialog
+// is already up (it's on the console either way).
+func (w *Window) notifyMapPack(msg string) {
+	if w.modal == nil && !w.dialogOpen {
+		w.showModal(widgets.NewConfirm("Map Packs", msg, "OK", ""), nil)
+	}
+}
+
+func (w *Window) saveMapPacks() {
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving map packs: %v\n", err)
+	}
+}
+
+// drawMapPackProgress shows the download's progress above the bottom edge.
+func (w *Window) drawMapPackProgress(screen *ebiten.Image) {
+	if w.mapPackInstall == nil {
+		return
+	}
+	received, total := w.mapPackInstall.Progress()
+	width, height := w.progressBarSize()
+	x, y := (float32(w.Width)-width)/2, float32(w.Height)-height-8
+	if w.mapCheck != nil {
+		y -= height + 6 // Above the map check's bar
+	}
+	vector.DrawFilledRect(screen, x, y, width, height, color.RGBA{0, 0, 0, 200}, false)
+	label := fmt.Sprintf("Downloading map pack... %.1f MB", float64(received)/(1<<20))
+	if total > 0 {
+		vector.DrawFilledRect(screen, x, y, width*float32(received)/float32(total), height, color.RGBA{40, 80, 130, 230}, false)
+		label = fmt.Sprintf("Downloading map pack... %d%%", received*100/total)
+	}
+	vector.StrokeRect(screen, x, y, width, height, 1, color.RGBA{120, 120, 120, 255}, false)
+	w.drawUITextIn(screen, label, int(x)+8, int(y), int(height), color.White)
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/maps"
+)
+
+// mapReload is a hot reload running off the game loop, so saving a big
+// zone in an external editor doesn't stall the map while it's reparsed.
+type mapReload struct {
+	code  string // File code being reloaded
+	done  chan mapReloadResult
+	again bool // The files changed again while loading
+}
+
+type mapReloadResult struct {
+	data *maps.ZoneMap
+	err  error
+}
+
+// reloadMap re-reads the current zone's files after an edit on disk; the
+// new geometry replaces the old once parsed (see updateMapReload), keeping
+// the camera, markers and everything else where they are.
+func (w *Window) reloadMap() {
+	if len(w.editor.dirty) > 0 {
+		fmt.Println("✏️  Map files changed on disk; keeping unsaved edits (save or leave edit mode to reload)")
+		return
+	}
+	if r := w.mapReload; r != nil && strings.EqualFold(r.code, w.mapFileCode) {
+		r.again = true // Read the files once more when this load finishes
+		return
+	}
+	fmt.Printf("♻️  Map files changed, reloading '%s'\n", w.mapFileCode)
+	r := &mapReload{code: w.mapFileCode, done: make(chan mapReloadResult, 1)}
+	w.mapReload = r
+	mapDir := w.MapDir
+	go func() {
+		data, err := maps.LoadZone(mapDir, r.code)
+		r.done <- mapReloadResult{data, err}
+	}()
+}
+
+// updateMapReload watches for file changes and swaps in finished reloads.
+func (w *Window) updateMapReload() {
+	if w.mapWatcher != nil {
+		select {
+		case <-w.mapWatcher.Changed:
+			w.reloadMap()
+		default:
+		}
+	}
+
+	r := w.mapReload
+	if r == nil {
+		return
+	}
+	var res mapReloadResult
+	select {
+	case res = <-r.done:
+	default:
+		return
+	}
+	w.mapReload = nil
+
+	switch {
+	case !strings.EqualFold(r.code, w.mapFileCode):
+		return // Zone changed meanwhile; the new map is already loaded
+	case res.err != nil:
+		fmt.Printf("❌ Error reloading map: %v\n", res.err)
+	case len(w.editor.dirty) > 0:
+		// Edited while the reload was parsing; the edits win
+	default:
+		w.MapData = res.data
+	}
+	if r.again {
+		w.reloadMap()
+	}
+}
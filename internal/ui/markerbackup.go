@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/widgets"
+)
+
+// restoreMarkerBackup puts back every zone's markers from one of the
+// rotated config.json backups (see config/backups.go). Each zone that
+// changes gets an undo step.
+func (w *Window) restoreMarkerBackup() {
+	var backups []*config.Config
+	var items []string
+	for _, b := range config.ListConfigBackups() {
+		cfg, err := config.LoadConfigBackup(b.Path)
+		if err != nil {
+			fmt.Printf("⚠️  Skipping unreadable config backup %s: %v\n", b.Path, err)
+			continue
+		}
+		backups = append(backups, cfg)
+		items = append(items, fmt.Sprintf("%s  %s", b.Time.Format("2006-01-02 15:04"), describeMarkerCount(cfg.BackupMarkers)))
+	}
+	if len(backups) == 0 {
+		w.showModal(widgets.NewConfirm("Restore Markers", "There are no config backups yet. One is made each hour the config changes.", "OK", ""), nil)
+		return
+	}
+
+	choose := widgets.NewDropdown("Restore Markers", "Backups, newest first:", items, 0)
+	w.showModal(choose, func(r widgets.Result) {
+		i, _ := choose.Selected()
+		if r != widgets.OK || i < 0 {
+			return
+		}
+		backup := backups[i].BackupMarkers
+		zones := w.changedMarkerZones(backup)
+		if len(zones) == 0 {
+			w.showModal(widgets.NewConfirm("Restore Markers", "The markers in that backup are the same as now.", "OK", ""), nil)
+			return
+		}
+		msg := fmt.Sprintf("Replace the markers in %d zones with the backup's?\n(%s now, %s in the backup)\n\nUndo puts the zones back one at a time.",
+			len(zones), describeMarkerCount(w.Config.AllMarkers()), describeMarkerCount(backup))
+		w.showModal(widgets.NewConfirm("Restore Markers", msg, "Restore", "Cancel"), func(r widgets.Result) {
+			if r != widgets.OK {
+				return
+			}
+			for _, zone := range zones {
+				step := w.snapshotZone("restore from backup", zone)
+				w.pushUndoStep(step)
+				step.markers = append([]config.Marker(nil), backup[zone]...)
+				w.restoreZone(step)
+			}
+			fmt.Printf("🛟 Restored markers in %d zones from backup\n", len(zones))
+		})
+	})
+}
+
+// changedMarkerZones lists the zones whose markers differ from backup's.
+func (w *Window) changedMarkerZones(backup map[string][]config.Marker) []string {
+	var zones []string
+	current := w.Config.AllMarkers()
+	for zone, markers := range current {
+		if !slices.Equal(markers, backup[zone]) {
+			zones = append(zones, zone)
+		}
+	}
+	for zone, markers := range backup {
+		if _, ok := current[zone]; !ok && len(markers) > 0 {
+			zones = append(zones, zone)
+		}
+	}
+	slices.Sort(zones)
+	return zones
+}
+
+func describeMarkerCount(markers map[string][]config.Marker) string {
+	n, zones := 0, 0
+	for _, m := range markers {
+		if len(m) > 0 {
+			n += len(m)
+			zones++
+		}
+	}
+	return fmt.Sprintf("%d markers in %d zones", n, zones)
+}
@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Alt + left-drag moves a marker. Dropping it near the player snaps it to
+// the player's position (hold Ctrl to place it freely); it is saved when the
+// button is released.
+
+const markerSnapPixels = 12.0 // Screen distance from the player that snaps
+
+type markerDrag struct {
+	id             string
+	zone           string
+	offX, offY     float64 // Grab point relative to the marker
+	startX, startY float64
+	snapped        bool
+	before         undoStep
+}
+
+// markerIndexAt returns the index of the visible marker under a world
+// position in the current zone, or -1.
+func (w *Window) markerIndexAt(worldX, worldY float64) int {
+	clickRadius := 15.0 / w.Zoom
+	for i, m := range w.Config.ZoneMarkers(w.CurrentZone) {
+		if w.Config.MarkerHidden(w.CurrentZone, m.ID) || w.Config.CategoryHidden(m.Category) {
+			continue
+		}
+		if math.Hypot(worldX-m.X, worldY-m.Y) <= clickRadius {
+			return i
+		}
+	}
+	return -1
+}
+
+// startMarkerDrag picks up the marker under the cursor; false if there is
+// none.
+func (w *Window) startMarkerDrag(worldX, worldY float64) bool {
+	if !w.ShowMarkers {
+		return false
+	}
+	i := w.markerIndexAt(worldX, worldY)
+	if i < 0 {
+		return false
+	}
+	m := w.Config.ZoneMarkers(w.CurrentZone)[i]
+	w.markerDrag = &markerDrag{
+		id:     m.ID,
+		zone:   w.CurrentZone,
+		offX:   m.X - worldX,
+		offY:   m.Y - worldY,
+		startX: m.X,
+		startY: m.Y,
+		before: w.snapshotZone(fmt.Sprintf("move marker '%s'", m.Label), w.CurrentZone),
+	}
+	return true
+}
+
+// draggedMarker returns the marker being dragged, or nil if it went away
+// (zone change, removed by a sync peer).
+func (w *Window) draggedMarker() *config.Marker {
+	d := w.markerDrag
+	if d == nil || d.zone != w.CurrentZone {
+		return nil
+	}
+	return w.markerByID(d.zone, d.id)
+}
+
+// updateMarkerDrag follows the cursor while the button is held and saves
+// the new position on release.
+func (w *Window) updateMarkerDrag(worldX, worldY float64) {
+	if w.markerDrag == nil {
+		return
+	}
+	m := w.draggedMarker()
+	if m == nil {
+		w.markerDrag = nil
+		return
+	}
+	d := w.markerDrag
+
+	x, y := worldX+d.offX, worldY+d.offY
+	d.snapped = false
+	if w.LogReader != nil && w.logZone == d.zone && !ebiten.IsKeyPressed(ebiten.KeyControl) {
+		px, py := w.playerPosition()
+		if math.Hypot(x-px, y-py)*w.Zoom <= markerSnapPixels {
+			x, y = px, py
+			d.snapped = true
+		}
+	}
+	m.X, m.Y = x, y
+
+	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		return
+	}
+	w.markerDrag = nil
+	if m.X == d.startX && m.Y == d.startY {
+		return
+	}
+	w.pushUndoStep(d.before)
+	w.syncMarker(d.zone, *m)
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error moving marker: %v\n", err)
+		return
+	}
+	where := ""
+	if d.snapped {
+		where = " (your position)"
+	}
+	fmt.Printf("📍 Moved '%s' to (%.1f, %.1f)%s in %s\n", m.Label, -m.Y, -m.X, where, d.zone)
+}
+
+// drawMarkerDrag draws a line back to where the marker was picked up, and a
+// ring around the player while snapped.
+func (w *Window) drawMarkerDrag(screen *ebiten.Image, cx, cy float64) {
+	m := w.draggedMarker()
+	if m == nil {
+		return
+	}
+	d := w.markerDrag
+	c := color.RGBA{255, 200, 0, 160}
+	x1, y1 := w.toScreen32(d.startX, d.startY, cx, cy)
+	x2, y2 := w.toScreen32(m.X, m.Y, cx, cy)
+	vector.StrokeLine(screen, x1, y1, x2, y2, 1, c, w.antiAlias)
+	vector.StrokeCircle(screen, x1, y1, 3, 1, c, w.antiAlias)
+	if d.snapped {
+		vector.StrokeCircle(screen, x2, y2, markerSnapPixels, 2, color.RGBA{0, 255, 255, 255}, w.antiAlias)
+	}
+}
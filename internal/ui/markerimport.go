@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/maps"
+	"github.com/ncruces/zenity"
+)
+
+const importPreviewRows = 5
+
+// importMarkers imports a CSV/TSV spawn table. The guessed column mapping
+// is previewed (with converted coordinates) before anything is saved, and
+// can be changed column by column.
+func (w *Window) importMarkers() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	path, err := zenity.SelectFile(
+		zenity.Title("Import Markers"),
+		zenity.FileFilter{Name: "Spreadsheets", Patterns: []string{"*.csv", "*.tsv", "*.txt"}},
+	)
+	if err != nil || path == "" {
+		return
+	}
+
+	table, err := config.ReadTable(path)
+	if err != nil {
+		fmt.Printf("❌ Error reading %s: %v\n", path, err)
+		zenity.Error(err.Error(), zenity.Title("Import Markers"))
+		return
+	}
+
+	mapping := table.GuessColumns()
+	for {
+		err := zenity.Question(
+			importPreview(table, mapping),
+			zenity.Title("Import Markers"),
+			zenity.OKLabel("Import"),
+			zenity.ExtraButton("Change Columns"),
+			zenity.NoIcon,
+		)
+		if errors.Is(err, zenity.ErrExtraButton) {
+			mapping = chooseColumns(table, mapping)
+			continue
+		}
+		if err != nil {
+			return
+		}
+		break
+	}
+
+	imported, skipped := table.Markers(mapping)
+	total := 0
+	for zone, markers := range imported {
+		w.Config.SetZoneMarkers(zone, append(w.Config.ZoneMarkers(zone), markers...))
+		total += len(markers)
+	}
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving imported markers: %v\n", err)
+		return
+	}
+	fmt.Printf("📥 Imported %d markers in %d zones (%d rows skipped)\n", total, len(imported), skipped)
+}
+
+// importPreview describes the mapping and shows the first rows as they'll be imported.
+func importPreview(table *config.Table, mapping config.ColumnMapping) string {
+	var b strings.Builder
+	b.WriteString("Columns:\n")
+	for _, field := range config.MappingFields {
+		fmt.Fprintf(&b, "  %s: %s\n", field, columnName(table, *mapping.Field(field)))
+	}
+
+	preview := &config.Table{Header: table.Header, Rows: table.Rows}
+	if len(preview.Rows) > importPreviewRows {
+		preview.Rows = preview.Rows[:importPreviewRows]
+	}
+	markers, skipped := preview.Markers(mapping)
+
+	fmt.Fprintf(&b, "\nFirst %d of %d rows:\n", len(preview.Rows), len(table.Rows))
+	zones := make([]string, 0, len(markers))
+	for zone := range markers {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	for _, zone := range zones {
+		for _, m := range markers[zone] {
+			fmt.Fprintf(&b, "  %s: %s at /loc %.1f, %.1f (%s)\n", zone, m.Label, -m.Y, -m.X, m.Color)
+		}
+	}
+	if skipped > 0 {
+		fmt.Fprintf(&b, "  (%d rows have no zone or loc with these columns)\n", skipped)
+	}
+	return b.String()
+}
+
+// chooseColumns asks which column feeds each marker field.
+func chooseColumns(table *config.Table, mapping config.ColumnMapping) config.ColumnMapping {
+	width := len(table.Header)
+	for _, row := range table.Rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+
+	options := []string{"(none)"}
+	for i := 0; i < width; i++ {
+		options = append(options, columnName(table, i))
+	}
+
+	for _, field := range config.MappingFields {
+		choice, err := zenity.List(
+			fmt.Sprintf("Which column holds %s?", field),
+			options,
+			zenity.Title("Import Markers"),
+			zenity.DefaultItems(columnName(table, *mapping.Field(field))),
+		)
+		if err != nil {
+			return mapping // Keep the rest as they were
+		}
+		for i, opt := range options {
+			if opt == choice {
+				*mapping.Field(field) = i - 1 // "(none)" -> -1
+				break
+			}
+		}
+	}
+	return mapping
+}
+
+// columnName labels a column by its header (or first row) value.
+func columnName(table *config.Table, i int) string {
+	if i < 0 {
+		return "(none)"
+	}
+	sample := ""
+	if table.Header != nil && i < len(table.Header) {
+		sample = table.Header[i]
+	} else if len(table.Rows) > 0 && i < len(table.Rows[0]) {
+		sample = table.Rows[0][i]
+	}
+	return fmt.Sprintf("%d: %s", i+1, sample)
+}
+
+// importMapPoints turns the P (label) entries of an EQ map file into
+// markers for the current zone, skipping ones the zone already has.
+func (w *Window) importMapPoints() {
+	if w.CurrentZone == "" {
+		fmt.Println("⚠️  Cannot import points: no zone detected")
+		return
+	}
+
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	path, err := zenity.SelectFile(
+		zenity.Title(fmt.Sprintf("Import Map Points into %s", w.CurrentZone)),
+		zenity.FileFilter{Name: "EQ map files", Patterns: []string{"*.txt"}},
+	)
+	if err != nil || path == "" {
+		return
+	}
+
+	zm, err := maps.LoadFile(path)
+	if err != nil {
+		fmt.Printf("❌ Error reading %s: %v\n", path, err)
+		zenity.Error(err.Error(), zenity.Title("Import Map Points"))
+		return
+	}
+
+	existing := w.Config.ZoneMarkers(w.CurrentZone)
+	var added []config.Marker
+	duplicates := 0
+	for _, lbl := range zm.Labels {
+		if lbl.Text == "" {
+			continue
+		}
+		if config.IsDuplicateMarker(lbl.Text, lbl.X, lbl.Y, existing) || config.IsDuplicateMarker(lbl.Text, lbl.X, lbl.Y, added) {
+			duplicates++
+			continue
+		}
+		added = append(added, config.Marker{
+			ID:    config.NewMarkerID(),
+			X:     lbl.X,
+			Y:     lbl.Y,
+			Label: lbl.Text,
+			Color: config.HexColor(lbl.Color),
+			Shape: w.markerShape,
+		})
+	}
+
+	if len(added) == 0 {
+		zenity.Info(fmt.Sprintf("No new points found (%d duplicates).", duplicates), zenity.Title("Import Map Points"))
+		return
+	}
+	err = zenity.Question(
+		fmt.Sprintf("Import %d points into %s?\n(%d duplicates skipped)", len(added), w.CurrentZone, duplicates),
+		zenity.Title("Import Map Points"),
+		zenity.OKLabel("Import"),
+	)
+	if err != nil {
+		return
+	}
+
+	w.Config.SetZoneMarkers(w.CurrentZone, append(existing, added...))
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving imported markers: %v\n", err)
+		return
+	}
+	fmt.Printf("📥 Imported %d points from %s into %s (%d duplicates skipped)\n", len(added), filepath.Base(path), w.CurrentZone, duplicates)
+}
@@ -0,0 +1,305 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/ncruces/zenity"
+)
+
+// maxPackDiffLines keeps the import preview dialog on screen.
+const maxPackDiffLines = 30
+
+// publishMarkerPack bundles the chosen zones' markers into a signed pack
+// with the next version number for its name.
+func (w *Window) publishMarkerPack() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	var zones []string
+	for zone := range w.Config.AllMarkers() {
+		zones = append(zones, zone)
+	}
+	if len(zones) == 0 {
+		zenity.Info("There are no markers to publish.", zenity.Title("Publish Marker Pack"))
+		return
+	}
+	sort.Strings(zones)
+	chosen, err := zenity.ListMultiple(
+		"Zones to include:",
+		zones,
+		zenity.Title("Publish Marker Pack"),
+		zenity.DefaultItems(w.CurrentZone),
+		zenity.Height(420),
+	)
+	if err != nil || len(chosen) == 0 {
+		return
+	}
+
+	lastName := ""
+	if len(w.Config.PublishedPacks) == 1 {
+		for name := range w.Config.PublishedPacks {
+			lastName = name
+		}
+	}
+	name, err := zenity.Entry("Pack name (members see updates under this name):",
+		zenity.Title("Publish Marker Pack"), zenity.EntryText(lastName))
+	name = strings.TrimSpace(name)
+	if err != nil || name == "" {
+		return
+	}
+	publisher := ""
+	if w.LogReader != nil {
+		publisher = w.LogReader.State().Character
+	}
+	publisher, err = zenity.Entry("Published by:", zenity.Title("Publish Marker Pack"), zenity.EntryText(publisher))
+	if err != nil {
+		return
+	}
+
+	version := w.Config.PublishedPacks[name] + 1
+	pack := config.MarkerPack{
+		Name:      name,
+		Version:   version,
+		Publisher: strings.TrimSpace(publisher),
+		Published: time.Now(),
+		Markers:   make(map[string][]config.Marker),
+	}
+	for _, zone := range chosen {
+		for _, m := range w.Config.ZoneMarkers(zone) {
+			m.Pack = "" // Set by the importer
+			pack.Markers[zone] = append(pack.Markers[zone], m)
+		}
+	}
+
+	path, err := zenity.SelectFileSave(
+		zenity.Title("Publish Marker Pack"),
+		zenity.Filename(fmt.Sprintf("%s v%d.json", name, version)),
+		zenity.ConfirmOverwrite(),
+		zenity.FileFilter{Name: "Marker packs", Patterns: []string{"*.json"}},
+	)
+	if err != nil || path == "" {
+		return
+	}
+	key, err := config.PublisherKey()
+	if err == nil {
+		err = config.WriteMarkerPack(path, pack, key)
+	}
+	if err != nil {
+		fmt.Printf("❌ Error publishing marker pack: %v\n", err)
+		zenity.Error(err.Error(), zenity.Title("Publish Marker Pack"))
+		return
+	}
+
+	if w.Config.PublishedPacks == nil {
+		w.Config.PublishedPacks = make(map[string]int)
+	}
+	w.Config.PublishedPacks[name] = version
+	w.Config.Save()
+
+	written, _ := config.ReadMarkerPack(path)
+	fingerprint := ""
+	if written != nil {
+		fingerprint = config.KeyFingerprint(written.Key)
+	}
+	fmt.Printf("📦 Published %s v%d: %d markers in %d zones (key %s)\n", name, version, countMarkers(pack.Markers), len(pack.Markers), fingerprint)
+	zenity.Info(
+		fmt.Sprintf("%s v%d saved to %s.\n\nYour key fingerprint is %s. Post it where members can check it when they import.",
+			name, version, filepath.Base(path), fingerprint),
+		zenity.Title("Publish Marker Pack"),
+	)
+}
+
+// packDiff is what installing a pack would change, per zone.
+type packDiff struct {
+	added, changed, removed map[string][]config.Marker
+}
+
+func (d packDiff) empty() bool {
+	return len(d.added) == 0 && len(d.changed) == 0 && len(d.removed) == 0
+}
+
+// diffMarkerPack compares a pack with the markers installed from the same
+// pack before (matched by ID). Markers of your own are never touched.
+func diffMarkerPack(have map[string][]config.Marker, pack *config.MarkerPack) packDiff {
+	d := packDiff{
+		added:   make(map[string][]config.Marker),
+		changed: make(map[string][]config.Marker),
+		removed: make(map[string][]config.Marker),
+	}
+	for zone, list := range pack.Markers {
+		installed := make(map[string]config.Marker)
+		own := make(map[string]bool)
+		for _, m := range have[zone] {
+			if m.Pack == pack.Name {
+				installed[m.ID] = m
+			} else {
+				own[m.ID] = true
+			}
+		}
+		for _, m := range list {
+			m.Pack = pack.Name
+			old, ok := installed[m.ID]
+			switch {
+			case own[m.ID]:
+				// The publisher's own copy (or an ID clash); leave it be
+			case !ok:
+				d.added[zone] = append(d.added[zone], m)
+			case old != m:
+				d.changed[zone] = append(d.changed[zone], m)
+			}
+		}
+	}
+	for zone, list := range have {
+		keep := make(map[string]bool)
+		for _, m := range pack.Markers[zone] {
+			keep[m.ID] = true
+		}
+		for _, m := range list {
+			if m.Pack == pack.Name && !keep[m.ID] {
+				d.removed[zone] = append(d.removed[zone], m)
+			}
+		}
+	}
+	return d
+}
+
+// describe lists the changes zone by zone for the import preview.
+func (d packDiff) describe() string {
+	zoneSet := make(map[string]bool)
+	for _, m := range []map[string][]config.Marker{d.added, d.changed, d.removed} {
+		for zone := range m {
+			zoneSet[zone] = true
+		}
+	}
+	zones := make([]string, 0, len(zoneSet))
+	for zone := range zoneSet {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+
+	var lines []string
+	for _, zone := range zones {
+		lines = append(lines, fmt.Sprintf("%s: +%d ~%d -%d", zone, len(d.added[zone]), len(d.changed[zone]), len(d.removed[zone])))
+		for _, c := range []struct {
+			sign string
+			list []config.Marker
+		}{{"+", d.added[zone]}, {"~", d.changed[zone]}, {"-", d.removed[zone]}} {
+			for _, m := range c.list {
+				lines = append(lines, fmt.Sprintf("    %s %s", c.sign, m.Label))
+			}
+		}
+	}
+	if len(lines) > maxPackDiffLines {
+		more := len(lines) - maxPackDiffLines
+		lines = append(lines[:maxPackDiffLines], fmt.Sprintf("    ... %d more", more))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// importMarkerPack checks a pack's signature, previews what it changes and
+// applies it.
+func (w *Window) importMarkerPack() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	path, err := zenity.SelectFile(
+		zenity.Title("Import Marker Pack"),
+		zenity.FileFilter{Name: "Marker packs", Patterns: []string{"*.json"}},
+	)
+	if err != nil || path == "" {
+		return
+	}
+	pack, err := config.ReadMarkerPack(path)
+	if err != nil {
+		fmt.Printf("❌ Error reading marker pack %s: %v\n", filepath.Base(path), err)
+		zenity.Error(err.Error(), zenity.Title("Import Marker Pack"))
+		return
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "%s v%d", pack.Name, pack.Version)
+	if pack.Publisher != "" {
+		fmt.Fprintf(&summary, " by %s", pack.Publisher)
+	}
+	fmt.Fprintf(&summary, ", published %s\nKey: %s\n", pack.Published.Format("2006-01-02"), config.KeyFingerprint(pack.Key))
+
+	installed, ok := w.Config.MarkerPacks[pack.Name]
+	if ok {
+		fmt.Fprintf(&summary, "Installed: v%d\n", installed.Version)
+		if installed.Key != pack.Key {
+			fmt.Fprintf(&summary, "\n⚠ SIGNED BY A DIFFERENT KEY than v%d (%s). Only apply if the publisher changed keys.\n",
+				installed.Version, config.KeyFingerprint(installed.Key))
+		}
+		if pack.Version <= installed.Version {
+			summary.WriteString("\n⚠ This is not newer than the installed version.\n")
+		}
+	}
+
+	diff := diffMarkerPack(w.Config.AllMarkers(), pack)
+	if diff.empty() {
+		summary.WriteString("\nNo changes to your markers.")
+	} else {
+		summary.WriteString("\n" + diff.describe())
+	}
+	summary.WriteString("\n\nYour own markers are kept; only markers from this pack change.")
+
+	if err := zenity.Question(
+		summary.String(),
+		zenity.Title("Import Marker Pack"),
+		zenity.OKLabel("Apply"),
+		zenity.NoIcon,
+	); err != nil {
+		return
+	}
+
+	w.applyMarkerPack(pack, diff)
+	fmt.Printf("📦 Installed %s v%d: %d added, %d changed, %d removed\n", pack.Name, pack.Version,
+		countMarkers(diff.added), countMarkers(diff.changed), countMarkers(diff.removed))
+}
+
+func (w *Window) applyMarkerPack(pack *config.MarkerPack, diff packDiff) {
+	for zone, list := range diff.removed {
+		gone := make(map[string]bool)
+		for _, m := range list {
+			gone[m.ID] = true
+		}
+		kept := w.Config.ZoneMarkers(zone)[:0]
+		for _, m := range w.Config.ZoneMarkers(zone) {
+			if !(m.Pack == pack.Name && gone[m.ID]) {
+				kept = append(kept, m)
+			}
+		}
+		w.Config.SetZoneMarkers(zone, kept)
+	}
+	for zone, list := range diff.changed {
+		for _, m := range list {
+			for i, old := range w.Config.ZoneMarkers(zone) {
+				if old.Pack == pack.Name && old.ID == m.ID {
+					w.Config.ZoneMarkers(zone)[i] = m
+				}
+			}
+		}
+	}
+	for zone, list := range diff.added {
+		w.Config.SetZoneMarkers(zone, append(w.Config.ZoneMarkers(zone), list...))
+	}
+
+	if w.Config.MarkerPacks == nil {
+		w.Config.MarkerPacks = make(map[string]config.InstalledPack)
+	}
+	w.Config.MarkerPacks[pack.Name] = config.InstalledPack{Version: pack.Version, Publisher: pack.Publisher, Key: pack.Key}
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving marker pack: %v\n", err)
+	}
+}
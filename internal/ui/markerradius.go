@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/widgets"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/ncruces/zenity"
+)
+
+const (
+	markerOptionCategory = "Category..."
+	markerOptionRadius   = "Radius..."
+)
+
+// markerOptions offers the marker settings that don't fit the label dialog.
+func (w *Window) markerOptions(zone, id string) {
+	options := widgets.NewDropdown("Edit Marker", "Marker options:", []string{markerOptionCategory, markerOptionRadius}, 0)
+	w.showModal(options, func(r widgets.Result) {
+		marker := w.markerByID(zone, id)
+		if r != widgets.OK || marker == nil {
+			return
+		}
+
+		// The category and radius choosers are still external dialogs
+		w.dialogOpen = true
+		defer func() {
+			w.dialogOpen = false
+			w.lastMousePressed = true
+		}()
+
+		before := w.snapshotZone("", zone)
+		switch _, choice := options.Selected(); choice {
+		case markerOptionCategory:
+			cat, ok := w.chooseCategory(marker.Category, false)
+			if !ok {
+				return
+			}
+			before.label = fmt.Sprintf("recategorize marker '%s'", marker.Label)
+			marker.Category = cat
+			fmt.Printf("📝 Marker '%s' moved to category '%s'\n", marker.Label, categoryName(cat))
+		case markerOptionRadius:
+			radius, ok := chooseMarkerRadius(marker.Radius)
+			if !ok {
+				return
+			}
+			before.label = fmt.Sprintf("resize marker '%s'", marker.Label)
+			marker.Radius = radius
+			fmt.Printf("⭕ Marker '%s' radius set to %.0f\n", marker.Label, radius)
+		default:
+			return
+		}
+		w.pushUndoStep(before)
+
+		w.syncMarker(zone, *marker)
+		if err := w.Config.Save(); err != nil {
+			fmt.Printf("❌ Error updating marker: %v\n", err)
+		}
+	})
+}
+
+// chooseMarkerRadius asks for a radius in map units; 0 or empty removes it.
+func chooseMarkerRadius(current float64) (float64, bool) {
+	text := ""
+	if current > 0 {
+		text = strconv.FormatFloat(current, 'f', -1, 64)
+	}
+	entry, err := zenity.Entry(
+		"Radius in map units (e.g. 50 for an aggro range, 0 for none):",
+		zenity.Title("Marker Radius"),
+		zenity.EntryText(text),
+	)
+	if err != nil {
+		return current, false
+	}
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return 0, true
+	}
+	v, err := strconv.ParseFloat(entry, 64)
+	if err != nil || v < 0 {
+		zenity.Error("Radius must be a number of 0 or more.", zenity.Title("Marker Radius"))
+		return current, false
+	}
+	return v, true
+}
+
+// drawMarkerRadius draws a translucent circle of the marker's color around
+// it, sized in world units so it scales with zoom.
+func (w *Window) drawMarkerRadius(screen *ebiten.Image, mx, my float32, radius float64, c color.RGBA) {
+	r := float32(radius * w.Zoom)
+	if r < 1 {
+		return
+	}
+	vector.DrawFilledCircle(screen, mx, my, r, color.RGBA{c.R, c.G, c.B, 40}, w.antiAlias)
+	vector.StrokeCircle(screen, mx, my, r, 1.5, color.RGBA{c.R, c.G, c.B, 160}, w.antiAlias)
+}
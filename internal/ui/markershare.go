@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/ncruces/zenity"
+)
+
+const (
+	exportCurrentZone = "Current zone"
+	exportAllZones    = "All zones"
+)
+
+// exportMarkerFile writes the current zone's markers (or every zone's) to a
+// JSON file that can be handed to other players.
+func (w *Window) exportMarkerFile() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	scope := exportAllZones
+	if len(w.Config.ZoneMarkers(w.CurrentZone)) > 0 {
+		choice, err := zenity.List(
+			"Export markers from:",
+			[]string{exportCurrentZone, exportAllZones},
+			zenity.Title("Export Markers"),
+			zenity.DefaultItems(exportCurrentZone),
+		)
+		if err != nil || choice == "" {
+			return
+		}
+		scope = choice
+	}
+
+	markers := make(map[string][]config.Marker)
+	name := "markers"
+	if scope == exportCurrentZone {
+		markers[w.CurrentZone] = w.Config.ZoneMarkers(w.CurrentZone)
+		name = w.CurrentZone + " markers"
+	} else {
+		markers = w.Config.AllMarkers()
+	}
+	if len(markers) == 0 {
+		zenity.Info("There are no markers to export.", zenity.Title("Export Markers"))
+		return
+	}
+
+	path, err := zenity.SelectFileSave(
+		zenity.Title("Export Markers"),
+		zenity.Filename(name+".json"),
+		zenity.ConfirmOverwrite(),
+		zenity.FileFilter{Name: "Marker files", Patterns: []string{"*.json"}},
+	)
+	if err != nil || path == "" {
+		return
+	}
+	if err := config.WriteMarkerFile(path, markers); err != nil {
+		fmt.Printf("❌ Error exporting markers: %v\n", err)
+		zenity.Error(err.Error(), zenity.Title("Export Markers"))
+		return
+	}
+	fmt.Printf("💾 Exported %d markers in %d zones to %s\n", countMarkers(markers), len(markers), path)
+}
+
+// importMarkerFile reads a marker file and either merges it into the
+// existing markers (skipping duplicates) or replaces the markers of every
+// zone the file covers.
+func (w *Window) importMarkerFile() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	path, err := zenity.SelectFile(
+		zenity.Title("Import Markers"),
+		zenity.FileFilter{Name: "Marker files", Patterns: []string{"*.json"}},
+	)
+	if err != nil || path == "" {
+		return
+	}
+	file, err := config.ReadMarkerFile(path)
+	if err != nil {
+		fmt.Printf("❌ Error reading %s: %v\n", path, err)
+		zenity.Error(err.Error(), zenity.Title("Import Markers"))
+		return
+	}
+
+	zones := make([]string, 0, len(file.Markers))
+	for zone := range file.Markers {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "%s has %d markers:\n", filepath.Base(path), countMarkers(file.Markers))
+	for _, zone := range zones {
+		fmt.Fprintf(&summary, "  %s: %d (you have %d)\n", zone, len(file.Markers[zone]), len(w.Config.ZoneMarkers(zone)))
+	}
+	summary.WriteString("\nMerge keeps your markers and skips duplicates.\nReplace discards your markers in these zones.")
+
+	err = zenity.Question(
+		summary.String(),
+		zenity.Title("Import Markers"),
+		zenity.OKLabel("Merge"),
+		zenity.ExtraButton("Replace"),
+		zenity.NoIcon,
+	)
+	replace := errors.Is(err, zenity.ErrExtraButton)
+	if err != nil && !replace {
+		return
+	}
+
+	added, duplicates := w.Config.ImportMarkerFile(file, replace)
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving imported markers: %v\n", err)
+		return
+	}
+	mode := "Merged"
+	if replace {
+		mode = "Replaced with"
+	}
+	fmt.Printf("📥 %s %d markers in %d zones from %s (%d duplicates skipped)\n", mode, added, len(zones), filepath.Base(path), duplicates)
+}
+
+func countMarkers(markers map[string][]config.Marker) int {
+	n := 0
+	for _, list := range markers {
+		n += len(list)
+	}
+	return n
+}
@@ -0,0 +1,195 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/devin-hart/nox-maps/internal/maps"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// lineMesh caches the map geometry as one triangle list (a quad per line) so
+// the whole map draws in a single DrawTriangles32 call. The Z-filtered line
+// set, vertex colors and a grid over the lines are built once per zone/Z-filter
+// change; each frame only positions the quads of the lines in view.
+type lineMesh struct {
+	// Inputs the mesh was built for
+	source   *maps.ZoneMap
+	zMode    int
+	zCenter  float64
+	zRange   float64
+	hidden   uint8       // Bitmask of hidden layers
+	look     paletteSpec // Background, color scheme and tint (see background.go)
+	dashZoom float64     // Zoom the dashes were cut for; 0 if there are none
+
+	palette  palette         // Line colors adjusted for the background, for this zone
+	lines    []maps.MapLine  // Dashed lines are cut into one entry per dash
+	vertices []ebiten.Vertex // Four per line, colors only
+	grid     *maps.LineGrid  // Over lines
+
+	// The quads in view, refilled each frame from vertices (for the colors)
+	visVertices []ebiten.Vertex
+	visIndices  []uint32
+}
+
+// Dashes (zone lines) are this many pixels on and off. They're cut in map
+// units for the zoom rounded to a power of two, so they only need recutting
+// when the zoom halves or doubles.
+const (
+	dashOn, dashOff = 8.0, 5.0
+	maxDashes       = 200 // Per line; a long line zoomed far in gets longer dashes
+)
+
+// meshGridCell is the grid's cell size in map units; zoomed in, the view
+// covers a handful of cells of a big outdoor zone.
+const meshGridCell = 250.0
+
+// ensure rebuilds the mesh if the zone, Z-filter parameters, hidden layers,
+// background or colors changed, or the zoom moved far enough to recut dashes.
+func (m *lineMesh) ensure(data *maps.ZoneMap, zMode int, zCenter, zRange float64, hidden uint8, look paletteSpec, zoom float64) {
+	dashZoom := math.Exp2(math.Round(math.Log2(zoom)))
+	if m.source == data && m.zMode == zMode && m.zRange == zRange && (zMode == 0 || m.zCenter == zCenter) && m.hidden == hidden && m.look == look && (m.dashZoom == 0 || m.dashZoom == dashZoom) {
+		return
+	}
+	if m.source != data || m.look != look {
+		m.palette = palette{paletteSpec: look}
+	}
+	m.look = look
+	m.dashZoom = 0
+	m.source = data
+	m.zMode = zMode
+	m.zCenter = zCenter
+	m.zRange = zRange
+	m.hidden = hidden
+
+	m.lines = m.lines[:0]
+	if data != nil {
+		for _, line := range data.Lines {
+			if hidden&(1<<line.Layer) != 0 {
+				continue
+			}
+			// Z-Level filtering: skip lines with both endpoints outside the range
+			if zMode > 0 {
+				z1InRange := math.Abs(line.Z1-zCenter) <= zRange
+				z2InRange := math.Abs(line.Z2-zCenter) <= zRange
+				if !z1InRange && !z2InRange {
+					continue
+				}
+			}
+			if line.Style == maps.StyleDashed {
+				m.lines = appendDashes(m.lines, line, dashZoom)
+				m.dashZoom = dashZoom
+				continue
+			}
+			m.lines = append(m.lines, line)
+		}
+	}
+
+	n := len(m.lines)
+	if cap(m.vertices) < n*4 {
+		m.vertices = make([]ebiten.Vertex, n*4)
+	}
+	m.vertices = m.vertices[:n*4]
+
+	for i, line := range m.lines {
+		c := m.palette.line(line.Color, line.Kind)
+		r := float32(c.R) / 255
+		g := float32(c.G) / 255
+		b := float32(c.B) / 255
+		a := float32(c.A) / 255
+		for j := 0; j < 4; j++ {
+			v := &m.vertices[i*4+j]
+			v.SrcX, v.SrcY = 1, 1
+			v.ColorR, v.ColorG, v.ColorB, v.ColorA = r, g, b, a
+		}
+	}
+	m.grid = maps.NewLineGrid(m.lines, meshGridCell)
+}
+
+// appendDashes cuts a line into dashes for the given zoom.
+func appendDashes(lines []maps.MapLine, line maps.MapLine, zoom float64) []maps.MapLine {
+	dx, dy, dz := line.X2-line.X1, line.Y2-line.Y1, line.Z2-line.Z1
+	length := math.Hypot(dx, dy)
+	period := (dashOn + dashOff) / zoom
+	if length <= period {
+		return append(lines, line)
+	}
+	if length/period > maxDashes {
+		period = length / maxDashes
+	}
+	on := period * dashOn / (dashOn + dashOff)
+	for start := 0.0; start < length; start += period {
+		end := math.Min(start+on, length)
+		dash := line
+		t1, t2 := start/length, end/length
+		dash.X1, dash.Y1, dash.Z1 = line.X1+dx*t1, line.Y1+dy*t1, line.Z1+dz*t1
+		dash.X2, dash.Y2, dash.Z2 = line.X1+dx*t2, line.Y1+dy*t2, line.Z1+dz*t2
+		lines = append(lines, dash)
+	}
+	return lines
+}
+
+// invalidate forces a rebuild on the next ensure (after the map's lines
+// were edited in place).
+func (m *lineMesh) invalidate() {
+	m.source = nil
+}
+
+// draw positions the quads of the lines in view for the current camera and
+// rotation, and draws them at once.
+func (m *lineMesh) draw(dst *ebiten.Image, camX, camY, zoom float64, rot viewRotation, cx, cy float64, lineWidth float32, antiAlias bool) {
+	if len(m.lines) == 0 {
+		return
+	}
+	half := float64(lineWidth) / 2
+
+	// The view's bounding box in map units (turned, if the map is), padded
+	// by the line width
+	bounds := dst.Bounds()
+	pad := (half + 1) / zoom
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, corner := range [4][2]float64{{0, 0}, {float64(bounds.Dx()), 0}, {0, float64(bounds.Dy())}, {float64(bounds.Dx()), float64(bounds.Dy())}} {
+		ux, uy := (corner[0]-cx)/zoom, (corner[1]-cy)/zoom
+		x, y := ux*rot.cos+uy*rot.sin+camX, -ux*rot.sin+uy*rot.cos+camY
+		minX, maxX = min(minX, x), max(maxX, x)
+		minY, maxY = min(minY, y), max(maxY, y)
+	}
+	minX, minY, maxX, maxY = minX-pad, minY-pad, maxX+pad, maxY+pad
+
+	m.visVertices = m.visVertices[:0]
+	m.visIndices = m.visIndices[:0]
+	m.grid.Query(minX, minY, maxX, maxY, func(i int, line maps.MapLine) {
+		if max(line.X1, line.X2) < minX || min(line.X1, line.X2) > maxX || max(line.Y1, line.Y2) < minY || min(line.Y1, line.Y2) > maxY {
+			return
+		}
+		dx1, dy1 := (line.X1-camX)*zoom, (line.Y1-camY)*zoom
+		dx2, dy2 := (line.X2-camX)*zoom, (line.Y2-camY)*zoom
+		x1 := dx1*rot.cos - dy1*rot.sin + cx
+		y1 := dx1*rot.sin + dy1*rot.cos + cy
+		x2 := dx2*rot.cos - dy2*rot.sin + cx
+		y2 := dx2*rot.sin + dy2*rot.cos + cy
+
+		// Offset both ends along the line's normal to get a quad
+		dx, dy := x2-x1, y2-y1
+		length := math.Hypot(dx, dy)
+		var nx, ny float64
+		if length > 0 {
+			nx, ny = -dy/length*half, dx/length*half
+		}
+
+		base := uint32(len(m.visVertices))
+		m.visVertices = append(m.visVertices, m.vertices[i*4:i*4+4]...)
+		v := m.visVertices[base : base+4]
+		v[0].DstX, v[0].DstY = float32(x1+nx), float32(y1+ny)
+		v[1].DstX, v[1].DstY = float32(x1-nx), float32(y1-ny)
+		v[2].DstX, v[2].DstY = float32(x2+nx), float32(y2+ny)
+		v[3].DstX, v[3].DstY = float32(x2-nx), float32(y2-ny)
+		m.visIndices = append(m.visIndices, base, base+1, base+2, base+1, base+3, base+2)
+	})
+	if len(m.visIndices) == 0 {
+		return
+	}
+
+	dst.DrawTriangles32(m.visVertices, m.visIndices, whiteImage, &ebiten.DrawTrianglesOptions{
+		AntiAlias: antiAlias,
+	})
+}
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"image/color"
+
+	"github.com/devin-hart/nox-maps/internal/maps"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	minimapSize   = 180 // Longest side in pixels
+	minimapMargin = 8
+)
+
+// minimap caches the whole zone drawn small; it is redrawn only when the
+// map or its hidden layers change.
+type minimap struct {
+	source *maps.ZoneMap
+	hidden uint8
+	look   paletteSpec
+	image  *ebiten.Image
+	scale  float64 // Pixels per map unit
+}
+
+func (m *minimap) ensure(data *maps.ZoneMap, hidden uint8, look paletteSpec, antiAlias bool) {
+	if m.source == data && m.hidden == hidden && m.look == look && m.image != nil {
+		return
+	}
+	m.source, m.hidden, m.look = data, hidden, look
+
+	width, height := data.MaxX-data.MinX, data.MaxY-data.MinY
+	if width <= 0 || height <= 0 {
+		m.image = nil
+		return
+	}
+	m.scale = minimapSize / max(width, height)
+	m.image = ebiten.NewImage(max(1, int(width*m.scale)), max(1, int(height*m.scale)))
+	for _, l := range data.Lines {
+		if hidden&(1<<l.Layer) != 0 {
+			continue
+		}
+		vector.StrokeLine(m.image,
+			float32((l.X1-data.MinX)*m.scale), float32((l.Y1-data.MinY)*m.scale),
+			float32((l.X2-data.MinX)*m.scale), float32((l.Y2-data.MinY)*m.scale),
+			1, mapColor(l.Color, l.Kind, look), antiAlias)
+	}
+}
+
+// minimapRect places the minimap in the bottom-right corner.
+func (w *Window) minimapRect() (px, py, width, height int, ok bool) {
+	if !w.showMinimap || w.MapData == nil || w.minimap.image == nil || w.minimap.source != w.MapData {
+		return 0, 0, 0, 0, false
+	}
+	b := w.minimap.image.Bounds()
+	width, height = b.Dx()+8, b.Dy()+8
+	return w.Width - width - minimapMargin, w.Height - height - minimapMargin, width, height, true
+}
+
+// handleMinimapClick centers the main view where the minimap was clicked.
+func (w *Window) handleMinimapClick(mx, my int) bool {
+	px, py, width, height, ok := w.minimapRect()
+	if !ok || mx < px || mx >= px+width || my < py || my >= py+height {
+		return false
+	}
+	w.CamX = w.MapData.MinX + float64(mx-px-4)/w.minimap.scale
+	w.CamY = w.MapData.MinY + float64(my-py-4)/w.minimap.scale
+	w.setFollow(false)
+	return true
+}
+
+// drawMinimap draws the zone overview with the main view's rectangle and
+// the player dot.
+func (w *Window) drawMinimap(screen *ebiten.Image) {
+	if !w.showMinimap || w.MapData == nil {
+		return
+	}
+	background := w.mapBackground()
+	w.minimap.ensure(w.MapData, w.hiddenLayerMask(), w.paletteSpec(), w.antiAlias)
+	px, py, width, height, ok := w.minimapRect()
+	if !ok {
+		return
+	}
+
+	fill := background.color
+	fill.A = 200
+	vector.DrawFilledRect(screen, float32(px), float32(py), float32(width), float32(height), fill, false)
+	vector.StrokeRect(screen, float32(px), float32(py), float32(width), float32(height), 1, color.RGBA{120, 120, 120, 255}, false)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(px+4), float64(py+4))
+	screen.DrawImage(w.minimap.image, op)
+
+	// World -> minimap pixel
+	toMini := func(x, y float64) (float32, float32) {
+		return float32(float64(px+4) + (x-w.MapData.MinX)*w.minimap.scale),
+			float32(float64(py+4) + (y-w.MapData.MinY)*w.minimap.scale)
+	}
+
+	// Main viewport, turned with the map (see rotation.go)
+	cx, cy := float64(w.Width)/2, float64(w.Height)/2
+	corners := [4][2]float64{{0, 0}, {float64(w.Width), 0}, {float64(w.Width), float64(w.Height)}, {0, float64(w.Height)}}
+	for i, a := range corners {
+		b := corners[(i+1)%4]
+		x1, y1 := toMini(w.toWorld(a[0], a[1], cx, cy))
+		x2, y2 := toMini(w.toWorld(b[0], b[1], cx, cy))
+		vector.StrokeLine(screen, x1, y1, x2, y2, 1, color.RGBA{255, 255, 0, 255}, false)
+	}
+
+	if w.LogReader != nil && !w.browsing() {
+		x, y := toMini(w.playerPosition())
+		vector.DrawFilledCircle(screen, x, y, 3, color.RGBA{0, 255, 0, 255}, w.antiAlias)
+	}
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/widgets"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// modal is an in-window dialog (see internal/widgets). Unlike a zenity
+// dialog it doesn't block the game loop, so the code that opens one
+// finishes in done, a frame or more later.
+type modal struct {
+	widget widgets.Modal
+	done   func(widgets.Result)
+}
+
+// showModal opens an in-window dialog, replacing any that's open.
+func (w *Window) showModal(m widgets.Modal, done func(widgets.Result)) {
+	w.modal = &modal{widget: m, done: done}
+}
+
+// updateModal runs the open dialog; false if there is none. The dialog owns
+// input until it closes.
+func (w *Window) updateModal() bool {
+	m := w.modal
+	if m == nil {
+		return false
+	}
+	r := m.widget.Update()
+	if r == widgets.Pending {
+		return true
+	}
+	w.modal = nil
+	w.lastMousePressed = true // The closing click shouldn't reach the map
+	if m.done != nil {
+		m.done(r)
+	}
+	return true
+}
+
+func (w *Window) drawModal(screen *ebiten.Image) {
+	if w.modal != nil {
+		widgets.SetFace(w.uiFace()) // Dialogs follow the UI font size
+		w.modal.widget.Draw(screen)
+	}
+}
+
+// markerByID finds a marker again after a dialog, since the slice may have
+// changed (or lost it) in the meantime.
+func (w *Window) markerByID(zone, id string) *config.Marker {
+	markers := w.Config.ZoneMarkers(zone)
+	for i := range markers {
+		if markers[i].ID == id {
+			return &markers[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"math"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/parser"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	defaultMotionSmoothing = 0.8
+	maxExtrapolation       = 1.5 // Stop predicting after this many /loc intervals without news
+)
+
+// motionModel turns discrete /loc samples into a continuously moving
+// position: it extrapolates from the last sample using the velocity between
+// the last two, and eases the drawn position toward that prediction.
+type motionModel struct {
+	lastX, lastY float64
+	lastTime     time.Time
+	interval     time.Duration // Time between the last two samples
+	vx, vy       float64       // Map units per second
+	x, y         float64       // Drawn position
+	ready        bool
+}
+
+// update feeds the latest state in once per frame.
+func (m *motionModel) update(s parser.PlayerState, smoothing float64) {
+	if s.LocTime.IsZero() {
+		m.x, m.y = s.X, s.Y
+		return
+	}
+	if !s.LocTime.Equal(m.lastTime) {
+		m.sample(s.X, s.Y, s.LocTime)
+	}
+
+	elapsed := time.Since(m.lastTime)
+	if m.interval > 0 && elapsed > time.Duration(float64(m.interval)*maxExtrapolation) {
+		elapsed = time.Duration(float64(m.interval) * maxExtrapolation)
+	}
+	predX := m.lastX + m.vx*elapsed.Seconds()
+	predY := m.lastY + m.vy*elapsed.Seconds()
+
+	// smoothing is the share of the gap left after one 60 Hz frame
+	keep := math.Pow(smoothing, 60/float64(ebiten.TPS()))
+	m.x = predX + (m.x-predX)*keep
+	m.y = predY + (m.y-predY)*keep
+}
+
+func (m *motionModel) sample(x, y float64, t time.Time) {
+	if !m.ready || math.Hypot(x-m.lastX, y-m.lastY) > followSnap {
+		// First fix or a teleport (zoning, death): jump there
+		*m = motionModel{lastX: x, lastY: y, lastTime: t, x: x, y: y, ready: true}
+		return
+	}
+	if dt := t.Sub(m.lastTime); dt > 0 {
+		m.vx = (x - m.lastX) / dt.Seconds()
+		m.vy = (y - m.lastY) / dt.Seconds()
+		m.interval = dt
+	}
+	m.lastX, m.lastY, m.lastTime = x, y, t
+}
+
+// playerPosition is where the player is drawn: the interpolated position,
+// or the raw /loc if interpolation is off.
+func (w *Window) playerPosition() (float64, float64) {
+	s := w.LogReader.State()
+	if w.Config.DisableInterpolation {
+		return s.X, s.Y
+	}
+	return w.motion.x, w.motion.y
+}
+
+// updateMotion advances the motion model for this frame.
+func (w *Window) updateMotion() {
+	if w.LogReader == nil || w.Config.DisableInterpolation {
+		return
+	}
+	smoothing := w.Config.MotionSmoothing
+	if smoothing <= 0 || smoothing >= 1 {
+		smoothing = defaultMotionSmoothing
+	}
+	w.motion.update(w.LogReader.State(), smoothing)
+}
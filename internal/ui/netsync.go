@@ -0,0 +1,293 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/netsync"
+	"github.com/devin-hart/nox-maps/internal/parser"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/ncruces/zenity"
+)
+
+const (
+	syncPositionEvery = time.Second
+	syncPeerTimeout   = 30 * time.Second // Hide a peer's arrow after this long without an update
+)
+
+// syncPeer is the last position another instance shared.
+type syncPeer struct {
+	state     parser.PlayerState
+	seen      time.Time
+	moved     time.Time    // When the shared position last changed (see afk.go)
+	trail     []trailPoint // Recent positions, oldest first (see peertrails.go)
+	trailZone string
+}
+
+// syncName is what other instances see us as.
+func (w *Window) syncName() string {
+	if w.LogReader != nil {
+		if name := w.LogReader.State().Character; name != "" {
+			return name
+		}
+	}
+	return "Player"
+}
+
+// hostSync starts hosting a marker sync session.
+func (w *Window) hostSync() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	listen := w.Config.Sync.Listen
+	if listen == "" {
+		listen = ":" + netsync.DefaultPort
+	}
+	addr, err := zenity.Entry(
+		"Listen on (others join this machine's LAN address and port):",
+		zenity.Title("Host Marker Sync"),
+		zenity.EntryText(listen),
+	)
+	if err != nil || strings.TrimSpace(addr) == "" {
+		return
+	}
+	code := w.Config.Sync.Code
+	if code == "" {
+		code = netsync.NewCode()
+	}
+	code, err = zenity.Entry(
+		"Join code (others need it to join):",
+		zenity.Title("Host Marker Sync"),
+		zenity.EntryText(code),
+	)
+	if err != nil || strings.TrimSpace(code) == "" {
+		return
+	}
+	s, err := netsync.Host(strings.TrimSpace(addr), w.syncName(), code)
+	if err != nil {
+		zenity.Error(fmt.Sprintf("Couldn't start hosting: %v", err), zenity.Title("Host Marker Sync"))
+		return
+	}
+	w.Config.Sync.Listen = strings.TrimSpace(addr)
+	w.Config.Sync.Code = strings.TrimSpace(code)
+	w.Config.Save()
+	w.startSync(s)
+	fmt.Printf("🔗 Hosting marker sync on %s (join code %s)\n", s.Addr, w.Config.Sync.Code)
+}
+
+// joinSync connects to another instance's session.
+func (w *Window) joinSync() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	addr, err := zenity.Entry(
+		"Host address (e.g. 192.168.1.20:7777):",
+		zenity.Title("Join Marker Sync"),
+		zenity.EntryText(w.Config.Sync.Host),
+	)
+	if err != nil || strings.TrimSpace(addr) == "" {
+		return
+	}
+	code, err := zenity.Entry(
+		"Join code (shown on the host's File menu):",
+		zenity.Title("Join Marker Sync"),
+		zenity.EntryText(w.Config.Sync.JoinCode),
+	)
+	if err != nil || strings.TrimSpace(code) == "" {
+		return
+	}
+	s, err := netsync.Join(strings.TrimSpace(addr), w.syncName(), code)
+	if err != nil {
+		zenity.Error(fmt.Sprintf("Couldn't join: %v", err), zenity.Title("Join Marker Sync"))
+		return
+	}
+	w.Config.Sync.Host = strings.TrimSpace(addr)
+	w.Config.Sync.JoinCode = strings.TrimSpace(code)
+	w.Config.Save()
+	w.startSync(s)
+	fmt.Printf("🔗 Joined marker sync at %s\n", s.Addr)
+}
+
+func (w *Window) startSync(s *netsync.Session) {
+	w.stopSync()
+	w.sync = s
+	w.syncPeers = make(map[string]*syncPeer)
+}
+
+func (w *Window) stopSync() {
+	if w.sync == nil {
+		return
+	}
+	w.sync.Close()
+	w.sync = nil
+	w.syncPeers = nil
+	fmt.Println("🔗 Left marker sync")
+}
+
+// updateSync applies what other instances sent and shares our position.
+func (w *Window) updateSync() {
+	if w.sync == nil {
+		return
+	}
+	for _, msg := range w.sync.Drain() {
+		w.applySyncMessage(msg)
+	}
+	if !w.sync.Connected() {
+		fmt.Println("⚠️  Marker sync host went away")
+		w.stopSync()
+		return
+	}
+
+	if w.Config.Sync.SharePosition && w.LogReader != nil && time.Since(w.lastSyncPosition) >= syncPositionEvery {
+		w.lastSyncPosition = time.Now()
+		s := w.LogReader.State()
+		if s.Zone != "" && !s.LocTime.IsZero() {
+			w.sync.Send(netsync.Message{Type: netsync.TypePosition, Zone: w.logZone, X: s.X, Y: s.Y, Heading: s.Heading})
+		}
+	}
+}
+
+func (w *Window) applySyncMessage(msg netsync.Message) {
+	switch msg.Type {
+	case netsync.TypeHello:
+		fmt.Printf("🔗 %s joined marker sync\n", msg.From)
+	case netsync.TypeLeave:
+		if msg.From != "" {
+			fmt.Printf("🔗 %s left marker sync\n", msg.From)
+			delete(w.syncPeers, msg.From)
+		}
+	case netsync.TypePosition:
+		p := w.syncPeers[msg.From]
+		if p == nil {
+			p = &syncPeer{}
+			w.syncPeers[msg.From] = p
+		}
+		if p.moved.IsZero() || math.Hypot(msg.X-p.state.X, msg.Y-p.state.Y) > 1 {
+			p.moved = time.Now()
+		}
+		p.state = parser.PlayerState{X: msg.X, Y: msg.Y, Heading: msg.Heading, Zone: msg.Zone, Character: msg.From}
+		p.seen = time.Now()
+		w.recordPeerTrail(p, msg.Zone, msg.X, msg.Y)
+	case netsync.TypeMarker:
+		if msg.Marker == nil || msg.Marker.ID == "" || msg.Zone == "" {
+			return
+		}
+		markers := w.Config.ZoneMarkers(msg.Zone)
+		for i := range markers {
+			if markers[i].ID == msg.Marker.ID {
+				markers[i] = *msg.Marker
+				w.Config.Save()
+				return
+			}
+		}
+		w.Config.SetZoneMarkers(msg.Zone, append(markers, *msg.Marker))
+		w.Config.Save()
+		fmt.Printf("🔗 %s placed '%s' in %s\n", msg.From, msg.Marker.Label, msg.Zone)
+	case netsync.TypeRemove:
+		markers := w.Config.ZoneMarkers(msg.Zone)
+		for i := range markers {
+			if markers[i].ID == msg.MarkerID {
+				fmt.Printf("🔗 %s removed '%s' from %s\n", msg.From, markers[i].Label, msg.Zone)
+				w.Config.SetZoneMarkers(msg.Zone, append(markers[:i:i], markers[i+1:]...))
+				w.Config.Save()
+				return
+			}
+		}
+	}
+}
+
+// syncMarker shares a placed or edited marker.
+func (w *Window) syncMarker(zone string, m config.Marker) {
+	if w.sync != nil && m.ID != "" {
+		w.sync.Send(netsync.Message{Type: netsync.TypeMarker, Zone: zone, Marker: &m})
+	}
+}
+
+// syncRemove shares a removed marker.
+func (w *Window) syncRemove(zone string, m config.Marker) {
+	if w.sync != nil && m.ID != "" {
+		w.sync.Send(netsync.Message{Type: netsync.TypeRemove, Zone: zone, MarkerID: m.ID})
+	}
+}
+
+// syncMenuItems are the File menu's marker sync entries.
+func (w *Window) syncMenuItems() []MenuItem {
+	if w.sync == nil {
+		return []MenuItem{
+			{
+				Label: "Host Marker Sync...",
+				Action: func() {
+					w.openMenu = ""
+					w.hostSync()
+				},
+			},
+			{
+				Label: "Join Marker Sync...",
+				Action: func() {
+					w.openMenu = ""
+					w.joinSync()
+				},
+			},
+		}
+	}
+
+	leave := fmt.Sprintf("Leave Marker Sync (%s)", w.sync.Addr)
+	if w.sync.IsHost() {
+		leave = fmt.Sprintf("Stop Hosting Sync (code %s, %d connected)", w.Config.Sync.Code, w.sync.Peers())
+	}
+	items := []MenuItem{
+		{
+			Label: fmt.Sprintf("Share Position: %s", map[bool]string{true: "ON", false: "OFF"}[w.Config.Sync.SharePosition]),
+			Action: func() {
+				w.Config.Sync.SharePosition = !w.Config.Sync.SharePosition
+				w.Config.Save()
+				w.openMenu = ""
+			},
+		},
+		{
+			Label: leave,
+			Action: func() {
+				w.stopSync()
+				w.openMenu = ""
+			},
+		},
+	}
+	return append(items, w.peerTrailMenuItems()...)
+}
+
+// syncPeerNames lists the peers that have shared a position, sorted so
+// each keeps its color.
+func (w *Window) syncPeerNames() []string {
+	names := make([]string, 0, len(w.syncPeers))
+	for name := range w.syncPeers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// drawSyncPeers draws other instances' shared positions (and trails) in the
+// shown zone.
+func (w *Window) drawSyncPeers(screen *ebiten.Image, cx, cy float64) {
+	for i, name := range w.syncPeerNames() {
+		p := w.syncPeers[name]
+		c := w.peerColor(name, i)
+		w.drawPeerTrail(screen, cx, cy, p, c)
+		if p.state.Zone != w.CurrentZone || time.Since(p.seen) > syncPeerTimeout {
+			continue
+		}
+		px, py := w.drawArrow(screen, cx, cy, p.state, c)
+		w.drawUIText(screen, name, int(px)+12, int(py)+4, c)
+		w.drawAFKBadge(screen, px, py, p.moved, c)
+	}
+}
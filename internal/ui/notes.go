@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// openNotesEditor starts editing the current zone's notes in the notes panel.
+func (w *Window) openNotesEditor() {
+	if w.CurrentZone == "" {
+		fmt.Println("⚠️  Cannot edit notes: no active zone")
+		return
+	}
+	w.notesBuffer = []rune(w.Config.ZoneNotes[w.CurrentZone])
+	w.notesEditing = true
+}
+
+// updateNotesEditor handles typing while the notes panel is open. It owns the
+// keyboard so map hotkeys don't fire while writing.
+func (w *Window) updateNotesEditor() {
+	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl)
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		w.notesEditing = false
+		return
+	}
+	if ctrl && inpututil.IsKeyJustPressed(ebiten.KeyS) {
+		w.saveNotes()
+		return
+	}
+
+	if !ctrl {
+		w.notesBuffer = ebiten.AppendInputChars(w.notesBuffer)
+	}
+	if repeatingKeyPressed(ebiten.KeyEnter) || repeatingKeyPressed(ebiten.KeyNumpadEnter) {
+		w.notesBuffer = append(w.notesBuffer, '\n')
+	}
+	if repeatingKeyPressed(ebiten.KeyBackspace) && len(w.notesBuffer) > 0 {
+		w.notesBuffer = w.notesBuffer[:len(w.notesBuffer)-1]
+	}
+}
+
+func (w *Window) saveNotes() {
+	notes := strings.TrimSpace(string(w.notesBuffer))
+	if notes == "" {
+		delete(w.Config.ZoneNotes, w.CurrentZone)
+	} else {
+		w.Config.ZoneNotes[w.CurrentZone] = notes
+	}
+	w.notesEditing = false
+	w.notesExpanded = true
+
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving notes: %v\n", err)
+	} else {
+		fmt.Printf("📝 Notes saved for %s\n", w.CurrentZone)
+	}
+}
+
+// repeatingKeyPressed reports a key press plus auto-repeat while held.
+func repeatingKeyPressed(key ebiten.Key) bool {
+	const (
+		delay    = 30
+		interval = 3
+	)
+	d := inpututil.KeyPressDuration(key)
+	if d == 1 {
+		return true
+	}
+	if d >= delay && (d-delay)%interval == 0 {
+		return true
+	}
+	return false
+}
+
+// drawNotesEditor draws the notes panel centered over the map.
+func (w *Window) drawNotesEditor(screen *ebiten.Image) {
+	scale := w.uiSize() / uiBaseSize
+	panelW, panelH := int(480*scale), int(260*scale)
+	if panelW > w.Width-20 {
+		panelW = w.Width - 20
+	}
+	px := (w.Width - panelW) / 2
+	py := (w.Height - panelH) / 2
+
+	vector.DrawFilledRect(screen, float32(px), float32(py), float32(panelW), float32(panelH), color.RGBA{20, 20, 20, 235}, false)
+	vector.StrokeRect(screen, float32(px), float32(py), float32(panelW), float32(panelH), 1, color.RGBA{180, 180, 180, 255}, false)
+
+	title := fmt.Sprintf("Notes: %s", w.CurrentZone)
+	rowH := w.panelRowHeight()
+	w.drawUITextIn(screen, title, px+8, py+4, rowH, color.RGBA{255, 200, 0, 255})
+	w.drawUITextIn(screen, "Ctrl+S save | Esc cancel", px+8, py+panelH-4-rowH, rowH, color.RGBA{150, 150, 150, 255})
+
+	// Show the tail of the buffer if it's longer than the panel
+	lines := strings.Split(string(w.notesBuffer)+"_", "\n")
+	maxLines := (panelH - 2*rowH - 20) / rowH
+	if len(lines) > maxLines {
+		lines = lines[len(lines)-maxLines:]
+	}
+	for i, line := range lines {
+		w.drawUITextIn(screen, line, px+8, py+rowH+10+i*rowH, rowH, color.White)
+	}
+}
+
+// zoneNotesInfo returns the info panel lines for the current zone's notes.
+func (w *Window) zoneNotesInfo() []string {
+	notes, ok := w.Config.ZoneNotes[w.CurrentZone]
+	if !ok {
+		return nil
+	}
+	if !w.notesExpanded {
+		return []string{"Notes: (collapsed)"}
+	}
+	// Long lines wrap so camp lists and key requirements stay beside the map
+	// instead of running off the window
+	width := min((w.Width-16)/6, maxNoteLineChars) // Debug font is 6 px wide
+	info := []string{"-- Notes --"}
+	for _, line := range strings.Split(notes, "\n") {
+		info = append(info, wrapNoteLine(line, width)...)
+	}
+	return info
+}
+
+const maxNoteLineChars = 60
+
+// wrapNoteLine breaks a line at spaces into pieces of at most width runes;
+// a word longer than that is cut.
+func wrapNoteLine(line string, width int) []string {
+	if width < 10 || len([]rune(line)) <= width {
+		return []string{line}
+	}
+	var out []string
+	cur := []rune{}
+	for _, word := range strings.Fields(line) {
+		r := []rune(word)
+		for len(r) > width {
+			if len(cur) > 0 {
+				out = append(out, string(cur))
+				cur = cur[:0]
+			}
+			out = append(out, string(r[:width]))
+			r = r[width:]
+		}
+		if len(cur) > 0 && len(cur)+1+len(r) > width {
+			out = append(out, string(cur))
+			cur = cur[:0]
+		}
+		if len(cur) > 0 {
+			cur = append(cur, ' ')
+		}
+		cur = append(cur, r...)
+	}
+	if len(cur) > 0 {
+		out = append(out, string(cur))
+	}
+	return out
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/ncruces/zenity"
+)
+
+// applyOverlayMode pushes the always-on-top, click-through and frameless
+// settings to the window.
+func (w *Window) applyOverlayMode() {
+	ebiten.SetWindowDecorated(!w.Config.Frameless)
+	ebiten.SetWindowFloating(w.Config.AlwaysOnTop)
+	ebiten.SetWindowMousePassthrough(w.Config.ClickThrough)
+}
+
+func (w *Window) toggleAlwaysOnTop() {
+	w.Config.AlwaysOnTop = !w.Config.AlwaysOnTop
+	w.applyOverlayMode()
+	w.Config.Save()
+	fmt.Printf("📌 Always on top %s\n", map[bool]string{true: "ON", false: "OFF"}[w.Config.AlwaysOnTop])
+}
+
+// toggleClickThrough lets mouse input fall through to the window below.
+// With it on the menu can't be clicked, so turning it on explains how to
+// get back: focus the map (Alt+Tab) and press the hotkey.
+func (w *Window) toggleClickThrough() {
+	w.Config.ClickThrough = !w.Config.ClickThrough
+	if w.Config.ClickThrough {
+		w.dialogOpen = true
+		err := zenity.Question(
+			fmt.Sprintf("Mouse clicks will pass through the map to the window underneath.\n\nTo turn this off, switch to the map with Alt+Tab and press %s.", w.hotkeyLabel(ActionClickThrough)),
+			zenity.Title("Click-Through"),
+			zenity.OKLabel("Enable"),
+		)
+		w.dialogOpen = false
+		w.lastMousePressed = true
+		if err != nil {
+			w.Config.ClickThrough = false
+			return
+		}
+	}
+	w.applyOverlayMode()
+	w.Config.Save()
+	fmt.Printf("🖱️  Click-through %s\n", map[bool]string{true: "ON", false: "OFF"}[w.Config.ClickThrough])
+}
+
+func (w *Window) toggleTray() {
+	w.Config.Tray = !w.Config.Tray
+	if w.Config.Tray {
+		w.startTray()
+	} else {
+		w.stopTray()
+		fmt.Println("🔔 Tray icon OFF")
+	}
+	w.Config.Save()
+}
+
+// setOpacity sets the map's opacity, which only goes below full when the
+// window can be transparent.
+func (w *Window) setOpacity(opacity float64) {
+	w.Opacity = min(max(opacity, 0.1), 1.0)
+	if !w.transparent {
+		w.Opacity = 1.0
+	}
+}
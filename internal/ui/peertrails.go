@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/ncruces/zenity"
+)
+
+const (
+	peerTrailSpacing        = 20.0 // Map units a peer moves between trail points
+	defaultPeerTrailSeconds = 60
+)
+
+// peerTrailLengths are the choices Peer Trail Length cycles through.
+var peerTrailLengths = []int{30, 60, 120, 300}
+
+// trailPoint is a breadcrumb with the time it was dropped, for trails that
+// fade out behind someone else.
+type trailPoint struct {
+	BreadcrumbPoint
+	At time.Time
+}
+
+// peerTrailLength is how long a peer's trail stays on the map.
+func (w *Window) peerTrailLength() time.Duration {
+	secs := w.Config.Sync.PeerTrailSeconds
+	if secs <= 0 {
+		secs = defaultPeerTrailSeconds
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// recordPeerTrail extends a peer's trail to its newest position. Changing
+// zones starts the trail over.
+func (w *Window) recordPeerTrail(p *syncPeer, zone string, x, y float64) {
+	if p.trailZone != zone {
+		p.trail = p.trail[:0]
+		p.trailZone = zone
+	}
+	now := time.Now()
+	cutoff := now.Add(-w.peerTrailLength())
+	drop := 0
+	for drop < len(p.trail) && p.trail[drop].At.Before(cutoff) {
+		drop++
+	}
+	p.trail = append(p.trail[:0], p.trail[drop:]...)
+
+	if n := len(p.trail); n > 0 {
+		last := p.trail[n-1]
+		if (x-last.X)*(x-last.X)+(y-last.Y)*(y-last.Y) < peerTrailSpacing*peerTrailSpacing {
+			return
+		}
+	}
+	p.trail = append(p.trail, trailPoint{BreadcrumbPoint: BreadcrumbPoint{X: x, Y: y}, At: now})
+}
+
+// drawPeerTrail draws a peer's recent path up to its arrow, fading with age.
+func (w *Window) drawPeerTrail(screen *ebiten.Image, cx, cy float64, p *syncPeer, c color.RGBA) {
+	if !w.Config.Sync.PeerTrails || p.trailZone != w.CurrentZone || len(p.trail) == 0 {
+		return
+	}
+	length := w.peerTrailLength()
+	points := append(p.trail, trailPoint{BreadcrumbPoint: BreadcrumbPoint{X: p.state.X, Y: p.state.Y}, At: p.seen})
+	for i := 1; i < len(points); i++ {
+		a, b := points[i-1], points[i]
+		fade := 1 - float64(time.Since(a.At))/float64(length)
+		if fade <= 0 {
+			continue
+		}
+		segment := c
+		segment.A = uint8(float64(c.A) * fade * 0.8)
+		ax, ay := w.toScreen32(a.X, a.Y, cx, cy)
+		bx, by := w.toScreen32(b.X, b.Y, cx, cy)
+		vector.StrokeLine(screen, ax, ay, bx, by, 2, segment, w.antiAlias)
+	}
+}
+
+// peerColor is the color picked for a peer, else one from the party
+// palette by its place in the list.
+func (w *Window) peerColor(name string, i int) color.RGBA {
+	if hex, ok := w.Config.Sync.PeerColors[name]; ok {
+		return config.ParseColor(hex)
+	}
+	return partyColor(len(partyColors) - 1 - i) // Counted from the other end than the local party
+}
+
+// pickPeerColor sets the color a peer is drawn in from now on.
+func (w *Window) pickPeerColor(name string, current color.RGBA) {
+	w.dialogOpen = true
+	picked, err := zenity.SelectColor(
+		zenity.Title("Color for "+name),
+		zenity.Color(current),
+	)
+	w.dialogOpen = false
+	w.lastMousePressed = true
+	if err != nil || picked == nil {
+		return
+	}
+
+	if w.Config.Sync.PeerColors == nil {
+		w.Config.Sync.PeerColors = make(map[string]string)
+	}
+	w.Config.Sync.PeerColors[name] = config.HexColor(picked)
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving peer color: %v\n", err)
+	}
+}
+
+// peerTrailMenuItems are the marker sync entries for peers' trails and colors.
+func (w *Window) peerTrailMenuItems() []MenuItem {
+	items := []MenuItem{
+		{
+			Label: fmt.Sprintf("Peer Trails: %s", map[bool]string{true: "ON", false: "OFF"}[w.Config.Sync.PeerTrails]),
+			Action: func() {
+				w.Config.Sync.PeerTrails = !w.Config.Sync.PeerTrails
+				w.Config.Save()
+				w.openMenu = ""
+			},
+		},
+		{
+			Label: fmt.Sprintf("Peer Trail Length: %ds", int(w.peerTrailLength()/time.Second)),
+			Action: func() {
+				next := peerTrailLengths[0]
+				for _, secs := range peerTrailLengths {
+					if secs > int(w.peerTrailLength()/time.Second) {
+						next = secs
+						break
+					}
+				}
+				w.Config.Sync.PeerTrailSeconds = next
+				w.Config.Save()
+				w.openMenu = ""
+			},
+		},
+	}
+
+	names := w.syncPeerNames()
+	if len(names) == 0 {
+		return items
+	}
+	var colors []MenuItem
+	for i, name := range names {
+		swatch := w.peerColor(name, i)
+		colors = append(colors, MenuItem{
+			Label:  name,
+			Swatch: &swatch,
+			Action: func() {
+				w.openMenu = ""
+				w.pickPeerColor(name, swatch)
+			},
+		})
+	}
+	return append(items, MenuItem{Label: "Peer Colors", Submenu: colors})
+}
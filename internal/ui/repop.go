@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/ncruces/zenity"
+)
+
+// repopBannerTimeout is how long the reset offer stays up after a repop.
+const repopBannerTimeout = 10 * time.Minute
+
+// repopItem is one thing a server-wide repop makes stale.
+type repopItem struct {
+	label string
+	reset func()
+}
+
+// repopBannerRect sits below the out-of-bounds banner when both are up.
+func (w *Window) repopBannerRect() (x, y, width int, ok bool) {
+	if w.repopAt.IsZero() || time.Since(w.repopAt) > repopBannerTimeout {
+		return 0, 0, 0, false
+	}
+	x, y, width = w.boundsBannerRect()
+	if w.outOfBounds {
+		y += w.bannerHeight() + 4
+	}
+	return x, y, width, true
+}
+
+func (w *Window) handleRepopBannerClick(mx, my int) bool {
+	x, y, width, ok := w.repopBannerRect()
+	if !ok || mx < x || mx >= x+width || my < y || my >= y+w.bannerHeight() {
+		return false
+	}
+	w.resetAfterRepop()
+	return true
+}
+
+func (w *Window) drawRepopBanner(screen *ebiten.Image) {
+	x, y, width, ok := w.repopBannerRect()
+	if !ok {
+		return
+	}
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), float32(w.bannerHeight()), color.RGBA{110, 70, 10, 230}, false)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(width), float32(w.bannerHeight()), 1, claimColor, false)
+	msg := fmt.Sprintf("Server repop at %s - camps are open  [Reset Camps...]", w.repopAt.Format("15:04"))
+	w.drawBannerText(screen, msg, x, y, color.RGBA{255, 240, 220, 255})
+}
+
+// repopItems lists every camp claim and waiting list, in every zone, since a
+// repop frees all camps at once.
+func (w *Window) repopItems() []repopItem {
+	zones := make(map[string]bool)
+	for zone := range w.Config.CampClaims {
+		zones[zone] = true
+	}
+	for zone := range w.Config.CampLists {
+		zones[zone] = true
+	}
+	sorted := make([]string, 0, len(zones))
+	for zone := range zones {
+		sorted = append(sorted, zone)
+	}
+	sort.Strings(sorted)
+
+	var items []repopItem
+	for _, zone := range sorted {
+		for _, c := range w.Config.CampClaims[zone] {
+			zone, camp := zone, c.Camp
+			items = append(items, repopItem{
+				label: fmt.Sprintf("Claim: %s - %s (%s, held %s)", camp, c.Claimer, zone, formatHeld(time.Since(c.Started))),
+				reset: func() { w.releaseCamp(zone, camp) },
+			})
+		}
+		for _, l := range w.Config.CampLists[zone] {
+			if len(l.Entries) == 0 {
+				continue
+			}
+			zone, camp := zone, l.Camp
+			items = append(items, repopItem{
+				label: fmt.Sprintf("List: %s (%s, %d waiting)", camp, zone, len(l.Entries)),
+				reset: func() { w.deleteCampList(zone, camp) },
+			})
+		}
+	}
+	return items
+}
+
+// resetAfterRepop confirms which claims and lists to clear, all checked by
+// default. Either way the banner goes away.
+func (w *Window) resetAfterRepop() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+	w.repopAt = time.Time{}
+
+	items := w.repopItems()
+	if len(items) == 0 {
+		zenity.Info("No camp claims or lists to reset.", zenity.Title("Server Repop"))
+		return
+	}
+	labels := make([]string, len(items))
+	for i, it := range items {
+		labels[i] = it.label
+	}
+	chosen, err := zenity.ListMultiple(
+		"The server repopped. Reset these?",
+		labels,
+		zenity.Title("Server Repop"),
+		zenity.DefaultItems(labels...),
+		zenity.CheckList(),
+		zenity.OKLabel("Reset"),
+		zenity.Height(420),
+	)
+	if err != nil {
+		return
+	}
+	picked := make(map[string]bool, len(chosen))
+	for _, c := range chosen {
+		picked[c] = true
+	}
+	count := 0
+	for _, it := range items {
+		if picked[it.label] {
+			it.reset()
+			count++
+		}
+	}
+	fmt.Printf("🌋 Reset %d camp claims/lists after repop\n", count)
+}
@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// The map can turn about the screen center: by a fixed angle
+// (map_rotation, degrees clockwise), or so the player's heading points up
+// (heading_up). Everything drawn on the map goes through toScreen and every
+// click through toWorld, so lines, labels, markers and trails turn together;
+// text stays upright.
+
+const (
+	rotateStep   = 15.0 // Degrees per rotate key
+	rotationRate = 8.0  // How quickly the view turns to a new angle (per second)
+
+	compassRoseRadius = 22
+	compassRoseMargin = 12
+)
+
+// viewRotation is the angle the map is drawn at, with its sine and cosine.
+type viewRotation struct {
+	angle    float64 // Radians, clockwise on screen
+	sin, cos float64
+}
+
+var northUp = viewRotation{cos: 1}
+
+func (r *viewRotation) set(angle float64) {
+	r.angle = math.Remainder(angle, 2*math.Pi)
+	r.sin, r.cos = math.Sincos(r.angle)
+}
+
+// toScreen converts a map position to the screen, around center (cx, cy).
+func (w *Window) toScreen(x, y, cx, cy float64) (float64, float64) {
+	r := &w.rotation
+	dx, dy := (x-w.CamX)*w.Zoom, (y-w.CamY)*w.Zoom
+	return dx*r.cos - dy*r.sin + cx, dx*r.sin + dy*r.cos + cy
+}
+
+// toScreen32 is toScreen for vector drawing.
+func (w *Window) toScreen32(x, y, cx, cy float64) (float32, float32) {
+	sx, sy := w.toScreen(x, y, cx, cy)
+	return float32(sx), float32(sy)
+}
+
+// toWorld converts a screen position to the map, around center (cx, cy).
+func (w *Window) toWorld(sx, sy, cx, cy float64) (float64, float64) {
+	dx, dy := w.screenDeltaToWorld((sx-cx)/w.Zoom, (sy-cy)/w.Zoom)
+	return dx + w.CamX, dy + w.CamY
+}
+
+// screenDeltaToWorld turns a screen direction into the map's (no zoom).
+func (w *Window) screenDeltaToWorld(dx, dy float64) (float64, float64) {
+	r := &w.rotation
+	return dx*r.cos + dy*r.sin, -dx*r.sin + dy*r.cos
+}
+
+// fillMapSquare fills a square of the map (top-left corner x, y) as it
+// lies on the turned screen.
+func (w *Window) fillMapSquare(dst *ebiten.Image, cx, cy, x, y, size float64, c color.Color) {
+	var path vector.Path
+	for i, corner := range [4][2]float64{{x, y}, {x + size, y}, {x + size, y + size}, {x, y + size}} {
+		sx, sy := w.toScreen32(corner[0], corner[1], cx, cy)
+		if i == 0 {
+			path.MoveTo(sx, sy)
+		} else {
+			path.LineTo(sx, sy)
+		}
+	}
+	path.Close()
+	op := &vector.DrawPathOptions{}
+	op.ColorScale.ScaleWithColor(c)
+	vector.FillPath(dst, &path, nil, op)
+}
+
+// rotationTarget is the angle the view should be at now.
+func (w *Window) rotationTarget() float64 {
+	if w.Config.HeadingUp && w.LogReader != nil && !w.browsing() {
+		return -math.Pi/2 - w.LogReader.State().Heading
+	}
+	return w.Config.MapRotation * math.Pi / 180
+}
+
+// updateRotation turns the view toward its target the short way round,
+// easing so heading-up doesn't snap with every /loc.
+func (w *Window) updateRotation() {
+	target := w.rotationTarget()
+	diff := math.Remainder(target-w.rotation.angle, 2*math.Pi)
+	if math.Abs(diff) < 0.001 {
+		if w.rotation.angle != target {
+			w.rotation.set(target)
+		}
+		return
+	}
+	t := 1 - math.Exp(-rotationRate/float64(ebiten.TPS()))
+	w.rotation.set(w.rotation.angle + diff*t)
+}
+
+// rotateView turns the fixed rotation by degrees (clockwise), leaving
+// heading-up.
+func (w *Window) rotateView(degrees float64) {
+	w.Config.HeadingUp = false
+	w.Config.MapRotation = math.Mod(w.Config.MapRotation+degrees+360, 360)
+	w.Config.Save()
+	fmt.Printf("🧭 Map rotation: %.0f°\n", w.Config.MapRotation)
+}
+
+// setNorthUp undoes any rotation.
+func (w *Window) setNorthUp() {
+	w.Config.HeadingUp = false
+	w.Config.MapRotation = 0
+	w.Config.Save()
+	fmt.Println("🧭 Map rotation: north up")
+}
+
+func (w *Window) toggleHeadingUp() {
+	w.Config.HeadingUp = !w.Config.HeadingUp
+	w.Config.Save()
+	fmt.Printf("🧭 Heading up %s\n", map[bool]string{true: "ON", false: "OFF"}[w.Config.HeadingUp])
+}
+
+func (w *Window) rotationLabel() string {
+	switch {
+	case w.Config.HeadingUp:
+		return "Heading Up"
+	case w.Config.MapRotation == 0:
+		return "North Up"
+	}
+	return fmt.Sprintf("%.0f°", w.Config.MapRotation)
+}
+
+// rotationMenuItems are the View > Rotation choices.
+func (w *Window) rotationMenuItems() []MenuItem {
+	return []MenuItem{
+		{Label: "North Up", Action: func() { w.setNorthUp(); w.openMenu = "" }},
+		{Label: fmt.Sprintf("Heading Up: %s", map[bool]string{true: "ON", false: "OFF"}[w.Config.HeadingUp]), Action: func() { w.toggleHeadingUp(); w.openMenu = "" }},
+		{Label: fmt.Sprintf("Rotate Left (%s)", w.hotkeyLabel(ActionRotateLeft)), Action: func() { w.rotateView(-rotateStep); w.openMenu = "" }},
+		{Label: fmt.Sprintf("Rotate Right (%s)", w.hotkeyLabel(ActionRotateRight)), Action: func() { w.rotateView(rotateStep); w.openMenu = "" }},
+	}
+}
+
+// compassRoseCenter is the rose's place in the bottom-left corner.
+func (w *Window) compassRoseCenter() (float32, float32) {
+	return compassRoseMargin + compassRoseRadius, float32(w.Height) - compassRoseMargin - compassRoseRadius
+}
+
+// handleCompassRoseClick turns the map back north up.
+func (w *Window) handleCompassRoseClick(mx, my int) bool {
+	if w.rotation.angle == 0 && !w.Config.HeadingUp {
+		return false
+	}
+	x, y := w.compassRoseCenter()
+	if math.Hypot(float64(mx)-float64(x), float64(my)-float64(y)) > compassRoseRadius {
+		return false
+	}
+	w.setNorthUp()
+	return true
+}
+
+// drawCompassRose shows where north is while the map is turned; it's
+// hidden when north is up anyway.
+func (w *Window) drawCompassRose(screen *ebiten.Image) {
+	if w.rotation.angle == 0 && !w.Config.HeadingUp {
+		return
+	}
+	x, y := w.compassRoseCenter()
+	r := float32(compassRoseRadius)
+	vector.DrawFilledCircle(screen, x, y, r, color.RGBA{0, 0, 0, 180}, w.antiAlias)
+	vector.StrokeCircle(screen, x, y, r, 1.5, color.RGBA{200, 200, 200, 255}, w.antiAlias)
+
+	// Map north on screen in red, then the other three points a quarter turn apart
+	north := -math.Pi/2 + w.rotation.angle
+	for i := range 4 {
+		a := north + float64(i)*math.Pi/2
+		c := color.RGBA{200, 200, 200, 255}
+		if i == 0 {
+			c = color.RGBA{230, 50, 50, 255}
+		}
+		tipX, tipY := x+float32(math.Cos(a))*r*0.45, y+float32(math.Sin(a))*r*0.45
+		vector.StrokeLine(screen, x, y, tipX, tipY, 2, c, w.antiAlias)
+	}
+	nx, ny := x+float32(math.Cos(north))*(r-8), y+float32(math.Sin(north))*(r-8)
+	w.drawUIText(screen, "N", int(nx)-w.uiTextWidth("N")/2, int(ny)+5, color.RGBA{230, 50, 50, 255})
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// sowSpeedFactor is roughly how much faster Spirit of Wolf runs than unbuffed.
+const sowSpeedFactor = 1.4
+
+var rulerColor = color.RGBA{0, 255, 255, 255}
+
+// ruler measures between two clicked points. With only the first point set
+// it follows the mouse.
+type ruler struct {
+	active bool
+	points int // 0, 1 or 2 set
+	ax, ay float64
+	bx, by float64
+}
+
+func (w *Window) toggleRuler() {
+	w.ruler = ruler{active: !w.ruler.active}
+	if w.ruler.active {
+		w.placingMarker, w.placingWaypoint = false, false
+		w.drawing = nil
+		fmt.Println("📏 Ruler ON - Left-click two points")
+	}
+}
+
+// rulerClick sets the next point; a third click starts a new measurement.
+func (w *Window) rulerClick(worldX, worldY float64) {
+	switch w.ruler.points {
+	case 1:
+		w.ruler.bx, w.ruler.by = worldX, worldY
+		w.ruler.points = 2
+		fmt.Printf("📏 %s\n", w.rulerSummary(w.ruler.ax, w.ruler.ay, worldX, worldY))
+	default:
+		w.ruler.ax, w.ruler.ay = worldX, worldY
+		w.ruler.points = 1
+	}
+}
+
+// rulerSummary gives the distance and run times, e.g.
+// "240 units: run ~8s, SoW ~6s".
+func (w *Window) rulerSummary(ax, ay, bx, by float64) string {
+	dist := math.Hypot(bx-ax, by-ay)
+	speed := w.travelOptions().RunSpeed
+	return fmt.Sprintf("%.0f units: run %s, SoW %s", dist,
+		formatTravelTime(dist/speed), formatTravelTime(dist/(speed*sowSpeedFactor)))
+}
+
+// drawRuler draws the measured segment with its summary at the midpoint.
+func (w *Window) drawRuler(screen *ebiten.Image, cx, cy float64) {
+	if !w.ruler.active || w.ruler.points == 0 {
+		return
+	}
+	ax, ay := w.ruler.ax, w.ruler.ay
+	bx, by := w.ruler.bx, w.ruler.by
+	if w.ruler.points == 1 {
+		mx, my := ebiten.CursorPosition()
+		bx, by = w.toWorld(float64(mx), float64(my), cx, cy)
+	}
+
+	sax, say := w.toScreen32(ax, ay, cx, cy)
+	sbx, sby := w.toScreen32(bx, by, cx, cy)
+	vector.StrokeLine(screen, sax, say, sbx, sby, 2, rulerColor, w.antiAlias)
+	vector.StrokeCircle(screen, sax, say, 4, 1.5, rulerColor, w.antiAlias)
+	vector.StrokeCircle(screen, sbx, sby, 4, 1.5, rulerColor, w.antiAlias)
+
+	label := w.rulerSummary(ax, ay, bx, by)
+	lx, ly := int((sax+sbx)/2)+8, int((say+sby)/2)-8
+	rowH := w.uiLineHeight() + 1
+	vector.DrawFilledRect(screen, float32(lx-3), float32(ly-rowH+4), float32(w.uiTextWidth(label)+6), float32(rowH), color.RGBA{0, 0, 0, 180}, false)
+	w.drawUITextIn(screen, label, lx, ly-rowH+4, rowH, rulerColor)
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/rules"
+)
+
+// defaultRuleMarkerColor is used for rule markers without a color.
+const defaultRuleMarkerColor = "#ff00ff"
+
+// updateRules carries out the actions of user log rules that fired.
+func (w *Window) updateRules() {
+	if w.LogReader == nil {
+		return
+	}
+	for _, hit := range w.LogReader.DrainRuleHits() {
+		text := hit.Text()
+		w.playRuleSound(hit.Rule)
+		switch hit.Rule.Action {
+		case rules.ActionMarker:
+			w.addRuleMarker(hit.Rule, hit.Zone, hit.X, hit.Y, text)
+		case rules.ActionSay:
+			if w.speaker == nil {
+				fmt.Printf("📜 %s: %s (turn on Spoken Announcements to hear it)\n", hit.Rule.Name, text)
+			}
+			w.announce(text)
+		default:
+			fmt.Printf("📜 %s: %s\n", hit.Rule.Name, text)
+		}
+	}
+}
+
+// addRuleMarker drops a marker for a rule, unless the same label is already
+// there (spawn messages tend to repeat).
+func (w *Window) addRuleMarker(rule config.Rule, zone string, x, y float64, label string) {
+	if zone == "" || config.IsDuplicateMarker(label, x, y, w.Config.ZoneMarkers(zone)) {
+		return
+	}
+	markerColor := rule.Color
+	if markerColor == "" {
+		markerColor = defaultRuleMarkerColor
+	}
+	marker := config.Marker{
+		ID:    config.NewMarkerID(),
+		X:     x,
+		Y:     y,
+		Label: label,
+		Color: markerColor,
+		Shape: w.markerShape,
+	}
+	w.Config.SetZoneMarkers(zone, append(w.Config.ZoneMarkers(zone), marker))
+	w.syncMarker(zone, marker)
+	w.Config.Save()
+	fmt.Printf("📜 %s: marked '%s' in %s\n", rule.Name, label, zone)
+}
@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/maps"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/ncruces/zenity"
+)
+
+// outOfBoundsMargin is how far past the map's extent a /loc may be before
+// it's treated as a missed zone change (maps don't always reach the walls).
+const outOfBoundsMargin = 300.0
+
+// checkPositionBounds flags positions that can't be on the current map.
+// Positions left over from before the last zone change are ignored until
+// the first fresh /loc arrives.
+func (w *Window) checkPositionBounds() {
+	w.outOfBounds = false
+	if w.LogReader == nil || w.MapData == nil || w.browsing() {
+		return
+	}
+	s := w.LogReader.State()
+	if s.X == w.zoneEntryX && s.Y == w.zoneEntryY {
+		return
+	}
+	b := maps.ZoneBounds{MinX: w.MapData.MinX, MaxX: w.MapData.MaxX, MinY: w.MapData.MinY, MaxY: w.MapData.MaxY}
+	if !b.Contains(s.X, s.Y, outOfBoundsMargin) {
+		if !w.outOfBoundsLogged {
+			fmt.Printf("⚠️  Position (%.1f, %.1f) is outside the %s map - missed zone change or wrong map?\n", -s.Y, -s.X, w.CurrentZone)
+			w.outOfBoundsLogged = true
+		}
+		w.outOfBounds = true
+	}
+}
+
+// boundsBannerRect is the warning banner's position (below the menu bar,
+// centered); the other banners stack under it. They widen with the UI font.
+func (w *Window) boundsBannerRect() (x, y, width int) {
+	width = int(math.Round(520 * w.uiSize() / uiBaseSize))
+	return (w.Width - width) / 2, w.menuBarHeight + 4, width
+}
+
+// bannerHeight is the height of a banner (22 at the medium font size).
+func (w *Window) bannerHeight() int {
+	return max(22, w.uiLineHeight()+7)
+}
+
+// drawBannerText draws a banner's message, centered vertically.
+func (w *Window) drawBannerText(screen *ebiten.Image, msg string, x, y int, c color.Color) {
+	w.drawUITextIn(screen, msg, x+10, y, w.bannerHeight(), c)
+}
+
+// handleBoundsBannerClick opens the zone search when the banner is clicked.
+func (w *Window) handleBoundsBannerClick(mx, my int) bool {
+	if !w.outOfBounds {
+		return false
+	}
+	x, y, width := w.boundsBannerRect()
+	if mx < x || mx >= x+width || my < y || my >= y+w.bannerHeight() {
+		return false
+	}
+	w.findZoneForPosition()
+	return true
+}
+
+func (w *Window) drawBoundsBanner(screen *ebiten.Image) {
+	if !w.outOfBounds {
+		return
+	}
+	x, y, width := w.boundsBannerRect()
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), float32(w.bannerHeight()), color.RGBA{120, 20, 20, 230}, false)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(width), float32(w.bannerHeight()), 1, color.RGBA{255, 80, 80, 255}, false)
+	w.drawBannerText(screen, "Position is off this map (missed zone change?)  [Find Zone...]", x, y, color.RGBA{255, 230, 230, 255})
+}
+
+// findZoneForPosition lists the zones whose maps contain the player's
+// position and switches to the one picked.
+func (w *Window) findZoneForPosition() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	if !w.zoneIndexReady("Find Zone") {
+		return
+	}
+
+	s := w.LogReader.State()
+	candidates := w.boundsIndex.Containing(s.X, s.Y, 0)
+	if len(candidates) == 0 {
+		zenity.Info(fmt.Sprintf("No map contains /loc %.0f, %.0f.", -s.Y, -s.X), zenity.Title("Find Zone"))
+		return
+	}
+
+	items := make([]string, len(candidates))
+	names := make([]string, len(candidates))
+	for i, c := range candidates {
+		names[i] = w.zoneNameForCode(c.FileCode)
+		items[i] = fmt.Sprintf("%s (%s)", names[i], c.FileCode)
+	}
+	choice, err := zenity.List(
+		fmt.Sprintf("Zones whose map contains /loc %.0f, %.0f:", -s.Y, -s.X),
+		items,
+		zenity.Title("Find Zone"),
+		zenity.Height(400),
+	)
+	if err != nil {
+		return
+	}
+
+	for i, item := range items {
+		if item == choice {
+			// Correct the tracked zone; its ZoneChanged event loads the map
+			w.LogReader.SetZone(names[i])
+			fmt.Printf("🌍 Zone corrected to '%s'\n", names[i])
+			return
+		}
+	}
+}
+
+// zoneNameForCode turns a map file code into a zone name in the form the
+// log uses, so markers and notes saved under that name still match.
+func (w *Window) zoneNameForCode(code string) string {
+	name := maps.GetZoneName(code)
+	if name == "" {
+		return code
+	}
+	return w.logZoneName(name)
+}
+
+// logZoneName turns a map_keys.json zone name into the form the log uses.
+func (w *Window) logZoneName(name string) string {
+	if known := w.Config.MarkerZone(name); known != "" {
+		return known
+	}
+	for known := range w.Config.ZoneNotes {
+		if strings.EqualFold(known, name) {
+			return known
+		}
+	}
+
+	// map_keys.json is lowercase; the log capitalizes each word
+	words := strings.Fields(name)
+	for i, word := range words {
+		if word != "of" && word != "the" || i == 0 {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/integrations/screenshotloc"
+)
+
+// startScreenshotOCR watches <EQ>/Screenshots when the integration is enabled.
+func (w *Window) startScreenshotOCR() {
+	if !w.Config.ScreenshotOCR || w.Config.EQPath == "" || w.ocrWatcher != nil {
+		return
+	}
+	backend, err := screenshotloc.NewCommandBackend(w.Config.OCRCommand)
+	if err != nil {
+		w.addNotice(fmt.Sprintf("Screenshot OCR disabled: %v", err))
+		return
+	}
+	dir := filepath.Join(w.Config.EQPath, "Screenshots")
+	w.ocrWatcher = screenshotloc.Watch(dir, backend, 2*time.Second)
+	fmt.Printf("📷 Watching %s for /loc screenshots\n", dir)
+}
+
+func (w *Window) stopScreenshotOCR() {
+	if w.ocrWatcher != nil {
+		w.ocrWatcher.Stop()
+		w.ocrWatcher = nil
+	}
+}
+
+// updateScreenshotOCR drops a marker for each screenshot /loc in the player's zone.
+func (w *Window) updateScreenshotOCR() {
+	if w.ocrWatcher == nil || w.logZone == "" {
+		return
+	}
+	select {
+	case r := <-w.ocrWatcher.Results:
+		marker := config.Marker{
+			ID:    config.NewMarkerID(),
+			X:     -r.LocX,
+			Y:     -r.LocY,
+			Label: fmt.Sprintf("Screenshot %s", time.Now().Format("15:04")),
+			Color: w.markerColor,
+			Shape: w.markerShape,
+		}
+		w.Config.SetZoneMarkers(w.logZone, append(w.Config.ZoneMarkers(w.logZone), marker))
+		if err := w.Config.Save(); err != nil {
+			fmt.Printf("❌ Error saving marker: %v\n", err)
+			return
+		}
+		fmt.Printf("📷 Marker from %s at (%.1f, %.1f) in %s\n", filepath.Base(r.Path), r.LocY, r.LocX, w.logZone)
+	default:
+	}
+}
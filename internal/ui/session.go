@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/parser"
+	"github.com/ncruces/zenity"
+)
+
+// exportSession writes the live session (position, corpses, camp timers,
+// waypoint, posted locs and trails) to a file another machine can import.
+func (w *Window) exportSession() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	w.saveBreadcrumbs() // The current trail goes in with the rest
+	s := &config.SessionFile{
+		CampClaims:  w.Config.CampClaims,
+		CampLists:   w.Config.CampLists,
+		Breadcrumbs: config.LoadAllBreadcrumbs(),
+	}
+	if w.LogReader != nil {
+		characters := w.LogReader.AllCharacters()
+		p := characters[0]
+		s.Character, s.Zone = p.Character, p.Zone
+		s.X, s.Y, s.Z, s.Heading = p.X, p.Y, p.Z, p.Heading
+		for _, c := range characters {
+			for _, corpse := range c.Corpses {
+				s.Corpses = append(s.Corpses, config.SessionPoint{Label: c.Character, Zone: corpse.Zone, X: corpse.X, Y: corpse.Y, Time: corpse.Time})
+			}
+		}
+	}
+	if t := w.Nav.Target; t != nil {
+		s.Waypoint = &config.SessionPoint{Label: t.Label, Zone: w.logZone, X: t.X, Y: t.Y}
+	}
+	for speaker, loc := range w.chatLocs {
+		s.ChatLocs = append(s.ChatLocs, config.SessionPoint{Label: speaker, Zone: loc.zone, X: loc.x, Y: loc.y, Time: loc.posted})
+	}
+
+	path, err := zenity.SelectFileSave(
+		zenity.Title("Export Session"),
+		zenity.Filename("nox-session.json"),
+		zenity.ConfirmOverwrite(),
+		zenity.FileFilter{Name: "Session files", Patterns: []string{"*.json"}},
+	)
+	if err != nil || path == "" {
+		return
+	}
+	if err := config.WriteSessionFile(path, s); err != nil {
+		fmt.Printf("❌ Error exporting session: %v\n", err)
+		zenity.Error(err.Error(), zenity.Title("Export Session"))
+		return
+	}
+	fmt.Printf("💾 Exported session to %s: %s\n", path, sessionSummary(s))
+}
+
+// importSession continues a session exported on another machine. Corpses
+// are added to the ones already known; camp claims, waiting lists and
+// trails replace this machine's for the zones the file covers.
+func (w *Window) importSession() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	path, err := zenity.SelectFile(
+		zenity.Title("Import Session"),
+		zenity.FileFilter{Name: "Session files", Patterns: []string{"*.json"}},
+	)
+	if err != nil || path == "" {
+		return
+	}
+	s, err := config.ReadSessionFile(path)
+	if err != nil {
+		fmt.Printf("❌ Error importing session: %v\n", err)
+		zenity.Error(err.Error(), zenity.Title("Import Session"))
+		return
+	}
+
+	err = zenity.Question(
+		fmt.Sprintf("Continue the session exported %s?\n\n%s\n\nCamp claims, waiting lists and trails in the file replace this machine's for the same zones.",
+			s.Exported.Format("Jan 2 15:04"), sessionSummary(s)),
+		zenity.Title("Import Session"),
+		zenity.OKLabel("Continue Session"),
+	)
+	if err != nil {
+		return
+	}
+	w.resumeSession(s)
+}
+
+func (w *Window) resumeSession(s *config.SessionFile) {
+	// Trails first, so entering the session's zone below loads its trail
+	for key, trail := range s.Breadcrumbs {
+		if err := config.SaveBreadcrumbsKey(key, trail); err != nil {
+			fmt.Printf("❌ Error saving breadcrumbs: %v\n", err)
+		}
+	}
+	if trail, ok := s.Breadcrumbs[config.BreadcrumbKey(w.trailCharacter, w.CurrentZone)]; ok && w.CurrentZone != "" {
+		w.Breadcrumbs = append(w.Breadcrumbs[:0], trail...)
+		w.unsavedBreadcrumbs = 0
+	}
+
+	for zone, claims := range s.CampClaims {
+		w.Config.CampClaims[zone] = claims
+	}
+	for zone, lists := range s.CampLists {
+		w.Config.CampLists[zone] = lists
+	}
+	w.Config.Save()
+
+	if w.LogReader != nil {
+		states := []parser.PlayerState{{Character: s.Character, Zone: s.Zone, X: s.X, Y: s.Y, Z: s.Z, Heading: s.Heading}}
+		index := map[string]int{s.Character: 0}
+		for _, c := range s.Corpses {
+			i, ok := index[c.Label]
+			if !ok {
+				i = len(states)
+				index[c.Label] = i
+				states = append(states, parser.PlayerState{Character: c.Label})
+			}
+			states[i].Corpses = append(states[i].Corpses, parser.Corpse{X: c.X, Y: c.Y, Zone: c.Zone, Time: c.Time})
+		}
+		w.LogReader.Resume(states)
+	}
+
+	if s.Waypoint != nil {
+		w.Nav.Set(s.Waypoint.X, s.Waypoint.Y, s.Waypoint.Label)
+	}
+	for _, loc := range s.ChatLocs {
+		if w.chatLocs == nil {
+			w.chatLocs = make(map[string]chatLoc)
+		}
+		if time.Since(loc.Time) < w.chatLocTimeout() {
+			w.chatLocs[loc.Label] = chatLoc{zone: loc.Zone, x: loc.X, y: loc.Y, posted: loc.Time}
+		}
+	}
+	fmt.Printf("📥 Resumed session: %s\n", sessionSummary(s))
+}
+
+// sessionSummary describes a session file in one line.
+func sessionSummary(s *config.SessionFile) string {
+	who := s.Character
+	if who == "" {
+		who = "Unknown character"
+	}
+	where := s.Zone
+	if where == "" {
+		where = "no zone"
+	}
+	claims := 0
+	for _, list := range s.CampClaims {
+		claims += len(list)
+	}
+	return fmt.Sprintf("%s in %s, %d corpse(s), %d camp claim(s), %d trail(s)", who, where, len(s.Corpses), claims, len(s.Breadcrumbs))
+}
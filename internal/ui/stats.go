@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/parser"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const statsSaveEvery = time.Minute
+
+var statsColor = color.RGBA{140, 255, 140, 255}
+
+// statsFor returns a character's stats, loading them on first use.
+func (w *Window) statsFor(character string) *config.CharacterStats {
+	if w.charStats == nil {
+		w.charStats = make(map[string]*config.CharacterStats)
+	}
+	s, ok := w.charStats[character]
+	if !ok {
+		s = config.LoadCharacterStats(character)
+		w.charStats[character] = s
+	}
+	return s
+}
+
+// updateStats counts kills, deaths and experience for every tracked
+// character. The primary character's session runs while the app is open.
+func (w *Window) updateStats() {
+	if w.LogReader == nil {
+		return
+	}
+	now := time.Now()
+	if primary := w.LogReader.State().Character; primary != "" {
+		w.statsFor(primary).Touch(now)
+	}
+
+	for _, ev := range w.LogReader.DrainStats() {
+		if ev.Character == "" {
+			continue
+		}
+		s := w.statsFor(ev.Character)
+		s.Touch(now)
+		switch ev.Kind {
+		case parser.StatKill:
+			s.Session.Kills++
+			s.LastKill = ev.Target
+		case parser.StatDeath:
+			s.Session.Deaths++
+		case parser.StatExperience:
+			s.Session.Experience++
+		}
+	}
+
+	if time.Since(w.statsSaved) >= statsSaveEvery {
+		w.saveStats()
+	}
+}
+
+// saveStats writes every loaded character's stats. The session clock moves
+// even without events, so this runs on a timer rather than on changes.
+func (w *Window) saveStats() {
+	w.statsSaved = time.Now()
+	if len(w.charStats) == 0 || w.simulated {
+		return
+	}
+	for _, s := range w.charStats {
+		if err := s.Save(); err != nil {
+			fmt.Printf("❌ Error saving stats for %s: %v\n", s.Character, err)
+		}
+	}
+}
+
+// newStatsSession starts a fresh session for the primary character.
+func (w *Window) newStatsSession() {
+	if w.LogReader == nil {
+		return
+	}
+	character := w.LogReader.State().Character
+	if character == "" {
+		return
+	}
+	s := w.statsFor(character)
+	s.NewSession(time.Now())
+	w.saveStats()
+	fmt.Printf("📊 New stats session for %s\n", s.Character)
+}
+
+func perHour(n int, d time.Duration) string {
+	if d < time.Minute {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f/hr", float64(n)/d.Hours())
+}
+
+func formatSessionTime(d time.Duration) string {
+	return fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// statsPanelLines describes the primary character's session.
+func (w *Window) statsPanelLines() []string {
+	if !w.showStats || w.LogReader == nil {
+		return nil
+	}
+	character := w.LogReader.State().Character
+	if character == "" {
+		return nil
+	}
+	s := w.statsFor(character)
+	d := s.Duration()
+	lines := []string{
+		fmt.Sprintf("Session: %s, %s", s.Character, formatSessionTime(d)),
+		fmt.Sprintf("Kills: %d (%s)", s.Session.Kills, perHour(s.Session.Kills, d)),
+		fmt.Sprintf("Deaths: %d", s.Session.Deaths),
+		fmt.Sprintf("XP messages: %d (%s)", s.Session.Experience, perHour(s.Session.Experience, d)),
+	}
+	if s.LastKill != "" {
+		lines = append(lines, "Last kill: "+s.LastKill)
+	}
+	if s.Sessions > 0 {
+		lines = append(lines, fmt.Sprintf("Before: %d sessions, %d kills, %d deaths",
+			s.Sessions, s.Totals.Kills, s.Totals.Deaths))
+	}
+	return lines
+}
+
+// drawStatsPanel draws the session stats in the bottom-left corner, above
+// the corpses panel when that's showing.
+func (w *Window) drawStatsPanel(screen *ebiten.Image) {
+	lines := w.statsPanelLines()
+	if len(lines) == 0 {
+		return
+	}
+	width, height := w.panelSize(lines)
+
+	bottom := w.Height - 8
+	if corpseLines, _ := w.corpsePanelLines(); len(corpseLines) > 0 {
+		_, py, _, _ := w.corpsePanelRect(corpseLines)
+		bottom = py - 8
+	}
+	px, py := 8, bottom-height
+
+	vector.DrawFilledRect(screen, float32(px), float32(py), float32(width), float32(height), color.RGBA{0, 0, 0, 180}, false)
+	vector.StrokeRect(screen, float32(px), float32(py), float32(width), float32(height), 1, statsColor, false)
+	w.drawPanelLines(screen, lines, px, py, func(i int) color.Color {
+		if i == 0 {
+			return statsColor
+		}
+		return color.RGBA{255, 255, 255, 255}
+	})
+}
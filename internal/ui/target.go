@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/parser"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// targetTimeout is how long the last considered mob stays on the map.
+const targetTimeout = 5 * time.Minute
+
+var conColors = map[string]color.RGBA{
+	parser.ConGreen:     {0, 200, 0, 255},
+	parser.ConLightBlue: {100, 200, 255, 255},
+	parser.ConBlue:      {40, 80, 255, 255},
+	parser.ConWhite:     {240, 240, 240, 255},
+	parser.ConYellow:    {255, 220, 0, 255},
+	parser.ConRed:       {255, 40, 40, 255},
+}
+
+func conColor(con string) color.RGBA {
+	if c, ok := conColors[con]; ok {
+		return c
+	}
+	return color.RGBA{180, 180, 180, 255}
+}
+
+// currentTarget returns the primary character's last considered mob if it's
+// recent and in the shown zone.
+func (w *Window) currentTarget() *parser.Target {
+	if w.LogReader == nil {
+		return nil
+	}
+	t := w.LogReader.State().Target
+	if t == nil || t.Zone != w.CurrentZone || time.Since(t.Time) > targetTimeout {
+		return nil
+	}
+	return t
+}
+
+// drawTarget marks where the considered mob is estimated to stand.
+func (w *Window) drawTarget(screen *ebiten.Image, cx, cy float64) {
+	t := w.currentTarget()
+	if t == nil {
+		return
+	}
+	c := conColor(t.Con)
+	x, y := w.toScreen32(t.X, t.Y, cx, cy)
+	vector.DrawFilledCircle(screen, x, y, 5, c, w.antiAlias)
+	vector.StrokeCircle(screen, x, y, 9, 1.5, c, w.antiAlias)
+	w.drawUIText(screen, t.Name, int(x)+12, int(y)+4, c)
+}
+
+// markTarget turns the considered mob's estimated spot into a marker in its
+// con color.
+func (w *Window) markTarget() {
+	t := w.currentTarget()
+	if t == nil {
+		return
+	}
+	marker := config.Marker{
+		ID:       config.NewMarkerID(),
+		X:        t.X,
+		Y:        t.Y,
+		Label:    t.Name,
+		Color:    config.HexColor(conColor(t.Con)),
+		Shape:    w.markerShape,
+		Category: w.markerCategory,
+	}
+	w.pushUndo(fmt.Sprintf("mark '%s'", t.Name))
+	w.Config.SetZoneMarkers(w.CurrentZone, append(w.Config.ZoneMarkers(w.CurrentZone), marker))
+	w.syncMarker(w.CurrentZone, marker)
+	w.Config.Save()
+	fmt.Printf("📍 Marked '%s' at (%.1f, %.1f) in %s\n", t.Name, -t.Y, -t.X, w.CurrentZone)
+}
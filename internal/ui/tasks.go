@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/ncruces/zenity"
+)
+
+// taskPanelOrigin places the panel on the right, below the waypoint compass.
+func (w *Window) taskPanelOrigin() (int, int) {
+	return w.Width - w.taskPanelWidth() - 8, w.menuBarHeight + 110
+}
+
+// taskPanelWidth and taskRowHeight size the panel (300 and 18 at the
+// medium font size).
+func (w *Window) taskPanelWidth() int {
+	return int(math.Round(300 * w.uiSize() / uiBaseSize))
+}
+
+func (w *Window) taskRowHeight() int {
+	return max(18, w.uiLineHeight()+3)
+}
+
+// taskAddWidth is the width of the header's [+].
+func (w *Window) taskAddWidth() int {
+	return w.uiTextWidth("[+]") + 6
+}
+
+// findMarker returns the current zone's marker with the given ID.
+func (w *Window) findMarker(id string) (config.Marker, bool) {
+	for _, m := range w.Config.ZoneMarkers(w.CurrentZone) {
+		if m.ID == id {
+			return m, true
+		}
+	}
+	return config.Marker{}, false
+}
+
+// handleTaskPanelClick reacts to a click inside the tasks panel and reports
+// whether the click was consumed.
+func (w *Window) handleTaskPanelClick(mx, my int, right bool) bool {
+	if !w.showTasks || w.CurrentZone == "" {
+		return false
+	}
+	px, py := w.taskPanelOrigin()
+	tasks := w.Config.ZoneTasks[w.CurrentZone]
+	panelH := w.taskRowHeight() * (len(tasks) + 1)
+	if mx < px || mx >= px+w.taskPanelWidth() || my < py || my >= py+panelH {
+		return false
+	}
+
+	row := (my - py) / w.taskRowHeight()
+	if row == 0 {
+		// Header: "+" adds a task
+		if !right && mx >= px+w.taskPanelWidth()-w.taskAddWidth() {
+			w.addTask()
+		}
+		return true
+	}
+
+	i := row - 1
+	if right {
+		w.removeTask(i)
+		return true
+	}
+
+	// Checkbox toggles, the text jumps to the linked marker
+	if mx < px+24 {
+		w.Config.ZoneTasks[w.CurrentZone][i].Done = !tasks[i].Done
+		if err := w.Config.Save(); err != nil {
+			fmt.Printf("❌ Error saving tasks: %v\n", err)
+		}
+	} else if m, ok := w.findMarker(tasks[i].MarkerID); ok {
+		w.CamX = m.X
+		w.CamY = m.Y
+	}
+	return true
+}
+
+func (w *Window) addTask() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	taskText, err := zenity.Entry("Task description:", zenity.Title("New Task"))
+	if err != nil || taskText == "" {
+		return
+	}
+	task := config.Task{Text: taskText}
+
+	// Optionally link a marker in this zone
+	markers := w.Config.ZoneMarkers(w.CurrentZone)
+	if len(markers) > 0 {
+		labels := []string{"(none)"}
+		for _, m := range markers {
+			labels = append(labels, m.Label)
+		}
+		choice, err := zenity.List("Link to a marker (optional):", labels, zenity.Title("Link Marker"))
+		if err == nil && choice != "" && choice != "(none)" {
+			for _, m := range markers {
+				if m.Label == choice {
+					task.MarkerID = m.ID
+					break
+				}
+			}
+			if task.MarkerID != "" {
+				task.HideMarker = zenity.Question(
+					"Hide the marker once this task is checked off?",
+					zenity.Title("Link Marker"),
+					zenity.OKLabel("Hide"),
+					zenity.CancelLabel("Keep Visible"),
+				) == nil
+			}
+		}
+	}
+
+	w.Config.ZoneTasks[w.CurrentZone] = append(w.Config.ZoneTasks[w.CurrentZone], task)
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving tasks: %v\n", err)
+	} else {
+		fmt.Printf("☑️  Task added in %s: '%s'\n", w.CurrentZone, task.Text)
+	}
+}
+
+func (w *Window) removeTask(i int) {
+	tasks := w.Config.ZoneTasks[w.CurrentZone]
+	if i < 0 || i >= len(tasks) {
+		return
+	}
+
+	w.dialogOpen = true
+	err := zenity.Question(
+		fmt.Sprintf("Delete task '%s'?", tasks[i].Text),
+		zenity.Title("Confirm Delete"),
+		zenity.OKLabel("Delete"),
+		zenity.CancelLabel("Cancel"),
+	)
+	w.dialogOpen = false
+	w.lastMousePressed = true
+	if err != nil {
+		return
+	}
+
+	w.Config.ZoneTasks[w.CurrentZone] = append(tasks[:i], tasks[i+1:]...)
+	if len(w.Config.ZoneTasks[w.CurrentZone]) == 0 {
+		delete(w.Config.ZoneTasks, w.CurrentZone)
+	}
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving tasks: %v\n", err)
+	}
+}
+
+func (w *Window) drawTaskPanel(screen *ebiten.Image) {
+	if !w.showTasks || w.CurrentZone == "" {
+		return
+	}
+	px, py := w.taskPanelOrigin()
+	tasks := w.Config.ZoneTasks[w.CurrentZone]
+	panelH := w.taskRowHeight() * (len(tasks) + 1)
+
+	vector.DrawFilledRect(screen, float32(px), float32(py), float32(w.taskPanelWidth()), float32(panelH), color.RGBA{0, 0, 0, 180}, false)
+	vector.StrokeRect(screen, float32(px), float32(py), float32(w.taskPanelWidth()), float32(panelH), 1, color.RGBA{180, 180, 180, 255}, false)
+
+	done := 0
+	for _, t := range tasks {
+		if t.Done {
+			done++
+		}
+	}
+	header := fmt.Sprintf("Tasks (%d/%d)", done, len(tasks))
+	rowH, panelW := w.taskRowHeight(), w.taskPanelWidth()
+	w.drawUITextIn(screen, header, px+6, py, rowH, color.RGBA{255, 200, 0, 255})
+	w.drawUITextIn(screen, "[+]", px+panelW-w.taskAddWidth(), py, rowH, color.RGBA{0, 255, 0, 255})
+	labelX := px + 8 + w.uiTextWidth("[x]")
+
+	for i, t := range tasks {
+		rowY := py + (i+1)*rowH
+		box := "[ ]"
+		textColor := color.RGBA{255, 255, 255, 255}
+		if t.Done {
+			box = "[x]"
+			textColor = color.RGBA{130, 130, 130, 255}
+		}
+		label := t.Text
+		if m, ok := w.findMarker(t.MarkerID); ok {
+			label += " @ " + m.Label
+		}
+		label = w.clipUIText(label, px+panelW-6-labelX)
+		w.drawUITextIn(screen, box, px+4, rowY, rowH, textColor)
+		w.drawUITextIn(screen, label, labelX, rowY, rowH, textColor)
+	}
+}
@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"slices"
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/ncruces/zenity"
+)
+
+// uiTheme is the look of the window: the map background, how map lines and
+// labels are tinted, and the menus.
+type uiTheme struct {
+	name       string
+	background color.RGBA
+	lineTint   color.RGBA // Map lines are pulled lineTintAmount toward it; zero for none
+	labels     color.RGBA // Every map label in this color; zero keeps the map's
+
+	menuBar, menuText, menuHover    color.RGBA
+	menuPanel, menuBorder, menuItem color.RGBA // menuItem: the dropdown entry under the mouse
+}
+
+const (
+	themeClassic   = "classic"
+	themeCustom    = "custom"
+	lineTintAmount = 0.35
+)
+
+var uiThemes = []uiTheme{
+	{
+		name:       themeClassic,
+		background: color.RGBA{0, 0, 0, 255},
+		menuBar:    color.RGBA{240, 240, 240, 255},
+		menuText:   color.RGBA{0, 0, 0, 255},
+		menuHover:  color.RGBA{200, 200, 200, 255},
+		menuPanel:  color.RGBA{250, 250, 250, 255},
+		menuBorder: color.RGBA{180, 180, 180, 255},
+		menuItem:   color.RGBA{200, 200, 255, 255},
+	},
+	{
+		name:       "dark",
+		background: color.RGBA{0, 0, 0, 255},
+		menuBar:    color.RGBA{32, 32, 36, 255},
+		menuText:   color.RGBA{220, 220, 220, 255},
+		menuHover:  color.RGBA{62, 62, 70, 255},
+		menuPanel:  color.RGBA{40, 40, 46, 255},
+		menuBorder: color.RGBA{84, 84, 94, 255},
+		menuItem:   color.RGBA{58, 70, 112, 255},
+	},
+	{
+		name:       "light",
+		background: color.RGBA{245, 245, 245, 255},
+		labels:     color.RGBA{40, 40, 40, 255},
+		menuBar:    color.RGBA{250, 250, 250, 255},
+		menuText:   color.RGBA{0, 0, 0, 255},
+		menuHover:  color.RGBA{220, 220, 220, 255},
+		menuPanel:  color.RGBA{255, 255, 255, 255},
+		menuBorder: color.RGBA{200, 200, 200, 255},
+		menuItem:   color.RGBA{210, 225, 250, 255},
+	},
+}
+
+// theme returns the configured theme (classic if unset or unknown).
+func (w *Window) theme() uiTheme {
+	if w.Config.Theme == themeCustom {
+		return w.customTheme()
+	}
+	for _, t := range uiThemes {
+		if t.name == w.Config.Theme {
+			return t
+		}
+	}
+	return uiThemes[0]
+}
+
+// customTheme is classic with the colors from custom_theme laid over it.
+func (w *Window) customTheme() uiTheme {
+	t := uiThemes[0]
+	t.name = themeCustom
+	for _, f := range themeFields(&t, &w.Config.CustomTheme) {
+		if *f.hex != "" {
+			*f.color = config.ParseColor(*f.hex)
+		}
+	}
+	return t
+}
+
+// themeField pairs a theme color with its custom_theme entry.
+type themeField struct {
+	label string
+	color *color.RGBA
+	hex   *string
+}
+
+func themeFields(t *uiTheme, c *config.ThemeColors) []themeField {
+	return []themeField{
+		{"Map Background", &t.background, &c.Background},
+		{"Line Tint", &t.lineTint, &c.LineTint},
+		{"Map Labels", &t.labels, &c.Labels},
+		{"Menu Bar", &t.menuBar, &c.MenuBar},
+		{"Menu Text", &t.menuText, &c.MenuText},
+		{"Menu Bar Highlight", &t.menuHover, &c.MenuHover},
+		{"Menu Background", &t.menuPanel, &c.MenuPanel},
+		{"Menu Border", &t.menuBorder, &c.MenuBorder},
+		{"Menu Item Highlight", &t.menuItem, &c.MenuSelected},
+	}
+}
+
+// setTheme switches themes. The map background follows the theme until one
+// is picked under View > Background again.
+func (w *Window) setTheme(name string) {
+	w.Config.Theme = name
+	w.Config.MapBackground = ""
+	w.Config.Save()
+	fmt.Printf("🎨 Theme: %s\n", name)
+}
+
+func themeLabel(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// themeMenuItems are the View > Theme choices.
+func (w *Window) themeMenuItems() []MenuItem {
+	var items []MenuItem
+	for _, name := range []string{themeClassic, "dark", "light", themeCustom} {
+		label := themeLabel(name)
+		if name == w.theme().name {
+			label = "* " + label
+		}
+		items = append(items, MenuItem{
+			Label: label,
+			Action: func() {
+				w.setTheme(name)
+				w.openMenu = ""
+			},
+		})
+	}
+	return append(items, MenuItem{
+		Label: "Edit Custom...",
+		Action: func() {
+			w.openMenu = ""
+			w.editCustomTheme()
+		},
+	})
+}
+
+const resetCustomTheme = "Reset All"
+
+// editCustomTheme picks the custom theme's colors one at a time and
+// switches to it.
+func (w *Window) editCustomTheme() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	for {
+		t := w.customTheme()
+		fields := themeFields(&t, &w.Config.CustomTheme)
+		items := make([]string, 0, len(fields)+1)
+		for _, f := range fields {
+			value := *f.hex
+			if value == "" {
+				value = "default"
+			}
+			items = append(items, fmt.Sprintf("%s: %s", f.label, value))
+		}
+		items = append(items, resetCustomTheme)
+		choice, err := zenity.List("Pick a color to change:", items,
+			zenity.Title("Custom Theme"),
+			zenity.Height(360),
+		)
+		if err != nil || choice == "" {
+			return
+		}
+		if choice == resetCustomTheme {
+			w.Config.CustomTheme = config.ThemeColors{}
+			w.setTheme(themeCustom)
+			continue
+		}
+		i := slices.Index(items, choice)
+		if i < 0 || i >= len(fields) {
+			return
+		}
+
+		f := fields[i]
+		picked, err := zenity.SelectColor(
+			zenity.Title(f.label),
+			zenity.Color(*f.color),
+		)
+		if errors.Is(err, zenity.ErrCanceled) || picked == nil {
+			continue
+		}
+		if err != nil {
+			return
+		}
+		*f.hex = config.HexColor(picked)
+		w.setTheme(themeCustom)
+	}
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/maps"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// thumbnailCache holds the zone thumbnails loaded so far, rendered to disk
+// by a background maps.Thumbnailer.
+type thumbnailCache struct {
+	job    *maps.Thumbnailer        // Render in progress
+	images map[string]*ebiten.Image // By lowercase file code; nil if there is none (yet)
+}
+
+// startThumbnails brings the thumbnail cache up to date in the background.
+func (w *Window) startThumbnails() {
+	w.thumbnails.job = maps.StartThumbnailer(w.MapDir, config.GetThumbnailDir())
+}
+
+// updateThumbnails notices a finished render, dropping what was loaded so
+// missing and redrawn thumbnails are read again.
+func (w *Window) updateThumbnails() {
+	t := &w.thumbnails
+	if t.job == nil {
+		return
+	}
+	select {
+	case <-t.job.Done:
+		t.job = nil
+		t.images = nil
+	default:
+	}
+}
+
+// thumbnail returns a zone's thumbnail, or nil if it hasn't been rendered.
+func (w *Window) thumbnail(code string) *ebiten.Image {
+	t := &w.thumbnails
+	key := strings.ToLower(code)
+	if img, ok := t.images[key]; ok {
+		return img
+	}
+	if t.images == nil {
+		t.images = make(map[string]*ebiten.Image)
+	}
+	var img *ebiten.Image
+	if src, err := maps.LoadThumbnail(config.GetThumbnailDir(), code); err == nil {
+		img = ebiten.NewImageFromImage(src)
+	}
+	t.images[key] = img
+	return img
+}
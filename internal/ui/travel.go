@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/maps"
+	"github.com/devin-hart/nox-maps/internal/travel"
+	"github.com/ncruces/zenity"
+)
+
+// openTravelPlanner asks for a destination zone and shows the fastest known
+// route there from the player's position, with a run-time estimate.
+func (w *Window) openTravelPlanner() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	if !w.zoneIndexReady("Travel Planner") {
+		return
+	}
+	from := travel.ResolveZone(w.logZone)
+	if from == "" || w.LogReader == nil {
+		zenity.Info("Your current zone isn't known yet.", zenity.Title("Travel Planner"))
+		return
+	}
+
+	graph := travel.BuildGraph(w.boundsIndex, w.travelLinks())
+	codes := graph.Zones()
+	items := make([]string, len(codes))
+	for i, code := range codes {
+		items[i] = fmt.Sprintf("%s (%s)", w.zoneNameForCode(code), code)
+	}
+	sort.Strings(items)
+
+	for {
+		choice, err := zenity.List(
+			fmt.Sprintf("Travel from %s to:\n%s", w.zoneNameForCode(from), w.travelOptionsSummary()),
+			items,
+			zenity.Title("Travel Planner"),
+			zenity.Height(520),
+			zenity.ExtraButton("Options..."),
+		)
+		if errors.Is(err, zenity.ErrExtraButton) {
+			w.chooseTravelOptions()
+			continue
+		}
+		if err != nil || choice == "" {
+			return
+		}
+
+		to := choice[strings.LastIndex(choice, "(")+1 : len(choice)-1]
+		s := w.LogReader.State()
+		route, err := graph.Plan(from, s.X, s.Y, to, w.travelOptions())
+		if err != nil {
+			zenity.Info(fmt.Sprintf("No known route to %s: %v", w.zoneNameForCode(to), err), zenity.Title("Travel Planner"))
+			continue
+		}
+
+		summary := w.routeSummary(from, route)
+		fmt.Printf("🧭 Route: %s\n", strings.SplitN(summary, "\n", 2)[0])
+		zenity.Info(summary, zenity.Title("Travel Planner"))
+		return
+	}
+}
+
+// travelLinks loads the bundled boat/port links plus the user's own.
+func (w *Window) travelLinks() []travel.Edge {
+	var links []travel.Edge
+	for _, path := range []string{maps.Locate(w.MapDir, "travel_links.json"), config.GetTravelLinksPath()} {
+		extra, err := travel.LoadLinks(path)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to read travel links %s: %v\n", path, err)
+			continue
+		}
+		links = append(links, extra...)
+	}
+	return links
+}
+
+func (w *Window) travelOptions() travel.Options {
+	opts := travel.Options{
+		RunSpeed: w.Config.Travel.RunSpeed,
+		UseBoats: w.Config.Travel.UseBoats,
+		UsePorts: w.Config.Travel.UsePorts,
+	}
+	if opts.RunSpeed <= 0 {
+		opts.RunSpeed = travel.DefaultRunSpeed
+	}
+	return opts
+}
+
+func (w *Window) travelOptionsSummary() string {
+	opts := w.travelOptions()
+	return fmt.Sprintf("Run speed %.0f, boats %s, ports %s",
+		opts.RunSpeed,
+		map[bool]string{true: "ON", false: "OFF"}[opts.UseBoats],
+		map[bool]string{true: "ON", false: "OFF"}[opts.UsePorts])
+}
+
+const (
+	travelUseBoats = "Use boats"
+	travelUsePorts = "Use ports (druid rings / wizard spires)"
+)
+
+// chooseTravelOptions edits the boat/port toggles and run speed.
+func (w *Window) chooseTravelOptions() {
+	var defaults []string
+	if w.Config.Travel.UseBoats {
+		defaults = append(defaults, travelUseBoats)
+	}
+	if w.Config.Travel.UsePorts {
+		defaults = append(defaults, travelUsePorts)
+	}
+	chosen, err := zenity.ListMultiple(
+		"Travel options:",
+		[]string{travelUseBoats, travelUsePorts},
+		zenity.Title("Travel Options"),
+		zenity.DefaultItems(defaults...),
+		zenity.CheckList(),
+	)
+	if err != nil {
+		return
+	}
+	w.Config.Travel.UseBoats, w.Config.Travel.UsePorts = false, false
+	for _, c := range chosen {
+		switch c {
+		case travelUseBoats:
+			w.Config.Travel.UseBoats = true
+		case travelUsePorts:
+			w.Config.Travel.UsePorts = true
+		}
+	}
+
+	speed, err := zenity.Entry(
+		"Run speed (map units per second):",
+		zenity.Title("Travel Options"),
+		zenity.EntryText(strconv.FormatFloat(w.travelOptions().RunSpeed, 'f', -1, 64)),
+	)
+	if err == nil {
+		if v, err := strconv.ParseFloat(strings.TrimSpace(speed), 64); err == nil && v > 0 {
+			w.Config.Travel.RunSpeed = v
+		}
+	}
+	w.Config.Save()
+}
+
+// routeSummary renders a route as a one-line zone chain with the total time
+// followed by one line per leg.
+func (w *Window) routeSummary(from string, route *travel.Route) string {
+	chain := []string{w.zoneNameForCode(from)}
+	var legs []string
+	for i, step := range route.Steps {
+		to := w.zoneNameForCode(step.Edge.To)
+		chain = append(chain, to)
+
+		var how string
+		switch step.Edge.Kind {
+		case travel.KindBoat:
+			how = "take the boat to " + to
+		case travel.KindPort:
+			how = "port to " + to
+		default:
+			how = "zone to " + to
+		}
+		if step.Distance > 0 {
+			how = fmt.Sprintf("run %.0f, %s", step.Distance, how)
+		}
+		if step.Edge.Note != "" {
+			how += " (" + step.Edge.Note + ")"
+		}
+		legs = append(legs, fmt.Sprintf("%d. %s: %s, %s", i+1, w.zoneNameForCode(step.Zone), how, formatTravelTime(step.Seconds)))
+	}
+	return fmt.Sprintf("%s: %s\n\n%s", strings.Join(chain, " → "), formatTravelTime(route.Seconds), strings.Join(legs, "\n"))
+}
+
+func formatTravelTime(seconds float64) string {
+	if seconds < 60 {
+		return fmt.Sprintf("~%.0fs", seconds)
+	}
+	return fmt.Sprintf("~%.0f min", seconds/60)
+}
@@ -0,0 +1,240 @@
+//go:build windows || linux
+
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"runtime"
+	"strings"
+
+	"fyne.io/systray"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// The tray icon (View > Tray Icon) shows and hides the map, sets the
+// opacity, picks the character to follow and quits. Its menu belongs to
+// systray's goroutine, so clicks are queued on actions and run in Update.
+// With the icon on, minimizing hides the window from the taskbar.
+
+var trayOpacities = []float64{1.0, 0.75, 0.5, 0.25}
+
+type trayIcon struct {
+	running bool
+	stopped bool // systray can't start again once it has quit
+	hidden  bool // Window hidden to the tray
+	actions chan func()
+
+	// Set once the menu is built; the UI keeps the checkmarks current
+	items          *trayItems
+	refresh        bool // Set every checkmark on the next update
+	shownOpacity   float64
+	shownCharacter string
+	shownHidden    bool
+}
+
+type trayItems struct {
+	show       *systray.MenuItem
+	opacity    []*systray.MenuItem
+	characters []*systray.MenuItem
+	names      []string // Character for each of characters; "" = newest log
+}
+
+// startTray puts the icon in the tray. Windows sends the icon's messages to
+// the thread that created it, so systray gets a thread of its own.
+func (w *Window) startTray() {
+	t := &w.tray
+	if t.running {
+		return
+	}
+	if t.stopped {
+		fmt.Println("⚠️  Restart nox-maps to bring back the tray icon")
+		return
+	}
+	t.running = true
+	t.actions = make(chan func(), 16)
+	labels, names := w.trayCharacters()
+	go func() {
+		runtime.LockOSThread()
+		systray.Run(func() { w.buildTrayMenu(labels, names) }, nil)
+	}()
+	fmt.Println("🔔 Tray icon ON")
+}
+
+func (w *Window) stopTray() {
+	t := &w.tray
+	if !t.running {
+		return
+	}
+	if t.hidden {
+		w.setTrayHidden(false)
+	}
+	systray.Quit()
+	t.running, t.stopped = false, true
+	t.items = nil
+}
+
+// buildTrayMenu runs on systray's goroutine once the icon is up.
+func (w *Window) buildTrayMenu(labels, names []string) {
+	post := func(fn func()) { w.tray.actions <- fn }
+
+	systray.SetIcon(trayIconData())
+	systray.SetTitle("Nox Maps")
+	systray.SetTooltip("Nox Maps")
+	systray.SetOnTapped(func() { post(func() { w.setTrayHidden(!w.tray.hidden) }) })
+
+	items := &trayItems{names: names}
+	items.show = systray.AddMenuItem("Hide Map", "Hide the map to the tray")
+	onClick(items.show, func() { post(func() { w.setTrayHidden(!w.tray.hidden) }) })
+
+	opacity := systray.AddMenuItem("Opacity", "")
+	for _, o := range trayOpacities {
+		item := opacity.AddSubMenuItemCheckbox(fmt.Sprintf("%.0f%%", o*100), "", false)
+		items.opacity = append(items.opacity, item)
+		onClick(item, func() { post(func() { w.setOpacity(o) }) })
+	}
+
+	if len(names) > 0 {
+		character := systray.AddMenuItem("Character", "Log to follow")
+		for i, label := range labels {
+			item := character.AddSubMenuItemCheckbox(label, "", false)
+			items.characters = append(items.characters, item)
+			onClick(item, func() { post(func() { w.setCharacter(names[i]) }) })
+		}
+	}
+
+	systray.AddSeparator()
+	onClick(systray.AddMenuItem("Quit", "Close Nox Maps"), func() {
+		post(func() {
+			w.Close()
+			os.Exit(0)
+		})
+	})
+
+	post(func() {
+		w.tray.items = items
+		w.tray.refresh = true
+	})
+}
+
+// onClick runs fn for each click on item, on the item's own goroutine.
+func onClick(item *systray.MenuItem, fn func()) {
+	go func() {
+		for range item.ClickedCh {
+			fn()
+		}
+	}()
+}
+
+// trayCharacters lists the characters with logs for the tray's Character
+// menu, newest log first. It's built once; new characters show up in
+// File > Character.
+func (w *Window) trayCharacters() (labels, names []string) {
+	if w.logSource == nil {
+		return nil, nil
+	}
+	labels, names = []string{"Newest Log"}, []string{""}
+	seen := make(map[string]bool)
+	for _, l := range w.logSource.Logs() {
+		key := strings.ToLower(l.Character)
+		if l.Character == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		labels = append(labels, l.Character)
+		names = append(names, l.Character)
+	}
+	return labels, names
+}
+
+// updateTray runs queued tray clicks, hides a minimized window to the tray
+// and brings the menu's checkmarks up to date.
+func (w *Window) updateTray() {
+	t := &w.tray
+	if t.actions == nil {
+		return
+	}
+	for len(t.actions) > 0 {
+		(<-t.actions)()
+	}
+	if !t.running {
+		return
+	}
+	if !t.hidden && ebiten.IsWindowMinimized() {
+		w.setTrayHidden(true)
+	}
+
+	items := t.items
+	if items == nil {
+		return
+	}
+	refresh := t.refresh
+	t.refresh = false
+	if refresh || t.hidden != t.shownHidden {
+		t.shownHidden = t.hidden
+		items.show.SetTitle(map[bool]string{true: "Show Map", false: "Hide Map"}[t.hidden])
+	}
+	if refresh || w.Opacity != t.shownOpacity {
+		t.shownOpacity = w.Opacity
+		for i, item := range items.opacity {
+			setChecked(item, math.Abs(w.Opacity-trayOpacities[i]) < 0.01)
+		}
+	}
+	if refresh || w.Config.Character != t.shownCharacter {
+		t.shownCharacter = w.Config.Character
+		for i, item := range items.characters {
+			setChecked(item, strings.EqualFold(items.names[i], w.Config.Character))
+		}
+	}
+}
+
+func setChecked(item *systray.MenuItem, checked bool) {
+	if checked {
+		item.Check()
+	} else {
+		item.Uncheck()
+	}
+}
+
+// setTrayHidden hides the window to the tray or brings it back.
+func (w *Window) setTrayHidden(hidden bool) {
+	w.tray.hidden = hidden
+	setWindowHidden(hidden)
+}
+
+// trayIconImage draws the icon: a north arrow on a dark disc.
+func trayIconImage() image.Image {
+	const size = 32
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	disc := color.RGBA{30, 40, 60, 255}
+	arrow := color.RGBA{90, 220, 120, 255}
+	for y := range size {
+		for x := range size {
+			fx, fy := float64(x)+0.5-size/2, float64(y)+0.5-size/2
+			if math.Hypot(fx, fy) > size/2-1 {
+				continue
+			}
+			img.SetRGBA(x, y, disc)
+			// Arrow from (0,-12) down to a notch at (0,6), 9 wide at the base
+			if fy >= -12 && fy <= 10 {
+				half := (fy + 12) * 9 / 22
+				notch := max(0, fy-6) * 9 / 4
+				if math.Abs(fx) <= half && math.Abs(fx) >= notch {
+					img.SetRGBA(x, y, arrow)
+				}
+			}
+		}
+	}
+	return img
+}
+
+func trayIconPNG() []byte {
+	var buf bytes.Buffer
+	png.Encode(&buf, trayIconImage())
+	return buf.Bytes()
+}
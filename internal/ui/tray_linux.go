@@ -0,0 +1,18 @@
+package ui
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// trayIconData is the icon as a PNG, which the StatusNotifierItem host takes.
+func trayIconData() []byte {
+	return trayIconPNG()
+}
+
+// setWindowHidden minimizes the window or restores it; ebiten can't take a
+// window off the taskbar here.
+func setWindowHidden(hidden bool) {
+	if hidden {
+		ebiten.MinimizeWindow()
+	} else {
+		ebiten.RestoreWindow()
+	}
+}
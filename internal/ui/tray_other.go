@@ -0,0 +1,16 @@
+//go:build !windows && !linux
+
+package ui
+
+import "fmt"
+
+// The tray icon needs cgo on macOS (see tray.go), so it's off elsewhere.
+
+type trayIcon struct{}
+
+func (w *Window) startTray() {
+	fmt.Println("⚠️  The tray icon isn't supported on this platform")
+}
+
+func (w *Window) stopTray()   {}
+func (w *Window) updateTray() {}
@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"encoding/binary"
+	"os"
+	"unsafe"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/sys/windows"
+)
+
+var (
+	user32            = windows.NewLazySystemDLL("user32.dll")
+	procShowWindow    = user32.NewProc("ShowWindow")
+	procSetForeground = user32.NewProc("SetForegroundWindow")
+)
+
+const (
+	swHide    = 0
+	swShow    = 5
+	swRestore = 9
+
+	glfwWindowClass = "GLFW30" // The map window's class; systray's window has its own
+)
+
+// trayIconData is the icon as an .ico holding the PNG, which is what
+// systray loads on Windows.
+func trayIconData() []byte {
+	data := trayIconPNG()
+	ico := make([]byte, 22, 22+len(data))
+	binary.LittleEndian.PutUint16(ico[2:], 1) // Icon
+	binary.LittleEndian.PutUint16(ico[4:], 1) // One image
+	ico[6], ico[7] = 32, 32                   // Size
+	binary.LittleEndian.PutUint16(ico[10:], 1)
+	binary.LittleEndian.PutUint16(ico[12:], 32) // Bits per pixel
+	binary.LittleEndian.PutUint32(ico[14:], uint32(len(data)))
+	binary.LittleEndian.PutUint32(ico[18:], 22)
+	return append(ico, data...)
+}
+
+// setWindowHidden hides the map window, taskbar button and all, or shows
+// it again. ebiten has no call for this, so it finds this process's GLFW
+// window.
+func setWindowHidden(hidden bool) {
+	hwnd := mapWindowHandle()
+	if hwnd == 0 {
+		return
+	}
+	if hidden {
+		procShowWindow.Call(uintptr(hwnd), swHide)
+		return
+	}
+	show := uintptr(swShow)
+	if ebiten.IsWindowMinimized() {
+		show = swRestore
+	}
+	procShowWindow.Call(uintptr(hwnd), show)
+	procSetForeground.Call(uintptr(hwnd))
+}
+
+var mapWindow windows.HWND // Found on first use
+
+func mapWindowHandle() windows.HWND {
+	if mapWindow == 0 {
+		windows.EnumWindows(findMapWindow, unsafe.Pointer(nil))
+	}
+	return mapWindow
+}
+
+// findMapWindow is the EnumWindows callback; Windows only has room for a
+// limited number of them, so it's made once.
+var findMapWindow = windows.NewCallback(func(hwnd windows.HWND, _ uintptr) uintptr {
+	var pid uint32
+	windows.GetWindowThreadProcessId(hwnd, &pid)
+	if pid != uint32(os.Getpid()) {
+		return 1
+	}
+	name := make([]uint16, 64)
+	n, _ := windows.GetClassName(hwnd, &name[0], int32(len(name)))
+	if windows.UTF16ToString(name[:n]) != glfwWindowClass {
+		return 1
+	}
+	mapWindow = hwnd
+	return 0 // Stop
+})
@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Marker and annotation edits are undone by putting back a copy of the
+// zone's lists from before the edit. Each edit calls pushUndo just before it
+// changes anything; undoing saves the current lists for redo.
+
+const maxUndo = 50
+
+// undoStep is one zone's markers and annotations as they were before (or,
+// on the redo stack, after) an edit.
+type undoStep struct {
+	label       string
+	zone        string
+	markers     []config.Marker
+	annotations []config.Annotation
+}
+
+type undoStack struct {
+	undo, redo []undoStep
+}
+
+func (w *Window) snapshotZone(label, zone string) undoStep {
+	s := undoStep{
+		label:   label,
+		zone:    zone,
+		markers: append([]config.Marker(nil), w.Config.ZoneMarkers(zone)...),
+	}
+	for _, a := range w.Config.Annotations[zone] {
+		a.Points = append([][2]float64(nil), a.Points...)
+		s.annotations = append(s.annotations, a)
+	}
+	return s
+}
+
+// pushUndo records the current zone before an edit described by label.
+// A new edit clears the redo stack.
+func (w *Window) pushUndo(label string) {
+	if w.CurrentZone == "" {
+		return
+	}
+	w.pushUndoStep(w.snapshotZone(label, w.CurrentZone))
+}
+
+// pushUndoStep records a snapshot taken earlier, for edits made in place
+// through a dialog that may still be cancelled.
+func (w *Window) pushUndoStep(step undoStep) {
+	w.undo.undo = append(w.undo.undo, step)
+	if len(w.undo.undo) > maxUndo {
+		w.undo.undo = w.undo.undo[len(w.undo.undo)-maxUndo:]
+	}
+	w.undo.redo = nil
+}
+
+// updateUndo handles Ctrl+Z (undo) and Ctrl+Y / Ctrl+Shift+Z (redo).
+func (w *Window) updateUndo() {
+	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta)
+	if !ctrl || w.dialogOpen || w.editor.active {
+		return
+	}
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyZ) && !shift:
+		w.undoEdit()
+	case inpututil.IsKeyJustPressed(ebiten.KeyY), inpututil.IsKeyJustPressed(ebiten.KeyZ) && shift:
+		w.redoEdit()
+	}
+}
+
+func (w *Window) undoEdit() {
+	if len(w.undo.undo) == 0 {
+		fmt.Println("↩️  Nothing to undo")
+		return
+	}
+	step := w.undo.undo[len(w.undo.undo)-1]
+	w.undo.undo = w.undo.undo[:len(w.undo.undo)-1]
+	w.undo.redo = append(w.undo.redo, w.snapshotZone(step.label, step.zone))
+	w.restoreZone(step)
+	fmt.Printf("↩️  Undid %s in %s\n", step.label, step.zone)
+}
+
+func (w *Window) redoEdit() {
+	if len(w.undo.redo) == 0 {
+		fmt.Println("↪️  Nothing to redo")
+		return
+	}
+	step := w.undo.redo[len(w.undo.redo)-1]
+	w.undo.redo = w.undo.redo[:len(w.undo.redo)-1]
+	w.undo.undo = append(w.undo.undo, w.snapshotZone(step.label, step.zone))
+	w.restoreZone(step)
+	fmt.Printf("↪️  Redid %s in %s\n", step.label, step.zone)
+}
+
+// restoreZone puts a step's lists back and tells sync peers which markers
+// came back, changed or went away.
+func (w *Window) restoreZone(step undoStep) {
+	before := make(map[string]config.Marker)
+	for _, m := range w.Config.ZoneMarkers(step.zone) {
+		before[m.ID] = m
+	}
+	for _, m := range step.markers {
+		if old, ok := before[m.ID]; !ok || old != m {
+			w.syncMarker(step.zone, m)
+		}
+		delete(before, m.ID)
+	}
+	for _, m := range before {
+		w.syncRemove(step.zone, m)
+	}
+
+	if len(step.markers) == 0 {
+		w.Config.SetZoneMarkers(step.zone, nil)
+	} else {
+		w.Config.SetZoneMarkers(step.zone, step.markers)
+	}
+	if len(step.annotations) == 0 {
+		delete(w.Config.Annotations, step.zone)
+	} else {
+		if w.Config.Annotations == nil {
+			w.Config.Annotations = make(map[string][]config.Annotation)
+		}
+		w.Config.Annotations[step.zone] = step.annotations
+	}
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving markers: %v\n", err)
+	}
+}
+
+// undoMenuItems are the Markers menu's Undo and Redo entries, when there is
+// something to undo or redo.
+func (w *Window) undoMenuItems() []MenuItem {
+	var items []MenuItem
+	if n := len(w.undo.undo); n > 0 {
+		items = append(items, MenuItem{
+			Label:  "Undo " + w.undo.undo[n-1].label,
+			Hotkey: "Ctrl+Z",
+			Action: func() {
+				w.openMenu = ""
+				w.undoEdit()
+			},
+		})
+	}
+	if n := len(w.undo.redo); n > 0 {
+		items = append(items, MenuItem{
+			Label:  "Redo " + w.undo.redo[n-1].label,
+			Hotkey: "Ctrl+Y",
+			Action: func() {
+				w.openMenu = ""
+				w.redoEdit()
+			},
+		})
+	}
+	return items
+}
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// The UI's modes are plain flags (dialogOpen, placingMarker, openMenu, ...).
+// One left set by an early return or a dialog that died can wedge input, so
+// the watchdog looks at them every frame and clears the ones that can't
+// still be meant; F9 (ActionResetUI) clears everything at once.
+
+const (
+	// Dialogs block the game loop, so a frame that sees dialogOpen is
+	// already past the dialog; the grace is only for the frame it closes on.
+	staleDialogAfter = 2 * time.Second
+	idleModeAfter    = 2 * time.Minute // Placement modes with no input this long are cancelled
+)
+
+type watchdog struct {
+	dialogSince   time.Time
+	lastInput     time.Time
+	mouseX        int
+	mouseY        int
+	width, height int
+}
+
+// runWatchdog recovers stuck UI state; called at the start of every Update.
+func (w *Window) runWatchdog() {
+	now := time.Now()
+	wd := &w.watchdog
+
+	if !w.dialogOpen {
+		wd.dialogSince = time.Time{}
+	} else if wd.dialogSince.IsZero() {
+		wd.dialogSince = now
+	} else if now.Sub(wd.dialogSince) > staleDialogAfter {
+		w.dialogOpen = false
+		wd.dialogSince = time.Time{}
+		fmt.Println("🐕 Watchdog: input was still blocked for a closed dialog; unblocked")
+	}
+
+	mx, my := ebiten.CursorPosition()
+	if mx != wd.mouseX || my != wd.mouseY || wd.lastInput.IsZero() ||
+		len(inpututil.AppendPressedKeys(nil)) > 0 ||
+		ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) || ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight) {
+		wd.lastInput = now
+	}
+	wd.mouseX, wd.mouseY = mx, my
+	if now.Sub(wd.lastInput) > idleModeAfter && (w.placingMarker || w.placingWaypoint || (w.ruler.active && w.ruler.points == 0)) {
+		w.placingMarker, w.placingWaypoint = false, false
+		w.ruler = ruler{}
+		fmt.Println("🐕 Watchdog: cancelled marker/waypoint/ruler placement left on with no input")
+	}
+
+	// Menu positions come from the old size; an open menu may now be off
+	// screen with no way to click it closed
+	if w.Width != wd.width || w.Height != wd.height {
+		if wd.width != 0 && w.openMenu != "" {
+			w.openMenu = ""
+			w.openSubmenu = -1
+		}
+		wd.width, wd.height = w.Width, w.Height
+	}
+}
+
+// resetUIState is the emergency way out: every mode, menu and modal flag
+// back to idle, and click-through off. Edit mode is left alone, since
+// leaving it asks about unsaved edits.
+func (w *Window) resetUIState() {
+	if m := w.draggedMarker(); m != nil {
+		m.X, m.Y = w.markerDrag.startX, w.markerDrag.startY
+	}
+	w.markerDrag = nil
+	w.dialogOpen = false
+	w.lastMousePressed = true
+	w.openMenu = ""
+	w.openSubmenu = -1
+	w.rebindingAction = ""
+	w.rebindSteps = nil
+	w.chords = chordState{}
+	w.notesEditing = false
+	w.modal = nil
+	w.placingMarker, w.placingWaypoint = false, false
+	w.ruler = ruler{}
+	w.drawing = nil
+	w.cleanCapture = false
+	w.editor.drawing = false
+	if w.Config.ClickThrough {
+		w.toggleClickThrough()
+	}
+	fmt.Println("🐕 UI state reset")
+}
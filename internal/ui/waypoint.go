@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/devin-hart/nox-maps/internal/nav"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+var waypointColor = color.RGBA{255, 0, 255, 255}
+
+// setWaypoint starts navigating to a world position.
+func (w *Window) setWaypoint(worldX, worldY float64) {
+	w.Nav.Set(worldX, worldY, "")
+	w.placingWaypoint = false
+	if w.LogReader != nil {
+		s := w.LogReader.State()
+		w.Nav.Update(s.X, s.Y)
+	}
+	fmt.Printf("🧭 Waypoint set at (%.1f, %.1f)\n", -worldY, -worldX)
+}
+
+// drawWaypoint draws the guide line from the player arrow to the target and
+// the target ring itself, in map space.
+func (w *Window) drawWaypoint(screen *ebiten.Image, cx, cy float64) {
+	if !w.Nav.Active() || w.LogReader == nil {
+		return
+	}
+	t := w.Nav.Target
+	sx, sy := w.playerPosition()
+
+	tx, ty := w.toScreen32(t.X, t.Y, cx, cy)
+	px, py := w.toScreen32(sx, sy, cx, cy)
+
+	if !w.browsing() { // The guide line only makes sense from the player's zone
+		vector.StrokeLine(screen, px, py, tx, ty, 2.0, color.RGBA{255, 0, 255, 160}, w.antiAlias)
+	}
+	vector.StrokeCircle(screen, tx, ty, 10, 2.0, waypointColor, w.antiAlias)
+	vector.StrokeLine(screen, tx-6, ty, tx+6, ty, 2.0, waypointColor, w.antiAlias)
+	vector.StrokeLine(screen, tx, ty-6, tx, ty+6, 2.0, waypointColor, w.antiAlias)
+	if t.Label != "" {
+		w.drawUIText(screen, t.Label, int(tx)+14, int(ty)+4, waypointColor)
+	}
+}
+
+const (
+	directionCompass  = "compass"
+	directionRelative = "relative"
+)
+
+func (w *Window) directionStyle() string {
+	if w.Config.DirectionStyle == directionRelative {
+		return directionRelative
+	}
+	return directionCompass
+}
+
+func (w *Window) toggleDirectionStyle() {
+	if w.directionStyle() == directionRelative {
+		w.Config.DirectionStyle = directionCompass
+	} else {
+		w.Config.DirectionStyle = directionRelative
+	}
+	w.Config.Save()
+}
+
+// directionLabel reports a bearing from the player in the configured style:
+// a 16-point compass direction ("NNE", or "north-northeast" when spoken) or
+// relative to the player's facing ("slightly left").
+func (w *Window) directionLabel(bearing float64, spoken bool) string {
+	if w.directionStyle() == directionRelative && w.LogReader != nil {
+		return nav.RelativeDirection(bearing, w.LogReader.State().Heading)
+	}
+	label := nav.CompassDirection16(bearing)
+	if spoken {
+		return nav.CompassWords(label)
+	}
+	return label
+}
+
+// drawCompass draws the HUD compass in the top-right corner: an arrow that
+// points at the waypoint plus the live distance countdown.
+func (w *Window) drawCompass(screen *ebiten.Image) {
+	if !w.Nav.Active() || w.browsing() {
+		return
+	}
+
+	radius := float32(28)
+	ccx := float32(w.Width) - radius - 16
+	ccy := float32(w.menuBarHeight) + radius + 16
+
+	vector.DrawFilledCircle(screen, ccx, ccy, radius, color.RGBA{0, 0, 0, 180}, w.antiAlias)
+	vector.StrokeCircle(screen, ccx, ccy, radius, 1.5, color.RGBA{200, 200, 200, 255}, w.antiAlias)
+	// North and the bearing turn with the map (see rotation.go)
+	north := -math.Pi/2 + w.rotation.angle
+	nx, ny := ccx+float32(math.Cos(north))*(radius-8), ccy+float32(math.Sin(north))*(radius-8)
+	w.drawUITextIn(screen, "N", int(nx)-w.uiTextWidth("N")/2, int(ny)-8, 16, color.RGBA{200, 200, 200, 255})
+
+	angle := w.Nav.Bearing + w.rotation.angle
+	tipX := ccx + float32(math.Cos(angle))*(radius-6)
+	tipY := ccy + float32(math.Sin(angle))*(radius-6)
+	leftX := ccx + float32(math.Cos(angle+2.6))*(radius*0.5)
+	leftY := ccy + float32(math.Sin(angle+2.6))*(radius*0.5)
+	rightX := ccx + float32(math.Cos(angle-2.6))*(radius*0.5)
+	rightY := ccy + float32(math.Sin(angle-2.6))*(radius*0.5)
+
+	vector.StrokeLine(screen, tipX, tipY, leftX, leftY, 2.0, waypointColor, w.antiAlias)
+	vector.StrokeLine(screen, leftX, leftY, rightX, rightY, 2.0, waypointColor, w.antiAlias)
+	vector.StrokeLine(screen, rightX, rightY, tipX, tipY, 2.0, waypointColor, w.antiAlias)
+
+	readout := fmt.Sprintf("%.0f (%s)", w.Nav.Distance, w.directionLabel(w.Nav.Bearing, false))
+	w.drawUIText(screen, readout, int(ccx)-w.uiTextWidth(readout)/2, int(ccy+radius)+16, waypointColor)
+}
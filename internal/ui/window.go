@@ -2,21 +2,29 @@ package ui
 
 import (
 	"fmt"
-	"image"
 	"image/color"
 	"math"
 	"os"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/eqlog"
+	"github.com/devin-hart/nox-maps/internal/integrations/screenshotloc"
+	"github.com/devin-hart/nox-maps/internal/integrations/sound"
+	"github.com/devin-hart/nox-maps/internal/integrations/speech"
+	"github.com/devin-hart/nox-maps/internal/mappack"
 	"github.com/devin-hart/nox-maps/internal/maps"
+	"github.com/devin-hart/nox-maps/internal/nav"
+	"github.com/devin-hart/nox-maps/internal/netsync"
 	"github.com/devin-hart/nox-maps/internal/parser"
+	"github.com/devin-hart/nox-maps/internal/widgets"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
-	"github.com/hajimehoshi/ebiten/v2/text"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 	"github.com/ncruces/zenity"
-	"golang.org/x/image/font/basicfont"
 )
 
 var whiteImage = ebiten.NewImage(3, 3)
@@ -31,10 +39,12 @@ type Window struct {
 
 	// Data Sources
 	LogReader     *parser.Engine
+	events        *parser.Subscription // LogReader's events (see events.go)
 	MapData       *maps.ZoneMap
 	MapDir        string
 	MapConfigPath string
-	CurrentZone   string
+	CurrentZone   string // Zone being shown (differs from logZone while browsing)
+	logZone       string // Zone last reported by the log
 	Config        *config.Config
 
 	// Viewport State
@@ -42,129 +52,358 @@ type Window struct {
 	Zoom       float64
 
 	// Display Options
-	Opacity         float64
-	LabelMode       int // 0 = all, 1 = custom+zone lines, 2 = zone lines only, 3 = none
-	ShowBreadcrumbs bool
-	Breadcrumbs     []BreadcrumbPoint
+	Opacity            float64
+	LabelMode          int // 0 = all, 1 = important+markers, 2 = important only, 3 = none
+	ShowBreadcrumbs    bool
+	Breadcrumbs        []BreadcrumbPoint
+	unsavedBreadcrumbs int             // Breadcrumbs added since the trail was last saved
+	trailCharacter     string          // Whose trail Breadcrumbs is (see characters.go)
+	zPresetDismissed   map[string]bool // "zone|preset" suggestions turned down (see zpresets.go)
+	ShowParty          bool            // Draw other tracked characters (multi-character mode)
+	FollowPlayer       bool            // Keep the camera on the player (see follow.go)
+
+	// Player arrow interpolation between /loc updates (see motion.go)
+	motion motionModel
 
 	// Z-Level Filtering
-	ZLevelMode      int     // 0 = off, 1 = auto, 2 = manual
-	ZLevelManual    float64 // Manual Z level when in manual mode
-	ZLevelRange     float64 // +/- range to show around Z level
+	ZLevelMode   int     // 0 = off, 1 = auto, 2 = manual
+	ZLevelManual float64 // Manual Z level when in manual mode
+	ZLevelRange  float64 // +/- range to show around Z level
 
 	// Input State
-	lastMouseX        int
-	lastMouseY        int
-	lastMousePressed  bool
-	lastMinusKey      bool
-	lastEqualsKey     bool
-	lastLKey          bool
-	lastBKey          bool
-	lastCKey          bool
-	lastKKey          bool
-	lastZKey          bool
-	lastPageUpKey   bool
-	lastPageDownKey bool
-	lastInsertKey   bool
-	lastDeleteKey   bool
-	lastHomeKey     bool
-	lastMKey        bool
+	lastMouseX       int
+	lastMouseY       int
+	lastMousePressed bool
+	chords           chordState // Multi-key bindings in progress (see keychords.go)
+	rebindingAction  string     // Action waiting for a new key ("" if none)
+	rebindSteps      keyBinding // Keys pressed so far while rebinding
+	rebindDeadline   time.Time  // Rebinding ends unless another key comes by then
 
 	// Menu State
-	openMenu       string // "File", "View", "Help", or ""
-	openSubmenu    int    // Index of menu item with open submenu (-1 if none)
-	menuBarHeight  int
-	showInfo       bool   // Show info panel
+	openMenu      string // "File", "View", "Help", or ""
+	openSubmenu   int    // Index of menu item with open submenu (-1 if none)
+	menuBarHeight int
+	menuTitlesEnd int  // Right edge of the last menu title
+	showInfo      bool // Show info panel
 
 	// Marker State
-	placingMarker bool
-	markerColor   string
-	markerShape   string
-	ShowMarkers   bool
-	lastRKey      bool
-	dialogOpen    bool // Prevents re-entry while zenity dialog is open
+	placingMarker  bool
+	markerColor    string
+	markerShape    string
+	markerCategory string                      // Category new markers are placed in
+	customShapes   map[string][]config.ShapeOp // User shapes from shapes.json
+	ShowMarkers    bool
+	dialogOpen     bool // Prevents re-entry while zenity dialog is open
+
+	// Waypoint Navigation
+	Nav             *nav.Navigator
+	placingWaypoint bool
+	ruler           ruler // Distance measuring (see ruler.go)
+
+	drawing *annotationDraft // Path or polygon being drawn (see annotations.go)
+
+	// Zone Notes (see notes.go)
+	notesEditing  bool
+	notesExpanded bool
+	notesBuffer   []rune
+
+	// Clean map captures (see capture.go)
+	cleanCapture     bool // Preview: hide player, trail and UI, show the caption
+	captureRequested bool // Save the next frame as a PNG
+
+	// Map editor (see editor.go)
+	editor mapEditor
+
+	// Cached map geometry (see mesh.go) and label priorities (see labelpriority.go)
+	mesh      lineMesh
+	labelPrio labelPriorities
+
+	// Marker and annotation edit history (see undo.go)
+	undo undoStack
+
+	markerDrag    *markerDrag         // Marker being moved with Alt+drag (see markerdrag.go)
+	watchdog      watchdog            // Stuck-state recovery (see watchdog.go)
+	modal         *modal              // In-window dialog that owns input (see modal.go)
+	zoomAnim      zoomAnim            // Wheel zoom in progress (see zoom.go)
+	rotation      viewRotation        // Angle the map is drawn at (see rotation.go)
+	windowMonitor *ebiten.MonitorType // Monitor last saved to config (see windowgeom.go)
+	windowDrag    windowDrag          // Frameless move or resize in progress (see frameless.go)
+	tray          trayIcon            // Tray icon and its queued clicks (see tray.go)
+
+	// Frame-sized images kept between frames and dropped when Layout
+	// changes the size: the map is drawn into offscreen, and layers below
+	// full opacity into layerScratch first (see drawlayers.go)
+	offscreen    *ebiten.Image
+	layerScratch *ebiten.Image
+
+	// Map hot-reload
+	mapWatcher  *maps.Watcher
+	mapFileCode string     // File code the current map was loaded from
+	mapReload   *mapReload // Reload in progress (see mapreload.go)
+
+	// Shared links (see links.go)
+	pendingLink     string
+	pendingLinkWait int
+
+	// Find (see find.go)
+	lastFind           string
+	findHighlightX     float64
+	findHighlightY     float64
+	findHighlightUntil time.Time
+
+	// Screenshot /loc OCR integration (see screenshotloc.go)
+	ocrWatcher *screenshotloc.Watcher
+
+	// Spoken announcements (see announce.go)
+	speaker       *speech.Speaker
+	announcedZone string
+	lastAnnounce  time.Time
+
+	// Alert sounds (see alerts.go)
+	soundPlayer *sound.Player
+	decayWarned map[string]bool // Corpses already warned about
+	afkAlerted  bool            // Sounded for the current idle stretch (see afk.go)
+
+	// Position vs. map bounds sanity check (see sanity.go)
+	outOfBounds       bool
+	outOfBoundsLogged bool
+	zoneEntryX        float64 // Player position when the zone changed
+	zoneEntryY        float64
+	boundsIndex       *maps.BoundsIndex // All-zones index (see zoneindex.go); nil until built
+	indexer           *maps.Indexer     // Background build in progress
+	thumbnails        thumbnailCache    // Zone thumbnails (see thumbnails.go)
+	wallCheck         wallCheck         // Trail vs. map walls (see wrongmap.go)
+	fonts             uiFonts           // TTF faces for the UI and map labels (see fonts.go)
+
+	simulated bool // Demo or replayed log (see SetSimulated); nothing about play is saved
+
+	mapCheck       *maps.IntegrityScan // Map file check in progress (see mapcheck.go)
+	mapPackInstall *mappack.Install    // Map pack download in progress (see mappacks.go)
+	cloudSync      cloudSync           // Markers and notes shared through a server (see cloudsync.go)
+
+	// Zone Tasks (see tasks.go)
+	showTasks bool
+
+	// Corpses panel (see corpses.go)
+	showCorpses bool
+
+	// Time spent per map cell (see heatmap.go)
+	showHeatmap bool
+	heatmaps    map[string]*config.Heatmap // Loaded so far, by zone
+	heatSaved   time.Time
+	heatSampled time.Time
+	heatHistory *heatHistory // Non-nil while showing cmd/analyze's history instead
+
+	// Session stats panel (see stats.go)
+	showStats  bool
+	charStats  map[string]*config.CharacterStats
+	statsSaved time.Time
+
+	// Camp waiting lists panel (see camplists.go)
+	showCampLists bool
+
+	// Last server-wide repop still offering a reset (see repop.go)
+	repopAt time.Time
+
+	// Live marker sync over the LAN (see netsync.go)
+	sync             *netsync.Session
+	syncPeers        map[string]*syncPeer
+	lastSyncPosition time.Time
+
+	// Locs posted in group/guild chat, by speaker (see chatlocs.go)
+	chatLocs      map[string]chatLoc
+	chatLocRegexp *regexp.Regexp
+	chatLocSource string
+
+	// Zone overview in the corner (see minimap.go)
+	showMinimap bool
+	minimap     minimap
+
+	logSource *eqlog.Reader // Set by main; nil without an EQ path (see logqueue.go)
+
+	// Rendering Capabilities (see health.go)
+	antiAlias     bool
+	transparent   bool
+	healthChecked bool
+	notices       []string // Degradation notices shown in the info panel
 }
 
-type BreadcrumbPoint struct {
-	X, Y float64
-}
+type BreadcrumbPoint = config.BreadcrumbPoint
 
 func NewWindow(engine *parser.Engine, mapDir string, mapConfigPath string, cfg *config.Config) *Window {
 	return &Window{
-		Width:           1280,
-		Height:          720,
+		Width:           defaultWindowWidth,
+		Height:          defaultWindowHeight,
 		Title:           "Nox Maps",
 		LogReader:       engine,
+		events:          engine.Subscribe(),
 		MapDir:          mapDir,
 		MapConfigPath:   mapConfigPath,
 		Config:          cfg,
 		Zoom:            1.0,
 		Opacity:         1.0,
-		LabelMode:       2, // Default to zone lines only
+		LabelMode:       2, // Default to important labels (zone lines) only
 		ShowBreadcrumbs: true,
 		Breadcrumbs:     make([]BreadcrumbPoint, 0),
+		ShowParty:       true,
+		showCorpses:     true,
+		showCampLists:   true,
 		ZLevelMode:      0,    // Default to off (0=off, 1=auto, 2=manual)
 		ZLevelManual:    0.0,
 		ZLevelRange:     50.0, // Show +/- 50 units
 		menuBarHeight:   24,
+		rotation:        northUp,
 		openMenu:        "",
 		openSubmenu:     -1,
 		showInfo:        true, // Show info panel by default
 		placingMarker:   false,
-		markerColor:     "red",
+		markerColor:     "#ff0000",
 		markerShape:     "circle",
 		ShowMarkers:     true, // Show markers by default
+		Nav:             nav.NewNavigator(),
+		antiAlias:       !cfg.DisableAntiAlias,
+		transparent:     !cfg.DisableTransparency,
 	}
 }
 
 func (w *Window) Init() error {
 	ebiten.SetWindowTitle(w.Title)
-	ebiten.SetWindowSize(w.Width, w.Height)
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
-	ebiten.SetScreenTransparent(true)
-
-	maps.LoadZoneConfig(w.MapConfigPath)
+	w.restoreWindowGeometry()
+	ebiten.SetScreenTransparent(w.transparent)
+	w.applyOverlayMode()
+
+	w.applyMapOverrides() // map_keys.json plus the user's choices (see wrongmap.go)
+	w.loadZoneInfo()
+	w.customShapes = config.LoadShapes()
+	w.startScreenshotOCR()
+	w.startAnnouncements()
+	w.startSoundAlerts()
+	w.startZoneIndex()
+	w.startThumbnails()
+	w.startMapCheck(true)
+	if w.Config.Tray {
+		w.startTray()
+	}
 	return nil
 }
 
+// Close persists session state; call it once the game loop has exited.
+func (w *Window) Close() {
+	w.saveBreadcrumbs()
+	w.saveHeatmaps()
+	w.saveStats()
+	w.stopScreenshotOCR()
+	w.stopAnnouncements()
+	w.stopSoundAlerts()
+	w.stopSync()
+	w.stopTray()
+	w.finishCloudSyncOnExit()
+	w.LogReader.Unsubscribe(w.events)
+	w.Config.Flush()
+}
+
 func (w *Window) Update() error {
-	// 1. MOUSE ZOOM (Wheel)
-	_, dy := ebiten.Wheel()
-	if dy > 0 {
-		w.Zoom *= 1.1
-	} else if dy < 0 {
-		w.Zoom /= 1.1
+	// 0. RENDERING HEALTH CHECK (first frame only)
+	if !w.healthChecked {
+		w.runHealthCheck()
 	}
 
-	// 2. MOUSE INPUT
+	// Changes queued from other goroutines, and debounced config writes
+	w.Config.Pump()
+	w.trackWindowGeometry()
+	w.updateTray()
+
+	// Chords in progress (see keychords.go); typing into a dialog doesn't count
+	w.updateChords(w.modal == nil && w.rebindingAction == "" && !w.notesEditing)
+
+	// Stuck-state recovery, and the emergency reset (F9) that works even
+	// while something else owns the keyboard (see watchdog.go)
+	w.runWatchdog()
+	if w.keyTriggered(ActionResetUI) {
+		w.resetUIState()
+	}
+
+	// In-window dialogs, key rebinding and the notes editor own the
+	// keyboard while open
+	if w.updateModal() {
+		return nil
+	}
+	if w.rebindingAction != "" {
+		w.updateRebinding()
+		return nil
+	}
+	if w.notesEditing {
+		w.updateNotesEditor()
+		return nil
+	}
+
+	// 1. MOUSE ZOOM (Wheel), eased and anchored on the cursor (see zoom.go)
 	mx, my := ebiten.CursorPosition()
+	_, dy := ebiten.Wheel()
+	w.wheelZoom(dy, mx, my)
+	w.updateZoom()
+
+	// 2. MOUSE INPUT
 	cx, cy := float64(w.Width)/2, float64(w.Height)/2
 
 	// Convert screen coordinates to world coordinates
-	worldX := (float64(mx) - cx) / w.Zoom + w.CamX
-	worldY := (float64(my) - cy) / w.Zoom + w.CamY
+	worldX, worldY := w.toWorld(float64(mx), float64(my), cx, cy)
 
 	// Left-click handling
 	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) && !w.lastMousePressed && !w.dialogOpen {
 		// Only handle clicks below menu bar
-		if my > w.menuBarHeight {
-			if w.placingMarker {
+		if w.startWindowDrag(mx, my) {
+			// Moving or resizing the frameless window
+		} else if w.handleTaskPanelClick(mx, my, false) {
+			// Consumed by the tasks panel
+		} else if w.handleBoundsBannerClick(mx, my) || w.handleWrongMapBannerClick(mx, my) {
+			// Consumed by the out-of-bounds or wrong-map warning
+		} else if w.handleRepopBannerClick(mx, my) {
+			// Consumed by the repop reset offer
+		} else if w.handleZPresetBannerClick(mx, my) {
+			// Applied or dismissed a Z preset suggestion
+		} else if w.handleMinimapClick(mx, my) {
+			// Jumped the main view
+		} else if w.handleCompassRoseClick(mx, my) {
+			// Back to north up
+		} else if my > w.menuBarHeight {
+			if w.editor.active {
+				w.editorClick(worldX, worldY)
+			} else if w.ruler.active {
+				w.rulerClick(worldX, worldY)
+			} else if w.drawing != nil {
+				w.addAnnotationPoint(worldX, worldY)
+			} else if w.placingWaypoint {
+				w.setWaypoint(worldX, worldY)
+			} else if ebiten.IsKeyPressed(ebiten.KeyAlt) && w.startMarkerDrag(worldX, worldY) {
+				// Moving a marker (see markerdrag.go)
+			} else if ebiten.IsKeyPressed(ebiten.KeyShift) {
+				w.copyMarkerLinkAt(worldX, worldY)
+			} else if w.placingMarker {
 				// Place new marker
 				w.placeMarker(worldX, worldY)
-			} else {
-				// Check if clicking on existing marker to edit label
-				w.editMarkerAt(worldX, worldY)
+			} else if !w.editMarkerAt(worldX, worldY) {
+				// Not on a marker; maybe on a path or polygon
+				w.editAnnotationAt(worldX, worldY)
 			}
 		}
 	}
 
+	w.updateMarkerDrag(worldX, worldY)
+	w.updateWindowDrag(mx, my)
+
 	// Right-click handling
 	rightPressed := ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight)
 	markerRemoved := false
 	if rightPressed && !w.lastMousePressed {
 		// Check if right-clicking on a marker to delete it
-		if my > w.menuBarHeight {
-			markerRemoved = w.removeMarkerAt(worldX, worldY)
+		if w.handleTaskPanelClick(mx, my, true) || w.handleCorpsePanelClick(mx, my) || w.handleCampListPanelClick(mx, my) {
+			markerRemoved = true // Don't start panning from the panel
+		} else if my > w.menuBarHeight && w.editor.active {
+			markerRemoved = w.editorRightClick(worldX, worldY)
+		} else if my > w.menuBarHeight {
+			markerRemoved = w.removeMarkerAt(worldX, worldY) || w.removeAnnotationAt(worldX, worldY)
 		}
 	}
 
@@ -172,128 +411,164 @@ func (w *Window) Update() error {
 	if rightPressed && !markerRemoved {
 		dx := float64(mx - w.lastMouseX)
 		dy := float64(my - w.lastMouseY)
+		if dx != 0 || dy != 0 {
+			w.setFollow(false) // Dragging means the user wants to look elsewhere
+		}
 
 		// Move Camera OPPOSITE to mouse drag to simulate "grabbing" the map
-		w.CamX -= dx / w.Zoom
-		w.CamY -= dy / w.Zoom
+		wx, wy := w.screenDeltaToWorld(dx/w.Zoom, dy/w.Zoom)
+		w.CamX -= wx
+		w.CamY -= wy
 	}
 
 	w.lastMouseX = mx
 	w.lastMouseY = my
 
-	// 3. KEYBOARD PAN
+	// 3. KEYBOARD PAN (keys are configurable, see keybinds.go)
+	// Directions are on screen, so they follow the map's rotation
 	moveSpeed := 10.0 / w.Zoom
-	if ebiten.IsKeyPressed(ebiten.KeyW) { w.CamY -= moveSpeed } // Up moves camera up (decreases Y)
-	if ebiten.IsKeyPressed(ebiten.KeyS) { w.CamY += moveSpeed }
-	if ebiten.IsKeyPressed(ebiten.KeyA) { w.CamX -= moveSpeed }
-	if ebiten.IsKeyPressed(ebiten.KeyD) { w.CamX += moveSpeed }
+	var panX, panY float64
+	if w.keyHeld(ActionPanUp) { panY -= moveSpeed } // Up moves camera up (decreases Y)
+	if w.keyHeld(ActionPanDown) { panY += moveSpeed }
+	if w.keyHeld(ActionPanLeft) { panX -= moveSpeed }
+	if w.keyHeld(ActionPanRight) { panX += moveSpeed }
+	panX, panY = w.screenDeltaToWorld(panX, panY)
+	w.CamX += panX
+	w.CamY += panY
+	if w.keyHeld(ActionPanUp) || w.keyHeld(ActionPanDown) || w.keyHeld(ActionPanLeft) || w.keyHeld(ActionPanRight) {
+		w.setFollow(false)
+	}
 
 	// 4. CENTER ON PLAYER (Spacebar)
-	if ebiten.IsKeyPressed(ebiten.KeySpace) && w.LogReader != nil {
-		w.CamX = w.LogReader.CurrentState.X
-		w.CamY = w.LogReader.CurrentState.Y
+	if w.keyHeld(ActionCenterPlayer) && w.LogReader != nil {
+		if w.browsing() {
+			w.viewZone(w.logZone) // Return from a linked zone
+		}
+		s := w.LogReader.State()
+		w.CamX, w.CamY = s.X, s.Y
+	}
+
+	// 4b. FOLLOW PLAYER (F key; Ctrl+F is Find, and bindings need their exact modifiers)
+	if w.keyTriggered(ActionFollowPlayer) {
+		w.setFollow(!w.FollowPlayer)
+	}
+	w.updateMotion()
+	w.updateFollow()
+
+	// 4b1. CAMERA BOOKMARKS (number keys, see bookmarks.go)
+	w.updateBookmarkKeys()
+
+	// 4b1a. ROTATION (, and . turn the map, H toggles heading up; see rotation.go)
+	if w.keyTriggered(ActionRotateLeft) {
+		w.rotateView(-rotateStep)
+	}
+	if w.keyTriggered(ActionRotateRight) {
+		w.rotateView(rotateStep)
+	}
+	if w.keyTriggered(ActionHeadingUp) {
+		w.toggleHeadingUp()
+	}
+	w.updateRotation()
+
+	// 4b2. CLICK-THROUGH (P key; the only way back once clicks pass through)
+	if w.keyTriggered(ActionClickThrough) {
+		w.toggleClickThrough()
 	}
 
+	// 4b3. CLEAN CAPTURE (O previews, F12 saves)
+	if w.keyTriggered(ActionCleanCapture) {
+		w.toggleCleanCapture()
+	}
+	if w.keyTriggered(ActionSaveCapture) {
+		w.captureRequested = true
+	}
+
+	// 4b4. UNDO / REDO marker and annotation edits (Ctrl+Z, Ctrl+Y)
+	w.updateUndo()
+
+	// 4c. MAP EDITOR (E key)
+	if w.keyTriggered(ActionEditMap) {
+		w.setEditing(!w.editor.active)
+	}
+	w.updateEditor()
+	w.updateAnnotations()
+
 	// 5. OPACITY CONTROLS (- and =)
-	minusPressed := ebiten.IsKeyPressed(ebiten.KeyMinus)
-	if minusPressed && !w.lastMinusKey {
+	if w.keyTriggered(ActionOpacityDown) {
 		w.Opacity -= 0.1
 		if w.Opacity < 0.1 { w.Opacity = 0.1 }
+		if !w.transparent { w.Opacity = 1.0 }
 	}
-	w.lastMinusKey = minusPressed
 
-	equalsPressed := ebiten.IsKeyPressed(ebiten.KeyEqual)
-	if equalsPressed && !w.lastEqualsKey {
+	if w.keyTriggered(ActionOpacityUp) {
 		w.Opacity += 0.1
 		if w.Opacity > 1.0 { w.Opacity = 1.0 }
 	}
-	w.lastEqualsKey = equalsPressed
 
 	// 6. CYCLE LABEL MODE (L key)
-	// 0 = all, 1 = custom+zone lines, 2 = zone lines only, 3 = none
-	lPressed := ebiten.IsKeyPressed(ebiten.KeyL)
-	if lPressed && !w.lastLKey {
+	// 0 = all, 1 = important+markers, 2 = important only, 3 = none
+	if w.keyTriggered(ActionCycleLabels) {
 		w.LabelMode = (w.LabelMode + 1) % 4
 	}
-	w.lastLKey = lPressed
 
 	// 7. TOGGLE BREADCRUMBS (B key)
-	bPressed := ebiten.IsKeyPressed(ebiten.KeyB)
-	if bPressed && !w.lastBKey {
+	if w.keyTriggered(ActionToggleBreadcrumbs) {
 		w.ShowBreadcrumbs = !w.ShowBreadcrumbs
 	}
-	w.lastBKey = bPressed
 
 	// 8. CLEAR BREADCRUMBS (C key)
-	cPressed := ebiten.IsKeyPressed(ebiten.KeyC)
-	if cPressed && !w.lastCKey {
-		w.Breadcrumbs = w.Breadcrumbs[:0]
+	if w.keyTriggered(ActionClearBreadcrumbs) {
+		w.clearBreadcrumbs()
 	}
-	w.lastCKey = cPressed
 
 	// 9. CLEAR CORPSE (K key)
-	kPressed := ebiten.IsKeyPressed(ebiten.KeyK)
-	if kPressed && !w.lastKKey && w.LogReader != nil {
-		w.LogReader.CurrentState.HasCorpse = false
+	if w.keyTriggered(ActionClearCorpse) && w.LogReader != nil {
+		w.LogReader.ClearCorpses(w.LogReader.State().Character)
 	}
-	w.lastKKey = kPressed
 
-	// 10. CYCLE Z-LEVEL MODE (Z key)
+	// 10. CYCLE Z-LEVEL MODE (Z key; Ctrl+Z is Undo)
 	// 0 = off, 1 = auto, 2 = manual
-	zPressed := ebiten.IsKeyPressed(ebiten.KeyZ)
-	if zPressed && !w.lastZKey {
+	if w.keyTriggered(ActionCycleZLevel) {
 		w.ZLevelMode = (w.ZLevelMode + 1) % 3
 		// When switching to manual, set manual level to current player Z
 		if w.ZLevelMode == 2 && w.LogReader != nil {
-			w.ZLevelManual = w.LogReader.CurrentState.Z
+			w.ZLevelManual = w.LogReader.State().Z
 		}
 	}
-	w.lastZKey = zPressed
 
 	// 11. MANUAL Z-LEVEL ADJUSTMENT (PageUp/PageDown)
-	pageUpPressed := ebiten.IsKeyPressed(ebiten.KeyPageUp)
-	if pageUpPressed && !w.lastPageUpKey {
+	if w.keyTriggered(ActionZLevelUp) {
 		w.ZLevelManual += 10.0
 		w.ZLevelMode = 2 // Switch to manual mode
 	}
-	w.lastPageUpKey = pageUpPressed
 
-	pageDownPressed := ebiten.IsKeyPressed(ebiten.KeyPageDown)
-	if pageDownPressed && !w.lastPageDownKey {
+	if w.keyTriggered(ActionZLevelDown) {
 		w.ZLevelManual -= 10.0
 		w.ZLevelMode = 2 // Switch to manual mode
 	}
-	w.lastPageDownKey = pageDownPressed
 
 	// 12. Z-LEVEL RANGE ADJUSTMENT (Insert and Delete)
-	insertPressed := ebiten.IsKeyPressed(ebiten.KeyInsert)
-	if insertPressed && !w.lastInsertKey {
+	if w.keyTriggered(ActionZRangeUp) {
 		w.ZLevelRange += 10.0
 		if w.ZLevelRange > 200.0 {
 			w.ZLevelRange = 200.0 // Maximum range
 		}
 	}
-	w.lastInsertKey = insertPressed
 
-	deletePressed := ebiten.IsKeyPressed(ebiten.KeyDelete)
-	if deletePressed && !w.lastDeleteKey {
+	if w.keyTriggered(ActionZRangeDown) {
 		w.ZLevelRange -= 10.0
 		if w.ZLevelRange < 10.0 {
 			w.ZLevelRange = 10.0 // Minimum range
 		}
 	}
-	w.lastDeleteKey = deletePressed
 
 	// 13. RE-FIT ZOOM (Home key)
-	homePressed := ebiten.IsKeyPressed(ebiten.KeyHome)
-	if homePressed && !w.lastHomeKey && w.MapData != nil {
+	if w.keyTriggered(ActionFitMap) && w.MapData != nil {
 		w.refitZoom()
 	}
-	w.lastHomeKey = homePressed
 
 	// 14. MARKER PLACEMENT (M key to toggle mode)
-	mPressed := ebiten.IsKeyPressed(ebiten.KeyM)
-	if mPressed && !w.lastMKey {
+	if w.keyTriggered(ActionPlaceMarker) {
 		w.placingMarker = !w.placingMarker
 		if w.placingMarker {
 			fmt.Println("📍 Marker placement mode ON - Left-click to place marker")
@@ -301,11 +576,9 @@ func (w *Window) Update() error {
 			fmt.Println("📍 Marker placement mode OFF")
 		}
 	}
-	w.lastMKey = mPressed
 
 	// 15. TOGGLE MARKER VISIBILITY (R key)
-	rPressed := ebiten.IsKeyPressed(ebiten.KeyR)
-	if rPressed && !w.lastRKey {
+	if w.keyTriggered(ActionToggleMarkers) {
 		w.ShowMarkers = !w.ShowMarkers
 		if w.ShowMarkers {
 			fmt.Println("📍 Markers visible")
@@ -313,47 +586,82 @@ func (w *Window) Update() error {
 			fmt.Println("📍 Markers hidden")
 		}
 	}
-	w.lastRKey = rPressed
-
-	// 16. BREADCRUMB TRACKING
-	// Add a breadcrumb every ~2 seconds when player moves
-	if w.LogReader != nil {
-		shouldAddBreadcrumb := false
-		if len(w.Breadcrumbs) == 0 {
-			shouldAddBreadcrumb = true
-		} else {
-			lastBC := w.Breadcrumbs[len(w.Breadcrumbs)-1]
-			dx := w.LogReader.CurrentState.X - lastBC.X
-			dy := w.LogReader.CurrentState.Y - lastBC.Y
-			dist := math.Sqrt(dx*dx + dy*dy)
-			// Add breadcrumb if moved more than 50 units
-			if dist > 50 {
-				shouldAddBreadcrumb = true
-			}
-		}
 
-		if shouldAddBreadcrumb {
-			w.Breadcrumbs = append(w.Breadcrumbs, BreadcrumbPoint{
-				X: w.LogReader.CurrentState.X,
-				Y: w.LogReader.CurrentState.Y,
-			})
-			// Limit to last 500 breadcrumbs
-			if len(w.Breadcrumbs) > 500 {
-				w.Breadcrumbs = w.Breadcrumbs[1:]
-			}
+	// 15b. WAYPOINT PLACEMENT (N key to toggle mode)
+	if w.keyTriggered(ActionSetWaypoint) {
+		w.placingWaypoint = !w.placingWaypoint
+		if w.placingWaypoint {
+			fmt.Println("🧭 Waypoint mode ON - Left-click a destination")
 		}
 	}
 
-	// 11. ZONE CHANGE DETECTION
-	if w.LogReader != nil && w.LogReader.CurrentState.Zone != w.CurrentZone {
-		w.CurrentZone = w.LogReader.CurrentState.Zone
-		w.loadMapForZone(w.CurrentZone)
-		w.Breadcrumbs = w.Breadcrumbs[:0] // Clear breadcrumbs when changing zones
-		// Note: Corpse marker persists across zone changes intentionally
+	// 15c. RULER (U key to toggle mode)
+	if w.keyTriggered(ActionRuler) {
+		w.toggleRuler()
 	}
+
+	// 16. LOG EVENTS (zone changes, breadcrumbs, waypoint arrival; see events.go)
+	w.updateEvents()
+
+	// MAP HOT-RELOAD (files edited on disk; see mapreload.go)
+	w.updateMapReload()
+
+	// ZONE INDEX AND THUMBNAILS (background builds)
+	w.updateZoneIndex()
+	w.updateThumbnails()
+	w.updateMapCheck()
+	w.updateMapPacks()
+	w.updateCloudSync()
+
+	// POSITION SANITY CHECK (missed zone change / wrong map)
+	w.checkPositionBounds()
+
+	// SHARED LINKS (Ctrl+V / startup link)
+	w.updateLinks()
+
+	// FIND (Ctrl+F)
+	w.updateFind()
+
+	// CAMP CLAIMS FROM OOC
+	w.updateChat()
+
+	// USER LOG RULES
+	w.updateRules()
+
+	// SESSION STATS & HEATMAP
+	w.updateStats()
+	w.updateHeatmap()
+
+	// MARKER SYNC (optional, LAN)
+	w.updateSync()
+
+	// SCREENSHOT /LOC MARKERS (optional integration)
+	w.updateScreenshotOCR()
+
+	// SPOKEN ANNOUNCEMENTS (optional integration)
+	w.updateAnnouncements()
+
+	// ALERT SOUNDS (optional integration)
+	w.updateAlerts()
+	w.updateAFKAlert()
 	return nil
 }
 
+// viewZone switches the map (and trail) to a zone. The player's own zone
+// comes from the log; any other zone is being browsed, e.g. from a link.
+func (w *Window) viewZone(zoneName string) {
+	w.setEditing(false) // Offers to save edits to the map we're leaving
+	w.saveBreadcrumbs() // Persist the trail of the zone we're leaving
+	w.CurrentZone = zoneName
+	w.loadMapForZone(w.CurrentZone)
+	w.Breadcrumbs = append(w.Breadcrumbs[:0], config.LoadBreadcrumbs(w.trailCharacter, w.CurrentZone)...)
+}
+
+// browsing reports whether the map shows a zone other than the player's.
+func (w *Window) browsing() bool {
+	return w.CurrentZone != w.logZone
+}
+
 func (w *Window) loadMapForZone(zoneName string) {
 	fmt.Printf("\n🗺️  Loading zone: '%s'\n", zoneName)
 	fileCode := maps.GetZoneFileName(zoneName)
@@ -364,6 +672,13 @@ func (w *Window) loadMapForZone(zoneName string) {
 		fmt.Printf("  Mapped to file: '%s'\n", fileCode)
 	}
 
+	// Watch the zone's files so edits show up live
+	if w.mapWatcher != nil {
+		w.mapWatcher.Stop()
+	}
+	w.mapFileCode = fileCode
+	w.mapWatcher = maps.WatchZone(w.MapDir, fileCode, time.Second)
+
 	data, err := maps.LoadZone(w.MapDir, fileCode)
 	if err != nil {
 		fmt.Printf("❌ Error loading map %s: %v\n", zoneName, err)
@@ -382,7 +697,7 @@ func (w *Window) loadMapForZone(zoneName string) {
 			// Calculate bounds for current Z-level
 			var activeZ float64
 			if w.ZLevelMode == 1 {
-				activeZ = w.LogReader.CurrentState.Z
+				activeZ = w.LogReader.State().Z
 			} else {
 				activeZ = w.ZLevelManual
 			}
@@ -441,20 +756,59 @@ func (w *Window) loadMapForZone(zoneName string) {
 }
 
 func (w *Window) getMarkerColor(colorName string) color.RGBA {
-	switch colorName {
-	case "red":
-		return color.RGBA{255, 0, 0, 255}
-	case "blue":
-		return color.RGBA{0, 100, 255, 255}
-	case "green":
-		return color.RGBA{0, 255, 0, 255}
-	case "yellow":
-		return color.RGBA{255, 255, 0, 255}
-	case "purple":
-		return color.RGBA{200, 0, 255, 255}
-	default:
-		return color.RGBA{255, 0, 0, 255} // Default to red
+	return config.ParseColor(colorName)
+}
+
+// pickMarkerColor opens the system color picker and makes the chosen color
+// current for new markers, remembering it in the recent swatches.
+func (w *Window) pickMarkerColor() {
+	w.dialogOpen = true
+	picked, err := zenity.SelectColor(
+		zenity.Title("Marker Color"),
+		zenity.Color(w.getMarkerColor(w.markerColor)),
+	)
+	w.dialogOpen = false
+	w.lastMousePressed = true
+
+	if err != nil || picked == nil {
+		return
+	}
+
+	w.setMarkerColor(config.HexColor(picked))
+}
+
+func (w *Window) setMarkerColor(hex string) {
+	w.markerColor = hex
+	w.Config.AddRecentColor(hex)
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving recent colors: %v\n", err)
+	}
+}
+
+// colorSubmenu lists the color picker followed by the recent color swatches.
+func (w *Window) colorSubmenu() []MenuItem {
+	items := []MenuItem{
+		{
+			Label: "Pick Color...",
+			Action: func() {
+				w.openMenu = ""
+				w.pickMarkerColor()
+			},
+		},
+	}
+	for _, hex := range w.Config.RecentColors {
+		hex := hex
+		swatch := config.ParseColor(hex)
+		items = append(items, MenuItem{
+			Label:  hex,
+			Swatch: &swatch,
+			Action: func() {
+				w.setMarkerColor(hex)
+				w.openMenu = ""
+			},
+		})
 	}
+	return items
 }
 
 func (w *Window) drawMarkerShape(screen *ebiten.Image, mx, my float32, shape string, markerColor color.RGBA) {
@@ -468,14 +822,14 @@ func (w *Window) drawMarkerShape(screen *ebiten.Image, mx, my float32, shape str
 
 	switch shape {
 	case "circle":
-		vector.DrawFilledCircle(screen, mx, my, size, markerColor, true)
-		vector.StrokeCircle(screen, mx, my, size, 2.0, blackOutline, true)
+		vector.DrawFilledCircle(screen, mx, my, size, markerColor, w.antiAlias)
+		vector.StrokeCircle(screen, mx, my, size, 2.0, blackOutline, w.antiAlias)
 
 	case "square":
 		// Draw filled square
-		vector.DrawFilledRect(screen, mx-size, my-size, size*2, size*2, markerColor, true)
+		vector.DrawFilledRect(screen, mx-size, my-size, size*2, size*2, markerColor, w.antiAlias)
 		// Draw outline
-		vector.StrokeRect(screen, mx-size, my-size, size*2, size*2, 2.0, blackOutline, true)
+		vector.StrokeRect(screen, mx-size, my-size, size*2, size*2, 2.0, blackOutline, w.antiAlias)
 
 	case "triangle":
 		// Draw upward-pointing triangle
@@ -493,13 +847,13 @@ func (w *Window) drawMarkerShape(screen *ebiten.Image, mx, my float32, shape str
 			vertices[i].ColorA = float32(markerColor.A) / 255
 		}
 		screen.DrawTriangles(vertices, indices, whiteImage, &ebiten.DrawTrianglesOptions{
-			AntiAlias: true,
+			AntiAlias: w.antiAlias,
 		})
 
 		// Draw outline
-		vector.StrokeLine(screen, mx, my-size, mx+size, my+size, 2.0, blackOutline, true)
-		vector.StrokeLine(screen, mx+size, my+size, mx-size, my+size, 2.0, blackOutline, true)
-		vector.StrokeLine(screen, mx-size, my+size, mx, my-size, 2.0, blackOutline, true)
+		vector.StrokeLine(screen, mx, my-size, mx+size, my+size, 2.0, blackOutline, w.antiAlias)
+		vector.StrokeLine(screen, mx+size, my+size, mx-size, my+size, 2.0, blackOutline, w.antiAlias)
+		vector.StrokeLine(screen, mx-size, my+size, mx, my-size, 2.0, blackOutline, w.antiAlias)
 
 	case "diamond":
 		// Draw diamond (rotated square)
@@ -518,14 +872,14 @@ func (w *Window) drawMarkerShape(screen *ebiten.Image, mx, my float32, shape str
 			vertices[i].ColorA = float32(markerColor.A) / 255
 		}
 		screen.DrawTriangles(vertices, indices, whiteImage, &ebiten.DrawTrianglesOptions{
-			AntiAlias: true,
+			AntiAlias: w.antiAlias,
 		})
 
 		// Draw outline
-		vector.StrokeLine(screen, mx, my-size, mx+size, my, 2.0, blackOutline, true)
-		vector.StrokeLine(screen, mx+size, my, mx, my+size, 2.0, blackOutline, true)
-		vector.StrokeLine(screen, mx, my+size, mx-size, my, 2.0, blackOutline, true)
-		vector.StrokeLine(screen, mx-size, my, mx, my-size, 2.0, blackOutline, true)
+		vector.StrokeLine(screen, mx, my-size, mx+size, my, 2.0, blackOutline, w.antiAlias)
+		vector.StrokeLine(screen, mx+size, my, mx, my+size, 2.0, blackOutline, w.antiAlias)
+		vector.StrokeLine(screen, mx, my+size, mx-size, my, 2.0, blackOutline, w.antiAlias)
+		vector.StrokeLine(screen, mx-size, my, mx, my-size, 2.0, blackOutline, w.antiAlias)
 
 	case "star":
 		// Draw 5-pointed star
@@ -558,7 +912,7 @@ func (w *Window) drawMarkerShape(screen *ebiten.Image, mx, my float32, shape str
 			vertices[i].ColorA = float32(markerColor.A) / 255
 		}
 		screen.DrawTriangles(vertices, indices, whiteImage, &ebiten.DrawTrianglesOptions{
-			AntiAlias: true,
+			AntiAlias: w.antiAlias,
 		})
 
 		// Draw outline by connecting all points
@@ -577,14 +931,89 @@ func (w *Window) drawMarkerShape(screen *ebiten.Image, mx, my float32, shape str
 			y1 := my + float32(math.Sin(angle1)*float64(radius1))
 			x2 := mx + float32(math.Cos(angle2)*float64(radius2))
 			y2 := my + float32(math.Sin(angle2)*float64(radius2))
-			vector.StrokeLine(screen, x1, y1, x2, y2, 2.0, blackOutline, true)
+			vector.StrokeLine(screen, x1, y1, x2, y2, 2.0, blackOutline, w.antiAlias)
 		}
 
 	default:
+		if ops, ok := w.customShapes[shape]; ok {
+			w.drawCustomShape(screen, mx, my, size, ops, markerColor)
+			return
+		}
 		// Fallback to circle
-		vector.DrawFilledCircle(screen, mx, my, size, markerColor, true)
-		vector.StrokeCircle(screen, mx, my, size, 2.0, blackOutline, true)
+		vector.DrawFilledCircle(screen, mx, my, size, markerColor, w.antiAlias)
+		vector.StrokeCircle(screen, mx, my, size, 2.0, blackOutline, w.antiAlias)
+	}
+}
+
+// drawCustomShape renders a user-defined shape (unit-box path ops scaled by size).
+func (w *Window) drawCustomShape(screen *ebiten.Image, mx, my, size float32, ops []config.ShapeOp, markerColor color.RGBA) {
+	var path vector.Path
+	for _, op := range ops {
+		x := mx + float32(op.X)*size
+		y := my + float32(op.Y)*size
+		switch op.Op {
+		case "move":
+			path.MoveTo(x, y)
+		case "line":
+			path.LineTo(x, y)
+		case "arc":
+			start := float32(op.Start * math.Pi / 180)
+			end := float32(op.End * math.Pi / 180)
+			path.Arc(x, y, float32(op.R)*size, start, end, vector.Clockwise)
+		case "close":
+			path.Close()
+		}
+	}
+
+	vertices, indices := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	for i := range vertices {
+		vertices[i].ColorR = float32(markerColor.R) / 255
+		vertices[i].ColorG = float32(markerColor.G) / 255
+		vertices[i].ColorB = float32(markerColor.B) / 255
+		vertices[i].ColorA = float32(markerColor.A) / 255
+	}
+	screen.DrawTriangles(vertices, indices, whiteImage, &ebiten.DrawTrianglesOptions{
+		AntiAlias: w.antiAlias,
+	})
+
+	// Draw outline
+	vertices, indices = path.AppendVerticesAndIndicesForStroke(nil, nil, &vector.StrokeOptions{
+		Width:    2.0,
+		LineJoin: vector.LineJoinRound,
+	})
+	for i := range vertices {
+		vertices[i].ColorR = 0
+		vertices[i].ColorG = 0
+		vertices[i].ColorB = 0
+		vertices[i].ColorA = 1
+	}
+	screen.DrawTriangles(vertices, indices, whiteImage, &ebiten.DrawTrianglesOptions{
+		AntiAlias: w.antiAlias,
+	})
+}
+
+// shapeSubmenu lists the built-in shapes followed by any custom shapes.
+func (w *Window) shapeSubmenu() []MenuItem {
+	names := []string{"circle", "square", "triangle", "diamond", "star"}
+	custom := make([]string, 0, len(w.customShapes))
+	for name := range w.customShapes {
+		custom = append(custom, name)
+	}
+	sort.Strings(custom)
+	names = append(names, custom...)
+
+	items := make([]MenuItem, 0, len(names))
+	for _, name := range names {
+		name := name
+		items = append(items, MenuItem{
+			Label: strings.ToUpper(name[:1]) + name[1:],
+			Action: func() {
+				w.markerShape = name
+				w.openMenu = ""
+			},
+		})
 	}
+	return items
 }
 
 func (w *Window) placeMarker(worldX, worldY float64) {
@@ -594,108 +1023,93 @@ func (w *Window) placeMarker(worldX, worldY float64) {
 	}
 
 	// Prompt for marker label
-	markerCount := len(w.Config.Markers[w.CurrentZone]) + 1
+	zone := w.CurrentZone
+	markerCount := len(w.Config.ZoneMarkers(zone)) + 1
 	defaultLabel := fmt.Sprintf("Marker %d", markerCount)
 
-	w.dialogOpen = true
-	label, err := zenity.Entry(
-		"Enter marker label:",
-		zenity.Title("New Marker"),
-		zenity.EntryText(defaultLabel),
-	)
-	w.dialogOpen = false
-	w.lastMousePressed = true // Prevent re-triggering on dialog close
-
-	// If user cancelled or error occurred, do nothing
-	if err != nil {
-		fmt.Println("📍 Marker placement cancelled")
-		w.placingMarker = false
-		return
-	}
+	// Exit placement mode once the label is asked for
+	w.placingMarker = false
 
-	// Use default if empty
-	if label == "" {
-		label = defaultLabel
-	}
+	input := widgets.NewTextInput("New Marker", "Enter marker label:", defaultLabel)
+	w.showModal(input, func(r widgets.Result) {
+		// If user cancelled, do nothing
+		if r != widgets.OK {
+			fmt.Println("📍 Marker placement cancelled")
+			return
+		}
 
-	marker := config.Marker{
-		X:     worldX,
-		Y:     worldY,
-		Label: label,
-		Color: w.markerColor,
-		Shape: w.markerShape,
-	}
+		// Use default if empty
+		label := strings.TrimSpace(input.Value())
+		if label == "" {
+			label = defaultLabel
+		}
 
-	// Add marker to config
-	w.Config.Markers[w.CurrentZone] = append(w.Config.Markers[w.CurrentZone], marker)
+		marker := config.Marker{
+			ID:       config.NewMarkerID(),
+			X:        worldX,
+			Y:        worldY,
+			Label:    label,
+			Color:    w.markerColor,
+			Shape:    w.markerShape,
+			Category: w.markerCategory,
+		}
 
-	// Save to disk
-	if err := w.Config.Save(); err != nil {
-		fmt.Printf("❌ Error saving marker: %v\n", err)
-	} else {
-		fmt.Printf("📍 Marker placed: '%s' at (%.1f, %.1f) in %s\n", label, worldX, worldY, w.CurrentZone)
-	}
+		// Add marker to config
+		w.pushUndoStep(w.snapshotZone(fmt.Sprintf("place marker '%s'", label), zone))
+		w.Config.SetZoneMarkers(zone, append(w.Config.ZoneMarkers(zone), marker))
+		w.syncMarker(zone, marker)
 
-	// Exit placement mode after placing marker
-	w.placingMarker = false
+		// Save to disk
+		if err := w.Config.Save(); err != nil {
+			fmt.Printf("❌ Error saving marker: %v\n", err)
+		} else {
+			fmt.Printf("📍 Marker placed: '%s' at (%.1f, %.1f) in %s\n", label, worldX, worldY, zone)
+		}
+	})
 }
 
+// removeMarkerAt asks to delete the marker under a right-click; false if
+// there is none.
 func (w *Window) removeMarkerAt(worldX, worldY float64) bool {
 	if w.CurrentZone == "" {
 		return false
 	}
 
-	markers, ok := w.Config.Markers[w.CurrentZone]
-	if !ok || len(markers) == 0 {
+	i := w.markerIndexAt(worldX, worldY)
+	if i < 0 {
 		return false
 	}
+	zone := w.CurrentZone
+	marker := w.Config.ZoneMarkers(zone)[i]
 
-	// Check if click is within range of any marker
-	// Use a fixed click radius of 15 units in world space
-	clickRadius := 15.0 / w.Zoom
-
-	for i, marker := range markers {
-		dx := worldX - marker.X
-		dy := worldY - marker.Y
-		distance := math.Sqrt(dx*dx + dy*dy)
-
-		if distance <= clickRadius {
-			// Confirm deletion
-			w.dialogOpen = true
-			err := zenity.Question(
-				fmt.Sprintf("Delete marker '%s'?", marker.Label),
-				zenity.Title("Confirm Delete"),
-				zenity.OKLabel("Delete"),
-				zenity.CancelLabel("Cancel"),
-			)
-			w.dialogOpen = false
-			w.lastMousePressed = true // Prevent re-triggering
-
-			if err != nil {
-				// User cancelled
-				return false
-			}
-
-			// Remove this marker
-			w.Config.Markers[w.CurrentZone] = append(markers[:i], markers[i+1:]...)
+	// Confirm deletion
+	confirm := widgets.NewConfirm("Confirm Delete", fmt.Sprintf("Delete marker '%s'?", marker.Label), "Delete", "Cancel")
+	w.showModal(confirm, func(r widgets.Result) {
+		if r != widgets.OK {
+			// User cancelled
+			return
+		}
 
-			// Remove the zone entry if no markers left
-			if len(w.Config.Markers[w.CurrentZone]) == 0 {
-				delete(w.Config.Markers, w.CurrentZone)
-			}
+		// Find it again; it may have moved in the slice while the dialog was open
+		markers := w.Config.ZoneMarkers(zone)
+		i := slices.IndexFunc(markers, func(m config.Marker) bool { return m.ID == marker.ID })
+		if i < 0 {
+			return
+		}
 
-			// Save to disk
-			if err := w.Config.Save(); err != nil {
-				fmt.Printf("❌ Error removing marker: %v\n", err)
-			} else {
-				fmt.Printf("🗑️  Marker removed: '%s' from %s\n", marker.Label, w.CurrentZone)
-			}
+		// Remove this marker
+		w.pushUndoStep(w.snapshotZone(fmt.Sprintf("delete marker '%s'", marker.Label), zone))
+		w.Config.SetZoneMarkers(zone, append(markers[:i], markers[i+1:]...))
+		w.syncRemove(zone, marker)
 
-			return true
+		// Save to disk
+		if err := w.Config.Save(); err != nil {
+			fmt.Printf("❌ Error removing marker: %v\n", err)
+		} else {
+			fmt.Printf("🗑️  Marker removed: '%s' from %s\n", marker.Label, zone)
 		}
-	}
-
-	return false
+	})
+	return true
 }
 
 func (w *Window) clearAllMarkers() {
@@ -703,98 +1117,88 @@ func (w *Window) clearAllMarkers() {
 		return
 	}
 
-	markers, ok := w.Config.Markers[w.CurrentZone]
-	if !ok || len(markers) == 0 {
-		w.dialogOpen = true
-		zenity.Info(
-			"No markers to delete in this zone.",
-			zenity.Title("No Markers"),
-		)
-		w.dialogOpen = false
-		w.lastMousePressed = true
+	zone := w.CurrentZone
+	markers := w.Config.ZoneMarkers(zone)
+	if len(markers) == 0 {
+		w.showModal(widgets.NewConfirm("No Markers", "No markers to delete in this zone.", "OK", ""), nil)
 		return
 	}
 
 	// Confirm deletion
-	w.dialogOpen = true
-	err := zenity.Question(
-		fmt.Sprintf("Delete all %d markers in %s?", len(markers), w.CurrentZone),
-		zenity.Title("Confirm Delete All"),
-		zenity.OKLabel("Delete All"),
-		zenity.CancelLabel("Cancel"),
-	)
-	w.dialogOpen = false
-	w.lastMousePressed = true
-
-	if err != nil {
-		// User cancelled
-		return
-	}
+	confirm := widgets.NewConfirm("Confirm Delete All", fmt.Sprintf("Delete all %d markers in %s?", len(markers), zone), "Delete All", "Cancel")
+	w.showModal(confirm, func(r widgets.Result) {
+		if r != widgets.OK {
+			// User cancelled
+			return
+		}
 
-	// Delete all markers in current zone
-	delete(w.Config.Markers, w.CurrentZone)
+		// Delete all markers in the zone, including any added since the dialog opened
+		markers := w.Config.ZoneMarkers(zone)
+		w.pushUndoStep(w.snapshotZone(fmt.Sprintf("clear all (%d markers)", len(markers)), zone))
+		w.Config.SetZoneMarkers(zone, nil)
+		for _, m := range markers {
+			w.syncRemove(zone, m)
+		}
 
-	// Save to disk
-	if err := w.Config.Save(); err != nil {
-		fmt.Printf("❌ Error deleting markers: %v\n", err)
-	} else {
-		fmt.Printf("🗑️  Deleted all %d markers from %s\n", len(markers), w.CurrentZone)
-	}
+		// Save to disk
+		if err := w.Config.Save(); err != nil {
+			fmt.Printf("❌ Error deleting markers: %v\n", err)
+		} else {
+			fmt.Printf("🗑️  Deleted all %d markers from %s\n", len(markers), zone)
+		}
+	})
 }
 
-func (w *Window) editMarkerAt(worldX, worldY float64) {
+// editMarkerAt edits the marker under a click; false if there is none.
+func (w *Window) editMarkerAt(worldX, worldY float64) bool {
 	if w.CurrentZone == "" {
-		return
+		return false
 	}
 
-	markers, ok := w.Config.Markers[w.CurrentZone]
-	if !ok || len(markers) == 0 {
-		return
+	i := w.markerIndexAt(worldX, worldY)
+	if i < 0 {
+		return false
 	}
+	zone := w.CurrentZone
+	marker := w.Config.ZoneMarkers(zone)[i]
+
+	// Show text input dialog for label
+	input := widgets.NewTextInput("Edit Marker", "Edit marker label:", marker.Label).WithExtra("Options...")
+	w.showModal(input, func(r widgets.Result) {
+		switch r {
+		case widgets.Extra:
+			w.markerOptions(zone, marker.ID)
+			return
+		case widgets.OK:
+		default:
+			// User cancelled
+			return
+		}
 
-	// Check if click is within range of any marker
-	// Use a fixed click radius of 15 units in world space
-	clickRadius := 15.0 / w.Zoom
-
-	for i, marker := range markers {
-		dx := worldX - marker.X
-		dy := worldY - marker.Y
-		distance := math.Sqrt(dx*dx + dy*dy)
-
-		if distance <= clickRadius {
-			// Show text input dialog for label
-			w.dialogOpen = true
-			newLabel, err := zenity.Entry(
-				"Edit marker label:",
-				zenity.Title("Edit Marker"),
-				zenity.EntryText(marker.Label),
-			)
-			w.dialogOpen = false
-			w.lastMousePressed = true // Prevent re-triggering on dialog close
-
-			// If user cancelled, do nothing
-			if err != nil {
-				return
-			}
-
-			// If empty, keep existing label
-			if newLabel == "" {
-				newLabel = marker.Label
-			}
+		m := w.markerByID(zone, marker.ID)
+		if m == nil {
+			return
+		}
 
-			// Update the marker label
-			w.Config.Markers[w.CurrentZone][i].Label = newLabel
+		// If empty, keep existing label
+		newLabel := strings.TrimSpace(input.Value())
+		if newLabel == "" || newLabel == m.Label {
+			return
+		}
 
-			// Save to disk
-			if err := w.Config.Save(); err != nil {
-				fmt.Printf("❌ Error updating marker: %v\n", err)
-			} else {
-				fmt.Printf("📝 Marker updated: '%s' -> '%s' in %s\n", marker.Label, newLabel, w.CurrentZone)
-			}
+		// Update the marker label
+		w.pushUndoStep(w.snapshotZone(fmt.Sprintf("rename marker '%s'", m.Label), zone))
+		m.Label = newLabel
+		w.syncMarker(zone, *m)
 
-			return
+		// Save to disk
+		if err := w.Config.Save(); err != nil {
+			fmt.Printf("❌ Error updating marker: %v\n", err)
+		} else {
+			fmt.Printf("📝 Marker updated: '%s' -> '%s' in %s\n", marker.Label, newLabel, zone)
 		}
-	}
+	})
+	return true
 }
 
 func (w *Window) refitZoom() {
@@ -809,7 +1213,7 @@ func (w *Window) refitZoom() {
 		// Calculate bounds for current Z-level
 		var activeZ float64
 		if w.ZLevelMode == 1 {
-			activeZ = w.LogReader.CurrentState.Z
+			activeZ = w.LogReader.State().Z
 		} else {
 			activeZ = w.ZLevelManual
 		}
@@ -865,132 +1269,176 @@ func (w *Window) refitZoom() {
 }
 
 func (w *Window) Draw(screen *ebiten.Image) {
-	// Create offscreen image for all map content
-	offscreen := ebiten.NewImage(w.Width, w.Height)
-	offscreen.Fill(color.Black)
+	// Offscreen image for all map content, reused until the size changes
+	if w.offscreen == nil {
+		w.offscreen = ebiten.NewImage(w.Width, w.Height)
+	}
+	offscreen := w.offscreen
+	background := w.mapBackground()
+	offscreen.Fill(background.color)
 
 	cx, cy := float64(w.Width)/2, float64(w.Height)/2
+	clean := w.cleanFrame() // Captures show the map and markers only
 
-	if w.MapData != nil {
-		// Determine active Z level for filtering (if enabled)
-		var activeZ float64
-		if w.ZLevelMode == 1 && w.LogReader != nil {
-			// Auto mode
-			activeZ = w.LogReader.CurrentState.Z
-		} else if w.ZLevelMode == 2 {
-			// Manual mode
-			activeZ = w.ZLevelManual
-		}
+	// Overlay layers in the user's draw order (see drawlayers.go)
+	w.drawLayers(offscreen, cx, cy, clean)
 
-		// DRAW LINES with stroke width for better visibility
-		lineWidth := float32(1.5)
-		if w.Zoom > 2.0 {
-			lineWidth = float32(2.0)
-		}
+	if clean {
+		w.drawCleanFrame(screen, offscreen)
+		return
+	}
 
-		for _, line := range w.MapData.Lines {
-			// Z-Level filtering: skip lines outside the Z range (if mode is not off)
-			if w.ZLevelMode > 0 {
-				// Check if either endpoint is within range
-				z1InRange := math.Abs(line.Z1-activeZ) <= w.ZLevelRange
-				z2InRange := math.Abs(line.Z2-activeZ) <= w.ZLevelRange
-				if !z1InRange && !z2InRange {
-					continue
-				}
-			}
+	// Apply opacity to entire screen and enable filtering for anti-aliasing
+	opts := &ebiten.DrawImageOptions{}
+	opts.ColorScale.ScaleAlpha(float32(w.Opacity))
+	opts.Filter = ebiten.FilterLinear
+	screen.DrawImage(offscreen, opts)
 
-			x1 := float32((line.X1 - w.CamX) * w.Zoom + cx)
-			y1 := float32((line.Y1 - w.CamY) * w.Zoom + cy)
-			x2 := float32((line.X2 - w.CamX) * w.Zoom + cx)
-			y2 := float32((line.Y2 - w.CamY) * w.Zoom + cy)
-			vector.StrokeLine(offscreen, x1, y1, x2, y2, lineWidth, line.Color, true)
-		}
+	// DRAW UI / DEBUG (drawn after offscreen is composited, so UI is always at full opacity)
+	w.drawUI(screen)
 
-		// DRAW LABELS (based on mode)
-		// 0 = all, 1 = custom+zone lines, 2 = zone lines only, 3 = none
-		if w.LabelMode < 3 {
-			for _, lbl := range w.MapData.Labels {
-				// Zone lines start with "to " (underscores were replaced with spaces)
-				isZoneLine := len(lbl.Text) >= 3 && lbl.Text[:3] == "to "
+	if w.notesEditing {
+		w.drawNotesEditor(screen)
+	}
+	if w.rebindingAction != "" {
+		w.drawRebindPrompt(screen)
+	}
+	w.drawChordHint(screen)
+	w.drawModal(screen)
+}
 
-				// Filter based on mode
-				if w.LabelMode == 2 && !isZoneLine {
-					// Mode 2: zone lines only - skip non-zone labels
-					continue
-				} else if w.LabelMode == 1 && !isZoneLine {
-					// Mode 1: custom+zone lines - skip map labels (but custom markers will be drawn later)
-					continue
-				}
+// drawMapLines draws the zone's geometry from the cached mesh.
+func (w *Window) drawMapLines(offscreen *ebiten.Image, cx, cy float64) {
+	if w.MapData == nil {
+		return
+	}
+
+	// Determine active Z level for filtering (if enabled)
+	var activeZ float64
+	if w.ZLevelMode == 1 && w.LogReader != nil {
+		// Auto mode
+		activeZ = w.LogReader.State().Z
+	} else if w.ZLevelMode == 2 {
+		// Manual mode
+		activeZ = w.ZLevelManual
+	}
+
+	// DRAW LINES with stroke width for better visibility
+	lineWidth := float32(1.5)
+	if w.Zoom > 2.0 {
+		lineWidth = float32(2.0)
+	}
 
-				lx := (lbl.X - w.CamX) * w.Zoom + cx
-				ly := (lbl.Y - w.CamY) * w.Zoom + cy
+	// Map geometry comes from the cached mesh, rebuilt only when the
+	// zone, Z-filter or colors change
+	w.mesh.ensure(w.MapData, w.ZLevelMode, activeZ, w.ZLevelRange, w.hiddenLayerMask(), w.paletteSpec(), w.Zoom)
+	w.mesh.draw(offscreen, w.CamX, w.CamY, w.Zoom, w.rotation, cx, cy, lineWidth, w.antiAlias)
+}
+
+func (w *Window) drawMapLabels(offscreen *ebiten.Image, cx, cy float64) {
+	if w.MapData == nil {
+		return
+	}
+
+	// DRAW LABELS (based on mode and each label's priority, see labelpriority.go)
+	// 0 = all, 1 = important+markers, 2 = important only, 3 = none
+	if w.LabelMode < 3 {
+		hiddenLayers := w.hiddenLayerMask()
+		priorities := w.mapLabelPriorities()
+		themeLabels := w.theme().labels
+		for i, lbl := range w.MapData.Labels {
+			if hiddenLayers&(1<<lbl.Layer) != 0 || !w.labelShown(priorities[i]) {
+				continue
+			}
+
+			lx, ly := w.toScreen(lbl.X, lbl.Y, cx, cy)
 
-				if lx > -50 && lx < float64(w.Width)+50 && ly > -50 && ly < float64(w.Height)+50 {
-					text.Draw(offscreen, lbl.Text, basicfont.Face7x13, int(lx), int(ly), lbl.Color)
+			// Rough cull: a label starts at its point and runs right
+			px := w.labelPixelSize(lbl.Size)
+			margin := float64(px * len(lbl.Text))
+			if lx > -margin && lx < float64(w.Width)+50 && ly > -50 && ly < float64(w.Height)+float64(px) {
+				c := w.mesh.palette.get(lbl.Color)
+				if themeLabels.A > 0 {
+					c = themeLabels
 				}
+				drawText(offscreen, lbl.Text, w.labelFace(px), int(lx), int(ly), c)
 			}
 		}
+	}
+}
 
-		// DRAW BREADCRUMBS as filled circles (if enabled)
-		if w.ShowBreadcrumbs {
-			breadcrumbColor := color.RGBA{255, 255, 0, 200}
-			breadcrumbSize := float32(1.5)
-			for _, bc := range w.Breadcrumbs {
-				bx := float32((bc.X - w.CamX) * w.Zoom + cx)
-				by := float32((bc.Y - w.CamY) * w.Zoom + cy)
-				vector.DrawFilledCircle(offscreen, bx, by, breadcrumbSize, breadcrumbColor, true)
-			}
+func (w *Window) drawBreadcrumbs(offscreen *ebiten.Image, cx, cy float64) {
+	if w.MapData == nil {
+		return
+	}
+
+	// DRAW BREADCRUMBS as filled circles (if enabled)
+	if w.ShowBreadcrumbs {
+		breadcrumbColor := color.RGBA{255, 255, 0, 200}
+		breadcrumbSize := float32(1.5)
+		for _, bc := range w.Breadcrumbs {
+			bx, by := w.toScreen32(bc.X, bc.Y, cx, cy)
+			vector.DrawFilledCircle(offscreen, bx, by, breadcrumbSize, breadcrumbColor, w.antiAlias)
 		}
 	}
+}
 
+func (w *Window) drawMarkers(offscreen *ebiten.Image, cx, cy float64) {
 	// DRAW CUSTOM MARKERS for current zone
 	if w.ShowMarkers {
-		if markers, ok := w.Config.Markers[w.CurrentZone]; ok {
+		if markers := w.Config.ZoneMarkers(w.CurrentZone); len(markers) > 0 {
 			for _, marker := range markers {
-				mx := float32((marker.X - w.CamX) * w.Zoom + cx)
-				my := float32((marker.Y - w.CamY) * w.Zoom + cy)
+				// Skip markers hidden by a completed task or their category
+				if w.Config.MarkerHidden(w.CurrentZone, marker.ID) || w.Config.CategoryHidden(marker.Category) {
+					continue
+				}
+
+				mx, my := w.toScreen32(marker.X, marker.Y, cx, cy)
 
 				// Get marker color
 				markerColor := w.getMarkerColor(marker.Color)
 
+				if marker.Radius > 0 {
+					w.drawMarkerRadius(offscreen, mx, my, marker.Radius, markerColor)
+				}
+
 				// Draw marker with selected shape
 				w.drawMarkerShape(offscreen, mx, my, marker.Shape, markerColor)
 
 				// Draw label based on label mode
-				// 0 = all labels, 1 = custom+zone lines, 2 = zone lines only, 3 = none
+				// 0 = all labels, 1 = important+markers, 2 = important only, 3 = none
 				if w.LabelMode <= 1 {
-					text.Draw(offscreen, marker.Label, basicfont.Face7x13, int(mx)+10, int(my)+4, color.RGBA{255, 200, 0, 255})
+					w.drawUIText(offscreen, marker.Label, int(mx)+10, int(my)+4, color.RGBA{255, 200, 0, 255})
 				}
 			}
 		}
 	}
+}
 
-	// DRAW CORPSE MARKER (only if in same zone)
-	if w.LogReader != nil && w.LogReader.CurrentState.HasCorpse && w.LogReader.CurrentState.CorpseZone == w.CurrentZone {
-		w.drawCorpseMarker(offscreen, cx, cy)
+// drawOthers draws party members, sync peers and locs posted in chat.
+func (w *Window) drawOthers(offscreen *ebiten.Image, cx, cy float64) {
+	// DRAW PARTY MEMBERS (other tracked characters in this zone)
+	if w.ShowParty && w.LogReader != nil {
+		for i, member := range w.LogReader.PartyMembers() {
+			if member.Zone != w.CurrentZone {
+				continue
+			}
+			c := partyColor(i)
+			px, py := w.drawArrow(offscreen, cx, cy, member, c)
+			w.drawUIText(offscreen, member.Character, int(px)+12, int(py)+4, c)
+			w.drawAFKBadge(offscreen, px, py, member.MovedTime, c)
+		}
 	}
-
-	// DRAW PLAYER ARROW
-	if w.LogReader != nil {
-		w.drawPlayerArrow(offscreen, cx, cy)
+	w.drawSyncPeers(offscreen, cx, cy)
+	if w.ShowParty {
+		w.drawChatLocs(offscreen, cx, cy)
 	}
+}
 
-	// Apply opacity to entire screen and enable filtering for anti-aliasing
-	opts := &ebiten.DrawImageOptions{}
-	opts.ColorScale.ScaleAlpha(float32(w.Opacity))
-	opts.Filter = ebiten.FilterLinear
-	screen.DrawImage(offscreen, opts)
-
-	// DRAW UI / DEBUG (drawn after offscreen is composited, so UI is always at full opacity)
-	w.drawUI(screen)
-}
-
-func (w *Window) drawCorpseMarker(screen *ebiten.Image, cx, cy float64) {
-	s := w.LogReader.CurrentState
-
-	// Convert Corpse World Pos to Screen Pos
-	corpseX := float32((s.CorpseX - w.CamX) * w.Zoom + cx)
-	corpseY := float32((s.CorpseY - w.CamY) * w.Zoom + cy)
+// drawCorpseAt draws the skull marker at a world position, with an optional label.
+func (w *Window) drawCorpseAt(screen *ebiten.Image, cx, cy, worldX, worldY float64, label string) {
+	// Convert Corpse World Pos to Screen Pos
+	corpseX, corpseY := w.toScreen32(worldX, worldY, cx, cy)
 
 	size := float32(12.0 * w.Zoom)
 	if size < 10 { size = 10 }
@@ -999,26 +1447,51 @@ func (w *Window) drawCorpseMarker(screen *ebiten.Image, cx, cy float64) {
 	c := color.RGBA{255, 0, 0, 255}
 
 	// Draw filled circle background
-	vector.DrawFilledCircle(screen, corpseX, corpseY, size, color.RGBA{255, 0, 0, 100}, true)
+	vector.DrawFilledCircle(screen, corpseX, corpseY, size, color.RGBA{255, 0, 0, 100}, w.antiAlias)
 
 	// Draw stroke circle
-	vector.StrokeCircle(screen, corpseX, corpseY, size, 2.5, c, true)
+	vector.StrokeCircle(screen, corpseX, corpseY, size, 2.5, c, w.antiAlias)
 
 	// Draw X with thicker lines
 	strokeWidth := float32(3.0)
-	vector.StrokeLine(screen, corpseX-size*0.6, corpseY-size*0.6, corpseX+size*0.6, corpseY+size*0.6, strokeWidth, c, true)
-	vector.StrokeLine(screen, corpseX-size*0.6, corpseY+size*0.6, corpseX+size*0.6, corpseY-size*0.6, strokeWidth, c, true)
+	vector.StrokeLine(screen, corpseX-size*0.6, corpseY-size*0.6, corpseX+size*0.6, corpseY+size*0.6, strokeWidth, c, w.antiAlias)
+	vector.StrokeLine(screen, corpseX-size*0.6, corpseY+size*0.6, corpseX+size*0.6, corpseY-size*0.6, strokeWidth, c, w.antiAlias)
+
+	if label != "" {
+		w.drawUIText(screen, label, int(corpseX+size)+4, int(corpseY)+4, c)
+	}
 }
 
 func (w *Window) drawPlayerArrow(screen *ebiten.Image, cx, cy float64) {
-	s := w.LogReader.CurrentState
+	s := w.LogReader.State()
+	s.X, s.Y = w.playerPosition()
+	c := color.RGBA{0, 255, 0, 255}
+	px, py := w.drawArrow(screen, cx, cy, s, c)
+	w.drawAFKBadge(screen, px, py, s.MovedTime, c)
+}
+
+// partyColors distinguishes party members from each other and from the
+// player's own green arrow.
+var partyColors = []color.RGBA{
+	{0, 200, 255, 255},   // Cyan
+	{255, 140, 0, 255},   // Orange
+	{255, 80, 200, 255},  // Pink
+	{180, 120, 255, 255}, // Lavender
+	{255, 255, 120, 255}, // Pale yellow
+	{120, 255, 200, 255}, // Mint
+}
 
+func partyColor(i int) color.RGBA {
+	return partyColors[i%len(partyColors)]
+}
+
+// drawArrow draws a heading arrow for any character and returns its screen position.
+func (w *Window) drawArrow(screen *ebiten.Image, cx, cy float64, s parser.PlayerState, c color.RGBA) (float32, float32) {
 	// Convert Player World Pos to Screen Pos
-	px := float32((s.X - w.CamX) * w.Zoom + cx)
-	py := float32((s.Y - w.CamY) * w.Zoom + cy)
+	px, py := w.toScreen32(s.X, s.Y, cx, cy)
 
-	// Heading
-	angle := s.Heading
+	// Heading, turned with the map
+	angle := s.Heading + w.rotation.angle
 
 	size := float32(10.0 * w.Zoom)
 	if size < 8 { size = 8 }
@@ -1034,8 +1507,6 @@ func (w *Window) drawPlayerArrow(screen *ebiten.Image, cx, cy float64) {
 	x3 := px + float32(math.Cos(angle - 2.6))*size
 	y3 := py + float32(math.Sin(angle - 2.6))*size
 
-	c := color.RGBA{0, 255, 0, 255}
-
 	// Draw filled triangle for better visibility
 	var path vector.Path
 	path.MoveTo(x1, y1)
@@ -1046,20 +1517,23 @@ func (w *Window) drawPlayerArrow(screen *ebiten.Image, cx, cy float64) {
 	// Fill the arrow
 	vertices, indices := path.AppendVerticesAndIndicesForFilling(nil, nil)
 	for i := range vertices {
+		vertices[i].SrcX, vertices[i].SrcY = 1, 1 // Inside whiteImage
 		vertices[i].ColorR = float32(c.R) / 255.0
 		vertices[i].ColorG = float32(c.G) / 255.0
 		vertices[i].ColorB = float32(c.B) / 255.0
 		vertices[i].ColorA = float32(c.A) / 255.0
 	}
-	screen.DrawTriangles(vertices, indices, ebiten.NewImage(1, 1).SubImage(image.Rect(0, 0, 1, 1)).(*ebiten.Image), &ebiten.DrawTrianglesOptions{
-		AntiAlias: true,
+	screen.DrawTriangles(vertices, indices, whiteImage, &ebiten.DrawTrianglesOptions{
+		AntiAlias: w.antiAlias,
 	})
 
 	// Draw stroke outline for better definition
 	strokeWidth := float32(1.5)
-	vector.StrokeLine(screen, x1, y1, x2, y2, strokeWidth, c, true)
-	vector.StrokeLine(screen, x2, y2, x3, y3, strokeWidth, c, true)
-	vector.StrokeLine(screen, x3, y3, x1, y1, strokeWidth, c, true)
+	vector.StrokeLine(screen, x1, y1, x2, y2, strokeWidth, c, w.antiAlias)
+	vector.StrokeLine(screen, x2, y2, x3, y3, strokeWidth, c, w.antiAlias)
+	vector.StrokeLine(screen, x3, y3, x1, y1, strokeWidth, c, w.antiAlias)
+
+	return px, py
 }
 
 type MenuButton struct {
@@ -1074,6 +1548,7 @@ type MenuItem struct {
 	Hotkey  string     // Optional hotkey text (e.g., "L", "Space", "PgUp")
 	Action  func()
 	Submenu []MenuItem // For nested menus
+	Swatch  *color.RGBA // Optional color chip drawn before the label
 }
 
 type Menu struct {
@@ -1082,16 +1557,19 @@ type Menu struct {
 }
 
 // calculateMenuWidth calculates the width of a dropdown menu based on its items
-func calculateMenuWidth(items []MenuItem) int {
+func (w *Window) calculateMenuWidth(items []MenuItem) int {
 	maxLabelWidth := 0
 	maxHotkeyWidth := 0
 	for _, item := range items {
-		labelWidth := len(item.Label) * 7
+		labelWidth := w.uiTextWidth(item.Label)
+		if item.Swatch != nil {
+			labelWidth += 18
+		}
 		if labelWidth > maxLabelWidth {
 			maxLabelWidth = labelWidth
 		}
 		if item.Hotkey != "" {
-			hotkeyWidth := len(item.Hotkey) * 7
+			hotkeyWidth := w.uiTextWidth(item.Hotkey)
 			if hotkeyWidth > maxHotkeyWidth {
 				maxHotkeyWidth = hotkeyWidth
 			}
@@ -1105,22 +1583,29 @@ func calculateMenuWidth(items []MenuItem) int {
 	return maxWidth
 }
 
+// menuTitleWidth is the width of a menu's entry in the menu bar.
+func (w *Window) menuTitleWidth(menu Menu) int {
+	return w.uiTextWidth(menu.Label) + 16
+}
+
 func (w *Window) drawUI(screen *ebiten.Image) {
 	mx, my := ebiten.CursorPosition()
+	rowH := w.menuRowHeight() // Menus grow with the UI font
+	w.menuBarHeight = rowH + 4
 	cx, cy := float64(w.Width)/2, float64(w.Height)/2
 
 	// Reverse transform: Screen -> World (map coordinates)
-	worldX := (float64(mx) - cx) / w.Zoom + w.CamX
-	worldY := (float64(my) - cy) / w.Zoom + w.CamY
+	worldX, worldY := w.toWorld(float64(mx), float64(my), cx, cy)
 
 	// Convert to EQ /loc format (Y, X with negation reversed)
 	mouseLocY := -worldY
 	mouseLocX := -worldX
-	playerLocY := -w.LogReader.CurrentState.Y
-	playerLocX := -w.LogReader.CurrentState.X
+	player := w.LogReader.State()
+	playerLocY := -player.Y
+	playerLocX := -player.X
 
 	// Define menus
-	labelModes := []string{"ALL", "CUSTOM + ZONE LINES", "ZONE LINES", "NONE"}
+	labelModes := []string{"ALL", "IMPORTANT + MARKERS", "IMPORTANT", "NONE"}
 	zModes := []string{"OFF", "AUTO", "MANUAL"}
 
 	menus := []Menu{
@@ -1146,9 +1631,173 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 						w.openMenu = ""
 					},
 				},
+				{
+					Label: "Load Zone...",
+					Action: func() {
+						w.openMenu = ""
+						w.openZonePicker()
+					},
+				},
+				{
+					Label: "Key Bindings...",
+					Action: func() {
+						w.openMenu = ""
+						w.openKeyBindings()
+					},
+				},
+				{
+					Label: "Character: " + w.characterChoiceLabel() + "...",
+					Action: func() {
+						w.openMenu = ""
+						w.openCharacterPicker()
+					},
+				},
+				{
+					Label: fmt.Sprintf("Track All Characters: %s", map[bool]string{true: "ON", false: "OFF"}[w.Config.TrackAllCharacters]),
+					Action: func() {
+						w.Config.TrackAllCharacters = !w.Config.TrackAllCharacters
+						if err := w.Config.Save(); err != nil {
+							fmt.Printf("Error saving config: %v\n", err)
+						} else {
+							fmt.Println("Please restart the application for changes to take effect.")
+						}
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Low-Latency Log Reading: %s", map[bool]string{true: "ON", false: "OFF"}[w.Config.LowLatency]),
+					Action: func() {
+						w.toggleLowLatency()
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Screenshot Loc OCR: %s", map[bool]string{true: "ON", false: "OFF"}[w.Config.ScreenshotOCR]),
+					Action: func() {
+						w.Config.ScreenshotOCR = !w.Config.ScreenshotOCR
+						if w.Config.ScreenshotOCR {
+							w.startScreenshotOCR()
+						} else {
+							w.stopScreenshotOCR()
+						}
+						if err := w.Config.Save(); err != nil {
+							fmt.Printf("Error saving config: %v\n", err)
+						}
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Spoken Announcements: %s", map[bool]string{true: "ON", false: "OFF"}[w.Config.Announcements]),
+					Hotkey: w.hotkeyLabel(ActionAnnounce),
+					Action: func() {
+						w.Config.Announcements = !w.Config.Announcements
+						if w.Config.Announcements {
+							w.startAnnouncements()
+						} else {
+							w.stopAnnouncements()
+						}
+						if err := w.Config.Save(); err != nil {
+							fmt.Printf("Error saving config: %v\n", err)
+						}
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Sound Alerts: %s", map[bool]string{true: "ON", false: "OFF"}[w.Config.SoundAlerts]),
+					Action: func() {
+						w.Config.SoundAlerts = !w.Config.SoundAlerts
+						if w.Config.SoundAlerts {
+							w.startSoundAlerts()
+						} else {
+							w.stopSoundAlerts()
+						}
+						if err := w.Config.Save(); err != nil {
+							fmt.Printf("Error saving config: %v\n", err)
+						}
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: "Alert Sounds...",
+					Action: func() {
+						w.openMenu = ""
+						w.chooseAlertSounds()
+					},
+				},
+				{
+					Label: "Open Link...",
+					Hotkey: "Ctrl+V",
+					Action: func() {
+						w.openMenu = ""
+						w.openLinkDialog()
+					},
+				},
+				{
+					Label: "Register nox:// Links",
+					Action: func() {
+						w.openMenu = ""
+						w.registerLinkHandler()
+					},
+				},
+				{
+					Label: "Rebuild Zone Index",
+					Action: func() {
+						w.openMenu = ""
+						if w.indexer == nil {
+							w.startZoneIndex()
+						}
+						if w.thumbnails.job == nil {
+							w.startThumbnails()
+						}
+					},
+				},
+				{
+					Label: "Export Breadcrumbs...",
+					Action: func() {
+						w.openMenu = ""
+						w.exportBreadcrumbs()
+					},
+				},
+				{
+					Label: "Restore Map Backup...",
+					Action: func() {
+						w.openMenu = ""
+						w.openMapBackups()
+					},
+				},
+				{
+					Label: "Manage Map Packs...",
+					Action: func() {
+						w.openMenu = ""
+						w.manageMapPacks()
+					},
+				},
+				{
+					Label: "Export Session...",
+					Action: func() {
+						w.openMenu = ""
+						w.exportSession()
+					},
+				},
+				{
+					Label: "Import Session...",
+					Action: func() {
+						w.openMenu = ""
+						w.importSession()
+					},
+				},
+				{
+					Label: "Save Map Capture",
+					Hotkey: w.hotkeyLabel(ActionSaveCapture),
+					Action: func() {
+						w.openMenu = ""
+						w.captureRequested = true
+					},
+				},
 				{
 					Label: "Exit",
 					Action: func() {
+						w.Close()
 						os.Exit(0)
 					},
 				},
@@ -1166,15 +1815,22 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 				},
 				{
 					Label: fmt.Sprintf("Labels: %s", labelModes[w.LabelMode]),
-					Hotkey: "L",
+					Hotkey: w.hotkeyLabel(ActionCycleLabels),
 					Action: func() {
 						w.LabelMode = (w.LabelMode + 1) % 4
 						w.openMenu = ""
 					},
 				},
+				{
+					Label: "Label Priorities...",
+					Action: func() {
+						w.openMenu = ""
+						w.openLabelPriorities()
+					},
+				},
 				{
 					Label: fmt.Sprintf("Breadcrumbs: %s", map[bool]string{true: "ON", false: "OFF"}[w.ShowBreadcrumbs]),
-					Hotkey: "B",
+					Hotkey: w.hotkeyLabel(ActionToggleBreadcrumbs),
 					Action: func() {
 						w.ShowBreadcrumbs = !w.ShowBreadcrumbs
 						w.openMenu = ""
@@ -1182,26 +1838,185 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 				},
 				{
 					Label: fmt.Sprintf("Markers: %s", map[bool]string{true: "ON", false: "OFF"}[w.ShowMarkers]),
-					Hotkey: "R",
+					Hotkey: w.hotkeyLabel(ActionToggleMarkers),
 					Action: func() {
 						w.ShowMarkers = !w.ShowMarkers
 						w.openMenu = ""
 					},
 				},
+				{
+					Label: fmt.Sprintf("Follow Player: %s", map[bool]string{true: "ON", false: "OFF"}[w.FollowPlayer]),
+					Hotkey: w.hotkeyLabel(ActionFollowPlayer),
+					Action: func() {
+						w.setFollow(!w.FollowPlayer)
+						w.openMenu = ""
+					},
+				},
+				{
+					Label:   "Rotation: " + w.rotationLabel(),
+					Submenu: w.rotationMenuItems(),
+				},
+				{
+					Label:   "Camera Bookmarks",
+					Submenu: w.bookmarkSubmenu(),
+				},
+				{
+					Label: "Reset Window Position",
+					Action: func() {
+						w.resetWindowGeometry()
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Always on Top: %s", map[bool]string{true: "ON", false: "OFF"}[w.Config.AlwaysOnTop]),
+					Action: func() {
+						w.toggleAlwaysOnTop()
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Click-Through: %s", map[bool]string{true: "ON", false: "OFF"}[w.Config.ClickThrough]),
+					Hotkey: w.hotkeyLabel(ActionClickThrough),
+					Action: func() {
+						w.openMenu = ""
+						w.toggleClickThrough()
+					},
+				},
+				{
+					Label: fmt.Sprintf("Frameless Window: %s", map[bool]string{true: "ON", false: "OFF"}[w.Config.Frameless]),
+					Action: func() {
+						w.toggleFrameless()
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Tray Icon: %s", map[bool]string{true: "ON", false: "OFF"}[w.Config.Tray]),
+					Action: func() {
+						w.toggleTray()
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Clean Capture View: %s", map[bool]string{true: "ON", false: "OFF"}[w.cleanCapture]),
+					Hotkey: w.hotkeyLabel(ActionCleanCapture),
+					Action: func() {
+						w.toggleCleanCapture()
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Directions: %s", strings.ToUpper(w.directionStyle())),
+					Action: func() {
+						w.toggleDirectionStyle()
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Party: %s", map[bool]string{true: "ON", false: "OFF"}[w.ShowParty]),
+					Action: func() {
+						w.ShowParty = !w.ShowParty
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Corpses Panel: %s", map[bool]string{true: "ON", false: "OFF"}[w.showCorpses]),
+					Action: func() {
+						w.showCorpses = !w.showCorpses
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Heatmap: %s", map[bool]string{true: "ON", false: "OFF"}[w.showHeatmap]),
+					Action: func() {
+						w.showHeatmap = !w.showHeatmap
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Heatmap Data: %s", map[bool]string{true: "History", false: "Live"}[w.heatHistory != nil]),
+					Action: func() {
+						w.toggleHeatHistory()
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Session Stats: %s", map[bool]string{true: "ON", false: "OFF"}[w.showStats]),
+					Action: func() {
+						w.showStats = !w.showStats
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Background: %s", w.mapBackgroundLabel()),
+					Action: func() {
+						w.cycleMapBackground()
+						w.openMenu = ""
+					},
+				},
+				{
+					Label:   "Theme: " + themeLabel(w.theme().name),
+					Submenu: w.themeMenuItems(),
+				},
+				{
+					Label: fmt.Sprintf("Map Colors: %s", w.mapColorsLabel()),
+					Action: func() {
+						w.cycleMapColors()
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Font Size: %s", fontSizeLabel(w.Config.UIFontSize)),
+					Action: func() {
+						w.cycleUIFontSize()
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Label Size: %s", fontSizeLabel(w.Config.LabelFontSize)),
+					Action: func() {
+						w.cycleLabelFontSize()
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Minimap: %s", map[bool]string{true: "ON", false: "OFF"}[w.showMinimap]),
+					Action: func() {
+						w.showMinimap = !w.showMinimap
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Camp Lists Panel: %s", map[bool]string{true: "ON", false: "OFF"}[w.showCampLists]),
+					Action: func() {
+						w.showCampLists = !w.showCampLists
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Zone Notes: %s", map[bool]string{true: "EXPANDED", false: "COLLAPSED"}[w.notesExpanded]),
+					Action: func() {
+						w.notesExpanded = !w.notesExpanded
+						w.openMenu = ""
+					},
+				},
 				{
 					Label: fmt.Sprintf("Z-Level: %s", zModes[w.ZLevelMode]),
-					Hotkey: "Z",
+					Hotkey: w.hotkeyLabel(ActionCycleZLevel),
 					Action: func() {
 						w.ZLevelMode = (w.ZLevelMode + 1) % 3
 						if w.ZLevelMode == 2 && w.LogReader != nil {
-							w.ZLevelManual = w.LogReader.CurrentState.Z
+							w.ZLevelManual = w.LogReader.State().Z
 						}
 						w.openMenu = ""
 					},
 				},
+				{
+					Label: "Z Presets",
+					Submenu: w.zPresetSubmenu(),
+				},
 				{
 					Label: "Opacity +",
-					Hotkey: "=",
+					Hotkey: w.hotkeyLabel(ActionOpacityUp),
 					Action: func() {
 						w.Opacity += 0.1
 						if w.Opacity > 1.0 { w.Opacity = 1.0 }
@@ -1210,10 +2025,11 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 				},
 				{
 					Label: "Opacity -",
-					Hotkey: "-",
+					Hotkey: w.hotkeyLabel(ActionOpacityDown),
 					Action: func() {
 						w.Opacity -= 0.1
 						if w.Opacity < 0.1 { w.Opacity = 0.1 }
+						if !w.transparent { w.Opacity = 1.0 }
 						w.openMenu = ""
 					},
 				},
@@ -1224,26 +2040,125 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 			Items: []MenuItem{
 				{
 					Label: "Center on Player",
-					Hotkey: "Space",
+					Hotkey: w.hotkeyLabel(ActionCenterPlayer),
 					Action: func() {
 						if w.LogReader != nil {
-							w.CamX = w.LogReader.CurrentState.X
-							w.CamY = w.LogReader.CurrentState.Y
+							if w.browsing() {
+								w.viewZone(w.logZone)
+							}
+							s := w.LogReader.State()
+							w.CamX, w.CamY = s.X, s.Y
 						}
 						w.openMenu = ""
 					},
 				},
+				{
+					Label: "Find...",
+					Hotkey: "Ctrl+F",
+					Action: func() {
+						w.openMenu = ""
+						w.openFind()
+					},
+				},
+				{
+					Label: "Find in All Zones...",
+					Hotkey: "Ctrl+Shift+F",
+					Action: func() {
+						w.openMenu = ""
+						w.openGlobalFind()
+					},
+				},
+				{
+					Label: "Map Coverage...",
+					Action: func() {
+						w.openMenu = ""
+						w.showMapCoverage()
+					},
+				},
+				{
+					Label: "Check Map Files",
+					Action: func() {
+						w.openMenu = ""
+						w.startMapCheck(false)
+					},
+				},
+				{
+					Label: fmt.Sprintf("Edit Map: %s", map[bool]string{true: "ON", false: "OFF"}[w.editor.active]),
+					Hotkey: w.hotkeyLabel(ActionEditMap),
+					Action: func() {
+						w.openMenu = ""
+						w.setEditing(!w.editor.active)
+					},
+				},
+				{
+					Label: "Zone Browser...",
+					Action: func() {
+						w.openMenu = ""
+						w.openZoneBrowser()
+					},
+				},
+				{
+					Label: "Travel Planner...",
+					Action: func() {
+						w.openMenu = ""
+						w.openTravelPlanner()
+					},
+				},
+				{
+					Label: "Publish Marker Pack...",
+					Action: func() {
+						w.openMenu = ""
+						w.publishMarkerPack()
+					},
+				},
+				{
+					Label: "Import Marker Pack...",
+					Action: func() {
+						w.openMenu = ""
+						w.importMarkerPack()
+					},
+				},
 				{
 					Label: "Fit Map to Window",
-					Hotkey: "Home",
+					Hotkey: w.hotkeyLabel(ActionFitMap),
 					Action: func() {
 						w.refitZoom()
 						w.openMenu = ""
 					},
 				},
+				{
+					Label: "Edit Zone Notes...",
+					Action: func() {
+						w.openNotesEditor()
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Tasks Panel: %s", map[bool]string{true: "ON", false: "OFF"}[w.showTasks]),
+					Action: func() {
+						w.showTasks = !w.showTasks
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Set Waypoint: %s", map[bool]string{true: "ON", false: "OFF"}[w.placingWaypoint]),
+					Hotkey: w.hotkeyLabel(ActionSetWaypoint),
+					Action: func() {
+						w.placingWaypoint = !w.placingWaypoint
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Ruler: %s", map[bool]string{true: "ON", false: "OFF"}[w.ruler.active]),
+					Hotkey: w.hotkeyLabel(ActionRuler),
+					Action: func() {
+						w.toggleRuler()
+						w.openMenu = ""
+					},
+				},
 				{
 					Label: "Z-Level Up",
-					Hotkey: "PgUp",
+					Hotkey: w.hotkeyLabel(ActionZLevelUp),
 					Action: func() {
 						w.ZLevelManual += 10.0
 						w.ZLevelMode = 2
@@ -1252,7 +2167,7 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 				},
 				{
 					Label: "Z-Level Down",
-					Hotkey: "PgDn",
+					Hotkey: w.hotkeyLabel(ActionZLevelDown),
 					Action: func() {
 						w.ZLevelManual -= 10.0
 						w.ZLevelMode = 2
@@ -1261,7 +2176,7 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 				},
 				{
 					Label: "Z-Range Increase",
-					Hotkey: "Ins",
+					Hotkey: w.hotkeyLabel(ActionZRangeUp),
 					Action: func() {
 						w.ZLevelRange += 10.0
 						if w.ZLevelRange > 200.0 { w.ZLevelRange = 200.0 }
@@ -1270,7 +2185,7 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 				},
 				{
 					Label: "Z-Range Decrease",
-					Hotkey: "Del",
+					Hotkey: w.hotkeyLabel(ActionZRangeDown),
 					Action: func() {
 						w.ZLevelRange -= 10.0
 						if w.ZLevelRange < 10.0 { w.ZLevelRange = 10.0 }
@@ -1284,7 +2199,7 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 			Items: []MenuItem{
 				{
 					Label: fmt.Sprintf("Place Marker: %s", map[bool]string{true: "ON", false: "OFF"}[w.placingMarker]),
-					Hotkey: "M",
+					Hotkey: w.hotkeyLabel(ActionPlaceMarker),
 					Action: func() {
 						w.placingMarker = !w.placingMarker
 						w.openMenu = ""
@@ -1292,106 +2207,160 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 				},
 				{
 					Label: fmt.Sprintf("Color: %s", w.markerColor),
-					Submenu: []MenuItem{
-						{
-							Label: "Red",
-							Action: func() {
-								w.markerColor = "red"
-								w.openMenu = ""
-							},
-						},
-						{
-							Label: "Blue",
-							Action: func() {
-								w.markerColor = "blue"
-								w.openMenu = ""
-							},
-						},
-						{
-							Label: "Green",
-							Action: func() {
-								w.markerColor = "green"
-								w.openMenu = ""
-							},
-						},
-						{
-							Label: "Yellow",
-							Action: func() {
-								w.markerColor = "yellow"
-								w.openMenu = ""
-							},
-						},
-						{
-							Label: "Purple",
-							Action: func() {
-								w.markerColor = "purple"
-								w.openMenu = ""
-							},
-						},
-					},
+					Submenu: w.colorSubmenu(),
 				},
 				{
 					Label: fmt.Sprintf("Shape: %s", w.markerShape),
-					Submenu: []MenuItem{
-						{
-							Label: "Circle",
-							Action: func() {
-								w.markerShape = "circle"
-								w.openMenu = ""
-							},
-						},
-						{
-							Label: "Square",
-							Action: func() {
-								w.markerShape = "square"
-								w.openMenu = ""
-							},
-						},
-						{
-							Label: "Triangle",
-							Action: func() {
-								w.markerShape = "triangle"
-								w.openMenu = ""
-							},
-						},
-						{
-							Label: "Diamond",
-							Action: func() {
-								w.markerShape = "diamond"
-								w.openMenu = ""
-							},
-						},
-						{
-							Label: "Star",
-							Action: func() {
-								w.markerShape = "star"
-								w.openMenu = ""
-							},
-						},
+					Submenu: w.shapeSubmenu(),
+				},
+				{
+					Label: fmt.Sprintf("Category: %s", categoryName(w.markerCategory)),
+					Submenu: w.categorySubmenu(),
+				},
+				{
+					Label: "Show Categories",
+					Submenu: w.categoryVisibilitySubmenu(),
+				},
+				{
+					Label: fmt.Sprintf("Draw Path: %s", map[bool]string{true: "ON", false: "OFF"}[w.drawing != nil && w.drawing.kind == annotationPath]),
+					Action: func() {
+						w.startAnnotation(annotationPath)
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: fmt.Sprintf("Draw Polygon: %s", map[bool]string{true: "ON", false: "OFF"}[w.drawing != nil && w.drawing.kind == annotationPolygon]),
+					Action: func() {
+						w.startAnnotation(annotationPolygon)
+						w.openMenu = ""
+					},
+				},
+				{
+					Label: "Import CSV/TSV...",
+					Action: func() {
+						w.openMenu = ""
+						w.importMarkers()
+					},
+				},
+				{
+					Label: "Import Map Points...",
+					Action: func() {
+						w.openMenu = ""
+						w.importMapPoints()
+					},
+				},
+				{
+					Label: "Export Markers...",
+					Action: func() {
+						w.openMenu = ""
+						w.exportMarkerFile()
+					},
+				},
+				{
+					Label: "Import Markers...",
+					Action: func() {
+						w.openMenu = ""
+						w.importMarkerFile()
+					},
+				},
+				{
+					Label: "Restore Markers from Backup...",
+					Action: func() {
+						w.openMenu = ""
+						w.restoreMarkerBackup()
 					},
 				},
 			},
 		},
+		w.layersMenu(),
 	}
 
 	// Add conditional menu items
+	if w.showHeatmap && w.heatHistory == nil {
+		menus[2].Items = append(menus[2].Items, MenuItem{ // Tools menu
+			Label: "Clear Heatmap",
+			Action: func() {
+				w.clearHeatmap()
+				w.openMenu = ""
+			},
+		})
+	}
+	if w.showStats {
+		menus[2].Items = append(menus[2].Items, MenuItem{ // Tools menu
+			Label: "New Stats Session",
+			Action: func() {
+				w.newStatsSession()
+				w.openMenu = ""
+			},
+		})
+	}
 	if w.ShowBreadcrumbs && len(w.Breadcrumbs) > 0 {
 		menus[2].Items = append(menus[2].Items, MenuItem{ // Tools menu
 			Label: "Clear Breadcrumbs",
-			Hotkey: "C",
+			Hotkey: w.hotkeyLabel(ActionClearBreadcrumbs),
+			Action: func() {
+				w.clearBreadcrumbs()
+				w.openMenu = ""
+			},
+		})
+	}
+
+	if w.Nav.Active() {
+		menus[2].Items = append(menus[2].Items, MenuItem{ // Tools menu
+			Label: "Clear Waypoint",
 			Action: func() {
-				w.Breadcrumbs = w.Breadcrumbs[:0]
+				w.Nav.Clear()
 				w.openMenu = ""
 			},
 		})
 	}
 
-	if w.LogReader != nil && w.LogReader.CurrentState.HasCorpse {
+	if w.LogReader != nil && w.LogReader.State().HasCorpse() {
 		menus[2].Items = append(menus[2].Items, MenuItem{ // Tools menu
-			Label: "Clear Corpse Marker",
-			Hotkey: "K",
+			Label: "Clear My Corpses",
+			Hotkey: w.hotkeyLabel(ActionClearCorpse),
 			Action: func() {
-				w.LogReader.CurrentState.HasCorpse = false
+				w.LogReader.ClearCorpses(w.LogReader.State().Character)
+				w.openMenu = ""
+			},
+		})
+	}
+
+	if len(w.editor.dirty) > 0 {
+		menus[2].Items = append(menus[2].Items, MenuItem{ // Tools menu
+			Label: "Save Map Edits",
+			Hotkey: "Ctrl+S",
+			Action: func() {
+				w.saveMapEdits()
+				w.openMenu = ""
+			},
+		})
+	}
+
+	menus[2].Items = append(menus[2].Items, w.campMenuItems()...) // Tools menu
+	menus[2].Items = append(menus[2].Items, w.afkMenuItems()...)
+
+	if t := w.currentTarget(); t != nil {
+		menus[3].Items = append(menus[3].Items, MenuItem{ // Markers menu
+			Label: fmt.Sprintf("Mark Target: %s", t.Name),
+			Action: func() {
+				w.markTarget()
+				w.openMenu = ""
+			},
+		})
+	}
+
+	// Marker sync and Cloud Sync entries go just above Exit
+	file := menus[0].Items
+	menus[0].Items = append(append(append(file[:len(file)-1:len(file)-1], w.syncMenuItems()...), w.cloudSyncMenuItems()...), file[len(file)-1])
+
+	// Every corpse (any character) can be cleared individually
+	for _, c := range w.outstandingCorpses() {
+		c := c
+		menus[2].Items = append(menus[2].Items, MenuItem{ // Tools menu
+			Label: fmt.Sprintf("Clear Corpse: %s", c.label()),
+			Action: func() {
+				w.LogReader.ClearCorpse(c.Character, c.Corpse)
 				w.openMenu = ""
 			},
 		})
@@ -1399,8 +2368,15 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 
 	// Add conditional marker menu items
 	if w.CurrentZone != "" {
-		if markers, ok := w.Config.Markers[w.CurrentZone]; ok && len(markers) > 0 {
+		if markers := w.Config.ZoneMarkers(w.CurrentZone); len(markers) > 0 {
 			menus[3].Items = append(menus[3].Items, MenuItem{ // Markers menu
+				Label: "Copy Marker Link...",
+				Hotkey: "Shift+Click",
+				Action: func() {
+					w.openMenu = ""
+					w.chooseMarkerLink()
+				},
+			}, MenuItem{
 				Label: fmt.Sprintf("Clear All (%d markers)", len(markers)),
 				Action: func() {
 					w.openMenu = ""
@@ -1409,23 +2385,24 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 			})
 		}
 	}
+	menus[3].Items = append(menus[3].Items, w.undoMenuItems()...)
 
 	// Handle submenu hover (before click handling)
 	if w.openMenu != "" {
 		x := 0
 		newSubmenu := -1 // Track what submenu should be open
 		for _, menu := range menus {
-			menuWidth := len(menu.Label)*7 + 16
+			menuWidth := w.menuTitleWidth(menu)
 			if menu.Label == w.openMenu {
-				maxWidth := calculateMenuWidth(menu.Items)
+				maxWidth := w.calculateMenuWidth(menu.Items)
 
 				// First pass: Check if hovering directly over a menu item with submenu
 				dropY := w.menuBarHeight
 				for i, item := range menu.Items {
-					itemY := dropY + i*20
+					itemY := dropY + i*rowH
 
 					// Check if hovering over the menu item itself
-					if mx >= x && mx < x+maxWidth && my >= itemY && my < itemY+20 {
+					if mx >= x && mx < x+maxWidth && my >= itemY && my < itemY+rowH {
 						if len(item.Submenu) > 0 {
 							newSubmenu = i
 						}
@@ -1437,11 +2414,12 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 				if newSubmenu == -1 && w.openSubmenu >= 0 && w.openSubmenu < len(menu.Items) {
 					item := menu.Items[w.openSubmenu]
 					if len(item.Submenu) > 0 {
-						itemY := dropY + w.openSubmenu*20
+						itemY := dropY + w.openSubmenu*rowH
+						subWidth := w.calculateMenuWidth(item.Submenu)
 						submenuX := x + maxWidth
 						submenuY := itemY
-						submenuHeight := len(item.Submenu) * 20
-						if mx >= submenuX && mx < submenuX+150 && my >= submenuY && my < submenuY+submenuHeight {
+						submenuHeight := len(item.Submenu) * rowH
+						if mx >= submenuX && mx < submenuX+subWidth && my >= submenuY && my < submenuY+submenuHeight {
 							newSubmenu = w.openSubmenu
 						}
 					}
@@ -1453,16 +2431,16 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 		w.openSubmenu = newSubmenu
 	}
 
-	// Handle menu interactions
+	// Handle menu interactions (not while an in-window dialog has the mouse)
 	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-		if !w.lastMousePressed {
+		if !w.lastMousePressed && w.modal == nil {
 			handled := false
 
 			// Check menu bar clicks
 			if my < w.menuBarHeight {
 				x := 0
 				for _, menu := range menus {
-					menuWidth := len(menu.Label)*7 + 16
+					menuWidth := w.menuTitleWidth(menu)
 					if mx >= x && mx < x+menuWidth {
 						if w.openMenu == menu.Label {
 							w.openMenu = ""
@@ -1482,23 +2460,24 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 			if !handled && w.openMenu != "" && w.openSubmenu >= 0 {
 				x := 0
 				for _, menu := range menus {
-					menuWidth := len(menu.Label)*7 + 16
+					menuWidth := w.menuTitleWidth(menu)
 					if menu.Label == w.openMenu {
-						maxWidth := calculateMenuWidth(menu.Items)
+						maxWidth := w.calculateMenuWidth(menu.Items)
 
 						if w.openSubmenu < len(menu.Items) {
 							submenu := menu.Items[w.openSubmenu].Submenu
+							subWidth := w.calculateMenuWidth(submenu)
 							dropY := w.menuBarHeight
 							submenuX := x + maxWidth
-							submenuY := dropY + w.openSubmenu*20
+							submenuY := dropY + w.openSubmenu*rowH
 
 							for _, subitem := range submenu {
-								if mx >= submenuX && mx < submenuX+150 && my >= submenuY && my < submenuY+20 {
+								if mx >= submenuX && mx < submenuX+subWidth && my >= submenuY && my < submenuY+rowH {
 									subitem.Action()
 									handled = true
 									break
 								}
-								submenuY += 20
+								submenuY += rowH
 							}
 						}
 						break
@@ -1511,15 +2490,15 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 			if !handled && w.openMenu != "" {
 				x := 0
 				for _, menu := range menus {
-					menuWidth := len(menu.Label)*7 + 16
+					menuWidth := w.menuTitleWidth(menu)
 					if menu.Label == w.openMenu {
-						maxWidth := calculateMenuWidth(menu.Items)
+						maxWidth := w.calculateMenuWidth(menu.Items)
 
 						// Check if click is in dropdown
 						dropY := w.menuBarHeight
 						for i, item := range menu.Items {
-							itemY := dropY + i*20
-							if mx >= x && mx < x+maxWidth && my >= itemY && my < itemY+20 {
+							itemY := dropY + i*rowH
+							if mx >= x && mx < x+maxWidth && my >= itemY && my < itemY+rowH {
 								// Only execute if no submenu
 								if len(item.Submenu) == 0 && item.Action != nil {
 									item.Action()
@@ -1546,27 +2525,23 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 	}
 
 	// Draw menu bar
-	menuBar := ebiten.NewImage(w.Width, w.menuBarHeight)
-	menuBar.Fill(color.RGBA{240, 240, 240, 255})
-	screen.DrawImage(menuBar, nil)
+	theme := w.theme()
+	vector.DrawFilledRect(screen, 0, 0, float32(w.Width), float32(w.menuBarHeight), theme.menuBar, false)
 
 	// Draw menu labels
 	x := 0
 	for _, menu := range menus {
-		menuWidth := len(menu.Label)*7 + 16
+		menuWidth := w.menuTitleWidth(menu)
 
 		// Highlight if hovered or open
 		if (mx >= x && mx < x+menuWidth && my < w.menuBarHeight) || w.openMenu == menu.Label {
-			highlight := ebiten.NewImage(menuWidth, w.menuBarHeight)
-			highlight.Fill(color.RGBA{200, 200, 200, 255})
-			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Translate(float64(x), 0)
-			screen.DrawImage(highlight, op)
+			vector.DrawFilledRect(screen, float32(x), 0, float32(menuWidth), float32(w.menuBarHeight), theme.menuHover, false)
 		}
 
-		text.Draw(screen, menu.Label, basicfont.Face7x13, x+8, 16, color.Black)
+		w.drawUIText(screen, menu.Label, x+8, w.menuBarHeight-8, theme.menuText)
 		x += menuWidth
 	}
+	w.menuTitlesEnd = x // The rest of the bar moves a frameless window
 
 	// Draw info text below menu bar (if enabled)
 	if w.showInfo {
@@ -1575,9 +2550,14 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 		// Status info only
 		statusInfo := []string{
 			fmt.Sprintf("Zone: %s", w.CurrentZone),
+		}
+		if line := w.zoneInfoLine(); line != "" {
+			statusInfo = append(statusInfo, line)
+		}
+		statusInfo = append(statusInfo,
 			fmt.Sprintf("Player: %.1f, %.1f", playerLocY, playerLocX),
 			fmt.Sprintf("Mouse: %.1f, %.1f", mouseLocY, mouseLocX),
-		}
+		)
 
 		if w.MapData != nil {
 			statusInfo = append(statusInfo, fmt.Sprintf("Map: X[%.0f to %.0f] Y[%.0f to %.0f]",
@@ -1587,7 +2567,7 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 		// Z-Level info
 		zModeLabels := []string{"OFF", "AUTO", "MANUAL"}
 		if w.ZLevelMode == 1 && w.LogReader != nil {
-			statusInfo = append(statusInfo, fmt.Sprintf("Z-Level: %.1f ±%.0f (%s)", w.LogReader.CurrentState.Z, w.ZLevelRange, zModeLabels[w.ZLevelMode]))
+			statusInfo = append(statusInfo, fmt.Sprintf("Z-Level: %.1f ±%.0f (%s)", w.LogReader.State().Z, w.ZLevelRange, zModeLabels[w.ZLevelMode]))
 		} else if w.ZLevelMode == 2 {
 			statusInfo = append(statusInfo, fmt.Sprintf("Z-Level: %.1f ±%.0f (%s)", w.ZLevelManual, w.ZLevelRange, zModeLabels[w.ZLevelMode]))
 		} else {
@@ -1596,21 +2576,69 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 
 		statusInfo = append(statusInfo, fmt.Sprintf("Zoom: %.2fx | Opacity: %.0f%%", w.Zoom, w.Opacity*100))
 
+		// Zone notes (collapsible)
+		statusInfo = append(statusInfo, w.zoneNotesInfo()...)
+
+		// Rendering degradation notices
+		for _, notice := range w.notices {
+			statusInfo = append(statusInfo, "! "+notice)
+		}
+		if line := w.logQueueInfo(); line != "" {
+			statusInfo = append(statusInfo, line)
+		}
+		if line := w.heatHistoryInfo(); line != "" {
+			statusInfo = append(statusInfo, line)
+		}
+
+		if w.Nav.Active() {
+			statusInfo = append(statusInfo, fmt.Sprintf("Waypoint: %.0f units %s", w.Nav.Distance, w.directionLabel(w.Nav.Bearing, false)))
+		}
+		if w.placingWaypoint {
+			statusInfo = append(statusInfo, ">>> CLICK TO SET WAYPOINT <<<")
+		}
+		if w.ruler.active {
+			statusInfo = append(statusInfo, ">>> RULER: click two points <<<")
+		}
+		if w.drawing != nil {
+			statusInfo = append(statusInfo, fmt.Sprintf(">>> DRAWING %s (%d points): Enter finishes, Backspace undoes, Esc cancels <<<",
+				strings.ToUpper(w.drawing.kind), len(w.drawing.points)))
+		}
+
+		if w.editor.active {
+			statusInfo = append(statusInfo, ">>> EDIT MAP: click to draw, right-click deletes, Esc ends line, Ctrl+S saves <<<")
+		}
+
 		// Marker placement mode indicator
 		if w.placingMarker {
 			statusInfo = append(statusInfo, fmt.Sprintf(">>> PLACING MARKER (%s %s) <<<", w.markerColor, w.markerShape))
 		}
 
-		ebitenutil.DebugPrintAt(screen, strings.Join(statusInfo, "\n"), 8, infoY)
+		w.drawInfoText(screen, statusInfo, 8, infoY)
 	}
 
+	// Waypoint compass (top-right) and tasks panel below it
+	w.drawCompass(screen)
+	w.drawTaskPanel(screen)
+	w.drawCorpsePanel(screen)
+	w.drawStatsPanel(screen)
+	w.drawCampListPanel(screen)
+	w.drawMinimap(screen)
+	w.drawCompassRose(screen)
+	w.drawResizeGrip(screen)
+	w.drawBoundsBanner(screen)
+	w.drawWrongMapBanner(screen)
+	w.drawMapCheckProgress(screen)
+	w.drawMapPackProgress(screen)
+	w.drawRepopBanner(screen)
+	w.drawZPresetBanner(screen)
+
 	// Draw crosshair when in marker placement mode
 	if w.placingMarker && my > w.menuBarHeight {
 		markerColor := w.getMarkerColor(w.markerColor)
 		// Draw crosshair at mouse position
 		crosshairSize := float32(20)
-		vector.StrokeLine(screen, float32(mx)-crosshairSize, float32(my), float32(mx)+crosshairSize, float32(my), 2, markerColor, true)
-		vector.StrokeLine(screen, float32(mx), float32(my)-crosshairSize, float32(mx), float32(my)+crosshairSize, 2, markerColor, true)
+		vector.StrokeLine(screen, float32(mx)-crosshairSize, float32(my), float32(mx)+crosshairSize, float32(my), 2, markerColor, w.antiAlias)
+		vector.StrokeLine(screen, float32(mx), float32(my)-crosshairSize, float32(mx), float32(my)+crosshairSize, 2, markerColor, w.antiAlias)
 		// Draw preview of marker shape at cursor
 		w.drawMarkerShape(screen, float32(mx), float32(my), w.markerShape, color.RGBA{
 			R: markerColor.R,
@@ -1624,50 +2652,41 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 	if w.openMenu != "" {
 		x := 0
 		for _, menu := range menus {
-			menuWidth := len(menu.Label)*7 + 16
+			menuWidth := w.menuTitleWidth(menu)
 			if menu.Label == w.openMenu {
-				maxWidth := calculateMenuWidth(menu.Items)
+				maxWidth := w.calculateMenuWidth(menu.Items)
 
 				// Draw dropdown background
-				dropHeight := len(menu.Items) * 20
-				dropdown := ebiten.NewImage(maxWidth, dropHeight)
-				dropdown.Fill(color.RGBA{250, 250, 250, 255})
+				dropHeight := len(menu.Items) * rowH
+				vector.DrawFilledRect(screen, float32(x), float32(w.menuBarHeight), float32(maxWidth), float32(dropHeight), theme.menuPanel, false)
 
 				// Draw border
-				vector.StrokeRect(screen, float32(x), float32(w.menuBarHeight), float32(maxWidth), float32(dropHeight), 1, color.RGBA{180, 180, 180, 255}, false)
-
-				op := &ebiten.DrawImageOptions{}
-				op.GeoM.Translate(float64(x), float64(w.menuBarHeight))
-				screen.DrawImage(dropdown, op)
+				vector.StrokeRect(screen, float32(x), float32(w.menuBarHeight), float32(maxWidth), float32(dropHeight), 1, theme.menuBorder, false)
 
 				// Draw items
 				for i, item := range menu.Items {
-					itemY := w.menuBarHeight + i*20
+					itemY := w.menuBarHeight + i*rowH
 
 					// Highlight if hovered or has submenu open
-					if (mx >= x && mx < x+maxWidth && my >= itemY && my < itemY+20) || w.openSubmenu == i {
-						itemBg := ebiten.NewImage(maxWidth, 20)
-						itemBg.Fill(color.RGBA{200, 200, 255, 255})
-						itemOp := &ebiten.DrawImageOptions{}
-						itemOp.GeoM.Translate(float64(x), float64(itemY))
-						screen.DrawImage(itemBg, itemOp)
+					if (mx >= x && mx < x+maxWidth && my >= itemY && my < itemY+rowH) || w.openSubmenu == i {
+						vector.DrawFilledRect(screen, float32(x), float32(itemY), float32(maxWidth), float32(rowH), theme.menuItem, false)
 					}
 
 					// Draw label on left
-					text.Draw(screen, item.Label, basicfont.Face7x13, x+8, itemY+14, color.Black)
+					w.drawUIText(screen, item.Label, x+8, itemY+rowH-6, theme.menuText)
 
 					// Draw submenu indicator (triangle) if item has submenu
 					if len(item.Submenu) > 0 {
 						// Draw a simple ">" character to indicate submenu
 						triX := x + maxWidth - 12
-						triY := itemY + 14
-						text.Draw(screen, ">", basicfont.Face7x13, triX, triY, color.Black)
+						triY := itemY + rowH - 6
+						w.drawUIText(screen, ">", triX, triY, theme.menuText)
 					}
 
 					// Draw hotkey on right (if it exists)
 					if item.Hotkey != "" {
-						hotkeyX := x + maxWidth - len(item.Hotkey)*7 - 8
-						text.Draw(screen, item.Hotkey, basicfont.Face7x13, hotkeyX, itemY+14, color.Black)
+						hotkeyX := x + maxWidth - w.uiTextWidth(item.Hotkey) - 8
+						w.drawUIText(screen, item.Hotkey, hotkeyX, itemY+rowH-6, theme.menuText)
 					}
 				}
 
@@ -1675,35 +2694,33 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 				if w.openSubmenu >= 0 && w.openSubmenu < len(menu.Items) {
 					submenu := menu.Items[w.openSubmenu].Submenu
 					if len(submenu) > 0 {
+						subWidth := w.calculateMenuWidth(submenu)
 						submenuX := x + maxWidth
-						submenuY := w.menuBarHeight + w.openSubmenu*20
-						submenuHeight := len(submenu) * 20
+						submenuY := w.menuBarHeight + w.openSubmenu*rowH
+						submenuHeight := len(submenu) * rowH
 
 						// Draw submenu background
-						submenuBg := ebiten.NewImage(150, submenuHeight)
-						submenuBg.Fill(color.RGBA{250, 250, 250, 255})
+						vector.DrawFilledRect(screen, float32(submenuX), float32(submenuY), float32(subWidth), float32(submenuHeight), theme.menuPanel, false)
 
 						// Draw border
-						vector.StrokeRect(screen, float32(submenuX), float32(submenuY), 150, float32(submenuHeight), 1, color.RGBA{180, 180, 180, 255}, false)
-
-						subOp := &ebiten.DrawImageOptions{}
-						subOp.GeoM.Translate(float64(submenuX), float64(submenuY))
-						screen.DrawImage(submenuBg, subOp)
+						vector.StrokeRect(screen, float32(submenuX), float32(submenuY), float32(subWidth), float32(submenuHeight), 1, theme.menuBorder, false)
 
 						// Draw submenu items
 						for j, subitem := range submenu {
-							subitemY := submenuY + j*20
+							subitemY := submenuY + j*rowH
 
 							// Highlight if hovered
-							if mx >= submenuX && mx < submenuX+150 && my >= subitemY && my < subitemY+20 {
-								subitemBg := ebiten.NewImage(150, 20)
-								subitemBg.Fill(color.RGBA{200, 200, 255, 255})
-								subitemOp := &ebiten.DrawImageOptions{}
-								subitemOp.GeoM.Translate(float64(submenuX), float64(subitemY))
-								screen.DrawImage(subitemBg, subitemOp)
+							if mx >= submenuX && mx < submenuX+subWidth && my >= subitemY && my < subitemY+rowH {
+								vector.DrawFilledRect(screen, float32(submenuX), float32(subitemY), float32(subWidth), float32(rowH), theme.menuItem, false)
 							}
 
-							text.Draw(screen, subitem.Label, basicfont.Face7x13, submenuX+8, subitemY+14, color.Black)
+							labelX := submenuX + 8
+							if subitem.Swatch != nil {
+								vector.DrawFilledRect(screen, float32(labelX), float32(subitemY+(rowH-12)/2), 12, 12, *subitem.Swatch, false)
+								vector.StrokeRect(screen, float32(labelX), float32(subitemY+(rowH-12)/2), 12, 12, 1, theme.menuText, false)
+								labelX += 18
+							}
+							w.drawUIText(screen, subitem.Label, labelX, subitemY+rowH-6, theme.menuText)
 						}
 					}
 				}
@@ -1716,7 +2733,21 @@ func (w *Window) drawUI(screen *ebiten.Image) {
 }
 
 func (w *Window) Layout(outsideWidth, outsideHeight int) (int, int) {
+	if outsideWidth != w.Width || outsideHeight != w.Height {
+		w.dropFrameImages()
+	}
 	w.Width = outsideWidth
 	w.Height = outsideHeight
 	return outsideWidth, outsideHeight
-}
\ No newline at end of file
+}
+
+// dropFrameImages frees the frame-sized images; Draw makes new ones at the
+// new size.
+func (w *Window) dropFrameImages() {
+	for _, img := range []**ebiten.Image{&w.offscreen, &w.layerScratch} {
+		if *img != nil {
+			(*img).Deallocate()
+			*img = nil
+		}
+	}
+}
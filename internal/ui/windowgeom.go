@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	defaultWindowWidth  = 1280
+	defaultWindowHeight = 720
+
+	minWindowVisible = 64 // Pixels of a restored window that must stay on its monitor
+)
+
+// restoreWindowGeometry puts the window back where it was last closed. A
+// monitor that has gone away falls back to the primary one, and a position
+// that would leave the window off its monitor is centered instead.
+func (w *Window) restoreWindowGeometry() {
+	g := w.Config.Window
+	if g.Width <= 0 || g.Height <= 0 {
+		ebiten.SetWindowSize(w.Width, w.Height)
+		return
+	}
+	m := findMonitor(g.Monitor, g.MonitorIndex)
+	ebiten.SetMonitor(m)
+	w.Width, w.Height = g.Width, g.Height
+	ebiten.SetWindowSize(g.Width, g.Height)
+
+	mw, mh := m.Size()
+	x, y := g.X, g.Y
+	if x+g.Width < minWindowVisible || x > mw-minWindowVisible || y < 0 || y > mh-minWindowVisible {
+		x, y = (mw-g.Width)/2, (mh-g.Height)/2
+	}
+	ebiten.SetWindowPosition(x, y)
+	if g.Maximized {
+		ebiten.MaximizeWindow()
+	}
+}
+
+// findMonitor returns the monitor with the saved name, preferring the one
+// at the saved index when several share it, or the primary monitor.
+func findMonitor(name string, index int) *ebiten.MonitorType {
+	monitors := ebiten.AppendMonitors(nil)
+	if name != "" {
+		if index >= 0 && index < len(monitors) && monitors[index].Name() == name {
+			return monitors[index]
+		}
+		for _, m := range monitors {
+			if m.Name() == name {
+				return m
+			}
+		}
+	}
+	return monitors[0]
+}
+
+// trackWindowGeometry records moves and resizes as they happen, since the
+// window is gone by the time Close runs. While maximized only the flag
+// changes, so un-maximizing after a restart returns to the old size.
+func (w *Window) trackWindowGeometry() {
+	if ebiten.IsFullscreen() || ebiten.IsWindowMinimized() {
+		return
+	}
+	g := w.Config.Window
+	m := ebiten.Monitor()
+	g.Maximized = ebiten.IsWindowMaximized()
+	if !g.Maximized {
+		g.X, g.Y = ebiten.WindowPosition()
+		g.Width, g.Height = ebiten.WindowSize()
+	}
+	if g == w.Config.Window && m == w.windowMonitor {
+		return
+	}
+	if g.Width <= 0 || g.Height <= 0 {
+		return
+	}
+	w.windowMonitor = m
+	if m != nil {
+		g.Monitor = m.Name()
+		for i, o := range ebiten.AppendMonitors(nil) {
+			if o == m {
+				g.MonitorIndex = i
+			}
+		}
+	}
+	if g != w.Config.Window {
+		w.Config.Window = g
+		w.Config.Save()
+	}
+}
+
+// resetWindowGeometry brings the window back to the default size, centered
+// on the primary monitor, for when it ended up somewhere unusable.
+func (w *Window) resetWindowGeometry() {
+	if ebiten.IsWindowMaximized() {
+		ebiten.RestoreWindow()
+	}
+	m := ebiten.AppendMonitors(nil)[0]
+	ebiten.SetMonitor(m)
+	ebiten.SetWindowSize(defaultWindowWidth, defaultWindowHeight)
+	mw, mh := m.Size()
+	ebiten.SetWindowPosition((mw-defaultWindowWidth)/2, (mh-defaultWindowHeight)/2)
+	w.Config.Window = config.WindowGeometry{}
+	w.Config.Save()
+	fmt.Println("🪟 Window position reset")
+}
@@ -0,0 +1,265 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/maps"
+	"github.com/devin-hart/nox-maps/internal/widgets"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// A character can't walk through walls, so a trail that keeps crossing the
+// map's lines means the map doesn't match the zone: the wrong file, or an
+// outdated one. One crossing proves nothing (doors, /loc gaps cutting
+// corners), so the hint waits for most of the recent steps to cross.
+const (
+	wallCheckSteps   = 12    // Recent trail steps considered
+	wallCheckTrigger = 7     // How many of them must cross a wall
+	wallCheckMaxStep = 250.0 // Longer steps are ports, gates or missed /locs
+	wallCheckZSlack  = 40.0  // Lines further above/below the player than this don't count
+	wallGridCell     = 100.0 // Map units
+	maxMapCandidates = 15
+)
+
+type wallStep struct {
+	x1, y1, x2, y2, z float64
+	crossed           bool
+}
+
+type wallCheck struct {
+	data    *maps.ZoneMap // Map the grid was built for; a new map starts over
+	grid    *maps.LineGrid
+	hasZ    bool       // Whether the map's lines carry heights
+	steps   []wallStep // Oldest first
+	warned  bool
+	ignored map[string]bool // File codes the user stopped checking (this session)
+}
+
+func (wc *wallCheck) reset(data *maps.ZoneMap) {
+	wc.data = data
+	wc.grid = maps.NewLineGrid(data.Lines, wallGridCell)
+	wc.hasZ = mapHasZ(data)
+	wc.steps = wc.steps[:0]
+	wc.warned = false
+}
+
+func mapHasZ(data *maps.ZoneMap) bool {
+	for _, l := range data.Lines {
+		if l.Z1 != 0 || l.Z2 != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// stepCrosses reports whether a step crosses any wall near its height.
+func stepCrosses(grid *maps.LineGrid, hasZ bool, s wallStep) bool {
+	keep := func(l maps.MapLine) bool {
+		return !hasZ || (s.z >= min(l.Z1, l.Z2)-wallCheckZSlack && s.z <= max(l.Z1, l.Z2)+wallCheckZSlack)
+	}
+	return len(grid.Crossings(s.x1, s.y1, s.x2, s.y2, keep)) > 0
+}
+
+// checkWallCrossing tests the step between two breadcrumbs against the
+// map, raising the wrong-map hint once enough recent steps cross walls.
+func (w *Window) checkWallCrossing(from, to BreadcrumbPoint) {
+	wc := &w.wallCheck
+	if w.MapData == nil || w.browsing() || wc.ignored[strings.ToLower(w.mapFileCode)] {
+		return
+	}
+	if wc.data != w.MapData {
+		wc.reset(w.MapData)
+	}
+	if math.Hypot(to.X-from.X, to.Y-from.Y) > wallCheckMaxStep {
+		return
+	}
+
+	s := wallStep{x1: from.X, y1: from.Y, x2: to.X, y2: to.Y, z: (from.Z + to.Z) / 2}
+	s.crossed = stepCrosses(wc.grid, wc.hasZ, s)
+	wc.steps = append(wc.steps, s)
+	if len(wc.steps) > wallCheckSteps {
+		wc.steps = wc.steps[1:]
+	}
+	if !wc.warned && crossedSteps(wc.steps) >= wallCheckTrigger {
+		wc.warned = true
+		fmt.Printf("⚠️  Trail crossed map walls on %d of the last %d steps - %s map (%s) may be wrong or outdated\n",
+			crossedSteps(wc.steps), len(wc.steps), w.CurrentZone, w.mapFileCode)
+	}
+}
+
+func crossedSteps(steps []wallStep) int {
+	n := 0
+	for _, s := range steps {
+		if s.crossed {
+			n++
+		}
+	}
+	return n
+}
+
+// wrongMapShown reports whether the hint banner is up; the out-of-bounds
+// banner, when showing, takes the same spot and wins.
+func (w *Window) wrongMapShown() bool {
+	return w.wallCheck.warned && w.wallCheck.data == w.MapData && !w.outOfBounds && !w.browsing()
+}
+
+func (w *Window) handleWrongMapBannerClick(mx, my int) bool {
+	if !w.wrongMapShown() {
+		return false
+	}
+	x, y, width := w.boundsBannerRect()
+	if mx < x || mx >= x+width || my < y || my >= y+w.bannerHeight() {
+		return false
+	}
+	w.wrongMapOptions()
+	return true
+}
+
+func (w *Window) drawWrongMapBanner(screen *ebiten.Image) {
+	if !w.wrongMapShown() {
+		return
+	}
+	x, y, width := w.boundsBannerRect()
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), float32(w.bannerHeight()), color.RGBA{110, 70, 10, 230}, false)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(width), float32(w.bannerHeight()), 1, color.RGBA{255, 180, 60, 255}, false)
+	w.drawBannerText(screen, "Trail keeps crossing walls (map wrong or outdated?)  [Options...]", x, y, color.RGBA{255, 240, 220, 255})
+}
+
+const (
+	wrongMapTryOther = "Try another map file..."
+	wrongMapDefault  = "Go back to the default map file"
+	wrongMapCheck    = "Check map files"
+	wrongMapIgnore   = "Stop checking this map"
+)
+
+func (w *Window) wrongMapOptions() {
+	items := []string{wrongMapTryOther}
+	if _, ok := w.Config.MapOverrides[strings.ToLower(w.CurrentZone)]; ok {
+		items = append(items, wrongMapDefault)
+	}
+	items = append(items, wrongMapCheck, wrongMapIgnore)
+
+	msg := fmt.Sprintf("Map %s (%s) options:", w.CurrentZone, w.mapFileCode)
+	options := widgets.NewDropdown("Map May Be Wrong", msg, items, 0)
+	w.showModal(options, func(r widgets.Result) {
+		if r != widgets.OK {
+			return
+		}
+		switch _, choice := options.Selected(); choice {
+		case wrongMapTryOther:
+			w.pickAlternateMap()
+		case wrongMapDefault:
+			w.setMapOverride(w.CurrentZone, "")
+		case wrongMapCheck:
+			w.startMapCheck(false)
+		case wrongMapIgnore:
+			if w.wallCheck.ignored == nil {
+				w.wallCheck.ignored = make(map[string]bool)
+			}
+			w.wallCheck.ignored[strings.ToLower(w.mapFileCode)] = true
+			w.wallCheck.warned = false
+		}
+	})
+}
+
+// pickAlternateMap scores every map that covers the trail by how many of
+// the recent steps would cross its walls, fewest first, and uses the one
+// picked for this zone from now on.
+func (w *Window) pickAlternateMap() {
+	w.dialogOpen = true
+	ready := w.zoneIndexReady("Try Another Map")
+	w.dialogOpen = false
+	w.lastMousePressed = true
+	if !ready {
+		return
+	}
+
+	steps := append([]wallStep(nil), w.wallCheck.steps...)
+	if len(steps) == 0 {
+		return
+	}
+	last := steps[len(steps)-1]
+
+	type candidate struct {
+		code    string
+		crossed int
+	}
+	var candidates []candidate
+	for _, b := range w.boundsIndex.Containing(last.x2, last.y2, 0) {
+		if len(candidates) == maxMapCandidates {
+			break
+		}
+		data, err := maps.LoadZoneQuiet(w.MapDir, b.FileCode)
+		if err != nil {
+			continue
+		}
+		grid, hasZ := maps.NewLineGrid(data.Lines, wallGridCell), mapHasZ(data)
+		c := candidate{code: b.FileCode}
+		for _, s := range steps {
+			if stepCrosses(grid, hasZ, s) {
+				c.crossed++
+			}
+		}
+		candidates = append(candidates, c)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { // Ties stay tightest fit first
+		return candidates[i].crossed < candidates[j].crossed
+	})
+
+	items := make([]string, len(candidates))
+	for i, c := range candidates {
+		items[i] = fmt.Sprintf("%s (%s) - crosses walls on %d of %d steps", w.zoneNameForCode(c.code), c.code, c.crossed, len(steps))
+		if strings.EqualFold(c.code, w.mapFileCode) {
+			items[i] += " [current]"
+		}
+	}
+	picker := widgets.NewPicker("Try Another Map", fmt.Sprintf("Maps covering your trail, for %s:", w.CurrentZone), items).
+		WithPreview(func(i int) *ebiten.Image { return w.thumbnail(candidates[i].code) })
+	zone := w.CurrentZone
+	w.showModal(picker, func(r widgets.Result) {
+		i, _ := picker.Selected()
+		if r != widgets.OK || i < 0 || zone != w.CurrentZone {
+			return
+		}
+		w.setMapOverride(zone, candidates[i].code)
+	})
+}
+
+// setMapOverride makes a zone load a different map file ("" goes back to
+// map_keys.json's) and reloads it if shown.
+func (w *Window) setMapOverride(zone, code string) {
+	key := strings.ToLower(zone)
+	if code == "" {
+		delete(w.Config.MapOverrides, key)
+		delete(maps.ZoneFileMap, key) // In case map_keys.json doesn't have it
+	} else {
+		if w.Config.MapOverrides == nil {
+			w.Config.MapOverrides = make(map[string]string)
+		}
+		w.Config.MapOverrides[key] = code
+	}
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving map override: %v\n", err)
+	}
+	w.applyMapOverrides()
+	fmt.Printf("🗺️  %s now uses map file '%s'\n", zone, maps.GetZoneFileName(zone))
+	if zone == w.CurrentZone {
+		w.loadMapForZone(zone)
+	}
+}
+
+// applyMapOverrides rereads map_keys.json and lays the user's per-zone map
+// choices over it.
+func (w *Window) applyMapOverrides() {
+	if err := maps.LoadZoneConfig(w.MapConfigPath); err != nil {
+		fmt.Printf("⚠️  Failed to read %s: %v\n", w.MapConfigPath, err)
+	}
+	for zone, code := range w.Config.MapOverrides {
+		maps.ZoneFileMap[zone] = code
+	}
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/maps"
+	"github.com/ncruces/zenity"
+)
+
+// startZoneIndex (re)builds the all-zones index in the background.
+func (w *Window) startZoneIndex() {
+	w.indexer = maps.StartIndexer(w.MapDir, config.GetZoneIndexPath())
+}
+
+// updateZoneIndex picks up a finished index build.
+func (w *Window) updateZoneIndex() {
+	if w.indexer == nil {
+		return
+	}
+	select {
+	case idx := <-w.indexer.Done:
+		w.boundsIndex = idx
+		w.indexer = nil
+	default:
+	}
+}
+
+// zoneIndexReady reports whether the index can be used, telling the user
+// how far along the build is if not. The caller handles dialogOpen.
+func (w *Window) zoneIndexReady(title string) bool {
+	if w.boundsIndex != nil {
+		return true
+	}
+	msg := "The zone index isn't available."
+	if w.indexer != nil {
+		done, total := w.indexer.Progress()
+		msg = fmt.Sprintf("The zone index is still being built (%d of %d zones). Try again in a moment.", done, total)
+	}
+	zenity.Info(msg, zenity.Title(title))
+	return false
+}
+
+// showMapCoverage reports which known zones have no map files.
+func (w *Window) showMapCoverage() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	if !w.zoneIndexReady("Map Coverage") {
+		return
+	}
+
+	missing := w.boundsIndex.MissingZones()
+	known := len(maps.ZoneFileMap)
+	summary := fmt.Sprintf("%d zones indexed. %d of %d known zones have maps.", len(w.boundsIndex.Zones), known-len(missing), known)
+	if len(missing) == 0 {
+		zenity.Info(summary, zenity.Title("Map Coverage"))
+		return
+	}
+	zenity.List(
+		summary+"\nMissing:",
+		missing,
+		zenity.Title("Map Coverage"),
+		zenity.Height(400),
+	)
+	fmt.Printf("🗂️  Missing maps: %s\n", strings.Join(missing, ", "))
+}
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/maps"
+	"github.com/ncruces/zenity"
+)
+
+// loadZoneInfo reads the bundled zone metadata, then the user's overrides.
+func (w *Window) loadZoneInfo() {
+	for _, path := range []string{maps.Locate(w.MapDir, "zone_info.json"), config.GetZoneInfoPath()} {
+		if err := maps.LoadZoneInfo(path); err != nil {
+			fmt.Printf("⚠️  Failed to read zone info %s: %v\n", path, err)
+		}
+	}
+}
+
+// zoneInfoLine is the info panel line for the shown zone ("" if unknown).
+func (w *Window) zoneInfoLine() string {
+	info, ok := maps.GetZoneInfo(w.mapFileCode)
+	if !ok {
+		return ""
+	}
+	return "Zone Info: " + info.Summary()
+}
+
+// openZoneBrowser lists every known zone with its level range and
+// environment, lowest levels first; picking one browses its map.
+func (w *Window) openZoneBrowser() {
+	w.dialogOpen = true
+	defer func() {
+		w.dialogOpen = false
+		w.lastMousePressed = true
+	}()
+
+	type zoneEntry struct {
+		name string
+		info maps.ZoneInfo
+		ok   bool
+	}
+	seen := make(map[string]bool)
+	var zones []zoneEntry
+	for _, code := range maps.ZoneFileMap {
+		code = strings.ToLower(code)
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		info, ok := maps.GetZoneInfo(code)
+		zones = append(zones, zoneEntry{w.zoneNameForCode(code), info, ok})
+	}
+	sort.Slice(zones, func(i, j int) bool {
+		a, b := zones[i], zones[j]
+		if a.ok != b.ok {
+			return a.ok // Zones without metadata last
+		}
+		if a.info.MinLevel != b.info.MinLevel {
+			return a.info.MinLevel < b.info.MinLevel
+		}
+		return a.name < b.name
+	})
+
+	items := make([]string, len(zones))
+	for i, z := range zones {
+		items[i] = z.name
+		if z.ok {
+			items[i] += " - " + z.info.Summary()
+		}
+	}
+	choice, err := zenity.List(
+		"Zones by level range (pick one to view its map):",
+		items,
+		zenity.Title("Zone Browser"),
+		zenity.Height(520),
+	)
+	if err != nil {
+		return
+	}
+	for i, item := range items {
+		if item != choice {
+			continue
+		}
+		zone := zones[i].name
+		if strings.EqualFold(zone, w.logZone) {
+			zone = w.logZone
+		}
+		if zone != w.CurrentZone {
+			w.viewZone(zone)
+		}
+		return
+	}
+}
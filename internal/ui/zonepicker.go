@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/devin-hart/nox-maps/internal/maps"
+	"github.com/devin-hart/nox-maps/internal/widgets"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// openZonePicker lists every zone name in map_keys.json with a filter box;
+// picking one loads its map whether or not the character is there, for
+// looking around or planning with no log running.
+func (w *Window) openZonePicker() {
+	if len(maps.ZoneFileMap) == 0 {
+		w.showModal(widgets.NewConfirm("Load Zone", "No zones are known (map_keys.json wasn't loaded).", "OK", ""), nil)
+		return
+	}
+
+	// Names and aliases both get a row; the file code lets "ecommons" find
+	// East Commonlands too
+	names := make([]string, 0, len(maps.ZoneFileMap))
+	for name := range maps.ZoneFileMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	items := make([]string, len(names))
+	for i, name := range names {
+		items[i] = fmt.Sprintf("%s (%s)", w.logZoneName(name), maps.ZoneFileMap[name])
+	}
+
+	picker := widgets.NewPicker("Load Zone", "Type to filter, Enter to open:", items).WithPreview(func(i int) *ebiten.Image {
+		return w.thumbnail(maps.ZoneFileMap[names[i]])
+	})
+	w.showModal(picker, func(r widgets.Result) {
+		i, _ := picker.Selected()
+		if r != widgets.OK || i < 0 {
+			return
+		}
+		zone := w.logZoneName(names[i])
+		if strings.EqualFold(zone, w.logZone) {
+			zone = w.logZone
+		}
+		if zone != w.CurrentZone {
+			w.viewZone(zone)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	zoomStep = 1.1  // Per wheel notch
+	zoomRate = 16.0 // How quickly the zoom catches up (per second)
+)
+
+// zoomAnim eases the zoom toward a target, keeping one screen point over
+// the same spot on the map.
+type zoomAnim struct {
+	target           float64 // 0 when idle
+	anchorX, anchorY float64 // Screen position that stays put
+	last             float64 // Zoom the animation last set; anything else cancels it
+}
+
+// wheelZoom starts (or extends) a zoom step anchored on the cursor. While
+// following the player it anchors on the center instead, so the player
+// stays there.
+func (w *Window) wheelZoom(dy float64, mx, my int) {
+	if dy == 0 {
+		return
+	}
+	target := w.Zoom
+	if w.zoomAnim.target != 0 {
+		target = w.zoomAnim.target // Notches in a row add up
+	}
+	if dy > 0 {
+		target *= zoomStep
+	} else {
+		target /= zoomStep
+	}
+
+	ax, ay := float64(mx), float64(my)
+	if w.FollowPlayer {
+		ax, ay = float64(w.Width)/2, float64(w.Height)/2
+	}
+	if w.Config.DisableSmoothZoom {
+		w.zoomAt(target, ax, ay)
+		return
+	}
+	w.zoomAnim = zoomAnim{target: target, anchorX: ax, anchorY: ay, last: w.Zoom}
+}
+
+// updateZoom moves the zoom a fraction of the remaining ratio each frame.
+func (w *Window) updateZoom() {
+	a := &w.zoomAnim
+	if a.target == 0 {
+		return
+	}
+	if w.Zoom != a.last { // Fit, find or another jump took over
+		a.target = 0
+		return
+	}
+	t := 1 - math.Exp(-zoomRate/float64(ebiten.TPS()))
+	next := w.Zoom * math.Pow(a.target/w.Zoom, t)
+	if math.Abs(math.Log(a.target/next)) < 0.002 {
+		next = a.target
+		a.target = 0
+	}
+	w.zoomAt(next, a.anchorX, a.anchorY)
+	a.last = w.Zoom
+}
+
+// zoomAt sets the zoom, moving the camera so the map point under screen
+// position (sx, sy) stays there.
+func (w *Window) zoomAt(zoom, sx, sy float64) {
+	cx, cy := float64(w.Width)/2, float64(w.Height)/2
+	worldX, worldY := w.toWorld(sx, sy, cx, cy)
+	w.Zoom = zoom
+	dx, dy := w.screenDeltaToWorld((sx-cx)/zoom, (sy-cy)/zoom)
+	w.CamX = worldX - dx
+	w.CamY = worldY - dy
+}
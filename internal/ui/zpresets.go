@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"slices"
+
+	"github.com/devin-hart/nox-maps/internal/config"
+	"github.com/devin-hart/nox-maps/internal/widgets"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Z presets are named Z-filter bands saved per zone ("Basement", "Crypt
+// level"). When the player's height falls in one that isn't in use, a
+// banner offers it.
+
+// zPresetSubmenu lists the zone's presets, then saving and deleting them.
+func (w *Window) zPresetSubmenu() []MenuItem {
+	var items []MenuItem
+	for _, p := range w.Config.ZPresets[w.CurrentZone] {
+		items = append(items, MenuItem{
+			Label: fmt.Sprintf("%s (Z %.0f ±%.0f)", p.Name, p.Z, p.Range),
+			Action: func() {
+				w.applyZPreset(p)
+				w.openMenu = ""
+			},
+		})
+	}
+	items = append(items, MenuItem{
+		Label: "Save Current Z as Preset...",
+		Action: func() {
+			w.openMenu = ""
+			w.saveZPreset()
+		},
+	})
+	if len(w.Config.ZPresets[w.CurrentZone]) > 0 {
+		items = append(items, MenuItem{
+			Label: "Delete Preset...",
+			Action: func() {
+				w.openMenu = ""
+				w.deleteZPreset()
+			},
+		})
+	}
+	return items
+}
+
+// applyZPreset shows just the preset's band, in manual mode so it stays put.
+func (w *Window) applyZPreset(p config.ZPreset) {
+	w.ZLevelMode = 2
+	w.ZLevelManual = p.Z
+	w.ZLevelRange = p.Range
+	fmt.Printf("🪜 Z preset '%s': %.0f ±%.0f\n", p.Name, p.Z, p.Range)
+}
+
+// currentZCenter is the height the Z filter is centered on now, or the
+// player's if it's off.
+func (w *Window) currentZCenter() float64 {
+	if w.ZLevelMode == 2 || w.LogReader == nil || w.browsing() {
+		return w.ZLevelManual
+	}
+	return w.LogReader.State().Z
+}
+
+// saveZPreset names the current Z center and range; a preset of the same
+// name is replaced.
+func (w *Window) saveZPreset() {
+	zone := w.CurrentZone
+	if zone == "" {
+		return
+	}
+	p := config.ZPreset{Z: math.Round(w.currentZCenter()), Range: w.ZLevelRange}
+	prompt := fmt.Sprintf("Name for Z %.0f ±%.0f in %s:", p.Z, p.Range, zone)
+	input := widgets.NewTextInput("Save Z Preset", prompt, fmt.Sprintf("Level %d", len(w.Config.ZPresets[zone])+1))
+	w.showModal(input, func(r widgets.Result) {
+		if r != widgets.OK || input.Value() == "" {
+			return
+		}
+		p.Name = input.Value()
+		if w.Config.ZPresets == nil {
+			w.Config.ZPresets = make(map[string][]config.ZPreset)
+		}
+		presets := slices.DeleteFunc(w.Config.ZPresets[zone], func(q config.ZPreset) bool { return q.Name == p.Name })
+		w.Config.ZPresets[zone] = append(presets, p)
+		w.saveZPresets()
+		w.applyZPreset(p)
+	})
+}
+
+func (w *Window) deleteZPreset() {
+	zone := w.CurrentZone
+	presets := w.Config.ZPresets[zone]
+	names := make([]string, len(presets))
+	for i, p := range presets {
+		names[i] = p.Name
+	}
+	choose := widgets.NewDropdown("Delete Z Preset", "Preset to delete:", names, 0)
+	w.showModal(choose, func(r widgets.Result) {
+		i, _ := choose.Selected()
+		if r != widgets.OK || i < 0 || i >= len(w.Config.ZPresets[zone]) {
+			return
+		}
+		w.Config.ZPresets[zone] = slices.Delete(w.Config.ZPresets[zone], i, i+1)
+		if len(w.Config.ZPresets[zone]) == 0 {
+			delete(w.Config.ZPresets, zone)
+		}
+		w.saveZPresets()
+	})
+}
+
+func (w *Window) saveZPresets() {
+	if err := w.Config.Save(); err != nil {
+		fmt.Printf("❌ Error saving Z presets: %v\n", err)
+	}
+}
+
+// suggestedZPreset is the preset whose band the player is in (the nearest
+// center if several), unless it's been dismissed or the Z filter is in manual
+// mode (the user has picked a height already).
+func (w *Window) suggestedZPreset() (config.ZPreset, bool) {
+	var best config.ZPreset
+	found := false
+	if w.LogReader == nil || w.browsing() || w.ZLevelMode == 2 {
+		return best, false
+	}
+	z := w.LogReader.State().Z
+	for _, p := range w.Config.ZPresets[w.CurrentZone] {
+		if math.Abs(z-p.Z) > p.Range || w.zPresetDismissed[w.CurrentZone+"|"+p.Name] {
+			continue
+		}
+		if !found || math.Abs(z-p.Z) < math.Abs(z-best.Z) {
+			best, found = p, true
+		}
+	}
+	return best, found
+}
+
+// zPresetDismissWidth is the width of the [x] at the banner's right end.
+func (w *Window) zPresetDismissWidth() int {
+	return w.uiTextWidth("[x]") + 10
+}
+
+// zPresetBannerRect goes below whichever other banners are up.
+func (w *Window) zPresetBannerRect() (x, y, width int) {
+	if x, y, width, ok := w.repopBannerRect(); ok {
+		return x, y + w.bannerHeight() + 4, width
+	}
+	x, y, width = w.boundsBannerRect()
+	if w.outOfBounds || w.wrongMapShown() {
+		y += w.bannerHeight() + 4
+	}
+	return x, y, width
+}
+
+// handleZPresetBannerClick applies the suggestion, or hides it for this
+// session when the [x] is clicked.
+func (w *Window) handleZPresetBannerClick(mx, my int) bool {
+	p, ok := w.suggestedZPreset()
+	if !ok {
+		return false
+	}
+	x, y, width := w.zPresetBannerRect()
+	if mx < x || mx >= x+width || my < y || my >= y+w.bannerHeight() {
+		return false
+	}
+	if mx >= x+width-w.zPresetDismissWidth() {
+		if w.zPresetDismissed == nil {
+			w.zPresetDismissed = make(map[string]bool)
+		}
+		w.zPresetDismissed[w.CurrentZone+"|"+p.Name] = true
+		return true
+	}
+	w.applyZPreset(p)
+	return true
+}
+
+func (w *Window) drawZPresetBanner(screen *ebiten.Image) {
+	p, ok := w.suggestedZPreset()
+	if !ok {
+		return
+	}
+	x, y, width := w.zPresetBannerRect()
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), float32(w.bannerHeight()), color.RGBA{20, 60, 90, 230}, false)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(width), float32(w.bannerHeight()), 1, color.RGBA{120, 190, 255, 255}, false)
+	msg := fmt.Sprintf("You're in Z preset '%s' (%.0f ±%.0f)  [Apply]", p.Name, p.Z, p.Range)
+	w.drawBannerText(screen, msg, x, y, color.RGBA{225, 240, 255, 255})
+	w.drawUITextIn(screen, "[x]", x+width-w.zPresetDismissWidth()+4, y, w.bannerHeight(), color.RGBA{225, 240, 255, 255})
+}
@@ -0,0 +1,48 @@
+package widgets
+
+import (
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Confirm shows a message with OK and Cancel buttons (just OK when the
+// cancel label is empty, for notices). Enter is OK, Esc is Cancel.
+type Confirm struct {
+	frame
+	lines []string
+}
+
+func NewConfirm(title, message, okLabel, cancelLabel string) *Confirm {
+	c := &Confirm{lines: strings.Split(message, "\n")}
+	c.title = title
+	if cancelLabel != "" {
+		c.addButton(cancelLabel, Cancel)
+	}
+	c.addButton(okLabel, OK)
+	return c
+}
+
+func (c *Confirm) Update() Result {
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		return Cancel
+	case enterPressed():
+		return OK
+	}
+	return c.clickedButton()
+}
+
+func (c *Confirm) Draw(screen *ebiten.Image) {
+	lineH := lineHeight()
+	width := 0
+	for _, l := range c.lines {
+		width = max(width, textWidth(l))
+	}
+	body := c.layout(screen.Bounds(), width, len(c.lines)*lineH)
+	c.draw(screen)
+	for i, l := range c.lines {
+		drawText(screen, clip([]rune(l), body.Dx(), false), body.Min.X, body.Min.Y+i*lineH, lineH, textColor)
+	}
+}
@@ -0,0 +1,126 @@
+package widgets
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// maxDropdownRows is how many items the open list shows before scrolling.
+const maxDropdownRows = 10
+
+// Dropdown picks one of a list of items. Clicking the field opens the list;
+// Up/Down change the choice, Enter accepts, Esc closes the list or cancels.
+type Dropdown struct {
+	frame
+	prompt   string
+	items    []string
+	selected int
+	open     bool
+	scroll   int // First item shown in the open list
+
+	field image.Rectangle
+	rows  []image.Rectangle // Open list rows, from scroll
+}
+
+func NewDropdown(title, prompt string, items []string, selected int) *Dropdown {
+	d := &Dropdown{prompt: prompt, items: items, selected: min(max(selected, 0), len(items)-1)}
+	d.title = title
+	d.addButton("Cancel", Cancel)
+	d.addButton("OK", OK)
+	return d
+}
+
+// Selected returns the chosen item's index and text (-1 and "" if there
+// are no items).
+func (d *Dropdown) Selected() (int, string) {
+	if d.selected < 0 {
+		return -1, ""
+	}
+	return d.selected, d.items[d.selected]
+}
+
+func (d *Dropdown) Update() Result {
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		if d.open {
+			d.open = false
+			return Pending
+		}
+		return Cancel
+	case enterPressed():
+		if d.open {
+			d.open = false
+			return Pending
+		}
+		return OK
+	case repeating(ebiten.KeyDown) && d.selected < len(d.items)-1:
+		d.selected++
+	case repeating(ebiten.KeyUp) && d.selected > 0:
+		d.selected--
+	}
+	d.scroll = min(max(d.scroll, d.selected-maxDropdownRows+1), d.selected)
+	d.scroll = max(d.scroll, 0)
+
+	if _, dy := ebiten.Wheel(); d.open && dy != 0 {
+		d.scroll = min(max(d.scroll-int(dy), 0), max(len(d.items)-maxDropdownRows, 0))
+	}
+
+	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return Pending
+	}
+	p := image.Pt(ebiten.CursorPosition())
+	if d.open {
+		// The open list covers the buttons
+		for i, r := range d.rows {
+			if p.In(r) {
+				d.selected = d.scroll + i
+			}
+		}
+		d.open = false
+		return Pending
+	}
+	if p.In(d.field) && len(d.items) > 0 {
+		d.open = true
+		return Pending
+	}
+	return d.clickedButton()
+}
+
+func (d *Dropdown) Draw(screen *ebiten.Image) {
+	lineH, buttonH := lineHeight(), buttonHeight()
+	width := textWidth(d.prompt)
+	for _, item := range d.items {
+		width = max(width, textWidth(item)+24)
+	}
+	body := d.layout(screen.Bounds(), width, lineH+buttonH)
+	d.draw(screen)
+	drawText(screen, d.prompt, body.Min.X, body.Min.Y, lineH, textColor)
+
+	d.field = image.Rect(body.Min.X, body.Min.Y+lineH+2, body.Max.X, body.Min.Y+lineH+buttonH)
+	fillRect(screen, d.field, fieldColor)
+	strokeRect(screen, d.field, borderColor)
+	_, current := d.Selected()
+	drawText(screen, clip([]rune(current), d.field.Dx()-24, false), d.field.Min.X+4, d.field.Min.Y, d.field.Dy(), textColor)
+	drawText(screen, "v", d.field.Max.X-12, d.field.Min.Y, d.field.Dy(), dimColor)
+
+	d.rows = d.rows[:0]
+	if !d.open {
+		return
+	}
+	cursor := image.Pt(ebiten.CursorPosition())
+	y := d.field.Max.Y
+	for i := d.scroll; i < len(d.items) && i < d.scroll+maxDropdownRows; i++ {
+		r := image.Rect(d.field.Min.X, y, d.field.Max.X, y+lineH+4)
+		bg := fieldColor
+		if i == d.selected || cursor.In(r) {
+			bg = hoverColor
+		}
+		fillRect(screen, r, bg)
+		drawText(screen, clip([]rune(d.items[i]), r.Dx()-8, false), r.Min.X+4, r.Min.Y, r.Dy(), textColor)
+		d.rows = append(d.rows, r)
+		y += lineH + 4
+	}
+	strokeRect(screen, image.Rect(d.field.Min.X, d.field.Max.Y, d.field.Max.X, y), borderColor)
+}
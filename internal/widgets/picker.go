@@ -0,0 +1,216 @@
+package widgets
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	pickerRows  = 14  // Matching items shown at once
+	previewSize = 160 // Box for the highlighted item's preview image
+)
+
+// Picker is a long list with a filter box: typing narrows the list to items
+// containing every typed word (case-insensitive), Up/Down/PageUp/PageDown
+// move the highlight, and Enter or a click picks it.
+type Picker struct {
+	frame
+	prompt  string
+	items   []string
+	filter  []rune
+	matches []int // Indexes into items
+	cursor  int   // Index into matches
+	scroll  int
+	chosen  int
+	preview func(i int) *ebiten.Image
+
+	field image.Rectangle
+	rows  []image.Rectangle // Shown rows, from scroll
+}
+
+func NewPicker(title, prompt string, items []string) *Picker {
+	p := &Picker{prompt: prompt, items: items, chosen: -1}
+	p.title = title
+	p.addButton("Cancel", Cancel)
+	p.addButton("Open", OK)
+	p.refilter()
+	return p
+}
+
+// WithPreview shows an image for the highlighted item beside the list;
+// preview gets the item's index and may return nil for none.
+func (p *Picker) WithPreview(preview func(i int) *ebiten.Image) *Picker {
+	p.preview = preview
+	return p
+}
+
+// Selected returns the picked item's index and text (-1 and "" if nothing
+// matched the filter).
+func (p *Picker) Selected() (int, string) {
+	if p.chosen < 0 {
+		return -1, ""
+	}
+	return p.chosen, p.items[p.chosen]
+}
+
+func (p *Picker) refilter() {
+	words := strings.Fields(strings.ToLower(string(p.filter)))
+	p.matches = p.matches[:0]
+	for i, item := range p.items {
+		lower := strings.ToLower(item)
+		all := true
+		for _, word := range words {
+			if !strings.Contains(lower, word) {
+				all = false
+				break
+			}
+		}
+		if all {
+			p.matches = append(p.matches, i)
+		}
+	}
+	p.cursor, p.scroll = 0, 0
+}
+
+// pick records the highlighted item; false if there is none.
+func (p *Picker) pick() bool {
+	if p.cursor >= len(p.matches) {
+		return false
+	}
+	p.chosen = p.matches[p.cursor]
+	return true
+}
+
+func (p *Picker) Update() Result {
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		return Cancel
+	case enterPressed():
+		if p.pick() {
+			return OK
+		}
+		return Pending
+	}
+
+	if typed := ebiten.AppendInputChars(nil); len(typed) > 0 {
+		p.filter = append(p.filter, typed...)
+		p.refilter()
+	}
+	if repeating(ebiten.KeyBackspace) && len(p.filter) > 0 {
+		p.filter = p.filter[:len(p.filter)-1]
+		p.refilter()
+	}
+
+	before := p.cursor
+	switch {
+	case repeating(ebiten.KeyDown):
+		p.cursor++
+	case repeating(ebiten.KeyUp):
+		p.cursor--
+	case repeating(ebiten.KeyPageDown):
+		p.cursor += pickerRows
+	case repeating(ebiten.KeyPageUp):
+		p.cursor -= pickerRows
+	}
+	p.cursor = max(min(p.cursor, len(p.matches)-1), 0)
+	if p.cursor != before {
+		// Keep the highlight on screen
+		p.scroll = min(max(p.scroll, p.cursor-pickerRows+1), p.cursor)
+	}
+	if _, dy := ebiten.Wheel(); dy != 0 {
+		p.scroll -= int(dy) * 3
+	}
+	p.scroll = max(min(p.scroll, len(p.matches)-pickerRows), 0)
+
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		pt := image.Pt(ebiten.CursorPosition())
+		for i, r := range p.rows {
+			if pt.In(r) {
+				p.cursor = p.scroll + i
+				p.pick()
+				return OK
+			}
+		}
+	}
+	r := p.clickedButton()
+	if r == OK && !p.pick() {
+		return Pending
+	}
+	return r
+}
+
+func (p *Picker) Draw(screen *ebiten.Image) {
+	lineH, buttonH := lineHeight(), buttonHeight()
+	width := textWidth(p.prompt)
+	for _, item := range p.items {
+		width = max(width, min(textWidth(item)+8, 480))
+	}
+	listW := width
+	if p.preview != nil {
+		width += pad + previewSize
+	}
+	listH := pickerRows * lineH
+	body := p.layout(screen.Bounds(), width, lineH+buttonH+4+listH+lineH)
+	if p.preview != nil {
+		listW = body.Dx() - pad - previewSize
+	}
+	p.draw(screen)
+	drawText(screen, p.prompt, body.Min.X, body.Min.Y, lineH, textColor)
+
+	p.field = image.Rect(body.Min.X, body.Min.Y+lineH+2, body.Max.X, body.Min.Y+lineH+buttonH)
+	fillRect(screen, p.field, fieldColor)
+	strokeRect(screen, p.field, borderColor)
+	shown := clip(p.filter, p.field.Dx()-12, true)
+	tx := p.field.Min.X + 4
+	drawText(screen, shown, tx, p.field.Min.Y, p.field.Dy(), textColor)
+	cx := float32(tx + textWidth(shown) + 1)
+	vector.StrokeLine(screen, cx, float32(p.field.Min.Y+4), cx, float32(p.field.Max.Y-4), 1, textColor, false)
+
+	list := image.Rect(body.Min.X, p.field.Max.Y+4, body.Min.X+listW, p.field.Max.Y+4+listH)
+	fillRect(screen, list, fieldColor)
+	strokeRect(screen, list, borderColor)
+	mouse := image.Pt(ebiten.CursorPosition())
+	p.rows = p.rows[:0]
+	for i := p.scroll; i < len(p.matches) && i < p.scroll+pickerRows; i++ {
+		y := list.Min.Y + (i-p.scroll)*lineH
+		r := image.Rect(list.Min.X+1, y, list.Max.X-1, y+lineH)
+		if i == p.cursor || mouse.In(r) {
+			fillRect(screen, r, hoverColor)
+		}
+		drawText(screen, clip([]rune(p.items[p.matches[i]]), r.Dx()-8, false), r.Min.X+4, r.Min.Y, r.Dy(), textColor)
+		p.rows = append(p.rows, r)
+	}
+
+	if p.preview != nil && p.cursor < len(p.matches) {
+		p.drawPreview(screen, image.Rect(list.Max.X+pad, list.Min.Y, body.Max.X, list.Min.Y+previewSize))
+	}
+
+	count := fmt.Sprintf("%d of %d", len(p.matches), len(p.items))
+	drawText(screen, count, body.Min.X, list.Max.Y, lineH, dimColor)
+}
+
+// drawPreview fits the highlighted item's image into box, keeping its shape.
+func (p *Picker) drawPreview(screen *ebiten.Image, box image.Rectangle) {
+	fillRect(screen, box, fieldColor)
+	strokeRect(screen, box, borderColor)
+	img := p.preview(p.matches[p.cursor])
+	if img == nil {
+		const msg = "No preview"
+		drawText(screen, msg, box.Min.X+(box.Dx()-textWidth(msg))/2, box.Min.Y, box.Dy(), dimColor)
+		return
+	}
+	b := img.Bounds()
+	scale := min(float64(box.Dx()-2)/float64(b.Dx()), float64(box.Dy()-2)/float64(b.Dy()))
+	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Scale(scale, scale)
+	opts.GeoM.Translate(
+		float64(box.Min.X)+(float64(box.Dx())-float64(b.Dx())*scale)/2,
+		float64(box.Min.Y)+(float64(box.Dy())-float64(b.Dy())*scale)/2)
+	opts.Filter = ebiten.FilterLinear
+	screen.DrawImage(img, opts)
+}
@@ -0,0 +1,105 @@
+package widgets
+
+import (
+	"image"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// TextInput asks for one line of text. Enter accepts, Esc cancels.
+type TextInput struct {
+	frame
+	prompt string
+	text   []rune
+
+	// The initial text starts selected: typing replaces it, Backspace
+	// clears it, an arrow key keeps it
+	selected bool
+	masked   bool // Shown as asterisks (passwords)
+	frames   int  // For the cursor blink
+	field    image.Rectangle
+}
+
+func NewTextInput(title, prompt, initial string) *TextInput {
+	t := &TextInput{prompt: prompt, text: []rune(initial), selected: initial != ""}
+	t.title = title
+	t.addButton("Cancel", Cancel)
+	t.addButton("OK", OK)
+	return t
+}
+
+// WithExtra adds a third button that closes the input with Extra.
+func (t *TextInput) WithExtra(label string) *TextInput {
+	t.buttons = append([]button{{label: label, result: Extra}}, t.buttons...)
+	return t
+}
+
+// Masked shows the text as asterisks.
+func (t *TextInput) Masked() *TextInput {
+	t.masked = true
+	return t
+}
+
+// Value is the text as typed, untrimmed.
+func (t *TextInput) Value() string {
+	return string(t.text)
+}
+
+func (t *TextInput) Update() Result {
+	t.frames++
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		return Cancel
+	case enterPressed():
+		return OK
+	}
+	if r := t.clickedButton(); r != Pending {
+		return r
+	}
+
+	if typed := ebiten.AppendInputChars(nil); len(typed) > 0 {
+		if t.selected {
+			t.text, t.selected = nil, false
+		}
+		t.text = append(t.text, typed...)
+	}
+	if repeating(ebiten.KeyBackspace) && len(t.text) > 0 {
+		if t.selected {
+			t.text, t.selected = nil, false
+		} else {
+			t.text = t.text[:len(t.text)-1]
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyLeft) || inpututil.IsKeyJustPressed(ebiten.KeyRight) || inpututil.IsKeyJustPressed(ebiten.KeyEnd) {
+		t.selected = false
+	}
+	return Pending
+}
+
+func (t *TextInput) Draw(screen *ebiten.Image) {
+	lineH, buttonH := lineHeight(), buttonHeight()
+	body := t.layout(screen.Bounds(), textWidth(t.prompt), lineH+buttonH)
+	t.draw(screen)
+	drawText(screen, t.prompt, body.Min.X, body.Min.Y, lineH, textColor)
+
+	t.field = image.Rect(body.Min.X, body.Min.Y+lineH+2, body.Max.X, body.Min.Y+lineH+buttonH)
+	fillRect(screen, t.field, fieldColor)
+	strokeRect(screen, t.field, borderColor)
+	typed := t.text
+	if t.masked {
+		typed = []rune(strings.Repeat("*", len(t.text)))
+	}
+	shown := clip(typed, t.field.Dx()-12, true)
+	tx := t.field.Min.X + 4
+	if t.selected {
+		fillRect(screen, image.Rect(tx-1, t.field.Min.Y+3, tx+textWidth(shown)+1, t.field.Max.Y-3), hoverColor)
+	}
+	drawText(screen, shown, tx, t.field.Min.Y, t.field.Dy(), textColor)
+	if t.frames/30%2 == 0 {
+		cx := float32(tx + textWidth(shown) + 1)
+		vector.StrokeLine(screen, cx, float32(t.field.Min.Y+4), cx, float32(t.field.Max.Y-4), 1, textColor, false)
+	}
+}
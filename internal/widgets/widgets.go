@@ -0,0 +1,211 @@
+// Package widgets draws small modal dialogs (text input, confirm box,
+// dropdown, filtered list) inside the Ebiten window, for the prompts that
+// shouldn't depend on an external dialog program.
+//
+// A widget is shown by calling its Update once per frame until it returns
+// something other than Pending, and its Draw after the rest of the frame.
+package widgets
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Result is how a widget was closed.
+type Result int
+
+const (
+	Pending Result = iota // Still open
+	OK
+	Cancel
+	Extra // The optional third button
+)
+
+// Modal is a widget that owns input while it's open.
+type Modal interface {
+	Update() Result
+	Draw(screen *ebiten.Image)
+}
+
+const (
+	pad      = 10
+	minWidth = 320
+)
+
+// face is what widgets draw text in: the UI's face once SetFace is called,
+// Go's TTF at 13 pixels before that. Rows and buttons grow with it.
+var face text.Face
+
+// SetFace sets the face for widget text, normally the UI font at the
+// user's chosen size. Call it from the goroutine that draws.
+func SetFace(f text.Face) {
+	face = f
+}
+
+func currentFace() text.Face {
+	if face == nil {
+		if source, err := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF)); err == nil {
+			face = &text.GoTextFace{Source: source, Size: 13}
+		} else {
+			face = text.NewGoXFace(basicfont.Face7x13)
+		}
+	}
+	return face
+}
+
+// lineHeight is the height of a row of text (16 at the default size).
+func lineHeight() int {
+	m := currentFace().Metrics()
+	return max(16, int(math.Ceil(m.HAscent+m.HDescent+m.HLineGap))+3)
+}
+
+// buttonHeight is the height of buttons and input fields (22 by default).
+func buttonHeight() int {
+	return lineHeight() + 6
+}
+
+func textWidth(s string) int {
+	return int(math.Ceil(text.Advance(s, currentFace())))
+}
+
+// drawText draws s from x, centered vertically in the row from top down
+// height pixels.
+func drawText(dst *ebiten.Image, s string, x, top, height int, c color.Color) {
+	f := currentFace()
+	m := f.Metrics()
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(float64(x), float64(top)+(float64(height)-m.HAscent-m.HDescent)/2)
+	op.ColorScale.ScaleWithColor(c)
+	text.Draw(dst, s, f, op)
+}
+
+var (
+	panelColor  = color.RGBA{20, 20, 20, 240}
+	borderColor = color.RGBA{180, 180, 180, 255}
+	titleColor  = color.RGBA{255, 200, 0, 255}
+	textColor   = color.RGBA{255, 255, 255, 255}
+	dimColor    = color.RGBA{150, 150, 150, 255}
+	fieldColor  = color.RGBA{45, 45, 45, 255}
+	hoverColor  = color.RGBA{70, 70, 90, 255}
+)
+
+type button struct {
+	label  string
+	result Result
+	rect   image.Rectangle
+}
+
+// frame is the box every widget draws in: a title, the widget's body and a
+// row of buttons along the bottom. Rectangles are laid out in Draw and used
+// for hit testing in the next Update.
+type frame struct {
+	title   string
+	buttons []button
+	rect    image.Rectangle
+}
+
+func (f *frame) addButton(label string, result Result) {
+	f.buttons = append(f.buttons, button{label: label, result: result})
+}
+
+// layout centers the frame on screen around a body of the given size and
+// returns the body's rectangle.
+func (f *frame) layout(screen image.Rectangle, bodyW, bodyH int) image.Rectangle {
+	lineH, buttonH := lineHeight(), buttonHeight()
+	w := max(bodyW, textWidth(f.title), minWidth) + 2*pad
+	w = min(w, screen.Dx()-20)
+	h := lineH + pad + bodyH + pad + buttonH + pad
+	x := screen.Min.X + (screen.Dx()-w)/2
+	y := screen.Min.Y + (screen.Dy()-h)/3 // A little above center, like a system dialog
+	f.rect = image.Rect(x, y, x+w, y+h)
+
+	// Buttons right-aligned, in the order added
+	bx := f.rect.Max.X - pad
+	for i := len(f.buttons) - 1; i >= 0; i-- {
+		bw := max(textWidth(f.buttons[i].label)+16, 64)
+		f.buttons[i].rect = image.Rect(bx-bw, f.rect.Max.Y-pad-buttonH, bx, f.rect.Max.Y-pad)
+		bx -= bw + 8
+	}
+	return image.Rect(x+pad, y+lineH+pad, x+w-pad, y+lineH+pad+bodyH)
+}
+
+// clickedButton returns the result of a button clicked this frame.
+func (f *frame) clickedButton() Result {
+	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return Pending
+	}
+	p := image.Pt(ebiten.CursorPosition())
+	for _, b := range f.buttons {
+		if p.In(b.rect) {
+			return b.result
+		}
+	}
+	return Pending
+}
+
+func (f *frame) draw(dst *ebiten.Image) {
+	fillRect(dst, f.rect, panelColor)
+	strokeRect(dst, f.rect, borderColor)
+	drawText(dst, f.title, f.rect.Min.X+pad, f.rect.Min.Y+pad/2, lineHeight(), titleColor)
+
+	cursor := image.Pt(ebiten.CursorPosition())
+	for _, b := range f.buttons {
+		bg := fieldColor
+		if cursor.In(b.rect) {
+			bg = hoverColor
+		}
+		fillRect(dst, b.rect, bg)
+		strokeRect(dst, b.rect, borderColor)
+		tx := b.rect.Min.X + (b.rect.Dx()-textWidth(b.label))/2
+		drawText(dst, b.label, tx, b.rect.Min.Y, b.rect.Dy(), textColor)
+	}
+}
+
+func fillRect(dst *ebiten.Image, r image.Rectangle, c color.Color) {
+	vector.DrawFilledRect(dst, float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()), c, false)
+}
+
+func strokeRect(dst *ebiten.Image, r image.Rectangle, c color.Color) {
+	vector.StrokeRect(dst, float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()), 1, c, false)
+}
+
+// clip shortens s to fit width pixels, keeping the end when tail is set
+// (for text being typed).
+func clip(s []rune, width int, tail bool) string {
+	if textWidth(string(s)) <= width {
+		return string(s)
+	}
+	if tail {
+		for len(s) > 1 && textWidth(string(s)) > width {
+			s = s[1:]
+		}
+		return string(s)
+	}
+	for len(s) > 0 && textWidth(string(s)+"...") > width {
+		s = s[:len(s)-1]
+	}
+	return string(s) + "..."
+}
+
+// repeating reports a key press plus auto-repeat while held.
+func repeating(key ebiten.Key) bool {
+	const (
+		delay    = 30
+		interval = 3
+	)
+	d := inpututil.KeyPressDuration(key)
+	return d == 1 || (d >= delay && (d-delay)%interval == 0)
+}
+
+func enterPressed() bool {
+	return inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter)
+}
@@ -0,0 +1,34 @@
+// Package eqlogparse classifies EverQuest log lines into typed events: zone
+// changes, /loc results, /who lists, tracking, deaths and corpse recovery,
+// chat, considers, kills and experience.
+//
+// It only reads text. What a line means for a character (where they are,
+// which corpse a rez recovered) is left to the caller, so the package can be
+// shared by any tool that reads eqlog_<Name>_<server>.txt files.
+//
+// The default patterns match P99 and most emulators. Servers that word a
+// message differently can replace any of them; a replacement that doesn't
+// compile falls back to the default:
+//
+//	p := eqlogparse.DefaultPatterns()
+//	p.ZoneEntry = `You have entered (.+)\.`
+//	c, errs := eqlogparse.Compile(p)
+//
+// Lines are classified one at a time with Classify, or read from a log with
+// Read (an iterator) or Stream (a channel for a tailed log):
+//
+//	for entry, err := range c.Read(f) {
+//		if err != nil {
+//			return err
+//		}
+//		switch ev := entry.Event.(type) {
+//		case eqlogparse.ZoneEntered:
+//			fmt.Println(entry.Time, "entered", ev.Zone)
+//		case eqlogparse.Location:
+//			fmt.Println("at", ev.Y, ev.X, ev.Z)
+//		}
+//	}
+//
+// Coordinates are reported as the client prints them (Y first); EQ map files
+// use the same axes negated.
+package eqlogparse
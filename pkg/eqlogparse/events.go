@@ -0,0 +1,99 @@
+package eqlogparse
+
+// Event is what a classified line says happened. It is one of the types
+// below.
+type Event interface {
+	event()
+}
+
+// Location is a /loc result.
+type Location struct {
+	Y, X, Z    float64
+	Heading    float64 // In the server's heading units; see HasHeading
+	HasHeading bool    // Some emulators append the heading as a fourth value
+	Err        error   // A value couldn't be read in the configured number format
+}
+
+// Loading is the client starting to zone; the next zone message says where.
+type Loading struct{}
+
+// ZoneEntered names the zone the character is now in, as the log wrote it
+// (a long name like "Greater Faydark" or a short one like "gfaydark").
+type ZoneEntered struct {
+	Zone string
+}
+
+// Who is one player in /who's list. The zone summary after the list
+// ("There are 3 players in X.") is reported as ZoneEntered.
+type Who struct {
+	Name      string
+	Level     int    // 0 when anonymous
+	Class     string // As listed: the class or its level title ("Warlord")
+	Race      string // Empty when anonymous
+	Guild     string
+	Anonymous bool
+}
+
+// Tracking is the character starting to track something.
+type Tracking struct {
+	Target string
+}
+
+// SenseHeading is the Sense Heading skill's message.
+type SenseHeading struct {
+	Direction string // "North", "NorthEast", ...
+}
+
+// Chat channels
+const (
+	ChannelOOC   = "ooc"
+	ChannelGroup = "group"
+	ChannelGuild = "guild"
+)
+
+// Chat is a line said on one of the Channel* channels.
+type Chat struct {
+	Channel string
+	Speaker string // "You" for the log's own character
+	Text    string
+	Self    bool
+}
+
+// Consider is the result of /con.
+type Consider struct {
+	Target string
+	Level  string // The level half, e.g. "looks kind of dangerous."
+}
+
+// Repop is a server-wide repop announcement (P99's earthquake).
+type Repop struct{}
+
+// Kill is the character killing something.
+type Kill struct {
+	Target string
+}
+
+// Experience is the character gaining solo, party or raid experience.
+type Experience struct{}
+
+// Death is the character being slain.
+type Death struct{}
+
+// Recovery is a corpse being summoned, rezzed or decaying.
+type Recovery struct {
+	Decayed bool
+}
+
+func (Location) event()     {}
+func (Loading) event()      {}
+func (ZoneEntered) event()  {}
+func (Who) event()          {}
+func (Tracking) event()     {}
+func (SenseHeading) event() {}
+func (Chat) event()         {}
+func (Consider) event()     {}
+func (Repop) event()        {}
+func (Kill) event()         {}
+func (Experience) event()   {}
+func (Death) event()        {}
+func (Recovery) event()     {}
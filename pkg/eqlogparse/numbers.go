@@ -0,0 +1,95 @@
+package eqlogparse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Number formats for Patterns.Numbers. Some localized clients write
+// /loc values with a decimal comma ("-123,45") or group thousands
+// ("1.234,56", "1,234.56", "1'234.56").
+const (
+	NumbersAuto  = ""      // Guess per number (see ParseNumber)
+	NumbersDot   = "dot"   // 1,234.56
+	NumbersComma = "comma" // 1.234,56
+)
+
+func validNumbers(format string) bool {
+	return format == NumbersAuto || format == NumbersDot || format == NumbersComma
+}
+
+// ParseNumber reads a number written in the given format. Apostrophes and
+// spaces always group thousands.
+//
+// Auto takes the last '.' or ',' as the decimal point when both appear, a
+// separator that appears more than once as grouping, and a single separator
+// as the decimal point - so "1,234" reads as 1.234; set the format
+// explicitly for clients that group thousands without decimals.
+//
+// Anything but digits, one decimal point and groups of three is an error,
+// so "1.234,56" in the dot format fails instead of reading as 1.23456.
+func ParseNumber(s, format string) (float64, error) {
+	bad := fmt.Errorf("%q is not a number", s)
+	clean := strings.TrimSpace(s)
+	sign := ""
+	if rest, ok := strings.CutPrefix(clean, "-"); ok {
+		sign, clean = "-", rest
+	}
+
+	decimal := byte('.')
+	switch format {
+	case NumbersDot:
+	case NumbersComma:
+		decimal = ','
+	default:
+		dots, commas := strings.Count(clean, "."), strings.Count(clean, ",")
+		switch {
+		case dots > 0 && commas > 0:
+			if strings.LastIndexByte(clean, ',') > strings.LastIndexByte(clean, '.') {
+				decimal = ','
+			}
+		case commas == 1:
+			decimal = ','
+		case dots > 1:
+			decimal = ',' // "1.234.567": the dots are grouping
+		}
+	}
+
+	group := '.'
+	if decimal == '.' {
+		group = ','
+	}
+	clean = strings.Map(func(r rune) rune {
+		switch r {
+		case '\'', ' ', '\u00a0', '\u202f': // Apostrophe, space, no-break spaces
+			return group
+		}
+		return r
+	}, clean)
+
+	whole, frac, hasFrac := strings.Cut(clean, string(decimal))
+	groups := strings.Split(whole, string(group))
+	for i, g := range groups {
+		if !isDigits(g) || (len(groups) > 1 && (len(g) > 3 || i > 0 && len(g) != 3)) {
+			return 0, bad
+		}
+	}
+	if hasFrac && !isDigits(frac) {
+		return 0, bad
+	}
+	v, err := strconv.ParseFloat(sign+strings.Join(groups, "")+"."+frac, 64)
+	if err != nil {
+		return 0, bad
+	}
+	return v, nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,65 @@
+package eqlogparse
+
+import "testing"
+
+func TestParseNumber(t *testing.T) {
+	const fail = -1e9 // Want an error
+	tests := []struct {
+		in               string
+		auto, dot, comma float64
+	}{
+		{"123.45", 123.45, 123.45, fail},
+		{"-123,45", -123.45, fail, -123.45},
+		{"1.234,56", 1234.56, fail, 1234.56},
+		{"1,234.56", 1234.56, 1234.56, fail},
+		{"1 234.5", 1234.5, 1234.5, fail},
+		{"1 234,5", 1234.5, fail, 1234.5},
+		{"1'234.56", 1234.56, 1234.56, fail},
+		{"1\u00a0234,5", 1234.5, fail, 1234.5}, // No-break space
+		{"1,234", 1.234, 1234, 1.234},          // Auto can't tell; a lone separator is the decimal point
+		{"1.234", 1.234, 1.234, 1234},
+		{"1.234.567", 1234567, fail, 1234567},
+		{"1,234,567.5", 1234567.5, 1234567.5, fail},
+		{"-0.5", -0.5, -0.5, fail},
+		{" 42 ", 42, 42, 42},
+		{"0", 0, 0, 0},
+
+		// Garbage fails in every format instead of reading as 0 or a
+		// different number
+		{"", fail, fail, fail},
+		{"-", fail, fail, fail},
+		{"abc", fail, fail, fail},
+		{"NaN", fail, fail, fail},
+		{"Inf", fail, fail, fail},
+		{"1e5", fail, fail, fail},
+		{"0x10", fail, fail, fail},
+		{"12.", fail, fail, fail},
+		{",5", fail, fail, fail},
+		{"1,2,3", fail, fail, fail},
+		{"12,34.5", fail, fail, fail}, // Groups are threes
+		{"1234,567.5", fail, fail, fail},
+		{"1.2.3,4", fail, fail, fail},
+		{"--1", fail, fail, fail},
+		{"1-2", fail, fail, fail},
+	}
+	for _, tt := range tests {
+		for _, f := range []struct {
+			format string
+			want   float64
+		}{
+			{NumbersAuto, tt.auto},
+			{NumbersDot, tt.dot},
+			{NumbersComma, tt.comma},
+		} {
+			got, err := ParseNumber(tt.in, f.format)
+			switch {
+			case f.want == fail && err == nil:
+				t.Errorf("ParseNumber(%q, %q) = %v, want an error", tt.in, f.format, got)
+			case f.want != fail && err != nil:
+				t.Errorf("ParseNumber(%q, %q): %v, want %v", tt.in, f.format, err, f.want)
+			case f.want != fail && got != f.want:
+				t.Errorf("ParseNumber(%q, %q) = %v, want %v", tt.in, f.format, got, f.want)
+			}
+		}
+	}
+}
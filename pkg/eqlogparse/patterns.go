@@ -0,0 +1,242 @@
+package eqlogparse
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Patterns are the regular expressions lines are classified with. An empty
+// field uses the default.
+type Patterns struct {
+	Location     string // Captures Y, X, Z and optionally heading
+	ZoneEntry    string // Captures the zone name (the first group that matched)
+	Death        string
+	Recovery     string
+	SenseHeading string // Captures a direction like "NorthEast"
+	OOC          string // Captures speaker ("You" for yourself) and message
+	Group        string // Same captures as OOC
+	Guild        string // Same captures as OOC
+	Consider     string // Captures the mob name and the level phrase
+	Repop        string
+	Kill         string // Captures what was killed
+	Experience   string
+	Loading      string // The client started zoning
+	Who          string // Captures level, class, name, race and guild of a /who row
+	Tracking     string // Captures what is being tracked
+
+	Numbers string // Number format of /loc values (see numbers.go)
+}
+
+// DefaultPatterns returns the patterns used for fields left empty.
+func DefaultPatterns() Patterns {
+	return Patterns{
+		// Some emulators append the heading as a fourth value. Values may use
+		// a decimal comma or thousands grouping (see numbers.go); the ", "
+		// between them keeps that unambiguous.
+		Location: `Your Location is (-?[\d.,']*\d), (-?[\d.,']*\d), (-?[\d.,']*\d)(?:, (-?[\d.,']*\d))?`,
+		// Emulators vary the entry message (no period, a long or short name),
+		// and /who's summary names the zone too ("There are 3 players in X.")
+		ZoneEntry: `You have entered (.+?)\.?$|There (?:is|are) \d+ (?:other )?players? in (.+?)\.?$`,
+		Death:     `You have been slain`,
+		// Multiple ways to recover a corpse
+		Recovery:     `Summoning.*corpse|corpse.*Summoning|You receive a resurrection|You have been resurrected|corpse decays`,
+		SenseHeading: `You think you are heading ([A-Za-z ]+)\.`,
+		OOC:          `(\w+) says? out of character, '(.*)'$`,
+		Group:        `(\w+) tells? (?:the group|your party), '(.*)'$`,
+		Guild:        `(\w+) (?:tells the guild|say to your guild), '(.*)'$`,
+		// The faction half varies ("regards you as an ally", "scowls at you, ready to attack")
+		Consider: `^(?:\[[^\]]*\] )?(.+?) (?:regards you|looks upon you|kindly considers you|judges you|looks your way|glowers at you|glares at you|scowls at you).* -- (.+)$`,
+		// P99's earthquake broadcast
+		Repop:      `The Gods of Norrath emit a sinister laugh`,
+		Kill:       `You have slain (.+?)!`,
+		Experience: `You gain(?:ed)? (?:party |raid )?experience`,
+		Loading:    `LOADING, PLEASE WAIT`,
+		// "[50 Warlord] Fippy (Barbarian) <Guild>", "[ANONYMOUS] Fippy  <Guild>",
+		// with AFK or <LINKDEAD> in front and "ZONE: x" or "LFG" after
+		Who:      `^(?:\[[^\]]*\] )?\s*(?:AFK |<LINKDEAD>)*\[(?:(\d+) ([^\]]+)|ANONYMOUS)\] (\w+)(?: \(([^)]+)\))?\s*(?:<([^>]+)>)?`,
+		Tracking: `You begin tracking (.+?)\.?$`,
+	}
+}
+
+// Classifier is a compiled set of Patterns. It is safe for concurrent use.
+type Classifier struct {
+	Location     *Matcher
+	ZoneEntry    *Matcher
+	Death        *Matcher
+	Recovery     *Matcher
+	SenseHeading *Matcher
+	OOC          *Matcher
+	Group        *Matcher
+	Guild        *Matcher
+	Consider     *Matcher
+	Repop        *Matcher
+	Kill         *Matcher
+	Experience   *Matcher
+	Loading      *Matcher
+	Who          *Matcher
+	Tracking     *Matcher
+
+	Numbers string
+}
+
+// Compile builds a Classifier. A pattern that doesn't compile (or lacks the
+// required capture groups) is reported and the default is used in its
+// place, so one typo doesn't stop every other kind of line being read.
+func Compile(p Patterns) (*Classifier, []error) {
+	def := DefaultPatterns()
+	var errs []error
+	pick := func(name, override, def string, groups int) *Matcher {
+		if override != "" {
+			re, err := regexp.Compile(override)
+			if err == nil && re.NumSubexp() < groups {
+				err = fmt.Errorf("needs %d capture groups, has %d", groups, re.NumSubexp())
+			}
+			if err == nil {
+				return newMatcher(re)
+			}
+			errs = append(errs, fmt.Errorf("%s pattern %q: %v", name, override, err))
+		}
+		return newMatcher(regexp.MustCompile(def))
+	}
+
+	c := &Classifier{
+		Location:     pick("location", p.Location, def.Location, 3),
+		ZoneEntry:    pick("zone_entry", p.ZoneEntry, def.ZoneEntry, 1),
+		Death:        pick("death", p.Death, def.Death, 0),
+		Recovery:     pick("recovery", p.Recovery, def.Recovery, 0),
+		SenseHeading: pick("sense_heading", p.SenseHeading, def.SenseHeading, 1),
+		OOC:          pick("ooc", p.OOC, def.OOC, 2),
+		Group:        pick("group", p.Group, def.Group, 2),
+		Guild:        pick("guild", p.Guild, def.Guild, 2),
+		Consider:     pick("consider", p.Consider, def.Consider, 2),
+		Repop:        pick("repop", p.Repop, def.Repop, 0),
+		Kill:         pick("kill", p.Kill, def.Kill, 1),
+		Experience:   pick("experience", p.Experience, def.Experience, 0),
+		Loading:      pick("loading", p.Loading, def.Loading, 0),
+		Who:          pick("who", p.Who, def.Who, 5),
+		Tracking:     pick("tracking", p.Tracking, def.Tracking, 1),
+
+		Numbers: p.Numbers,
+	}
+	if !validNumbers(c.Numbers) {
+		errs = append(errs, fmt.Errorf("numbers %q: use %q, %q or leave it empty", c.Numbers, NumbersDot, NumbersComma))
+		c.Numbers = NumbersAuto
+	}
+	return c, errs
+}
+
+// Classify reports what a line says happened, or nil if it's nothing the
+// patterns know. The timestamp prefix may be left on. Patterns are tried in
+// a fixed order (/loc first, corpse recovery last) and the first match wins.
+func (c *Classifier) Classify(line string) Event {
+	if m := c.Location.FindStringSubmatch(line); len(m) >= 4 {
+		return c.location(m)
+	}
+
+	if c.Loading.MatchString(line) {
+		return Loading{}
+	}
+	if zone, ok := c.zone(line); ok {
+		if zone == "" {
+			return nil // Matched, but not a real zone
+		}
+		return ZoneEntered{Zone: zone}
+	}
+
+	if m := c.Who.FindStringSubmatch(line); len(m) >= 6 {
+		return who(m)
+	}
+
+	if m := c.SenseHeading.FindStringSubmatch(line); len(m) >= 2 {
+		return SenseHeading{Direction: m[1]}
+	}
+
+	for _, ch := range []struct {
+		channel string
+		re      *Matcher
+	}{
+		{ChannelOOC, c.OOC},
+		{ChannelGroup, c.Group},
+		{ChannelGuild, c.Guild},
+	} {
+		if m := ch.re.FindStringSubmatch(line); len(m) >= 3 {
+			return Chat{Channel: ch.channel, Speaker: m[1], Text: m[2], Self: m[1] == "You"}
+		}
+	}
+
+	if m := c.Consider.FindStringSubmatch(line); len(m) >= 3 {
+		return Consider{Target: m[1], Level: m[2]}
+	}
+	if c.Repop.MatchString(line) {
+		return Repop{}
+	}
+	if m := c.Kill.FindStringSubmatch(line); len(m) >= 2 {
+		return Kill{Target: m[1]}
+	}
+	if m := c.Tracking.FindStringSubmatch(line); len(m) >= 2 {
+		return Tracking{Target: m[1]}
+	}
+	if c.Experience.MatchString(line) {
+		return Experience{}
+	}
+	if c.Death.MatchString(line) {
+		return Death{}
+	}
+	if c.Recovery.MatchString(line) {
+		return Recovery{Decayed: strings.Contains(line, "decays")}
+	}
+	return nil
+}
+
+// Zone returns the zone a line reports entering, or "" if it doesn't.
+func (c *Classifier) Zone(line string) string {
+	zone, _ := c.zone(line)
+	return zone
+}
+
+// zone matches ZoneEntry; ok with an empty zone means the line matched but
+// names something that isn't a zone.
+func (c *Classifier) zone(line string) (zone string, ok bool) {
+	m := c.ZoneEntry.FindStringSubmatch(line)
+	if m == nil {
+		return "", false
+	}
+	for _, s := range m[1:] {
+		if s != "" {
+			zone = strings.TrimSpace(s)
+			break
+		}
+	}
+	// "an Arena (PvP) area" is a status, not a zone name, and /who all
+	// counts players "in EverQuest"
+	if zone == "" || strings.Contains(zone, "(PvP)") || strings.HasSuffix(zone, " area") || zone == "EverQuest" {
+		return "", true
+	}
+	return zone, true
+}
+
+func (c *Classifier) location(m []string) Location {
+	var loc Location
+	var errY, errX, errZ error
+	loc.Y, errY = ParseNumber(m[1], c.Numbers)
+	loc.X, errX = ParseNumber(m[2], c.Numbers)
+	loc.Z, errZ = ParseNumber(m[3], c.Numbers)
+	if err := errors.Join(errY, errX, errZ); err != nil {
+		return Location{Err: err}
+	}
+	if len(m) >= 5 && m[4] != "" {
+		if h, err := ParseNumber(m[4], c.Numbers); err == nil {
+			loc.Heading, loc.HasHeading = h, true
+		}
+	}
+	return loc
+}
+
+func who(m []string) Who {
+	w := Who{Name: m[3], Class: m[2], Race: m[4], Guild: m[5], Anonymous: m[1] == ""}
+	w.Level, _ = strconv.Atoi(m[1])
+	return w
+}
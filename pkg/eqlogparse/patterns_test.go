@@ -0,0 +1,123 @@
+package eqlogparse
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	c, errs := Compile(Patterns{})
+	if len(errs) > 0 {
+		t.Fatalf("default patterns: %v", errs)
+	}
+	const ts = "[Mon Oct 12 20:01:02 2026] "
+	tests := []struct {
+		line string
+		want Event
+	}{
+		{ts + "Your Location is -1234.50, 567.25, 12.00", Location{Y: -1234.5, X: 567.25, Z: 12}},
+		{"Your Location is 10.00, 20.00, 30.00, 128.00", Location{Y: 10, X: 20, Z: 30, Heading: 128, HasHeading: true}},
+		{"Your Location is -123,45, 1.234,56, 0,00", Location{Y: -123.45, X: 1234.56, Z: 0}},
+		{ts + "LOADING, PLEASE WAIT...", Loading{}},
+		{ts + "You have entered Greater Faydark.", ZoneEntered{Zone: "Greater Faydark"}},
+		{ts + "You have entered gfaydark", ZoneEntered{Zone: "gfaydark"}},
+		{ts + "There are 3 players in Greater Faydark.", ZoneEntered{Zone: "Greater Faydark"}},
+		{ts + "There is 1 other player in Kelethin.", ZoneEntered{Zone: "Kelethin"}},
+		{ts + "There are 512 players in EverQuest.", nil},
+		{ts + "You have entered an Arena (PvP) area.", nil},
+		{ts + "[50 Warlord] Fippy (Barbarian) <Nox Raiders>", Who{Name: "Fippy", Level: 50, Class: "Warlord", Race: "Barbarian", Guild: "Nox Raiders"}},
+		{ts + "[ANONYMOUS] Tamsin  <Nox Raiders>", Who{Name: "Tamsin", Guild: "Nox Raiders", Anonymous: true}},
+		{ts + " AFK [12 Ranger] Ulric (Wood Elf)", Who{Name: "Ulric", Level: 12, Class: "Ranger", Race: "Wood Elf"}},
+		{"[60 Grave Lord] Vesna (Dark Elf) <Shadow Court> ZONE: gfaydark", Who{Name: "Vesna", Level: 60, Class: "Grave Lord", Race: "Dark Elf", Guild: "Shadow Court"}},
+		{ts + "Players on EverQuest:", nil},
+		{ts + "You think you are heading NorthEast.", SenseHeading{Direction: "NorthEast"}},
+		{ts + "Fippy says out of character, 'LFG'", Chat{Channel: ChannelOOC, Speaker: "Fippy", Text: "LFG"}},
+		{ts + "You say out of character, 'WTS Cloak'", Chat{Channel: ChannelOOC, Speaker: "You", Text: "WTS Cloak", Self: true}},
+		{ts + "Tamsin tells the group, 'ready'", Chat{Channel: ChannelGroup, Speaker: "Tamsin", Text: "ready"}},
+		{ts + "You tell your party, 'inc'", Chat{Channel: ChannelGroup, Speaker: "You", Text: "inc", Self: true}},
+		{ts + "Ulric tells the guild, 'grats'", Chat{Channel: ChannelGuild, Speaker: "Ulric", Text: "grats"}},
+		{ts + "You say to your guild, 'ty'", Chat{Channel: ChannelGuild, Speaker: "You", Text: "ty", Self: true}},
+		{ts + "Tamsin tells the raid,  'CH on MT'", nil},
+		{ts + "a fire beetle scowls at you, ready to attack -- looks like an even fight.", Consider{Target: "a fire beetle", Level: "looks like an even fight."}},
+		{ts + "The Gods of Norrath emit a sinister laugh as they toy with their creations.", Repop{}},
+		{ts + "You have slain an orc pawn!", Kill{Target: "an orc pawn"}},
+		{ts + "You begin tracking an orc pawn.", Tracking{Target: "an orc pawn"}},
+		{ts + "You gain party experience!!", Experience{}},
+		{ts + "You have been slain by a Teir'Dal ranger!", Death{}},
+		{ts + "Summoning your corpse.", Recovery{}},
+		{ts + "You have been resurrected.", Recovery{}},
+		{ts + "This corpse decays.", Recovery{Decayed: true}},
+		{ts + "You slash an orc pawn for 12 points of damage.", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := c.Classify(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Classify(%q) = %#v, want %#v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyBadNumber(t *testing.T) {
+	c, _ := Compile(Patterns{Numbers: NumbersComma})
+	loc, ok := c.Classify("Your Location is 1,2,3, 2, 3").(Location)
+	if !ok || loc.Err == nil {
+		t.Errorf("Classify = %#v, want a Location with Err", loc)
+	}
+}
+
+func TestCompileOverrides(t *testing.T) {
+	c, errs := Compile(Patterns{
+		ZoneEntry: `Entering zone: (\w+)`,
+		Tracking:  `Now tracking (.+)`,
+		Death:     `(`,    // Doesn't compile
+		Kill:      `Slew`, // No capture group
+		Numbers:   "roman",
+	})
+	if len(errs) != 3 {
+		t.Errorf("Compile reported %d errors, want 3: %v", len(errs), errs)
+	}
+	for _, want := range []string{"death", "kill", "numbers"} {
+		found := false
+		for _, err := range errs {
+			found = found || strings.HasPrefix(err.Error(), want)
+		}
+		if !found {
+			t.Errorf("no error for %s in %v", want, errs)
+		}
+	}
+
+	tests := []struct {
+		line string
+		want Event
+	}{
+		{"Entering zone: qeynos2", ZoneEntered{Zone: "qeynos2"}},
+		{"You have entered Qeynos.", nil},
+		{"Now tracking a gnoll", Tracking{Target: "a gnoll"}},
+		{"You have been slain by a gnoll!", Death{}},         // Default kept
+		{"You have slain a gnoll!", Kill{Target: "a gnoll"}}, // Default kept
+	}
+	for _, tt := range tests {
+		if got := c.Classify(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Classify(%q) = %#v, want %#v", tt.line, got, tt.want)
+		}
+	}
+	if c.Numbers != NumbersAuto {
+		t.Errorf("Numbers = %q, want auto", c.Numbers)
+	}
+}
+
+func TestZone(t *testing.T) {
+	c, _ := Compile(Patterns{})
+	tests := map[string]string{
+		"You have entered The Feerrott.":       "The Feerrott",
+		"There are 2 players in North Qeynos.": "North Qeynos",
+		"There are 80 players in EverQuest.":   "",
+		"Your Location is 1.00, 2.00, 3.00":    "",
+	}
+	for line, want := range tests {
+		if got := c.Zone(line); got != want {
+			t.Errorf("Zone(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
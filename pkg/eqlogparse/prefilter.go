@@ -0,0 +1,138 @@
+package eqlogparse
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode/utf8"
+)
+
+// Most log lines are combat spam that no pattern can match, and running
+// every regex over them dominated parse time. Each pattern therefore
+// carries the literals a matching line must contain (at least one of them),
+// worked out from the regex itself so user overrides get one too, and the
+// regex only runs when a cheap substring check passes.
+
+// Matcher is a compiled pattern with its substring pre-check.
+type Matcher struct {
+	*regexp.Regexp
+	needles []string // A match contains at least one; empty means always run
+}
+
+func newMatcher(re *regexp.Regexp) *Matcher {
+	return &Matcher{Regexp: re, needles: requiredLiterals(re.String())}
+}
+
+// mayMatch is the pre-check: false means the regex can't match.
+func (m *Matcher) mayMatch(line string) bool {
+	if len(m.needles) == 0 {
+		return true
+	}
+	for _, n := range m.needles {
+		if strings.Contains(line, n) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Matcher) MatchString(line string) bool {
+	return m.mayMatch(line) && m.Regexp.MatchString(line)
+}
+
+func (m *Matcher) FindStringSubmatch(line string) []string {
+	if !m.mayMatch(line) {
+		return nil
+	}
+	return m.Regexp.FindStringSubmatch(line)
+}
+
+// minNeedle is the shortest literal worth checking; shorter ones pass
+// too many lines to pay for themselves.
+const minNeedle = 3
+
+// requiredLiterals returns literals one of which appears in every match of
+// expr, or nil when there is no useful set.
+func requiredLiterals(expr string) []string {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return nil
+	}
+	needles := literalsOf(re.Simplify())
+	for _, n := range needles {
+		if len(n) < minNeedle {
+			return nil
+		}
+	}
+	return needles
+}
+
+// literalsOf walks the syntax tree. Case-insensitive literals are given up
+// on rather than folded.
+func literalsOf(re *syntax.Regexp) []string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			return nil
+		}
+		return []string{string(re.Rune)}
+
+	case syntax.OpCapture, syntax.OpPlus:
+		return literalsOf(re.Sub[0])
+
+	case syntax.OpRepeat:
+		if re.Min < 1 {
+			return nil
+		}
+		return literalsOf(re.Sub[0])
+
+	case syntax.OpAlternate:
+		var all []string
+		for _, sub := range re.Sub {
+			lits := literalsOf(sub)
+			if lits == nil {
+				return nil
+			}
+			all = append(all, lits...)
+		}
+		return all
+
+	case syntax.OpConcat:
+		// Adjacent literals join into one; of the candidates, keep the set
+		// whose shortest member is longest.
+		var best []string
+		consider := func(lits []string) {
+			if lits != nil && shortest(lits) > shortest(best) {
+				best = lits
+			}
+		}
+		var run []rune
+		for _, sub := range re.Sub {
+			if sub.Op == syntax.OpLiteral && sub.Flags&syntax.FoldCase == 0 {
+				run = append(run, sub.Rune...)
+				continue
+			}
+			if len(run) > 0 {
+				consider([]string{string(run)})
+				run = nil
+			}
+			consider(literalsOf(sub))
+		}
+		if len(run) > 0 {
+			consider([]string{string(run)})
+		}
+		return best
+	}
+	return nil
+}
+
+func shortest(lits []string) int {
+	if len(lits) == 0 {
+		return 0
+	}
+	n := utf8.RuneCountInString(lits[0])
+	for _, l := range lits[1:] {
+		n = min(n, utf8.RuneCountInString(l))
+	}
+	return n
+}
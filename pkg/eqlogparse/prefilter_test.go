@@ -0,0 +1,103 @@
+package eqlogparse
+
+import (
+	"bufio"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readLines reads a fixture from testdata, one string per line.
+func readLines(tb testing.TB, name string) []string {
+	tb.Helper()
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimSpace(scanner.Text()))
+	}
+	if err := scanner.Err(); err != nil {
+		tb.Fatal(err)
+	}
+	return lines
+}
+
+// unfiltered returns a copy of c whose matchers always run their regex, the
+// way lines were classified before the pre-check.
+func unfiltered(c *Classifier) *Classifier {
+	u := *c
+	for _, m := range []**Matcher{
+		&u.Location, &u.ZoneEntry, &u.Death, &u.Recovery, &u.SenseHeading,
+		&u.OOC, &u.Group, &u.Guild, &u.Consider, &u.Repop, &u.Kill,
+		&u.Experience, &u.Loading, &u.Who, &u.Tracking,
+	} {
+		*m = &Matcher{Regexp: (*m).Regexp}
+	}
+	return &u
+}
+
+func TestRequiredLiterals(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{`You have been slain`, []string{"You have been slain"}},
+		{`You have slain (.+?)!`, []string{"You have slain "}},
+		{`Summoning.*corpse|corpse decays`, []string{"Summoning", "corpse decays"}},
+		{`(\w+) tells? the group, '(.*)'$`, []string{" the group, '"}},
+		{`(?i)you have been slain`, nil}, // Case folding isn't attempted
+		{`a|bc`, nil},                    // Too short to pay off
+		{`(?:You )?gain`, []string{"gain"}},
+		{`x*`, nil},
+		{`(`, nil},
+	}
+	for _, tt := range tests {
+		if got := requiredLiterals(tt.expr); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("requiredLiterals(%q) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+// The pre-check must never change what a line is classified as.
+func TestPrefilterMatchesUnfiltered(t *testing.T) {
+	c, _ := Compile(Patterns{})
+	u := unfiltered(c)
+	for _, line := range readLines(t, "raidnight.txt") {
+		if got, want := c.Classify(line), u.Classify(line); !reflect.DeepEqual(got, want) {
+			t.Errorf("Classify(%q) = %#v, unfiltered %#v", line, got, want)
+		}
+	}
+}
+
+// BenchmarkClassify runs a raid night's log (mostly combat spam) through
+// Classify with and without the pre-check.
+func BenchmarkClassify(b *testing.B) {
+	lines := readLines(b, "raidnight.txt")
+	c, _ := Compile(Patterns{})
+	for _, bench := range []struct {
+		name string
+		c    *Classifier
+	}{
+		{"Filtered", c},
+		{"Unfiltered", unfiltered(c)},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			var size int64
+			for _, line := range lines {
+				size += int64(len(line)) + 1
+			}
+			b.SetBytes(size)
+			b.ReportAllocs()
+			for b.Loop() {
+				for _, line := range lines {
+					bench.c.Classify(line)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package eqlogparse
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"strings"
+	"time"
+)
+
+// maxLineSize bounds a single log line; longer ones are reported as errors.
+const maxLineSize = 1 << 20
+
+// Entry is a classified log line.
+type Entry struct {
+	Time  time.Time // From the line's timestamp; zero if it had none
+	Line  string    // The whole line, timestamp included
+	Event Event
+}
+
+// Read classifies the lines of a log, yielding the ones that match a
+// pattern. A read error is yielded once and ends the sequence.
+func (c *Classifier) Read(r io.Reader) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		for scanner.Scan() {
+			if entry, ok := c.entry(scanner.Text()); ok && !yield(entry, nil) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield(Entry{}, err)
+		}
+	}
+}
+
+// Stream classifies lines as they arrive, for a log being tailed, sending
+// the ones that match a pattern. The returned channel is closed once lines
+// is.
+func (c *Classifier) Stream(lines <-chan string) <-chan Entry {
+	out := make(chan Entry, 64)
+	go func() {
+		defer close(out)
+		for line := range lines {
+			if entry, ok := c.entry(line); ok {
+				out <- entry
+			}
+		}
+	}()
+	return out
+}
+
+func (c *Classifier) entry(line string) (Entry, bool) {
+	line = strings.TrimSpace(line) // Windows logs end in \r\n
+	ev := c.Classify(line)
+	if ev == nil {
+		return Entry{}, false
+	}
+	t, _ := ParseTimestamp(line)
+	return Entry{Time: t, Line: line, Event: ev}, true
+}
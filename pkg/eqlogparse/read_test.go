@@ -0,0 +1,152 @@
+package eqlogparse
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Events the fixtures classify to, in order.
+var fixtureEvents = map[string][]Event{
+	"session.txt": {
+		ZoneEntered{Zone: "Greater Faydark"},
+		Location{Y: -1234.5, X: 567.25, Z: 12},
+		SenseHeading{Direction: "NorthEast"},
+		Chat{Channel: ChannelOOC, Speaker: "Fippy", Text: "LFG level 12 ranger"},
+		Chat{Channel: ChannelGroup, Speaker: "You", Text: "pulling", Self: true},
+		Chat{Channel: ChannelGroup, Speaker: "Tamsin", Text: "ready"},
+		Chat{Channel: ChannelGuild, Speaker: "Ulric", Text: "grats"},
+		Tracking{Target: "an orc pawn"},
+		Consider{Target: "an orc pawn", Level: "looks like a reasonably safe opponent."},
+		Kill{Target: "an orc pawn"},
+		Experience{},
+		Death{},
+		Loading{},
+		ZoneEntered{Zone: "Kelethin"},
+		Recovery{},
+		Repop{},
+		Recovery{Decayed: true},
+	},
+	"who.txt": {
+		Who{Name: "Fippy", Level: 50, Class: "Warlord", Race: "Barbarian", Guild: "Nox Raiders"},
+		Who{Name: "Tamsin", Guild: "Nox Raiders", Anonymous: true},
+		Who{Name: "Ulric", Level: 12, Class: "Ranger", Race: "Wood Elf"},
+		Who{Name: "Vesna", Level: 60, Class: "Grave Lord", Race: "Dark Elf", Guild: "Shadow Court"},
+		ZoneEntered{Zone: "Greater Faydark"},
+		Who{Name: "Newbie", Level: 1, Class: "Warrior", Race: "Human"},
+	},
+}
+
+func TestRead(t *testing.T) {
+	c, _ := Compile(Patterns{})
+	for name, want := range fixtureEvents {
+		f, err := os.Open("testdata/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []Event
+		for entry, err := range c.Read(f) {
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if entry.Time.IsZero() {
+				t.Errorf("%s: no time for %q", name, entry.Line)
+			}
+			if strings.HasSuffix(entry.Line, "\r") {
+				t.Errorf("%s: line kept its \\r: %q", name, entry.Line)
+			}
+			got = append(got, entry.Event)
+		}
+		f.Close()
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Read classified\n%#v\nwant\n%#v", name, got, want)
+		}
+	}
+}
+
+func TestReadEntry(t *testing.T) {
+	c, _ := Compile(Patterns{})
+	log := "[Mon Oct 12 20:01:02 2026] You have entered Kelethin.\r\nYou have slain a bat!\n"
+	var entries []Entry
+	for entry, err := range c.Read(strings.NewReader(log)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	want := []Entry{
+		{
+			Time:  time.Date(2026, time.October, 12, 20, 1, 2, 0, time.Local),
+			Line:  "[Mon Oct 12 20:01:02 2026] You have entered Kelethin.",
+			Event: ZoneEntered{Zone: "Kelethin"},
+		},
+		{Line: "You have slain a bat!", Event: Kill{Target: "a bat"}}, // No timestamp
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("Read = %#v, want %#v", entries, want)
+	}
+}
+
+func TestReadStop(t *testing.T) {
+	c, _ := Compile(Patterns{})
+	f, err := os.Open("testdata/session.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	n := 0
+	for range c.Read(f) {
+		if n++; n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Errorf("read %d entries after stopping at 2", n)
+	}
+}
+
+func TestReadLongLine(t *testing.T) {
+	c, _ := Compile(Patterns{})
+	log := "You have entered Kelethin.\n" + strings.Repeat("x", maxLineSize+1) + "\nYou have slain a bat!\n"
+	var events []Event
+	var errs []error
+	for entry, err := range c.Read(strings.NewReader(log)) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		events = append(events, entry.Event)
+	}
+	if len(errs) != 1 {
+		t.Errorf("got errors %v, want one", errs)
+	}
+	if want := []Event{ZoneEntered{Zone: "Kelethin"}}; !reflect.DeepEqual(events, want) {
+		t.Errorf("got %#v before the error, want %#v", events, want)
+	}
+}
+
+func TestStream(t *testing.T) {
+	c, _ := Compile(Patterns{})
+	for name, want := range fixtureEvents {
+		data, err := os.ReadFile("testdata/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := make(chan string)
+		go func() {
+			for _, line := range strings.Split(string(data), "\n") {
+				lines <- line
+			}
+			close(lines)
+		}()
+		var got []Event
+		for entry := range c.Stream(lines) {
+			got = append(got, entry.Event)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Stream classified\n%#v\nwant\n%#v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package eqlogparse
+
+import (
+	"strings"
+	"time"
+)
+
+// timestampLayout is the prefix EQ writes on every log line:
+// "[Mon Oct 12 20:01:02 2026] You have entered ..."
+const timestampLayout = "Mon Jan 02 15:04:05 2006"
+
+// ParseTimestamp reads the time a line was written from its prefix, in
+// local time.
+func ParseTimestamp(line string) (time.Time, bool) {
+	if !strings.HasPrefix(line, "[") {
+		return time.Time{}, false
+	}
+	end := strings.IndexByte(line, ']')
+	if end < 0 {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(timestampLayout, line[1:end], time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}